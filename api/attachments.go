@@ -0,0 +1,378 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/scttfrdmn/apprise-go/apprise"
+)
+
+// Default attachment limits, used when the ServerConfig leaves them unset
+const (
+	defaultMaxAttachments    = 10
+	defaultMaxAttachmentSize = 10 << 20 // 10MB
+	defaultMaxRequestSize    = 50 << 20 // 50MB
+)
+
+// attachmentFetchTimeout bounds downloading an attachment given by URL
+const attachmentFetchTimeout = 30 * time.Second
+
+// nonPublicNetworks are ranges, beyond loopback, private and link-local
+// addresses, that attachment URLs may not reach: "this network", carrier-grade
+// NAT, IETF protocol assignments and benchmarking
+var nonPublicNetworks = []*net.IPNet{
+	mustParseCIDR("0.0.0.0/8"),
+	mustParseCIDR("100.64.0.0/10"),
+	mustParseCIDR("192.0.0.0/24"),
+	mustParseCIDR("198.18.0.0/15"),
+}
+
+func mustParseCIDR(cidr string) *net.IPNet {
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		panic(err)
+	}
+	return network
+}
+
+var (
+	// ErrTooManyAttachments is returned when a notification carries more
+	// attachments than the server allows
+	ErrTooManyAttachments = errors.New("too many attachments")
+	// ErrAttachmentTooLarge is returned when an attachment exceeds the
+	// server's size limit
+	ErrAttachmentTooLarge = errors.New("attachment too large")
+)
+
+// AttachmentRequest is an attachment sent with a notification request. Data
+// holds base64 content or a data: URL; URL names an http(s) resource that is
+// fetched when the notification is sent. In JSON an attachment may also be
+// given as a plain string holding either form.
+type AttachmentRequest struct {
+	Name     string `json:"name,omitempty"`
+	MimeType string `json:"mime_type,omitempty"`
+	Data     string `json:"data,omitempty"`
+	URL      string `json:"url,omitempty"`
+}
+
+// UnmarshalJSON accepts an attachment object or a URL/data string
+func (a *AttachmentRequest) UnmarshalJSON(data []byte) error {
+	var source string
+	if err := json.Unmarshal(data, &source); err == nil {
+		*a = attachmentFromString(source)
+		return nil
+	}
+
+	type attachmentRequest AttachmentRequest
+	return json.Unmarshal(data, (*attachmentRequest)(a))
+}
+
+// attachmentFromString interprets a URL or data string as an attachment
+func attachmentFromString(source string) AttachmentRequest {
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		return AttachmentRequest{URL: source}
+	}
+	return AttachmentRequest{Data: source}
+}
+
+// maxAttachments returns the number of attachments allowed per notification
+func (s *Server) maxAttachments() int {
+	if s.config.MaxAttachments > 0 {
+		return s.config.MaxAttachments
+	}
+	return defaultMaxAttachments
+}
+
+// maxAttachmentSize returns the size allowed for each attachment
+func (s *Server) maxAttachmentSize() int64 {
+	if s.config.MaxAttachmentSize > 0 {
+		return s.config.MaxAttachmentSize
+	}
+	return defaultMaxAttachmentSize
+}
+
+// maxRequestSize returns the size allowed for a notify request body
+func (s *Server) maxRequestSize() int64 {
+	if s.config.MaxRequestSize > 0 {
+		return s.config.MaxRequestSize
+	}
+	return defaultMaxRequestSize
+}
+
+// readNotifyRequest decodes a notify request body into dst. JSON bodies are
+// decoded directly. For multipart bodies the JSON is taken from a "payload"
+// field, or for a single notification built from the form fields, and the
+// uploaded files are returned for use as attachments. The caller must call
+// cleanup once the files are no longer needed.
+func (s *Server) readNotifyRequest(w http.ResponseWriter, r *http.Request, dst interface{}) (files []*multipart.FileHeader, cleanup func(), err error) {
+	cleanup = func() {}
+	r.Body = http.MaxBytesReader(w, r.Body, s.maxRequestSize())
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		return nil, cleanup, json.NewDecoder(r.Body).Decode(dst)
+	}
+
+	if err := r.ParseMultipartForm(maxFormMemory); err != nil {
+		return nil, cleanup, err
+	}
+	cleanup = func() { _ = r.MultipartForm.RemoveAll() }
+
+	form := r.MultipartForm
+	if payload := form.Value["payload"]; len(payload) > 0 {
+		err = json.Unmarshal([]byte(payload[0]), dst)
+	} else if req, ok := dst.(*NotificationRequest); ok {
+		*req = notificationFromForm(form.Value)
+	} else {
+		err = fmt.Errorf("multipart requests require a JSON payload field")
+	}
+
+	for _, headers := range form.File {
+		files = append(files, headers...)
+	}
+	return files, cleanup, err
+}
+
+// notificationFromForm builds a notification request from form fields named
+// like the JSON request fields. "attach" fields hold URL or data attachments.
+func notificationFromForm(values map[string][]string) NotificationRequest {
+	first := func(name string) string {
+		if v := values[name]; len(v) > 0 {
+			return v[0]
+		}
+		return ""
+	}
+
+	req := NotificationRequest{
+		Title:  first("title"),
+		Body:   first("body"),
+		Type:   first("type"),
+		Format: first("format"),
+		Tags:   values["tags"],
+	}
+	req.Async, _ = strconv.ParseBool(first("async"))
+	for _, urls := range values["urls"] {
+		req.URLs = append(req.URLs, splitURLList(urls)...)
+	}
+	for _, source := range values["attach"] {
+		req.Attachments = append(req.Attachments, attachmentFromString(source))
+	}
+	return req
+}
+
+// buildAttachments loads a notification's attachments, enforcing the
+// server's limits. It returns nil when there are none.
+func (s *Server) buildAttachments(specs []AttachmentRequest, files []*multipart.FileHeader) (*apprise.AttachmentManager, error) {
+	if len(specs)+len(files) == 0 {
+		return nil, nil
+	}
+	if count := len(specs) + len(files); count > s.maxAttachments() {
+		return nil, fmt.Errorf("%w: %d given, at most %d allowed", ErrTooManyAttachments, count, s.maxAttachments())
+	}
+
+	maxSize := s.maxAttachmentSize()
+	am := apprise.NewAttachmentManager()
+	am.SetMaxSize(maxSize)
+
+	for i, spec := range specs {
+		name := spec.Name
+		if name == "" {
+			name = fmt.Sprintf("attachment-%d", i+1)
+		}
+
+		if spec.URL != "" {
+			// Only remote resources; paths on the server are never read
+			if !strings.HasPrefix(spec.URL, "http://") && !strings.HasPrefix(spec.URL, "https://") {
+				return nil, fmt.Errorf("attachment %s: URL must use http or https", name)
+			}
+			if spec.Name == "" {
+				if u, err := url.Parse(spec.URL); err == nil && path.Base(u.Path) != "/" && path.Base(u.Path) != "." {
+					name = path.Base(u.Path)
+				}
+			}
+			data, mimeType, err := s.fetchAttachment(spec.URL, maxSize)
+			if err != nil {
+				return nil, fmt.Errorf("attachment %s: %w", name, err)
+			}
+			if spec.MimeType != "" {
+				mimeType = spec.MimeType
+			}
+			if err := am.AddData(data, name, mimeType); err != nil {
+				return nil, fmt.Errorf("attachment %s: %w", name, err)
+			}
+			continue
+		}
+
+		data, mimeType, err := decodeAttachmentData(spec.Data, maxSize)
+		if err != nil {
+			return nil, fmt.Errorf("attachment %s: %w", name, err)
+		}
+		if spec.MimeType != "" {
+			mimeType = spec.MimeType
+		}
+		if err := am.AddData(data, name, mimeType); err != nil {
+			return nil, fmt.Errorf("attachment %s: %w", name, err)
+		}
+	}
+
+	for _, fh := range files {
+		if fh.Size > maxSize {
+			return nil, fmt.Errorf("%w: %s is %d bytes, at most %d allowed", ErrAttachmentTooLarge, fh.Filename, fh.Size, maxSize)
+		}
+
+		file, err := fh.Open()
+		if err != nil {
+			return nil, fmt.Errorf("attachment %s: %w", fh.Filename, err)
+		}
+		data, err := io.ReadAll(file)
+		_ = file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("attachment %s: %w", fh.Filename, err)
+		}
+
+		mimeType := fh.Header.Get("Content-Type")
+		if mimeType == "" || mimeType == "application/octet-stream" {
+			mimeType = http.DetectContentType(data)
+		}
+		if err := am.AddData(data, fh.Filename, mimeType); err != nil {
+			return nil, fmt.Errorf("attachment %s: %w", fh.Filename, err)
+		}
+	}
+
+	return am, nil
+}
+
+// fetchAttachment downloads an attachment of at most maxSize bytes from an
+// http(s) URL
+func (s *Server) fetchAttachment(rawURL string, maxSize int64) ([]byte, string, error) {
+	resp, err := s.attachmentClient.Get(rawURL)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, "", fmt.Errorf("failed to fetch: HTTP status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch: %w", err)
+	}
+	if int64(len(data)) > maxSize {
+		return nil, "", fmt.Errorf("%w: more than %d bytes", ErrAttachmentTooLarge, maxSize)
+	}
+
+	mimeType := resp.Header.Get("Content-Type")
+	if mimeType == "" {
+		mimeType = http.DetectContentType(data)
+	}
+	return data, mimeType, nil
+}
+
+// newAttachmentClient returns the client fetching attachments given by URL.
+// Unless allowPrivate is set it refuses to connect to loopback, private,
+// link-local and other non-public addresses, which holds for redirects and
+// for host names resolving differently later too.
+func newAttachmentClient(allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	if !allowPrivate {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+				return fmt.Errorf("attachment URLs may not reach non-public address %s", host)
+			}
+			return nil
+		}
+	}
+
+	return &http.Client{
+		Timeout: attachmentFetchTimeout,
+		Transport: &http.Transport{
+			// No proxy, so the address check applies to the attachment's host
+			Proxy:               nil,
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 10 * time.Second,
+			MaxIdleConns:        10,
+			IdleConnTimeout:     30 * time.Second,
+		},
+	}
+}
+
+// isPublicIP reports whether ip is a globally routable unicast address
+func isPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsMulticast() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() {
+		return false
+	}
+	for _, network := range nonPublicNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// decodeAttachmentData decodes base64 or data: URL content and detects its
+// MIME type
+func decodeAttachmentData(source string, maxSize int64) ([]byte, string, error) {
+	if source == "" {
+		return nil, "", fmt.Errorf("either data or url is required")
+	}
+
+	var data []byte
+	var mimeType string
+	if strings.HasPrefix(source, "data:") {
+		attachment, err := apprise.NewMemoryAttachmentFromDataURL(source)
+		if err != nil {
+			return nil, "", err
+		}
+		reader, err := attachment.Open()
+		if err != nil {
+			return nil, "", err
+		}
+		data, err = io.ReadAll(reader)
+		_ = reader.Close()
+		if err != nil {
+			return nil, "", err
+		}
+		mimeType = attachment.GetMimeType()
+	} else {
+		var err error
+		if data, err = base64.StdEncoding.DecodeString(source); err != nil {
+			return nil, "", fmt.Errorf("invalid base64 data: %w", err)
+		}
+		mimeType = http.DetectContentType(data)
+	}
+
+	if int64(len(data)) > maxSize {
+		return nil, "", fmt.Errorf("%w: %d bytes, at most %d allowed", ErrAttachmentTooLarge, len(data), maxSize)
+	}
+	return data, mimeType, nil
+}
+
+// requestErrorStatus returns the status for a request body that could not be
+// read or whose attachments were rejected
+func requestErrorStatus(err error) int {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) || errors.Is(err, ErrAttachmentTooLarge) {
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusBadRequest
+}
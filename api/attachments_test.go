@@ -0,0 +1,233 @@
+package api
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"log"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/scttfrdmn/apprise-go/apprise"
+)
+
+// uploadRecorder is a stand-in Discord API that records uploaded files
+type uploadRecorder struct {
+	mu    sync.Mutex
+	files map[string]string // file name to content
+}
+
+func newUploadServer(t *testing.T) (*uploadRecorder, string) {
+	t.Helper()
+	rec := &uploadRecorder{files: make(map[string]string)}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err == nil {
+			rec.mu.Lock()
+			for _, headers := range r.MultipartForm.File {
+				for _, fh := range headers {
+					f, _ := fh.Open()
+					data, _ := io.ReadAll(f)
+					f.Close()
+					rec.files[fh.Filename] = string(data)
+				}
+			}
+			rec.mu.Unlock()
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+	return rec, "discord://123/token?api_url=" + server.URL
+}
+
+func (rec *uploadRecorder) names() []string {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	var names []string
+	for name := range rec.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func newAttachmentTestServer(t *testing.T, config *ServerConfig) *Server {
+	t.Helper()
+	config.JWTSecret = "test-secret"
+	server, err := NewServer(config, apprise.New(), nil, log.New(os.Stdout, "[test] ", log.LstdFlags))
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	return server
+}
+
+func TestNotifyWithJSONAttachments(t *testing.T) {
+	rec, discordURL := newUploadServer(t)
+	server := newAttachmentTestServer(t, &ServerConfig{})
+
+	w, _ := doJSON(server, "POST", "/api/v1/notify", map[string]interface{}{
+		"body": "Build finished",
+		"urls": []string{discordURL},
+		"attachments": []interface{}{
+			map[string]string{"name": "build.log", "data": base64.StdEncoding.EncodeToString([]byte("ok"))},
+			"data:text/plain;base64," + base64.StdEncoding.EncodeToString([]byte("second")),
+		},
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	names := rec.names()
+	if len(names) != 2 || names[0] != "attachment-2" || names[1] != "build.log" {
+		t.Errorf("Unexpected uploaded files: %v", names)
+	}
+	if rec.files["build.log"] != "ok" || rec.files["attachment-2"] != "second" {
+		t.Errorf("Unexpected uploaded content: %v", rec.files)
+	}
+}
+
+func TestNotifyWithMultipartAttachments(t *testing.T) {
+	rec, discordURL := newUploadServer(t)
+	server := newAttachmentTestServer(t, &ServerConfig{})
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	writer.WriteField("body", "Screenshot attached")
+	writer.WriteField("urls", discordURL)
+	part, _ := writer.CreateFormFile("attach", "screen.png")
+	part.Write([]byte("\x89PNG\r\n\x1a\nimage"))
+	writer.Close()
+
+	req := httptest.NewRequest("POST", "/api/v1/notify", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if names := rec.names(); len(names) != 1 || names[0] != "screen.png" {
+		t.Errorf("Unexpected uploaded files: %v", names)
+	}
+
+	// Bulk requests carry their JSON in a payload field
+	rec.files = make(map[string]string)
+	body.Reset()
+	writer = multipart.NewWriter(&body)
+	payload, _ := json.Marshal(BulkNotificationRequest{Notifications: []NotificationRequest{
+		{Body: "one", URLs: []string{discordURL}},
+		{Body: "two", URLs: []string{discordURL}},
+	}})
+	writer.WriteField("payload", string(payload))
+	part, _ = writer.CreateFormFile("attach", "shared.txt")
+	part.Write([]byte("shared"))
+	writer.Close()
+
+	req = httptest.NewRequest("POST", "/api/v1/notify/bulk", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	w = httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200 from bulk, got %d: %s", w.Code, w.Body.String())
+	}
+	if rec.files["shared.txt"] != "shared" {
+		t.Errorf("Expected the shared file to be uploaded, got %v", rec.files)
+	}
+}
+
+func TestNotifyAttachmentLimits(t *testing.T) {
+	_, discordURL := newUploadServer(t)
+	server := newAttachmentTestServer(t, &ServerConfig{
+		MaxAttachments:    1,
+		MaxAttachmentSize: 4,
+		MaxRequestSize:    1024,
+	})
+
+	tests := []struct {
+		name        string
+		attachments []interface{}
+		status      int
+	}{
+		{"too many", []interface{}{"aGk=", "aGk="}, http.StatusBadRequest},
+		{"too large", []interface{}{base64.StdEncoding.EncodeToString([]byte("too large"))}, http.StatusRequestEntityTooLarge},
+		{"invalid base64", []interface{}{"not base64!"}, http.StatusBadRequest},
+		{"server path", []interface{}{map[string]string{"url": "file:///etc/passwd"}}, http.StatusBadRequest},
+		{"request body", []interface{}{strings.Repeat("a", 2048)}, http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, _ := doJSON(server, "POST", "/api/v1/notify", map[string]interface{}{
+				"body":        "hello",
+				"urls":        []string{discordURL},
+				"attachments": tt.attachments,
+			})
+			if w.Code != tt.status {
+				t.Errorf("Expected %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestNotifyAttachmentURLs(t *testing.T) {
+	// The file server listens on loopback, which attachment URLs may not
+	// reach unless explicitly allowed
+	files := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte("remote"))
+	}))
+	defer files.Close()
+
+	rec, discordURL := newUploadServer(t)
+	notify := func(server *Server) *httptest.ResponseRecorder {
+		w, _ := doJSON(server, "POST", "/api/v1/notify", map[string]interface{}{
+			"body":        "hello",
+			"urls":        []string{discordURL},
+			"attachments": []interface{}{map[string]string{"url": files.URL + "/report.txt"}},
+		})
+		return w
+	}
+
+	if w := notify(newAttachmentTestServer(t, &ServerConfig{})); w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected a loopback attachment URL to be refused, got %d: %s", w.Code, w.Body.String())
+	}
+	if len(rec.names()) != 0 {
+		t.Errorf("Expected nothing to be uploaded, got %v", rec.names())
+	}
+
+	if w := notify(newAttachmentTestServer(t, &ServerConfig{AllowPrivateAttachmentURLs: true})); w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if rec.files["report.txt"] != "remote" {
+		t.Errorf("Expected the fetched file to be uploaded, got %v", rec.files)
+	}
+}
+
+func TestIsPublicIP(t *testing.T) {
+	for addr, want := range map[string]bool{
+		"93.184.216.34":   true,
+		"2606:4700::1111": true,
+		"127.0.0.1":       false,
+		"10.1.2.3":        false,
+		"172.16.0.1":      false,
+		"192.168.1.1":     false,
+		"169.254.169.254": false,
+		"100.64.0.1":      false,
+		"0.0.0.0":         false,
+		"::1":             false,
+		"fe80::1":         false,
+		"fd00::1":         false,
+	} {
+		if got := isPublicIP(net.ParseIP(addr)); got != want {
+			t.Errorf("isPublicIP(%s) = %v, want %v", addr, got, want)
+		}
+	}
+}
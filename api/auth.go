@@ -121,39 +121,51 @@ func (s *Server) AuthMiddleware(next http.Handler) http.Handler {
 			return
 		}
 
-		// Validate JWT token
-		if strings.HasPrefix(token, "ak_") {
-			// API Key authentication
-			user, err := s.validateAPIKey(token)
-			if err != nil {
+		user, err := s.userFromToken(token)
+		if err != nil {
+			if strings.HasPrefix(token, "ak_") {
 				s.sendError(w, http.StatusUnauthorized, "Invalid API key", err)
-				return
+			} else {
+				s.sendError(w, http.StatusUnauthorized, "Invalid token", err)
 			}
-			ctx := context.WithValue(r.Context(), userContextKey, user)
-			next.ServeHTTP(w, r.WithContext(ctx))
-			return
-		}
-
-		// JWT token authentication
-		claims, err := s.ValidateToken(token)
-		if err != nil {
-			s.sendError(w, http.StatusUnauthorized, "Invalid token", err)
 			return
 		}
 
-		// Create user from claims
-		user := &User{
-			ID:       claims.UserID,
-			Username: claims.Username,
-			Roles:    claims.Roles,
-		}
-
 		// Add user to request context
 		ctx := context.WithValue(r.Context(), userContextKey, user)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
+// userFromToken authenticates an API key or JWT token and returns its user
+func (s *Server) userFromToken(token string) (*User, error) {
+	if token == "" {
+		return nil, fmt.Errorf("no credentials provided")
+	}
+
+	// API Key authentication
+	if strings.HasPrefix(token, "ak_") {
+		return s.validateAPIKey(token)
+	}
+
+	// JWT token authentication. The user is looked up so disabled users
+	// and changed roles take effect before the token expires.
+	claims, err := s.ValidateToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.users.GetUser(claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.Enabled {
+		return nil, ErrUserDisabled
+	}
+
+	return user, nil
+}
+
 // isPublicEndpoint checks if an endpoint should be publicly accessible
 func (s *Server) isPublicEndpoint(path string) bool {
 	publicEndpoints := []string{
@@ -165,7 +177,9 @@ func (s *Server) isPublicEndpoint(path string) bool {
 		"/dashboard.html",
 		"/dashboard.js",
 		"/api/v1/auth/login",
-		"/api/v1/auth/register",
+	}
+	if s.config.AllowRegistration {
+		publicEndpoints = append(publicEndpoints, "/api/v1/auth/register")
 	}
 
 	for _, endpoint := range publicEndpoints {
@@ -197,22 +211,55 @@ func (s *Server) extractToken(r *http.Request) string {
 	return r.URL.Query().Get("api_key")
 }
 
-// validateAPIKey validates an API key and returns the associated user
+// validateAPIKey validates an API key and returns the associated user. A key
+// with scopes only carries those of the user's roles.
 func (s *Server) validateAPIKey(apiKey string) (*User, error) {
-	// In a real implementation, this would query a database
-	// For now, return a mock admin user
-	if apiKey == "ak_development_admin_key_do_not_use_in_production" {
-		return &User{
-			ID:       "admin",
-			Username: "admin",
-			Email:    "admin@localhost",
-			Roles:    []string{"admin", "user"},
-			Enabled:  true,
-			Created:  time.Now(),
-		}, nil
-	}
-	
-	return nil, fmt.Errorf("invalid API key")
+	user, record, err := s.users.ValidateAPIKey(apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(record.Scopes) > 0 {
+		var roles []string
+		for _, scope := range record.Scopes {
+			if hasRole(user, scope) {
+				roles = append(roles, scope)
+			}
+		}
+		user.Roles = roles
+	}
+
+	return user, nil
+}
+
+// hasRole reports whether user holds role. Admins hold every role.
+func hasRole(user *User, role string) bool {
+	for _, userRole := range user.Roles {
+		if userRole == role || userRole == "admin" {
+			return true
+		}
+	}
+	return false
+}
+
+// requireAdmin reports whether the request comes from an administrator,
+// checking its credentials even where authentication is not required, and
+// sends an error response when it does not
+func (s *Server) requireAdmin(w http.ResponseWriter, r *http.Request) bool {
+	user, ok := GetUserFromContext(r.Context())
+	if !ok {
+		var err error
+		if user, err = s.userFromToken(s.extractToken(r)); err != nil {
+			s.sendError(w, http.StatusUnauthorized, "Authentication required", nil)
+			return false
+		}
+	}
+
+	if !hasRole(user, "admin") {
+		s.sendError(w, http.StatusForbidden, "Insufficient permissions", nil)
+		return false
+	}
+	return true
 }
 
 // GetUserFromContext extracts the user from the request context
@@ -231,15 +278,7 @@ func (s *Server) RequireRole(role string) func(http.Handler) http.Handler {
 				return
 			}
 
-			hasRole := false
-			for _, userRole := range user.Roles {
-				if userRole == role || userRole == "admin" {
-					hasRole = true
-					break
-				}
-			}
-
-			if !hasRole {
+			if !hasRole(user, role) {
 				s.sendError(w, http.StatusForbidden, "Insufficient permissions", nil)
 				return
 			}
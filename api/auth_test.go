@@ -4,8 +4,13 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"log"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -23,6 +28,7 @@ func TestAuthenticationFlow(t *testing.T) {
 		LogLevel:      "info",
 		RequireAuth:   true,
 		TokenDuration: 1, // 1 hour
+		AdminPassword: "admin",
 		RateLimit: RateLimitConfig{
 			Enabled:        false, // Disable for auth tests
 			RequestsPerMin: 60,
@@ -203,25 +209,112 @@ func TestJWTTokenValidation(t *testing.T) {
 
 func TestAPIKeyAuthentication(t *testing.T) {
 	config := &ServerConfig{
-		RequireAuth: true,
-		JWTSecret:   "test-secret",
+		RequireAuth:   true,
+		JWTSecret:     "test-secret",
+		AdminPassword: "admin",
 	}
 
-	server := &Server{config: config}
+	server, err := NewServer(config, apprise.New(), nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	admin, err := server.authenticateUser("admin", "admin")
+	if err != nil {
+		t.Fatalf("Failed to authenticate admin: %v", err)
+	}
+	token, err := server.CreateToken(admin)
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+
+	createKey := func(request APIKeyCreateRequest) (int, map[string]interface{}) {
+		body, _ := json.Marshal(request)
+		req := httptest.NewRequest("POST", "/api/v1/auth/apikeys", bytes.NewBuffer(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+
+		var response APIResponse
+		json.NewDecoder(w.Body).Decode(&response)
+		data, _ := response.Data.(map[string]interface{})
+		return w.Code, data
+	}
 
 	t.Run("Valid API key authentication", func(t *testing.T) {
+		code, data := createKey(APIKeyCreateRequest{Name: "ci"})
+		if code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", code)
+		}
+		key := data["key"].(string)
+
 		req := httptest.NewRequest("GET", "/api/v1/services", nil)
-		req.Header.Set("X-API-Key", "ak_development_admin_key_do_not_use_in_production")
+		req.Header.Set("X-API-Key", key)
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200 with valid API key, got %d", w.Code)
+		}
 
-		// Simulate middleware execution
-		user, err := server.validateAPIKey("ak_development_admin_key_do_not_use_in_production")
+		user, err := server.validateAPIKey(key)
 		if err != nil {
 			t.Fatalf("Expected valid API key to work: %v", err)
 		}
-
 		if user.Username != "admin" {
 			t.Errorf("Expected admin user, got %s", user.Username)
 		}
+
+		keys, err := server.getUserAPIKeys(admin.ID)
+		if err != nil || len(keys) != 1 || keys[0].LastUsed == nil || keys[0].Key != "" {
+			t.Errorf("Expected one used key without its secret, got %+v (%v)", keys, err)
+		}
+	})
+
+	t.Run("Scoped API key", func(t *testing.T) {
+		code, data := createKey(APIKeyCreateRequest{Name: "notify-only", Scopes: []string{"user"}})
+		if code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", code)
+		}
+
+		user, err := server.validateAPIKey(data["key"].(string))
+		if err != nil {
+			t.Fatalf("Expected scoped API key to work: %v", err)
+		}
+		if len(user.Roles) != 1 || user.Roles[0] != "user" {
+			t.Errorf("Expected scoped key to carry only the user role, got %v", user.Roles)
+		}
+	})
+
+	t.Run("Expired API key", func(t *testing.T) {
+		past := time.Now().Add(-time.Hour)
+		if code, _ := createKey(APIKeyCreateRequest{Name: "old", ExpiresAt: &past}); code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for past expiry, got %d", code)
+		}
+
+		key := GenerateAPIKey()
+		record := &APIKeyRecord{ID: generateKeyID(), UserID: admin.ID, Name: "old", Prefix: key[:10], ExpiresAt: &past, Created: time.Now()}
+		if err := server.users.CreateAPIKey(record, key); err != nil {
+			t.Fatalf("Failed to store key: %v", err)
+		}
+		if _, err := server.validateAPIKey(key); !errors.Is(err, ErrAPIKeyExpired) {
+			t.Errorf("Expected ErrAPIKeyExpired, got %v", err)
+		}
+	})
+
+	t.Run("Deleted API key", func(t *testing.T) {
+		_, data := createKey(APIKeyCreateRequest{Name: "temp"})
+
+		req := httptest.NewRequest("DELETE", "/api/v1/auth/apikeys/"+data["id"].(string), nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d", w.Code)
+		}
+
+		if _, err := server.validateAPIKey(data["key"].(string)); err == nil {
+			t.Error("Expected deleted API key to be rejected")
+		}
 	})
 
 	t.Run("Invalid API key authentication", func(t *testing.T) {
@@ -232,6 +325,138 @@ func TestAPIKeyAuthentication(t *testing.T) {
 	})
 }
 
+func TestRegisterRoles(t *testing.T) {
+	config := &ServerConfig{JWTSecret: "test-secret", AdminPassword: "admin"}
+	server, err := NewServer(config, apprise.New(), nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	register := func(request RegisterRequest, token string) int {
+		body, _ := json.Marshal(request)
+		req := httptest.NewRequest("POST", "/api/v1/auth/register", bytes.NewBuffer(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := register(RegisterRequest{Username: "bob", Email: "bob@example.com", Password: "secret"}, ""); code != http.StatusForbidden {
+		t.Errorf("Expected status 403 without self-registration, got %d", code)
+	}
+
+	server.config.AllowRegistration = true
+	if code := register(RegisterRequest{Username: "bob", Email: "bob@example.com", Password: "secret", Roles: []string{"admin"}}, ""); code != http.StatusForbidden {
+		t.Errorf("Expected status 403 for self-assigned roles, got %d", code)
+	}
+	if code := register(RegisterRequest{Username: "bob", Email: "bob@example.com", Password: "secret"}, ""); code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", code)
+	}
+	if code := register(RegisterRequest{Username: "bob", Email: "bob@example.com", Password: "other"}, ""); code != http.StatusConflict {
+		t.Errorf("Expected status 409 for duplicate username, got %d", code)
+	}
+
+	user, err := server.authenticateUser("bob", "secret")
+	if err != nil {
+		t.Fatalf("Expected registered user to log in: %v", err)
+	}
+	if len(user.Roles) != 1 || user.Roles[0] != "user" {
+		t.Errorf("Expected user role, got %v", user.Roles)
+	}
+
+	admin, _ := server.authenticateUser("admin", "admin")
+	token, _ := server.CreateToken(admin)
+	if code := register(RegisterRequest{Username: "ops", Email: "ops@example.com", Password: "secret", Roles: []string{"admin"}}, token); code != http.StatusOK {
+		t.Errorf("Expected admins to assign roles, got %d", code)
+	}
+}
+
+func TestRegisterRequiresAdmin(t *testing.T) {
+	config := &ServerConfig{JWTSecret: "test-secret", AdminPassword: "admin", RequireAuth: true}
+	server, err := NewServer(config, apprise.New(), nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	body, _ := json.Marshal(RegisterRequest{Username: "mallory", Email: "mallory@example.com", Password: "secret"})
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, httptest.NewRequest("POST", "/api/v1/auth/register", bytes.NewBuffer(body)))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected anonymous registration to be rejected with 401, got %d", w.Code)
+	}
+
+	admin, _ := server.authenticateUser("admin", "admin")
+	token, _ := server.CreateToken(admin)
+	req := httptest.NewRequest("POST", "/api/v1/auth/register", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	w = httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected admins to register users, got %d", w.Code)
+	}
+}
+
+func TestDisabledUserToken(t *testing.T) {
+	config := &ServerConfig{JWTSecret: "test-secret", AdminPassword: "admin", RequireAuth: true}
+	server, err := NewServer(config, apprise.New(), nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	user := &User{ID: "user_ops", Username: "ops", Email: "ops@example.com", Roles: []string{"user"}, Enabled: true, Created: time.Now()}
+	if err := server.users.CreateUser(user, "secret"); err != nil {
+		t.Fatal(err)
+	}
+	token, _ := server.CreateToken(user)
+
+	whoami := func() int {
+		req := httptest.NewRequest("GET", "/api/v1/auth/whoami", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := whoami(); code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", code)
+	}
+	if _, err := server.db.Exec(`UPDATE api_users SET enabled = 0 WHERE id = ?`, user.ID); err != nil {
+		t.Fatal(err)
+	}
+	if code := whoami(); code != http.StatusUnauthorized {
+		t.Errorf("Expected the token of a disabled user to be rejected, got %d", code)
+	}
+}
+
+func TestInitialAdminPasswordFile(t *testing.T) {
+	var logs bytes.Buffer
+	dbPath := filepath.Join(t.TempDir(), "api.db")
+	server, err := NewServer(&ServerConfig{DatabasePath: dbPath, JWTSecret: "test-secret"}, apprise.New(), nil, log.New(&logs, "", 0))
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	defer server.Shutdown(context.Background())
+
+	path := dbPath + ".admin-password"
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Expected the generated password to be written: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Expected mode 0600, got %v", info.Mode().Perm())
+	}
+
+	password, _ := os.ReadFile(path)
+	if strings.Contains(logs.String(), strings.TrimSpace(string(password))) {
+		t.Error("Expected the password not to be logged")
+	}
+	if _, err := server.authenticateUser("admin", strings.TrimSpace(string(password))); err != nil {
+		t.Errorf("Expected the written password to log in: %v", err)
+	}
+}
+
 func TestUserContext(t *testing.T) {
 	user := &User{
 		ID:       "test-user",
@@ -0,0 +1,232 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+var (
+	// ErrConfigNotFound is returned when no configuration is stored for a key
+	ErrConfigNotFound = errors.New("configuration not found")
+	// ErrInvalidConfigKey is returned for keys that are empty, too long or
+	// contain characters other than letters, digits, '-' and '_'
+	ErrInvalidConfigKey = errors.New("invalid configuration key")
+	// ErrServiceNotFound is returned when no service is stored under an ID
+	ErrServiceNotFound = errors.New("service not found")
+)
+
+// configKeyPattern matches the keys accepted by the Python apprise-api
+var configKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,128}$`)
+
+// StoredConfig is a named Apprise configuration held by the server
+type StoredConfig struct {
+	Key     string    `json:"key"`
+	Format  string    `json:"format"`
+	Config  string    `json:"config"`
+	Created time.Time `json:"created"`
+	Updated time.Time `json:"updated"`
+}
+
+// StoredService is a service URL added through the services API, which the
+// server notifies when a request names no URLs
+type StoredService struct {
+	ID      string    `json:"id"`
+	URL     string    `json:"url"`
+	Tags    []string  `json:"tags"`
+	Created time.Time `json:"created"`
+	Updated time.Time `json:"updated"`
+}
+
+// ConfigStore persists keyed Apprise configurations and the services added
+// through the API in SQLite
+type ConfigStore struct {
+	db *sql.DB
+}
+
+// NewConfigStore creates a configuration store on db, creating its table if
+// needed
+func NewConfigStore(db *sql.DB) (*ConfigStore, error) {
+	query := `
+	CREATE TABLE IF NOT EXISTS api_configs (
+		config_key TEXT PRIMARY KEY,
+		format TEXT NOT NULL,
+		config TEXT NOT NULL,
+		created_at DATETIME NOT NULL,
+		updated_at DATETIME NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS api_services (
+		id TEXT PRIMARY KEY,
+		url TEXT NOT NULL,
+		tags TEXT NOT NULL,
+		created_at DATETIME NOT NULL,
+		updated_at DATETIME NOT NULL
+	);`
+
+	if _, err := db.Exec(query); err != nil {
+		return nil, fmt.Errorf("failed to initialize config schema: %w", err)
+	}
+	return &ConfigStore{db: db}, nil
+}
+
+// ValidateConfigKey checks that key can be used to store a configuration
+func ValidateConfigKey(key string) error {
+	if !configKeyPattern.MatchString(key) {
+		return fmt.Errorf("%w: '%s'", ErrInvalidConfigKey, key)
+	}
+	return nil
+}
+
+// Save stores the configuration for key, replacing any existing one
+func (cs *ConfigStore) Save(key, format, config string) error {
+	if err := ValidateConfigKey(key); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	query := `INSERT INTO api_configs (config_key, format, config, created_at, updated_at)
+			  VALUES (?, ?, ?, ?, ?)
+			  ON CONFLICT(config_key) DO UPDATE SET
+				format = excluded.format,
+				config = excluded.config,
+				updated_at = excluded.updated_at`
+
+	if _, err := cs.db.Exec(query, key, format, config, now, now); err != nil {
+		return fmt.Errorf("failed to save configuration: %w", err)
+	}
+	return nil
+}
+
+// Get returns the configuration stored for key
+func (cs *ConfigStore) Get(key string) (*StoredConfig, error) {
+	query := `SELECT config_key, format, config, created_at, updated_at
+			  FROM api_configs WHERE config_key = ?`
+
+	var stored StoredConfig
+	err := cs.db.QueryRow(query, key).Scan(&stored.Key, &stored.Format, &stored.Config,
+		&stored.Created, &stored.Updated)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("key '%s': %w", key, ErrConfigNotFound)
+		}
+		return nil, fmt.Errorf("failed to scan configuration: %w", err)
+	}
+
+	return &stored, nil
+}
+
+// Delete removes the configuration stored for key
+func (cs *ConfigStore) Delete(key string) error {
+	result, err := cs.db.Exec(`DELETE FROM api_configs WHERE config_key = ?`, key)
+	if err != nil {
+		return fmt.Errorf("failed to delete configuration: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("key '%s': %w", key, ErrConfigNotFound)
+	}
+
+	return nil
+}
+
+// AddService stores a service
+func (cs *ConfigStore) AddService(service *StoredService) error {
+	tagsJSON, _ := json.Marshal(service.Tags)
+
+	query := `INSERT INTO api_services (id, url, tags, created_at, updated_at)
+			  VALUES (?, ?, ?, ?, ?)`
+
+	if _, err := cs.db.Exec(query, service.ID, service.URL, string(tagsJSON), service.Created, service.Updated); err != nil {
+		return fmt.Errorf("failed to save service: %w", err)
+	}
+	return nil
+}
+
+// GetService returns the service stored under id
+func (cs *ConfigStore) GetService(id string) (*StoredService, error) {
+	query := `SELECT id, url, tags, created_at, updated_at FROM api_services WHERE id = ?`
+
+	service, err := scanService(cs.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("service '%s': %w", id, ErrServiceNotFound)
+	}
+	return service, err
+}
+
+// ListServices returns the stored services in the order they were added
+func (cs *ConfigStore) ListServices() ([]*StoredService, error) {
+	rows, err := cs.db.Query(`SELECT id, url, tags, created_at, updated_at FROM api_services ORDER BY created_at, id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query services: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	services := []*StoredService{}
+	for rows.Next() {
+		service, err := scanService(rows)
+		if err != nil {
+			return nil, err
+		}
+		services = append(services, service)
+	}
+
+	return services, rows.Err()
+}
+
+// UpdateService replaces the URL and tags of the service stored under id
+func (cs *ConfigStore) UpdateService(id, serviceURL string, tags []string) error {
+	tagsJSON, _ := json.Marshal(tags)
+
+	result, err := cs.db.Exec(`UPDATE api_services SET url = ?, tags = ?, updated_at = ? WHERE id = ?`,
+		serviceURL, string(tagsJSON), time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to update service: %w", err)
+	}
+	return requireAffectedService(result, id)
+}
+
+// DeleteService removes the service stored under id
+func (cs *ConfigStore) DeleteService(id string) error {
+	result, err := cs.db.Exec(`DELETE FROM api_services WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete service: %w", err)
+	}
+	return requireAffectedService(result, id)
+}
+
+// requireAffectedService returns ErrServiceNotFound when result changed no
+// rows
+func requireAffectedService(result sql.Result, id string) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("service '%s': %w", id, ErrServiceNotFound)
+	}
+	return nil
+}
+
+// scanService scans a stored service
+func scanService(row rowScanner) (*StoredService, error) {
+	var service StoredService
+	var tagsJSON string
+	if err := row.Scan(&service.ID, &service.URL, &tagsJSON, &service.Created, &service.Updated); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan service: %w", err)
+	}
+	if err := json.Unmarshal([]byte(tagsJSON), &service.Tags); err != nil {
+		return nil, fmt.Errorf("failed to parse tags: %w", err)
+	}
+	return &service, nil
+}
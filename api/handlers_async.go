@@ -0,0 +1,207 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/scttfrdmn/apprise-go/apprise"
+)
+
+// NotifyJobStatus reports the progress of a notification sent asynchronously
+type NotifyJobStatus struct {
+	JobID        int64           `json:"job_id"`
+	Status       string          `json:"status"`
+	ErrorMessage string          `json:"error_message,omitempty"`
+	RetryCount   int             `json:"retry_count"`
+	CreatedAt    time.Time       `json:"created_at"`
+	StartedAt    *time.Time      `json:"started_at,omitempty"`
+	CompletedAt  *time.Time      `json:"completed_at,omitempty"`
+	NextRetryAt  *time.Time      `json:"next_retry_at,omitempty"`
+	Total        int             `json:"total"`
+	Successful   int             `json:"successful"`
+	Failed       int             `json:"failed"`
+	Results      []ServiceResult `json:"results"`
+}
+
+// jobOwnerMetadata is the queued job metadata key holding the ID of the user
+// who queued the notification
+const jobOwnerMetadata = "api_owner"
+
+// isAsyncRequest reports whether a notify request asked to be sent in the
+// background, with "async": true in the body or ?async=true in the URL
+func isAsyncRequest(r *http.Request, req NotificationRequest) bool {
+	if req.Async {
+		return true
+	}
+	async, _ := strconv.ParseBool(r.URL.Query().Get("async"))
+	return async
+}
+
+// queueNotification adds a notify request to the scheduler's queue and
+// answers 202 Accepted with the job ID and the URL to poll for its status
+func (s *Server) queueNotification(w http.ResponseWriter, r *http.Request, req NotificationRequest, notifyType apprise.NotifyType) {
+	if s.scheduler == nil {
+		s.sendError(w, http.StatusServiceUnavailable, "Asynchronous notifications require the scheduler", nil)
+		return
+	}
+
+	job, err := s.enqueueNotification(r, req, notifyType)
+	if err != nil {
+		s.sendError(w, http.StatusInternalServerError, "Failed to queue notification", err)
+		return
+	}
+
+	statusURL := jobStatusURL(job)
+	w.Header().Set("Location", statusURL)
+	s.sendJSON(w, http.StatusAccepted, APIResponse{
+		Success: true,
+		Message: "Notification queued",
+		Data: map[string]interface{}{
+			"job_id":     job.ID,
+			"status":     job.Status,
+			"status_url": statusURL,
+		},
+		Timestamp: s.getCurrentTime(),
+	})
+}
+
+// enqueueNotification adds a notify request to the scheduler's queue on
+// behalf of the user making r
+func (s *Server) enqueueNotification(r *http.Request, req NotificationRequest, notifyType apprise.NotifyType) (*apprise.QueuedJob, error) {
+	metadata := jobMetadata(r, req.Metadata, "")
+	if req.Format != "" {
+		metadata[apprise.QueueMetadataBodyFormat] = req.Format
+	}
+
+	return s.scheduler.QueueNotification(apprise.QueuedJob{
+		Title:      req.Title,
+		Body:       req.Body,
+		NotifyType: notifyType,
+		Services:   req.URLs,
+		Tags:       req.Tags,
+		Metadata:   metadata,
+	})
+}
+
+// jobStatusURL returns the URL reporting the status of a queued notification
+func jobStatusURL(job *apprise.QueuedJob) string {
+	return fmt.Sprintf("/api/v1/notify/jobs/%d", job.ID)
+}
+
+// handleNotifyStatus reports the status of an asynchronous notification and
+// the result of its latest attempt for each service it has been sent to
+func (s *Server) handleNotifyStatus(w http.ResponseWriter, r *http.Request) {
+	if s.scheduler == nil {
+		s.sendError(w, http.StatusServiceUnavailable, "Scheduler not available", nil)
+		return
+	}
+
+	jobID, err := strconv.ParseInt(mux.Vars(r)["job_id"], 10, 64)
+	if err != nil {
+		s.sendError(w, http.StatusBadRequest, "Invalid job ID", err)
+		return
+	}
+
+	job, err := s.scheduler.GetQueuedJob(jobID)
+	if err != nil {
+		s.sendQueueError(w, "Failed to retrieve notification job", err)
+		return
+	}
+	if !s.canReadJob(r, job) {
+		s.sendQueueError(w, "Failed to retrieve notification job", apprise.ErrQueuedJobNotFound)
+		return
+	}
+
+	metrics, err := s.scheduler.GetJobMetrics(jobID)
+	if err != nil {
+		s.sendError(w, http.StatusInternalServerError, "Failed to retrieve notification results", err)
+		return
+	}
+	metrics = latestJobRun(metrics)
+
+	status := NotifyJobStatus{
+		JobID:        job.ID,
+		Status:       job.Status,
+		ErrorMessage: job.ErrorMessage,
+		RetryCount:   job.RetryCount,
+		CreatedAt:    job.CreatedAt,
+		StartedAt:    job.StartedAt,
+		CompletedAt:  job.CompletedAt,
+		NextRetryAt:  job.NextRetryAt,
+		Total:        len(metrics),
+		Results:      make([]ServiceResult, len(metrics)),
+	}
+	for i, m := range metrics {
+		attempts, _ := strconv.Atoi(m.Metadata["attempts"])
+		status.Results[i] = ServiceResult{
+			ServiceID:  m.ServiceID,
+			Success:    m.Status == "success",
+			DurationMs: float64(m.DurationMs),
+			Attempts:   attempts,
+			Error:      m.ErrorMessage,
+			ErrorClass: m.Metadata["error_class"],
+		}
+		if status.Results[i].Success {
+			status.Successful++
+		}
+	}
+	status.Failed = status.Total - status.Successful
+
+	s.sendSuccess(w, "Notification status retrieved", status)
+}
+
+// jobMetadata copies the metadata a client gave for a queued job, keeping
+// the owner out of the client's hands: new jobs belong to the caller, and
+// existing ones keep the given owner
+func jobMetadata(r *http.Request, metadata map[string]string, owner string) map[string]string {
+	result := make(map[string]string, len(metadata)+1)
+	for key, value := range metadata {
+		result[key] = value
+	}
+	delete(result, jobOwnerMetadata)
+
+	if owner == "" {
+		if user, ok := GetUserFromContext(r.Context()); ok {
+			owner = user.ID
+		}
+	}
+	if owner != "" {
+		result[jobOwnerMetadata] = owner
+	}
+	return result
+}
+
+// canReadJob reports whether the request may see a queued job: with
+// authentication required, only admins and the user who queued it can
+func (s *Server) canReadJob(r *http.Request, job *apprise.QueuedJob) bool {
+	if !s.config.RequireAuth {
+		return true
+	}
+	user, ok := GetUserFromContext(r.Context())
+	if !ok {
+		return false
+	}
+	return hasRole(user, "admin") || job.Metadata[jobOwnerMetadata] == user.ID
+}
+
+// latestJobRun keeps the metrics of the last run of a job, which a retry or
+// requeue records again for every service
+func latestJobRun(metrics []apprise.NotificationMetrics) []apprise.NotificationMetrics {
+	latest := 0
+	for _, m := range metrics {
+		if run, _ := strconv.Atoi(m.Metadata["job_run"]); run > latest {
+			latest = run
+		}
+	}
+
+	var results []apprise.NotificationMetrics
+	for _, m := range metrics {
+		if run, _ := strconv.Atoi(m.Metadata["job_run"]); run == latest {
+			results = append(results, m)
+		}
+	}
+	return results
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -35,6 +36,7 @@ type RegisterRequest struct {
 type APIKeyCreateRequest struct {
 	Name        string    `json:"name"`
 	Description string    `json:"description"`
+	Scopes      []string   `json:"scopes,omitempty"` // Roles the key may use; defaults to the caller's roles
 	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
 }
 
@@ -44,6 +46,8 @@ type APIKeyResponse struct {
 	Name        string     `json:"name"`
 	Description string     `json:"description"`
 	Key         string     `json:"key,omitempty"` // Only returned on creation
+	Prefix      string     `json:"prefix"`
+	Scopes      []string   `json:"scopes"`
 	ExpiresAt   *time.Time `json:"expires_at"`
 	Created     time.Time  `json:"created"`
 	LastUsed    *time.Time `json:"last_used"`
@@ -62,10 +66,14 @@ func (s *Server) handleLogin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Authenticate user (mock implementation)
+	// Authenticate user
 	user, err := s.authenticateUser(req.Username, req.Password)
 	if err != nil {
-		s.sendError(w, http.StatusUnauthorized, "Invalid credentials", err)
+		if errors.Is(err, ErrInvalidCredentials) || errors.Is(err, ErrUserDisabled) {
+			s.sendError(w, http.StatusUnauthorized, "Invalid credentials", err)
+		} else {
+			s.sendError(w, http.StatusInternalServerError, "Failed to authenticate", err)
+		}
 		return
 	}
 
@@ -86,6 +94,9 @@ func (s *Server) handleLogin(w http.ResponseWriter, r *http.Request) {
 	// Update user's last seen time
 	now := time.Now()
 	user.LastSeen = &now
+	if err := s.users.TouchUser(user.ID, now); err != nil {
+		s.logger.Printf("Failed to update last seen for %s: %v", user.Username, err)
+	}
 
 	response := LoginResponse{
 		Token:     token,
@@ -109,25 +120,46 @@ func (s *Server) handleRegister(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Check if user already exists (mock implementation)
+	// Only administrators may register users unless self-registration is
+	// allowed. Self-registered users get the user role; only administrators
+	// may assign other roles.
+	caller, err := s.userFromToken(s.extractToken(r))
+	isAdmin := err == nil && hasRole(caller, "admin")
+	if !s.config.AllowRegistration && !isAdmin {
+		s.sendError(w, http.StatusForbidden, "Only administrators can register users", nil)
+		return
+	}
+
+	roles := []string{"user"}
+	if len(req.Roles) > 0 {
+		if !isAdmin {
+			s.sendError(w, http.StatusForbidden, "Only administrators can assign roles", nil)
+			return
+		}
+		roles = req.Roles
+	}
+
+	// Check if user already exists
 	if s.userExists(req.Username) {
 		s.sendError(w, http.StatusConflict, "Username already exists", nil)
 		return
 	}
 
-	// Create user (mock implementation)
 	user := &User{
 		ID:       generateUserID(),
 		Username: req.Username,
 		Email:    req.Email,
-		Roles:    req.Roles,
+		Roles:    roles,
 		Enabled:  true,
 		Created:  time.Now(),
 	}
 
-	// In a real implementation, hash the password and store user in database
 	if err := s.createUser(user, req.Password); err != nil {
-		s.sendError(w, http.StatusInternalServerError, "Failed to create user", err)
+		if errors.Is(err, ErrUserExists) {
+			s.sendError(w, http.StatusConflict, "Username already exists", nil)
+		} else {
+			s.sendError(w, http.StatusInternalServerError, "Failed to create user", err)
+		}
 		return
 	}
 
@@ -197,6 +229,20 @@ func (s *Server) handleCreateAPIKey(w http.ResponseWriter, r *http.Request) {
 		s.sendError(w, http.StatusBadRequest, "API key name is required", nil)
 		return
 	}
+	if req.ExpiresAt != nil && req.ExpiresAt.Before(time.Now()) {
+		s.sendError(w, http.StatusBadRequest, "API key expiry must be in the future", nil)
+		return
+	}
+	for _, scope := range req.Scopes {
+		if !hasRole(user, scope) {
+			s.sendError(w, http.StatusForbidden, fmt.Sprintf("Scope %q is not granted to this user", scope), nil)
+			return
+		}
+	}
+	// Default to the caller's roles so a scoped key cannot mint a broader one
+	if len(req.Scopes) == 0 {
+		req.Scopes = user.Roles
+	}
 
 	// Generate API key
 	apiKey := GenerateAPIKey()
@@ -205,17 +251,18 @@ func (s *Server) handleCreateAPIKey(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Create API key record (mock implementation)
 	keyRecord := &APIKeyResponse{
 		ID:          generateKeyID(),
 		Name:        req.Name,
 		Description: req.Description,
 		Key:         apiKey,
+		Prefix:      apiKey[:10],
+		Scopes:      req.Scopes,
 		ExpiresAt:   req.ExpiresAt,
 		Created:     time.Now(),
 	}
 
-	// Store API key (mock implementation)
+	// Store API key; only its hash is persisted
 	if err := s.storeAPIKey(user.ID, keyRecord); err != nil {
 		s.sendError(w, http.StatusInternalServerError, "Failed to store API key", err)
 		return
@@ -232,7 +279,7 @@ func (s *Server) handleListAPIKeys(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Get API keys for user (mock implementation)
+	// Get API keys for user
 	keys, err := s.getUserAPIKeys(user.ID)
 	if err != nil {
 		s.sendError(w, http.StatusInternalServerError, "Failed to retrieve API keys", err)
@@ -262,9 +309,12 @@ func (s *Server) handleDeleteAPIKey(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Delete API key (mock implementation)
 	if err := s.deleteAPIKey(user.ID, keyID); err != nil {
-		s.sendError(w, http.StatusNotFound, "API key not found", err)
+		if errors.Is(err, ErrAPIKeyNotFound) {
+			s.sendError(w, http.StatusNotFound, "API key not found", err)
+		} else {
+			s.sendError(w, http.StatusInternalServerError, "Failed to delete API key", err)
+		}
 		return
 	}
 
@@ -284,46 +334,61 @@ func (s *Server) handleGetRateLimitStatus(w http.ResponseWriter, r *http.Request
 	s.sendSuccess(w, "Rate limit status", status)
 }
 
-// Mock authentication functions (replace with real database operations)
+// User store helpers
 
 func (s *Server) authenticateUser(username, password string) (*User, error) {
-	// Mock implementation - in production, verify against database with hashed passwords
-	if username == "admin" && password == "admin" {
-		return &User{
-			ID:       "admin",
-			Username: "admin",
-			Email:    "admin@localhost",
-			Roles:    []string{"admin", "user"},
-			Enabled:  true,
-			Created:  time.Now(),
-		}, nil
-	}
-	return nil, fmt.Errorf("invalid credentials")
+	return s.users.Authenticate(username, password)
 }
 
 func (s *Server) userExists(username string) bool {
-	// Mock implementation
-	return username == "admin"
+	exists, err := s.users.UserExists(username)
+	if err != nil {
+		s.logger.Printf("Failed to check for user %s: %v", username, err)
+	}
+	return exists
 }
 
 func (s *Server) createUser(user *User, password string) error {
-	// Mock implementation - in production, hash password and store in database
-	return nil
+	return s.users.CreateUser(user, password)
 }
 
 func (s *Server) storeAPIKey(userID string, key *APIKeyResponse) error {
-	// Mock implementation
-	return nil
+	return s.users.CreateAPIKey(&APIKeyRecord{
+		ID:          key.ID,
+		UserID:      userID,
+		Name:        key.Name,
+		Description: key.Description,
+		Prefix:      key.Prefix,
+		Scopes:      key.Scopes,
+		ExpiresAt:   key.ExpiresAt,
+		Created:     key.Created,
+	}, key.Key)
 }
 
 func (s *Server) getUserAPIKeys(userID string) ([]*APIKeyResponse, error) {
-	// Mock implementation
-	return []*APIKeyResponse{}, nil
+	records, err := s.users.ListAPIKeys(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]*APIKeyResponse, 0, len(records))
+	for _, record := range records {
+		keys = append(keys, &APIKeyResponse{
+			ID:          record.ID,
+			Name:        record.Name,
+			Description: record.Description,
+			Prefix:      record.Prefix,
+			Scopes:      record.Scopes,
+			ExpiresAt:   record.ExpiresAt,
+			Created:     record.Created,
+			LastUsed:    record.LastUsed,
+		})
+	}
+	return keys, nil
 }
 
 func (s *Server) deleteAPIKey(userID, keyID string) error {
-	// Mock implementation
-	return nil
+	return s.users.DeleteAPIKey(userID, keyID)
 }
 
 func generateUserID() string {
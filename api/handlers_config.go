@@ -2,7 +2,13 @@ package api
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/scttfrdmn/apprise-go/apprise"
 )
 
 // ConfigResponse represents the server configuration response
@@ -32,7 +38,7 @@ type ConfigUpdateRequest struct {
 // ConfigLoadRequest represents a request to load configuration from file
 type ConfigLoadRequest struct {
 	Path   string `json:"path"`
-	Format string `json:"format,omitempty"` // yaml, json, toml
+	Format string `json:"format,omitempty"` // text or yaml, detected when empty
 }
 
 // handleGetConfig returns the current server configuration
@@ -89,16 +95,91 @@ func (s *Server) handleLoadConfig(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// TODO: Implement configuration file loading
-	// This would involve reading the configuration file and applying the settings
+	// Loading reads files on the server, so only administrators may do it,
+	// and only from the configuration directory
+	if !s.requireAdmin(w, r) {
+		return
+	}
+
+	path, err := s.resolveConfigPath(req.Path)
+	if err != nil {
+		s.sendError(w, http.StatusForbidden, "Configuration path not allowed", err)
+		return
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		s.logger.Printf("Failed to read configuration file %s: %v", path, err)
+		s.sendError(w, http.StatusBadRequest, "Failed to read configuration file", nil)
+		return
+	}
+
+	// Validate the whole file before adding any of its services
+	format := req.Format
+	if format == "" {
+		format = apprise.DetectConfigFormat(string(content))
+	}
+	// Parse errors may quote the file, so their details are only logged
+	loaded, err := loadAppriseConfig(format, string(content), nil)
+	if err != nil {
+		s.logger.Printf("Invalid configuration file %s: %v", path, err)
+		s.sendError(w, http.StatusBadRequest, "Invalid configuration file", nil)
+		return
+	}
+
+	// Loading a file again would add each of its services a second time
+	s.loadedConfigsMu.Lock()
+	defer s.loadedConfigsMu.Unlock()
+	if s.loadedConfigs[path] {
+		s.sendError(w, http.StatusConflict, "Configuration file is already loaded", nil)
+		return
+	}
+
+	if _, err := loadAppriseConfig(format, string(content), s.apprise); err != nil {
+		s.logger.Printf("Failed to add services from %s: %v", path, err)
+		s.sendError(w, http.StatusBadRequest, "Failed to add services", nil)
+		return
+	}
+	s.loadedConfigs[path] = true
 
 	s.sendSuccess(w, "Configuration loaded successfully", map[string]interface{}{
-		"path":   req.Path,
-		"format": req.Format,
-		"message": "Configuration loaded from file",
+		"path":     req.Path,
+		"format":   format,
+		"services": loaded,
+		"total":    s.apprise.Count(),
 	})
 }
 
+// resolveConfigPath returns the file path refers to, relative paths being
+// taken from the configuration directory. Paths outside that directory,
+// including through symbolic links, are refused.
+func (s *Server) resolveConfigPath(path string) (string, error) {
+	if s.config.ConfigDir == "" {
+		return "", fmt.Errorf("no configuration directory is set")
+	}
+
+	dir, err := filepath.Abs(s.config.ConfigDir)
+	if err != nil {
+		return "", err
+	}
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = resolved
+	}
+
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	path = filepath.Clean(path)
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+
+	if path != dir && !strings.HasPrefix(path, dir+string(filepath.Separator)) {
+		return "", fmt.Errorf("path is outside the configuration directory")
+	}
+	return path, nil
+}
+
 // Helper methods to get configuration information
 
 func (s *Server) getServerVersion() string {
@@ -133,7 +214,7 @@ func (s *Server) getFeatures() map[string]bool {
 		"queue_management":     s.scheduler != nil,
 		"web_dashboard":        false, // TODO: Implement web dashboard
 		"authentication":       false, // TODO: Implement authentication
-		"rate_limiting":        false, // TODO: Implement rate limiting
+		"rate_limiting":        s.rateLimiter != nil,
 		"webhooks":             false, // TODO: Implement webhook callbacks
 	}
 
@@ -146,8 +227,9 @@ func (s *Server) getLimits() map[string]interface{} {
 		"max_title_length":         250,    // Default max title length
 		"max_services_per_request": 50,     // Max services in single request
 		"max_bulk_notifications":   100,    // Max notifications in bulk request
-		"max_attachments":          10,     // Max attachments per notification
-		"max_attachment_size":      "10MB", // Max size per attachment
+		"max_attachments":          s.maxAttachments(),    // Max attachments per notification
+		"max_attachment_size":      s.maxAttachmentSize(), // Max bytes per attachment
+		"max_request_size":         s.maxRequestSize(),    // Max bytes per notify request
 		"request_timeout":          "30s",  // Request timeout
 		"queue_size":               10000,  // Max queue size (if scheduler enabled)
 		"max_retries":              5,      // Max retry attempts
@@ -1,8 +1,9 @@
 package api
 
 import (
-	"encoding/json"
+	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -18,6 +19,12 @@ type NotificationRequest struct {
 	Format   string            `json:"format,omitempty"`
 	Tags     []string          `json:"tags,omitempty"`
 	Metadata map[string]string `json:"metadata,omitempty"`
+
+	Attachments []AttachmentRequest `json:"attachments,omitempty"`
+
+	// Async queues the notification and returns its job ID without waiting
+	// for the services to respond
+	Async bool `json:"async,omitempty"`
 }
 
 // BulkNotificationRequest represents multiple notification requests
@@ -56,13 +63,29 @@ func (s *Server) handleRoot(w http.ResponseWriter, r *http.Request) {
 
 // handleHealth provides health check endpoint
 func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	circuits := apprise.CircuitBreakerStates()
+	openCircuits := 0
+	for _, circuit := range circuits {
+		if circuit.State != apprise.CircuitClosed {
+			openCircuits++
+		}
+	}
+
+	// Open circuits mean some providers are down, not the server itself
+	status, message := "healthy", "API server is healthy"
+	if openCircuits > 0 {
+		status, message = "degraded", "API server is healthy, some notification services are failing"
+	}
+
 	health := map[string]interface{}{
-		"status":    "healthy",
-		"version":   apprise.GetVersion(),
-		"scheduler": s.scheduler != nil,
-		"services":  len(apprise.GetSupportedServices()),
+		"status":           status,
+		"version":          apprise.GetVersion(),
+		"scheduler":        s.scheduler != nil,
+		"services":         len(apprise.GetSupportedServices()),
+		"circuit_breakers": circuits,
+		"open_circuits":    openCircuits,
 	}
-	s.sendSuccess(w, "API server is healthy", health)
+	s.sendSuccess(w, message, health)
 }
 
 // handleVersion provides version information
@@ -126,8 +149,14 @@ func (s *Server) handleDocs(w http.ResponseWriter, r *http.Request) {
     
     <div class="endpoint">
         <div class="method">POST /api/v1/notify</div>
-        <p>Send a single notification</p>
-        <p><strong>Body:</strong> <code>{"body": "message", "urls": ["service://..."], "title": "optional"}</code></p>
+        <p>Send a single notification. Without <code>urls</code>, the server's configured services matching <code>tags</code> are notified.</p>
+        <p><strong>Body:</strong> <code>{"body": "message", "urls": ["service://..."], "title": "optional", "tags": ["optional"], "attachments": [{"name": "log.txt", "data": "base64..."}, "https://..."]}</code></p>
+        <p>Files may also be uploaded as <code>multipart/form-data</code>, with the other fields as form fields or as JSON in a <code>payload</code> field.</p>
+    </div>
+    
+    <div class="endpoint">
+        <div class="method">GET /api/v1/notify/jobs/{job_id}</div>
+        <p>Get the status and per-service results of a notification sent with <code>"async": true</code> (or <code>?async=true</code>), which returns <code>202</code> with a job ID instead of waiting for the services. Requires the scheduler.</p>
     </div>
     
     <div class="endpoint">
@@ -145,6 +174,31 @@ func (s *Server) handleDocs(w http.ResponseWriter, r *http.Request) {
         <div class="method">POST /api/v1/services</div>
         <p>Add a new service</p>
         <p><strong>Body:</strong> <code>{"url": "service://...", "tags": ["optional"]}</code></p>
+    </div>
+
+    <h2>Persistent Configuration</h2>
+    <p>Compatible with the Python apprise-api. Bodies may be JSON or form encoded.</p>
+
+    <div class="endpoint">
+        <div class="method">POST /add/{key}</div>
+        <p>Store URLs or a text/YAML configuration under a key</p>
+        <p><strong>Body:</strong> <code>{"urls": "service://..., service://..."}</code> or <code>{"config": "...", "format": "text|yaml"}</code></p>
+    </div>
+
+    <div class="endpoint">
+        <div class="method">POST /get/{key}</div>
+        <p>Retrieve the configuration stored under a key</p>
+    </div>
+
+    <div class="endpoint">
+        <div class="method">POST /del/{key}</div>
+        <p>Remove the configuration stored under a key</p>
+    </div>
+
+    <div class="endpoint">
+        <div class="method">POST /notify/{key}</div>
+        <p>Notify the services stored under a key</p>
+        <p><strong>Body:</strong> <code>{"body": "message", "title": "optional", "type": "info", "tag": "optional"}</code></p>
     </div>`
 
 	if s.scheduler != nil {
@@ -179,8 +233,10 @@ func (s *Server) handleDocs(w http.ResponseWriter, r *http.Request) {
 // handleNotify processes a single notification request
 func (s *Server) handleNotify(w http.ResponseWriter, r *http.Request) {
 	var req NotificationRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		s.sendError(w, http.StatusBadRequest, "Invalid request body", err)
+	files, cleanup, err := s.readNotifyRequest(w, r, &req)
+	defer cleanup()
+	if err != nil {
+		s.sendError(w, requestErrorStatus(err), "Invalid request body", err)
 		return
 	}
 
@@ -189,45 +245,124 @@ func (s *Server) handleNotify(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Create temporary Apprise instance for this request
-	tempApprise := apprise.New()
-
-	// Add services from URLs
-	for _, url := range req.URLs {
-		if err := tempApprise.Add(url); err != nil {
-			s.sendError(w, http.StatusBadRequest, "Invalid service URL: "+url, err)
-			return
-		}
+	targets, err := s.notifyTargets(req.URLs)
+	if err != nil {
+		s.sendError(w, http.StatusBadRequest, "Invalid service URL", err)
+		return
+	}
+	if targets.CountByTag(req.Tags...) == 0 {
+		s.sendError(w, http.StatusBadRequest, "No services to notify", nil)
+		return
 	}
 
 	// Parse notification type
 	notifyType := apprise.NotifyTypeInfo
 	if req.Type != "" {
-		if parsedType, err := parseNotifyType(req.Type); err == nil {
-			notifyType = parsedType
+		parsedType, err := parseNotifyType(req.Type)
+		if err != nil {
+			s.sendError(w, http.StatusBadRequest, "Invalid notification type", err)
+			return
+		}
+		notifyType = parsedType
+	}
+
+	if isAsyncRequest(r, req) {
+		// Attachments are held in memory and cannot be queued
+		if len(req.Attachments)+len(files) > 0 {
+			s.sendError(w, http.StatusBadRequest, "Attachments are not supported for asynchronous notifications", nil)
+			return
 		}
+		s.queueNotification(w, r, req, notifyType)
+		return
+	}
+
+	attachments, err := s.buildAttachments(req.Attachments, files)
+	if err != nil {
+		s.sendError(w, requestErrorStatus(err), "Invalid attachment", err)
+		return
 	}
 
 	// Create notification request
 	notification := apprise.NotificationRequest{
-		Title:      req.Title,
-		Body:       req.Body,
-		NotifyType: notifyType,
-		Tags:       req.Tags,
-		BodyFormat: req.Format,
+		Title:         req.Title,
+		Body:          req.Body,
+		NotifyType:    notifyType,
+		Tags:          req.Tags,
+		BodyFormat:    req.Format,
+		AttachmentMgr: attachments,
 	}
 
 	// Send notifications
-	responses := tempApprise.NotifyAll(notification)
+	responses := targets.NotifyAll(notification)
+	result, successful := summarizeResponses(responses)
+
+	if successful == len(responses) {
+		s.sendSuccess(w, "All notifications sent successfully", result)
+	} else if successful > 0 {
+		s.sendSuccess(w, "Some notifications sent successfully", result)
+	} else {
+		status, retryAfter := failureStatus(responses)
+		if retryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Round(time.Second)/time.Second)))
+		}
+		// Still include the result data
+		response := APIResponse{
+			Success:   false,
+			Message:   "All notifications failed",
+			Data:      result,
+			Timestamp: s.getCurrentTime(),
+		}
+		s.sendJSON(w, status, response)
+		return
+	}
+}
 
-	// Process results
+// notifyTargets returns the services a notification request is sent to.
+// Without URLs these are the server's configured services. Services given
+// by URL are always notified, whatever tags the request carries.
+func (s *Server) notifyTargets(urls []string) (*apprise.Apprise, error) {
+	if len(urls) == 0 {
+		return s.apprise, nil
+	}
+
+	targets := apprise.New()
+	for _, url := range urls {
+		if err := targets.Add(url, apprise.TagAlways); err != nil {
+			return nil, fmt.Errorf("invalid service URL %s: %w", url, err)
+		}
+	}
+	return targets, nil
+}
+
+// ServiceResult reports the outcome of a notification for one service
+type ServiceResult struct {
+	ServiceID  string  `json:"service_id"`
+	Success    bool    `json:"success"`
+	DurationMs float64 `json:"duration_ms"`
+	Attempts   int     `json:"attempts"`
+	Error      string  `json:"error,omitempty"`
+	ErrorClass string  `json:"error_class,omitempty"`
+	Failover   string  `json:"failover,omitempty"`
+}
+
+// summarizeResponses builds the result returned for a set of notification
+// responses and counts the successful ones
+func summarizeResponses(responses []apprise.NotificationResponse) (map[string]interface{}, int) {
 	successful := 0
-	var errors []string
-	for _, resp := range responses {
+	results := make([]ServiceResult, len(responses))
+	for i, resp := range responses {
+		results[i] = ServiceResult{
+			ServiceID:  resp.ServiceID,
+			Success:    resp.Success,
+			DurationMs: float64(resp.Duration.Microseconds()) / 1000,
+			Attempts:   resp.Attempts,
+			Failover:   resp.Failover,
+		}
 		if resp.Success {
 			successful++
 		} else if resp.Error != nil {
-			errors = append(errors, resp.Error.Error())
+			results[i].Error = resp.Error.Error()
+			results[i].ErrorClass = string(apprise.ClassifyError(resp.Error))
 		}
 	}
 
@@ -235,35 +370,53 @@ func (s *Server) handleNotify(w http.ResponseWriter, r *http.Request) {
 		"total":      len(responses),
 		"successful": successful,
 		"failed":     len(responses) - successful,
+		"results":    results,
 	}
 
-	if len(errors) > 0 {
-		result["errors"] = errors
-	}
+	return result, successful
+}
 
-	if successful == len(responses) {
-		s.sendSuccess(w, "All notifications sent successfully", result)
-	} else if successful > 0 {
-		s.sendSuccess(w, "Some notifications sent successfully", result)
-	} else {
-		s.sendError(w, http.StatusInternalServerError, "All notifications failed", nil)
-		// Still include the result data
-		response := APIResponse{
-			Success:   false,
-			Message:   "All notifications failed",
-			Data:      result,
-			Timestamp: s.getCurrentTime(),
+// failureStatus picks the HTTP status for a request whose notifications all
+// failed. When every failure has the same class the status reflects it;
+// mixed failures are reported as 500. For rate limiting, the longest
+// Retry-After requested by a service is returned as well.
+func failureStatus(responses []apprise.NotificationResponse) (int, time.Duration) {
+	var class apprise.ErrorClass
+	var retryAfter time.Duration
+	for i, resp := range responses {
+		respClass := apprise.ClassifyError(resp.Error)
+		if i > 0 && respClass != class {
+			return http.StatusInternalServerError, 0
+		}
+		class = respClass
+		if delay := apprise.GetRetryAfter(resp.Error); delay > retryAfter {
+			retryAfter = delay
 		}
-		s.sendJSON(w, http.StatusInternalServerError, response)
-		return
+	}
+
+	switch class {
+	case apprise.ErrorClassRateLimited:
+		return http.StatusTooManyRequests, retryAfter
+	case apprise.ErrorClassAuth, apprise.ErrorClassInvalidRecipient, apprise.ErrorClassPayloadTooLarge:
+		// The caller supplied the credentials, recipients and payload
+		return http.StatusUnprocessableEntity, 0
+	case apprise.ErrorClassRemote5xx, apprise.ErrorClassTransientNetwork:
+		return http.StatusBadGateway, 0
+	case apprise.ErrorClassTimeout:
+		return http.StatusGatewayTimeout, 0
+	default:
+		return http.StatusInternalServerError, 0
 	}
 }
 
-// handleBulkNotify processes multiple notification requests
+// handleBulkNotify processes multiple notification requests.
+// Files uploaded with a multipart request are attached to every notification.
 func (s *Server) handleBulkNotify(w http.ResponseWriter, r *http.Request) {
 	var req BulkNotificationRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		s.sendError(w, http.StatusBadRequest, "Invalid request body", err)
+	files, cleanup, err := s.readNotifyRequest(w, r, &req)
+	defer cleanup()
+	if err != nil {
+		s.sendError(w, requestErrorStatus(err), "Invalid request body", err)
 		return
 	}
 
@@ -275,60 +428,63 @@ func (s *Server) handleBulkNotify(w http.ResponseWriter, r *http.Request) {
 	results := make([]map[string]interface{}, len(req.Notifications))
 
 	for i, notification := range req.Notifications {
-		// Create temporary Apprise instance for this notification
-		tempApprise := apprise.New()
-
-		// Add services from URLs
-		for _, url := range notification.URLs {
-			if err := tempApprise.Add(url); err != nil {
-				results[i] = map[string]interface{}{
-					"success": false,
-					"error":   "Invalid service URL: " + url,
-				}
-				continue
+		targets, err := s.notifyTargets(notification.URLs)
+		if err != nil {
+			results[i] = map[string]interface{}{
+				"success": false,
+				"error":   err.Error(),
 			}
+			continue
+		}
+		if targets.CountByTag(notification.Tags...) == 0 {
+			results[i] = map[string]interface{}{
+				"success": false,
+				"error":   "No services to notify",
+			}
+			continue
 		}
 
 		// Parse notification type
 		notifyType := apprise.NotifyTypeInfo
 		if notification.Type != "" {
-			if parsedType, err := parseNotifyType(notification.Type); err == nil {
-				notifyType = parsedType
+			parsedType, err := parseNotifyType(notification.Type)
+			if err != nil {
+				results[i] = map[string]interface{}{
+					"success": false,
+					"error":   err.Error(),
+				}
+				continue
 			}
+			notifyType = parsedType
 		}
 
-		// Create notification request
-		notificationReq := apprise.NotificationRequest{
-			Title:      notification.Title,
-			Body:       notification.Body,
-			NotifyType: notifyType,
-			Tags:       notification.Tags,
-			BodyFormat: notification.Format,
+		if isAsyncRequest(r, notification) {
+			results[i] = s.queueBulkNotification(r, notification, notifyType)
+			continue
 		}
 
-		// Send notifications
-		responses := tempApprise.NotifyAll(notificationReq)
-
-		// Process results
-		successful := 0
-		var errors []string
-		for _, resp := range responses {
-			if resp.Success {
-				successful++
-			} else if resp.Error != nil {
-				errors = append(errors, resp.Error.Error())
+		attachments, err := s.buildAttachments(notification.Attachments, files)
+		if err != nil {
+			results[i] = map[string]interface{}{
+				"success": false,
+				"error":   err.Error(),
 			}
+			continue
 		}
 
-		results[i] = map[string]interface{}{
-			"total":      len(responses),
-			"successful": successful,
-			"failed":     len(responses) - successful,
+		// Create notification request
+		notificationReq := apprise.NotificationRequest{
+			Title:         notification.Title,
+			Body:          notification.Body,
+			NotifyType:    notifyType,
+			Tags:          notification.Tags,
+			BodyFormat:    notification.Format,
+			AttachmentMgr: attachments,
 		}
 
-		if len(errors) > 0 {
-			results[i]["errors"] = errors
-		}
+		// Send notifications
+		responses := targets.NotifyAll(notificationReq)
+		results[i], _ = summarizeResponses(responses)
 	}
 
 	s.sendSuccess(w, "Bulk notifications processed", map[string]interface{}{
@@ -337,6 +493,38 @@ func (s *Server) handleBulkNotify(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// queueBulkNotification queues one notification of a bulk request and
+// returns its result, which holds the job ID when it was queued
+func (s *Server) queueBulkNotification(r *http.Request, notification NotificationRequest, notifyType apprise.NotifyType) map[string]interface{} {
+	var err error
+	switch {
+	case s.scheduler == nil:
+		err = fmt.Errorf("asynchronous notifications require the scheduler")
+	case len(notification.Attachments) > 0:
+		// Attachments are held in memory and cannot be queued
+		err = fmt.Errorf("attachments are not supported for asynchronous notifications")
+	}
+
+	var job *apprise.QueuedJob
+	if err == nil {
+		job, err = s.enqueueNotification(r, notification, notifyType)
+	}
+	if err != nil {
+		return map[string]interface{}{
+			"success": false,
+			"error":   err.Error(),
+		}
+	}
+
+	return map[string]interface{}{
+		"success":    true,
+		"queued":     true,
+		"job_id":     job.ID,
+		"status":     job.Status,
+		"status_url": jobStatusURL(job),
+	}
+}
+
 // Helper function to parse notification type
 func parseNotifyType(typeStr string) (apprise.NotifyType, error) {
 	switch strings.ToLower(typeStr) {
@@ -349,7 +537,7 @@ func parseNotifyType(typeStr string) (apprise.NotifyType, error) {
 	case "error", "failure":
 		return apprise.NotifyTypeError, nil
 	default:
-		return apprise.NotifyTypeInfo, nil
+		return apprise.NotifyTypeInfo, fmt.Errorf("unknown notification type %q", typeStr)
 	}
 }
 
@@ -0,0 +1,281 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/scttfrdmn/apprise-go/apprise"
+)
+
+// The handlers in this file implement the persistent configuration endpoints
+// of the Python apprise-api (/add, /get, /del and /notify with a key), so
+// existing clients can use this server unchanged. Like the Python server they
+// accept JSON or form-encoded bodies.
+
+// maxFormMemory bounds the memory used to parse multipart form bodies
+const maxFormMemory = 32 << 20
+
+// handleAddKeyConfig stores a configuration under a key, replacing any
+// configuration already stored there. The body holds either "urls", a
+// comma and/or space separated list of Apprise URLs, or "config" with an
+// optional "format" of text or yaml.
+func (s *Server) handleAddKeyConfig(w http.ResponseWriter, r *http.Request) {
+	key := mux.Vars(r)["key"]
+	if err := ValidateConfigKey(key); err != nil {
+		s.sendError(w, http.StatusBadRequest, "Invalid configuration key", err)
+		return
+	}
+
+	fields, err := readKeyedRequest(r)
+	if err != nil {
+		s.sendError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	format := strings.ToLower(fields["format"])
+	config := fields["config"]
+	if urls := fields["urls"]; urls != "" {
+		config = strings.Join(splitURLList(urls), "\n")
+		format = apprise.ConfigFormatText
+	}
+	if strings.TrimSpace(config) == "" {
+		s.sendError(w, http.StatusBadRequest, "Either urls or config is required", nil)
+		return
+	}
+	if format == "" {
+		format = apprise.DetectConfigFormat(config)
+	}
+
+	count, err := loadAppriseConfig(format, config, nil)
+	if err != nil {
+		s.sendError(w, http.StatusBadRequest, "Invalid configuration", err)
+		return
+	}
+
+	if err := s.configs.Save(key, format, config); err != nil {
+		s.sendError(w, http.StatusInternalServerError, "Failed to save configuration", err)
+		return
+	}
+
+	s.sendSuccess(w, "Successfully saved configuration", map[string]interface{}{
+		"key":    key,
+		"format": format,
+		"urls":   count,
+	})
+}
+
+// handleGetKeyConfig returns the configuration stored under a key as plain
+// text, or as {"format", "config"} JSON when the client accepts JSON
+func (s *Server) handleGetKeyConfig(w http.ResponseWriter, r *http.Request) {
+	key := mux.Vars(r)["key"]
+
+	stored, err := s.configs.Get(key)
+	if err != nil {
+		s.sendKeyedConfigError(w, err)
+		return
+	}
+
+	if strings.Contains(r.Header.Get("Accept"), "application/json") {
+		s.sendJSON(w, http.StatusOK, map[string]string{
+			"format": stored.Format,
+			"config": stored.Config,
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("X-Apprise-Config-Type", stored.Format)
+	w.WriteHeader(http.StatusOK)
+	_, _ = io.WriteString(w, stored.Config)
+}
+
+// handleDeleteKeyConfig removes the configuration stored under a key
+func (s *Server) handleDeleteKeyConfig(w http.ResponseWriter, r *http.Request) {
+	key := mux.Vars(r)["key"]
+
+	if err := s.configs.Delete(key); err != nil {
+		s.sendKeyedConfigError(w, err)
+		return
+	}
+
+	s.sendSuccess(w, "Successfully removed configuration", map[string]interface{}{
+		"key": key,
+	})
+}
+
+// handleNotifyKey sends a notification to the services stored under a key.
+// The body takes "body", "title", "type", "format" and a "tag" filter using
+// Apprise tag expressions.
+func (s *Server) handleNotifyKey(w http.ResponseWriter, r *http.Request) {
+	key := mux.Vars(r)["key"]
+
+	fields, err := readKeyedRequest(r)
+	if err != nil {
+		s.sendError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if fields["body"] == "" {
+		s.sendError(w, http.StatusBadRequest, "Body is required", nil)
+		return
+	}
+
+	stored, err := s.configs.Get(key)
+	if err != nil {
+		s.sendKeyedConfigError(w, err)
+		return
+	}
+
+	app := apprise.New()
+	if _, err := loadAppriseConfig(stored.Format, stored.Config, app); err != nil {
+		s.sendError(w, http.StatusInternalServerError, "Failed to load stored configuration", err)
+		return
+	}
+
+	tag := fields["tag"]
+	if tag == "" {
+		tag = fields["tags"]
+	}
+	var tags []string
+	if tag != "" {
+		tags = []string{tag}
+	}
+
+	if app.CountByTag(tags...) == 0 {
+		s.sendError(w, http.StatusFailedDependency, "No services match the tag filter", nil)
+		return
+	}
+
+	notifyType := apprise.NotifyTypeInfo
+	if fields["type"] != "" {
+		parsedType, err := parseNotifyType(fields["type"])
+		if err != nil {
+			s.sendError(w, http.StatusBadRequest, "Invalid notification type", err)
+			return
+		}
+		notifyType = parsedType
+	}
+
+	responses := app.NotifyAll(apprise.NotificationRequest{
+		Title:      fields["title"],
+		Body:       fields["body"],
+		NotifyType: notifyType,
+		Tags:       tags,
+		BodyFormat: fields["format"],
+	})
+
+	result, successful := summarizeResponses(responses)
+	if successful == len(responses) {
+		s.sendSuccess(w, "All notifications sent successfully", result)
+		return
+	}
+
+	// The Python apprise-api reports partial failures as 424 too
+	s.sendJSON(w, http.StatusFailedDependency, APIResponse{
+		Success:   false,
+		Message:   "One or more notifications could not be sent",
+		Data:      result,
+		Timestamp: s.getCurrentTime(),
+	})
+}
+
+// sendKeyedConfigError answers a missing configuration with 204 No Content,
+// as the Python apprise-api does
+func (s *Server) sendKeyedConfigError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrConfigNotFound):
+		w.WriteHeader(http.StatusNoContent)
+	case errors.Is(err, ErrInvalidConfigKey):
+		s.sendError(w, http.StatusBadRequest, "Invalid configuration key", err)
+	default:
+		s.sendError(w, http.StatusInternalServerError, "Failed to load configuration", err)
+	}
+}
+
+// loadAppriseConfig parses config and adds its services to app, returning the
+// number of URLs it holds. A nil app only validates the configuration.
+func loadAppriseConfig(format, config string, app *apprise.Apprise) (int, error) {
+	if app == nil {
+		app = apprise.New()
+	}
+
+	loader := apprise.NewConfigLoader(app)
+	if err := loader.AddFromString(config, format); err != nil {
+		return 0, err
+	}
+
+	count := len(loader.URLs())
+	if count == 0 {
+		return 0, fmt.Errorf("configuration contains no URLs")
+	}
+	if err := loader.ApplyToApprise(); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// readKeyedRequest reads the fields of a JSON or form-encoded request body.
+// JSON lists, such as several tags, are joined with commas.
+func readKeyedRequest(r *http.Request) (map[string]string, error) {
+	fields := make(map[string]string)
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case "application/json":
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil && err != io.EOF {
+			return nil, err
+		}
+		for name, value := range body {
+			switch v := value.(type) {
+			case nil:
+			case string:
+				fields[name] = v
+			case []interface{}:
+				parts := make([]string, 0, len(v))
+				for _, item := range v {
+					parts = append(parts, fmt.Sprint(item))
+				}
+				fields[name] = strings.Join(parts, ",")
+			default:
+				fields[name] = fmt.Sprint(v)
+			}
+		}
+	case "multipart/form-data":
+		if err := r.ParseMultipartForm(maxFormMemory); err != nil {
+			return nil, err
+		}
+		fallthrough
+	default:
+		if err := r.ParseForm(); err != nil {
+			return nil, err
+		}
+		for name, values := range r.PostForm {
+			fields[name] = strings.Join(values, ",")
+		}
+	}
+
+	return fields, nil
+}
+
+// splitURLList splits a comma and/or space separated list of URLs. Commas
+// inside a URL, such as in a list of recipients, are kept.
+func splitURLList(list string) []string {
+	var urls []string
+	for _, part := range strings.FieldsFunc(list, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n' || r == '\r'
+	}) {
+		if !strings.Contains(part, "://") && len(urls) > 0 {
+			urls[len(urls)-1] += "," + part
+			continue
+		}
+		urls = append(urls, part)
+	}
+	return urls
+}
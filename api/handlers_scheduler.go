@@ -2,6 +2,8 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"strconv"
 	"time"
@@ -37,6 +39,29 @@ type QueuedJobRequest struct {
 	RetryDelay string            `json:"retry_delay,omitempty"` // Duration string like "5m"
 }
 
+// DeadLetterRequest selects failed jobs for a bulk dead-letter operation:
+// either the listed jobs or, with All set, every failed job
+type DeadLetterRequest struct {
+	JobIDs []int64 `json:"job_ids,omitempty"`
+	All    bool    `json:"all,omitempty"`
+}
+
+// decodeDeadLetterRequest reads the job selection of a bulk dead-letter
+// operation, requiring exactly one of job_ids and all
+func decodeDeadLetterRequest(r *http.Request) (DeadLetterRequest, error) {
+	var req DeadLetterRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		return req, err
+	}
+	if req.All && len(req.JobIDs) > 0 {
+		return req, errors.New("job_ids and all cannot be combined")
+	}
+	if !req.All && len(req.JobIDs) == 0 {
+		return req, errors.New(`job_ids or "all": true is required`)
+	}
+	return req, nil
+}
+
 // TemplateRequest represents a request to create/update a template
 type TemplateRequest struct {
 	Name        string            `json:"name"`
@@ -46,6 +71,11 @@ type TemplateRequest struct {
 	Description string            `json:"description,omitempty"`
 }
 
+// TemplateRenderRequest represents a request to render a template
+type TemplateRenderRequest struct {
+	Variables map[string]interface{} `json:"variables,omitempty"`
+}
+
 // MetricsReportRequest represents a request for metrics report
 type MetricsReportRequest struct {
 	StartTime string `json:"start_time"` // RFC3339 format
@@ -105,9 +135,12 @@ func (s *Server) handleCreateScheduledJob(w http.ResponseWriter, r *http.Request
 	// Parse notification type
 	notifyType := apprise.NotifyTypeInfo
 	if req.Type != "" {
-		if parsedType, err := parseNotifyType(req.Type); err == nil {
-			notifyType = parsedType
+		parsedType, err := parseNotifyType(req.Type)
+		if err != nil {
+			s.sendError(w, http.StatusBadRequest, "Invalid notification type", err)
+			return
 		}
+		notifyType = parsedType
 	}
 
 	// Create scheduled job
@@ -201,9 +234,12 @@ func (s *Server) handleUpdateScheduledJob(w http.ResponseWriter, r *http.Request
 		existingJob.Body = req.Body
 	}
 	if req.Type != "" {
-		if parsedType, err := parseNotifyType(req.Type); err == nil {
-			existingJob.NotifyType = parsedType
+		parsedType, err := parseNotifyType(req.Type)
+		if err != nil {
+			s.sendError(w, http.StatusBadRequest, "Invalid notification type", err)
+			return
 		}
+		existingJob.NotifyType = parsedType
 	}
 	if len(req.Services) > 0 {
 		existingJob.Services = req.Services
@@ -321,12 +357,21 @@ func (s *Server) handleListQueuedJobs(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	jobs, err := s.scheduler.GetQueuedJobs(limit)
+	queued, err := s.scheduler.GetQueuedJobs(limit)
 	if err != nil {
 		s.sendError(w, http.StatusInternalServerError, "Failed to retrieve queued jobs", err)
 		return
 	}
 
+	// Jobs hold service URLs and their credentials, so users only see
+	// their own
+	jobs := make([]apprise.QueuedJob, 0, len(queued))
+	for i := range queued {
+		if s.canReadJob(r, &queued[i]) {
+			jobs = append(jobs, queued[i])
+		}
+	}
+
 	s.sendSuccess(w, "Queued jobs retrieved", map[string]interface{}{
 		"total": len(jobs),
 		"limit": limit,
@@ -360,9 +405,12 @@ func (s *Server) handleAddToQueue(w http.ResponseWriter, r *http.Request) {
 	// Parse notification type
 	notifyType := apprise.NotifyTypeInfo
 	if req.Type != "" {
-		if parsedType, err := parseNotifyType(req.Type); err == nil {
-			notifyType = parsedType
+		parsedType, err := parseNotifyType(req.Type)
+		if err != nil {
+			s.sendError(w, http.StatusBadRequest, "Invalid notification type", err)
+			return
 		}
+		notifyType = parsedType
 	}
 
 	// Parse retry delay
@@ -391,7 +439,7 @@ func (s *Server) handleAddToQueue(w http.ResponseWriter, r *http.Request) {
 		NotifyType: notifyType,
 		Services:   req.Services,
 		Tags:       req.Tags,
-		Metadata:   req.Metadata,
+		Metadata:   jobMetadata(r, req.Metadata, ""),
 		Priority:   priority,
 		MaxRetries: maxRetries,
 		RetryDelay: retryDelay,
@@ -494,39 +542,466 @@ func (s *Server) handleMetricsReport(w http.ResponseWriter, r *http.Request) {
 	s.sendSuccess(w, "Metrics report generated", report)
 }
 
-// Placeholder handlers for other endpoints
+// handleGetQueuedJob returns a specific queued job
 func (s *Server) handleGetQueuedJob(w http.ResponseWriter, r *http.Request) {
-	s.sendError(w, http.StatusNotImplemented, "Not implemented yet", nil)
+	if s.scheduler == nil {
+		s.sendError(w, http.StatusServiceUnavailable, "Scheduler not available", nil)
+		return
+	}
+
+	jobID, err := strconv.ParseInt(mux.Vars(r)["job_id"], 10, 64)
+	if err != nil {
+		s.sendError(w, http.StatusBadRequest, "Invalid job ID", err)
+		return
+	}
+
+	job, ok := s.getOwnQueuedJob(w, r, jobID)
+	if !ok {
+		return
+	}
+
+	s.sendSuccess(w, "Queued job retrieved", job)
 }
 
+// handleUpdateQueuedJob updates the content or delivery settings of a
+// queued job. Fields left empty in the request keep their current value.
 func (s *Server) handleUpdateQueuedJob(w http.ResponseWriter, r *http.Request) {
-	s.sendError(w, http.StatusNotImplemented, "Not implemented yet", nil)
+	if s.scheduler == nil {
+		s.sendError(w, http.StatusServiceUnavailable, "Scheduler not available", nil)
+		return
+	}
+
+	jobID, err := strconv.ParseInt(mux.Vars(r)["job_id"], 10, 64)
+	if err != nil {
+		s.sendError(w, http.StatusBadRequest, "Invalid job ID", err)
+		return
+	}
+
+	var req QueuedJobRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.sendError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	// Get existing job
+	job, ok := s.getOwnQueuedJob(w, r, jobID)
+	if !ok {
+		return
+	}
+
+	// Update fields
+	if req.Title != "" {
+		job.Title = req.Title
+	}
+	if req.Body != "" {
+		job.Body = req.Body
+	}
+	if req.Type != "" {
+		parsedType, err := parseNotifyType(req.Type)
+		if err != nil {
+			s.sendError(w, http.StatusBadRequest, "Invalid notification type", err)
+			return
+		}
+		job.NotifyType = parsedType
+	}
+	if len(req.Services) > 0 {
+		job.Services = req.Services
+	}
+	if req.Tags != nil {
+		job.Tags = req.Tags
+	}
+	if req.Metadata != nil {
+		job.Metadata = jobMetadata(r, req.Metadata, job.Metadata[jobOwnerMetadata])
+	}
+	if req.Priority > 0 {
+		job.Priority = req.Priority
+	}
+	if req.MaxRetries > 0 {
+		job.MaxRetries = req.MaxRetries
+	}
+	if req.RetryDelay != "" {
+		retryDelay, err := time.ParseDuration(req.RetryDelay)
+		if err != nil {
+			s.sendError(w, http.StatusBadRequest, "Invalid retry delay", err)
+			return
+		}
+		job.RetryDelay = retryDelay
+	}
+
+	if err := s.scheduler.UpdateQueuedJob(*job); err != nil {
+		s.sendQueueError(w, "Failed to update queued job", err)
+		return
+	}
+
+	s.sendSuccess(w, "Queued job updated successfully", job)
 }
 
+// handleRetryQueuedJob resets a queued job to pending so it is sent again
 func (s *Server) handleRetryQueuedJob(w http.ResponseWriter, r *http.Request) {
-	s.sendError(w, http.StatusNotImplemented, "Not implemented yet", nil)
+	if s.scheduler == nil {
+		s.sendError(w, http.StatusServiceUnavailable, "Scheduler not available", nil)
+		return
+	}
+
+	jobID, err := strconv.ParseInt(mux.Vars(r)["job_id"], 10, 64)
+	if err != nil {
+		s.sendError(w, http.StatusBadRequest, "Invalid job ID", err)
+		return
+	}
+
+	if _, ok := s.getOwnQueuedJob(w, r, jobID); !ok {
+		return
+	}
+
+	if err := s.scheduler.RetryQueuedJob(jobID); err != nil {
+		s.sendQueueError(w, "Failed to retry queued job", err)
+		return
+	}
+
+	job, err := s.scheduler.GetQueuedJob(jobID)
+	if err != nil {
+		s.sendQueueError(w, "Failed to retrieve queued job", err)
+		return
+	}
+
+	s.sendSuccess(w, "Queued job scheduled for retry", job)
 }
 
+// handleListDeadLetterJobs returns queued jobs that failed after exhausting
+// their retries. Dead letters span every user's jobs, so the dead-letter
+// endpoints are for administrators only.
+func (s *Server) handleListDeadLetterJobs(w http.ResponseWriter, r *http.Request) {
+	if s.scheduler == nil {
+		s.sendError(w, http.StatusServiceUnavailable, "Scheduler not available", nil)
+		return
+	}
+	if !s.requireAdmin(w, r) {
+		return
+	}
+
+	// Parse query parameters
+	limit := 50 // default
+	if parsedLimit, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && parsedLimit > 0 {
+		limit = parsedLimit
+	}
+	offset := 0
+	if parsedOffset, err := strconv.Atoi(r.URL.Query().Get("offset")); err == nil && parsedOffset > 0 {
+		offset = parsedOffset
+	}
+
+	jobs, err := s.scheduler.GetDeadLetterJobs(limit, offset)
+	if err != nil {
+		s.sendError(w, http.StatusInternalServerError, "Failed to retrieve dead-letter jobs", err)
+		return
+	}
+	total, err := s.scheduler.CountDeadLetterJobs()
+	if err != nil {
+		s.sendError(w, http.StatusInternalServerError, "Failed to count dead-letter jobs", err)
+		return
+	}
+
+	s.sendSuccess(w, "Dead-letter jobs retrieved", map[string]interface{}{
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+		"jobs":   jobs,
+	})
+}
+
+// handleRequeueDeadLetterJobs requeues the selected failed jobs, or all of
+// them when asked to explicitly
+func (s *Server) handleRequeueDeadLetterJobs(w http.ResponseWriter, r *http.Request) {
+	if s.scheduler == nil {
+		s.sendError(w, http.StatusServiceUnavailable, "Scheduler not available", nil)
+		return
+	}
+	if !s.requireAdmin(w, r) {
+		return
+	}
+
+	req, err := decodeDeadLetterRequest(r)
+	if err != nil {
+		s.sendError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	requeued, err := s.scheduler.RequeueDeadLetterJobs(req.JobIDs, req.All)
+	if err != nil {
+		s.sendError(w, http.StatusInternalServerError, "Failed to requeue dead-letter jobs", err)
+		return
+	}
+
+	s.sendSuccess(w, "Dead-letter jobs requeued", map[string]interface{}{
+		"requeued": requeued,
+	})
+}
+
+// handlePurgeDeadLetterJobs deletes the selected failed jobs, or all of them
+// when asked to explicitly
+func (s *Server) handlePurgeDeadLetterJobs(w http.ResponseWriter, r *http.Request) {
+	if s.scheduler == nil {
+		s.sendError(w, http.StatusServiceUnavailable, "Scheduler not available", nil)
+		return
+	}
+	if !s.requireAdmin(w, r) {
+		return
+	}
+
+	req, err := decodeDeadLetterRequest(r)
+	if err != nil {
+		s.sendError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	purged, err := s.scheduler.PurgeDeadLetterJobs(req.JobIDs, req.All)
+	if err != nil {
+		s.sendError(w, http.StatusInternalServerError, "Failed to purge dead-letter jobs", err)
+		return
+	}
+
+	s.sendSuccess(w, "Dead-letter jobs purged", map[string]interface{}{
+		"purged": purged,
+	})
+}
+
+// getOwnQueuedJob returns a queued job the caller may see, sending an
+// error response otherwise. Other users' jobs are reported as not found.
+func (s *Server) getOwnQueuedJob(w http.ResponseWriter, r *http.Request, jobID int64) (*apprise.QueuedJob, bool) {
+	job, err := s.scheduler.GetQueuedJob(jobID)
+	if err != nil {
+		s.sendQueueError(w, "Failed to retrieve queued job", err)
+		return nil, false
+	}
+	if !s.canReadJob(r, job) {
+		s.sendQueueError(w, "Failed to retrieve queued job", apprise.ErrQueuedJobNotFound)
+		return nil, false
+	}
+	return job, true
+}
+
+// sendQueueError maps queue errors to HTTP statuses: unknown jobs are 404
+// and jobs that are currently being sent are 409
+func (s *Server) sendQueueError(w http.ResponseWriter, message string, err error) {
+	switch {
+	case errors.Is(err, apprise.ErrQueuedJobNotFound):
+		s.sendError(w, http.StatusNotFound, "Queued job not found", err)
+	case errors.Is(err, apprise.ErrQueuedJobRunning):
+		s.sendError(w, http.StatusConflict, "Queued job is running", err)
+	default:
+		s.sendError(w, http.StatusInternalServerError, message, err)
+	}
+}
+
+// handleListTemplates returns all notification templates
 func (s *Server) handleListTemplates(w http.ResponseWriter, r *http.Request) {
-	s.sendError(w, http.StatusNotImplemented, "Not implemented yet", nil)
+	if s.scheduler == nil {
+		s.sendError(w, http.StatusServiceUnavailable, "Scheduler not available", nil)
+		return
+	}
+
+	templates, err := s.scheduler.GetTemplateManager().GetTemplates()
+	if err != nil {
+		s.sendError(w, http.StatusInternalServerError, "Failed to retrieve templates", err)
+		return
+	}
+
+	s.sendSuccess(w, "Templates retrieved", map[string]interface{}{
+		"total":     len(templates),
+		"templates": templates,
+	})
 }
 
+// handleCreateTemplate creates a new notification template
 func (s *Server) handleCreateTemplate(w http.ResponseWriter, r *http.Request) {
-	s.sendError(w, http.StatusNotImplemented, "Not implemented yet", nil)
+	if s.scheduler == nil {
+		s.sendError(w, http.StatusServiceUnavailable, "Scheduler not available", nil)
+		return
+	}
+
+	var req TemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.sendError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	// Validate required fields
+	if req.Name == "" {
+		s.sendError(w, http.StatusBadRequest, "Name is required", nil)
+		return
+	}
+	if req.Body == "" {
+		s.sendError(w, http.StatusBadRequest, "Body is required", nil)
+		return
+	}
+
+	tm := s.scheduler.GetTemplateManager()
+	template := apprise.NotificationTemplate{
+		Name:        req.Name,
+		Title:       req.Title,
+		Body:        req.Body,
+		Variables:   req.Variables,
+		Description: req.Description,
+	}
+	if template.Variables == nil {
+		template.Variables = map[string]string{}
+	}
+
+	if err := tm.ValidateTemplate(template); err != nil {
+		s.sendError(w, http.StatusBadRequest, "Invalid template", err)
+		return
+	}
+
+	created, err := tm.AddTemplate(template)
+	if err != nil {
+		s.sendTemplateError(w, "Failed to create template", err)
+		return
+	}
+
+	s.sendSuccess(w, "Template created successfully", created)
 }
 
+// handleGetTemplate returns a specific notification template
 func (s *Server) handleGetTemplate(w http.ResponseWriter, r *http.Request) {
-	s.sendError(w, http.StatusNotImplemented, "Not implemented yet", nil)
+	if s.scheduler == nil {
+		s.sendError(w, http.StatusServiceUnavailable, "Scheduler not available", nil)
+		return
+	}
+
+	name := mux.Vars(r)["template_name"]
+
+	template, err := s.scheduler.GetTemplateManager().GetTemplate(name)
+	if err != nil {
+		s.sendTemplateError(w, "Failed to retrieve template", err)
+		return
+	}
+
+	s.sendSuccess(w, "Template retrieved", template)
 }
 
+// handleUpdateTemplate updates an existing notification template. Fields
+// left empty in the request keep their current value.
 func (s *Server) handleUpdateTemplate(w http.ResponseWriter, r *http.Request) {
-	s.sendError(w, http.StatusNotImplemented, "Not implemented yet", nil)
+	if s.scheduler == nil {
+		s.sendError(w, http.StatusServiceUnavailable, "Scheduler not available", nil)
+		return
+	}
+
+	name := mux.Vars(r)["template_name"]
+
+	var req TemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.sendError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if req.Name != "" && req.Name != name {
+		s.sendError(w, http.StatusBadRequest, "Template name cannot be changed", nil)
+		return
+	}
+
+	tm := s.scheduler.GetTemplateManager()
+
+	// Get existing template
+	existing, err := tm.GetTemplate(name)
+	if err != nil {
+		s.sendTemplateError(w, "Failed to retrieve template", err)
+		return
+	}
+
+	// Update fields
+	if req.Title != "" {
+		existing.Title = req.Title
+	}
+	if req.Body != "" {
+		existing.Body = req.Body
+	}
+	if req.Variables != nil {
+		existing.Variables = req.Variables
+	}
+	if req.Description != "" {
+		existing.Description = req.Description
+	}
+
+	if err := tm.ValidateTemplate(*existing); err != nil {
+		s.sendError(w, http.StatusBadRequest, "Invalid template", err)
+		return
+	}
+
+	if err := tm.UpdateTemplate(*existing); err != nil {
+		s.sendTemplateError(w, "Failed to update template", err)
+		return
+	}
+
+	// Re-read to pick up the new updated_at
+	updated, err := tm.GetTemplate(name)
+	if err != nil {
+		s.sendTemplateError(w, "Failed to retrieve template", err)
+		return
+	}
+
+	s.sendSuccess(w, "Template updated successfully", updated)
 }
 
+// handleDeleteTemplate removes a notification template
 func (s *Server) handleDeleteTemplate(w http.ResponseWriter, r *http.Request) {
-	s.sendError(w, http.StatusNotImplemented, "Not implemented yet", nil)
+	if s.scheduler == nil {
+		s.sendError(w, http.StatusServiceUnavailable, "Scheduler not available", nil)
+		return
+	}
+
+	name := mux.Vars(r)["template_name"]
+
+	if err := s.scheduler.GetTemplateManager().DeleteTemplate(name); err != nil {
+		s.sendTemplateError(w, "Failed to delete template", err)
+		return
+	}
+
+	s.sendSuccess(w, "Template deleted successfully", map[string]interface{}{
+		"name": name,
+	})
 }
 
+// handleRenderTemplate renders a template with the supplied variables
+// without sending it
 func (s *Server) handleRenderTemplate(w http.ResponseWriter, r *http.Request) {
-	s.sendError(w, http.StatusNotImplemented, "Not implemented yet", nil)
-}
\ No newline at end of file
+	if s.scheduler == nil {
+		s.sendError(w, http.StatusServiceUnavailable, "Scheduler not available", nil)
+		return
+	}
+
+	name := mux.Vars(r)["template_name"]
+
+	var req TemplateRenderRequest
+	// An empty body renders with the template defaults
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		s.sendError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	rendered, err := s.scheduler.GetTemplateManager().RenderTemplate(name, req.Variables)
+	if err != nil {
+		s.sendTemplateError(w, "Failed to render template", err)
+		return
+	}
+
+	s.sendSuccess(w, "Template rendered", map[string]interface{}{
+		"name":  name,
+		"title": rendered.Title,
+		"body":  rendered.Body,
+	})
+}
+
+// sendTemplateError maps template manager errors to HTTP statuses: unknown
+// templates are 404, duplicate names 409, and template errors 400
+func (s *Server) sendTemplateError(w http.ResponseWriter, message string, err error) {
+	switch {
+	case errors.Is(err, apprise.ErrTemplateNotFound):
+		s.sendError(w, http.StatusNotFound, "Template not found", err)
+	case errors.Is(err, apprise.ErrTemplateExists):
+		s.sendError(w, http.StatusConflict, "Template already exists", err)
+	case errors.Is(err, apprise.ErrInvalidTemplate):
+		s.sendError(w, http.StatusBadRequest, message, err)
+	default:
+		s.sendError(w, http.StatusInternalServerError, message, err)
+	}
+}
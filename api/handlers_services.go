@@ -2,7 +2,9 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -57,16 +59,34 @@ func (s *Server) handleAddService(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Add service to the main Apprise instance
-	if err := s.apprise.Add(req.URL); err != nil {
+	// Validate the URL before storing it
+	if err := apprise.New().Add(req.URL, req.Tags...); err != nil {
 		s.sendError(w, http.StatusBadRequest, "Failed to add service", err)
 		return
 	}
 
-	// TODO: Store service configuration in database for persistence
-	// For now, we just confirm the service was added
+	now := time.Now()
+	service := &StoredService{
+		ID:      generateServiceID(),
+		URL:     req.URL,
+		Tags:    req.Tags,
+		Created: now,
+		Updated: now,
+	}
+	if err := s.configs.AddService(service); err != nil {
+		s.sendError(w, http.StatusInternalServerError, "Failed to store service", err)
+		return
+	}
+
+	// Add the service to the main Apprise instance, which notifications
+	// without URLs are sent to
+	if err := s.syncServices(); err != nil {
+		s.sendError(w, http.StatusInternalServerError, "Failed to apply services", err)
+		return
+	}
 
 	s.sendSuccess(w, "Service added successfully", map[string]interface{}{
+		"id":   service.ID,
 		"url":  req.URL,
 		"tags": req.Tags,
 	})
@@ -82,6 +102,18 @@ func (s *Server) handleGetService(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Services added through the API are looked up by ID, anything else
+	// as a supported service type
+	stored, err := s.configs.GetService(serviceID)
+	if err == nil {
+		s.sendSuccess(w, "Service retrieved", stored)
+		return
+	}
+	if !errors.Is(err, ErrServiceNotFound) {
+		s.sendError(w, http.StatusInternalServerError, "Failed to retrieve service", err)
+		return
+	}
+
 	// Check if service is supported
 	supportedServices := apprise.GetSupportedServices()
 	var found bool
@@ -115,7 +147,8 @@ func (s *Server) handleGetService(w http.ResponseWriter, r *http.Request) {
 	s.sendSuccess(w, "Service information retrieved", serviceInfo)
 }
 
-// handleUpdateService updates a service configuration
+// handleUpdateService replaces the URL and tags of a service added through
+// the API
 func (s *Server) handleUpdateService(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	serviceID := vars["service_id"]
@@ -126,8 +159,28 @@ func (s *Server) handleUpdateService(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// TODO: Implement service configuration updates
-	// This would require storing service configurations in a database
+	if req.URL == "" {
+		s.sendError(w, http.StatusBadRequest, "URL is required", nil)
+		return
+	}
+	if err := apprise.New().Add(req.URL, req.Tags...); err != nil {
+		s.sendError(w, http.StatusBadRequest, "Invalid service URL", err)
+		return
+	}
+
+	if err := s.configs.UpdateService(serviceID, req.URL, req.Tags); err != nil {
+		if errors.Is(err, ErrServiceNotFound) {
+			s.sendError(w, http.StatusNotFound, "Service not found", err)
+		} else {
+			s.sendError(w, http.StatusInternalServerError, "Failed to update service", err)
+		}
+		return
+	}
+
+	if err := s.syncServices(); err != nil {
+		s.sendError(w, http.StatusInternalServerError, "Failed to apply services", err)
+		return
+	}
 
 	s.sendSuccess(w, "Service updated successfully", map[string]interface{}{
 		"service_id": serviceID,
@@ -136,7 +189,7 @@ func (s *Server) handleUpdateService(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// handleDeleteService removes a service
+// handleDeleteService removes a service added through the API
 func (s *Server) handleDeleteService(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	serviceID := vars["service_id"]
@@ -146,8 +199,19 @@ func (s *Server) handleDeleteService(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// TODO: Implement service removal from persistent storage
-	// For now, we just confirm the request
+	if err := s.configs.DeleteService(serviceID); err != nil {
+		if errors.Is(err, ErrServiceNotFound) {
+			s.sendError(w, http.StatusNotFound, "Service not found", err)
+		} else {
+			s.sendError(w, http.StatusInternalServerError, "Failed to remove service", err)
+		}
+		return
+	}
+
+	if err := s.syncServices(); err != nil {
+		s.sendError(w, http.StatusInternalServerError, "Failed to apply services", err)
+		return
+	}
 
 	s.sendSuccess(w, "Service removed successfully", map[string]interface{}{
 		"service_id": serviceID,
@@ -204,29 +268,10 @@ func (s *Server) handleTestService(w http.ResponseWriter, r *http.Request) {
 
 	responses := tempApprise.NotifyAll(notification)
 
-	// Process results
-	successful := 0
-	var errors []string
-	for _, resp := range responses {
-		if resp.Success {
-			successful++
-		} else if resp.Error != nil {
-			errors = append(errors, resp.Error.Error())
-		}
-	}
-
-	result := map[string]interface{}{
-		"service_id": serviceID,
-		"url":        testURL,
-		"success":    successful > 0,
-		"total":      len(responses),
-		"successful": successful,
-		"failed":     len(responses) - successful,
-	}
-
-	if len(errors) > 0 {
-		result["errors"] = errors
-	}
+	result, successful := summarizeResponses(responses)
+	result["service_id"] = serviceID
+	result["url"] = testURL
+	result["success"] = successful > 0
 
 	if successful > 0 {
 		s.sendSuccess(w, "Service test completed successfully", result)
@@ -243,6 +288,25 @@ func (s *Server) handleTestService(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// syncServices replaces the services added through the API in the main
+// Apprise instance with the stored ones
+func (s *Server) syncServices() error {
+	stored, err := s.configs.ListServices()
+	if err != nil {
+		return err
+	}
+
+	urls := make([]apprise.URLConfig, 0, len(stored))
+	for _, service := range stored {
+		urls = append(urls, apprise.URLConfig{URL: service.URL, Tags: service.Tags})
+	}
+	return s.services.Replace(urls)
+}
+
+func generateServiceID() string {
+	return "svc_" + strconv.FormatInt(time.Now().UnixNano(), 36)
+}
+
 // Helper method for consistent timestamp handling
 func (s *Server) getCurrentTimeValue() time.Time {
 	return time.Now()
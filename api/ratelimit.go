@@ -2,45 +2,130 @@ package api
 
 import (
 	"fmt"
+	"math"
 	"net"
 	"net/http"
 	"strconv"
-	"sync"
+	"strings"
 	"time"
 )
 
+// Rate limiting algorithms
+const (
+	// RateLimitSlidingWindow allows RequestsPerMin requests in any window of
+	// WindowSize, estimated from the counts of the current and previous
+	// windows. It is the default.
+	RateLimitSlidingWindow = "sliding_window"
+	// RateLimitTokenBucket allows bursts of BurstSize requests, refilled at
+	// RequestsPerMin per WindowSize
+	RateLimitTokenBucket = "token_bucket"
+)
+
+// Rate limit state backends
+const (
+	// RateLimitBackendMemory keeps state in process memory (the default)
+	RateLimitBackendMemory = "memory"
+	// RateLimitBackendDatabase keeps state in the server's database, so
+	// replicas sharing the database share their limits
+	RateLimitBackendDatabase = "database"
+)
+
 // RateLimitConfig holds rate limiting configuration
 type RateLimitConfig struct {
 	Enabled        bool          `json:"enabled"`
 	RequestsPerMin int           `json:"requests_per_minute"`
 	BurstSize      int           `json:"burst_size"`
 	WindowSize     time.Duration `json:"window_size"`
+
+	// Algorithm is RateLimitSlidingWindow (default) or RateLimitTokenBucket
+	Algorithm string `json:"algorithm,omitempty"`
+	// Backend is RateLimitBackendMemory (default) or RateLimitBackendDatabase
+	Backend string `json:"backend,omitempty"`
+	// Routes adds limits for requests matching a route, counted separately
+	// from the client's overall limit
+	Routes []RouteRateLimit `json:"routes,omitempty"`
+	// APIKeys replaces the overall limit for clients using an API key,
+	// keyed by the key prefix shown when API keys are listed
+	APIKeys map[string]RateLimitRule `json:"api_keys,omitempty"`
+	// Store overrides the backend with a custom state store
+	Store RateLimitStore `json:"-"`
+}
+
+// RateLimitRule is a limit of RequestsPerMin requests per WindowSize. An
+// empty WindowSize takes the overall configuration's window, and an empty
+// BurstSize allows token bucket bursts of RequestsPerMin. A rule without
+// requests does not limit anything.
+type RateLimitRule struct {
+	RequestsPerMin int           `json:"requests_per_minute"`
+	BurstSize      int           `json:"burst_size,omitempty"`
+	WindowSize     time.Duration `json:"window_size,omitempty"`
 }
 
-// ClientInfo tracks rate limiting info for a client
-type ClientInfo struct {
-	RequestCount int
-	WindowStart  time.Time
-	LastRequest  time.Time
-	mutex        sync.RWMutex
+// RouteRateLimit limits requests whose path starts with PathPrefix and, if
+// set, whose method is Method. The longest matching prefix applies.
+type RouteRateLimit struct {
+	Method     string `json:"method,omitempty"`
+	PathPrefix string `json:"path_prefix"`
+	RateLimitRule
+}
+
+// RateLimitDecision is the outcome of checking a request against its limits
+type RateLimitDecision struct {
+	Allowed    bool
+	Limit      int           // requests allowed by the most restrictive limit
+	Remaining  int           // requests left under that limit
+	RetryAfter time.Duration // set when the request is not allowed
 }
 
 // RateLimiter manages rate limiting for clients
 type RateLimiter struct {
-	config  RateLimitConfig
-	clients map[string]*ClientInfo
-	mutex   sync.RWMutex
-	
+	config RateLimitConfig
+	store  RateLimitStore
+
 	// Cleanup timer
 	cleanupTicker *time.Ticker
 	stopCleanup   chan bool
 }
 
-// NewRateLimiter creates a new rate limiter
+// limitCheck is one limit a request is counted against
+type limitCheck struct {
+	key  string
+	rule RateLimitRule
+}
+
+// validate checks the algorithm and backend names
+func (c RateLimitConfig) validate() error {
+	switch c.Algorithm {
+	case "", RateLimitSlidingWindow, RateLimitTokenBucket:
+	default:
+		return fmt.Errorf("unknown rate limit algorithm %q", c.Algorithm)
+	}
+	switch c.Backend {
+	case "", RateLimitBackendMemory, RateLimitBackendDatabase:
+	default:
+		return fmt.Errorf("unknown rate limit backend %q", c.Backend)
+	}
+	return nil
+}
+
+// NewRateLimiter creates a new rate limiter. State is kept in config.Store,
+// or in memory when it is nil.
 func NewRateLimiter(config RateLimitConfig) *RateLimiter {
+	if config.WindowSize <= 0 {
+		config.WindowSize = time.Minute
+	}
+	if config.Algorithm == "" {
+		config.Algorithm = RateLimitSlidingWindow
+	}
+
+	store := config.Store
+	if store == nil {
+		store = NewMemoryRateLimitStore()
+	}
+
 	rl := &RateLimiter{
 		config:      config,
-		clients:     make(map[string]*ClientInfo),
+		store:       store,
 		stopCleanup: make(chan bool),
 	}
 
@@ -75,61 +160,237 @@ func (rl *RateLimiter) cleanup() {
 
 // cleanupStaleClients removes clients that haven't made requests recently
 func (rl *RateLimiter) cleanupStaleClients() {
-	rl.mutex.Lock()
-	defer rl.mutex.Unlock()
-
-	cutoff := time.Now().Add(-rl.config.WindowSize * 2)
-	for clientID, client := range rl.clients {
-		client.mutex.RLock()
-		shouldDelete := client.LastRequest.Before(cutoff)
-		client.mutex.RUnlock()
+	cutoff := time.Now().Add(-rl.longestWindow() * 2)
+	_, _ = rl.store.Cleanup(cutoff)
+}
 
-		if shouldDelete {
-			delete(rl.clients, clientID)
-		}
+// longestWindow returns the largest window of any configured rule, after
+// which every key has fully recovered
+func (rl *RateLimiter) longestWindow() time.Duration {
+	window := rl.config.WindowSize
+	for _, route := range rl.config.Routes {
+		window = maxDuration(window, rl.ruleDefaults(route.RateLimitRule).WindowSize)
+	}
+	for _, rule := range rl.config.APIKeys {
+		window = maxDuration(window, rl.ruleDefaults(rule).WindowSize)
 	}
+	return window
 }
 
-// CheckLimit checks if a client has exceeded rate limits
+// CheckLimit checks if a client has exceeded its overall rate limit and
+// counts the request. It returns whether the request is allowed, the
+// requests remaining and, when it is not, how long until one is allowed.
 func (rl *RateLimiter) CheckLimit(clientID string) (bool, int, time.Duration) {
 	if !rl.config.Enabled {
 		return true, rl.config.RequestsPerMin, 0
 	}
 
-	rl.mutex.Lock()
-	client, exists := rl.clients[clientID]
+	decision, err := rl.CheckRequest(clientID, "", "", "")
+	if err != nil {
+		// Without its state the limiter cannot judge; let the request through
+		return true, rl.config.RequestsPerMin, 0
+	}
+	return decision.Allowed, decision.Remaining, decision.RetryAfter
+}
+
+// CheckRequest counts a request against the client's overall limit, or its
+// API key's limit, and against the limit of the route it is for. The
+// decision reports the most restrictive of them.
+func (rl *RateLimiter) CheckRequest(clientID, method, path, apiKey string) (RateLimitDecision, error) {
+	decision := RateLimitDecision{Allowed: true, Limit: rl.config.RequestsPerMin, Remaining: rl.config.RequestsPerMin}
+	if !rl.config.Enabled {
+		return decision, nil
+	}
+
+	now := time.Now()
+	for i, check := range rl.checksFor(clientID, method, path, apiKey) {
+		var result RateLimitDecision
+		err := rl.store.Update(check.key, func(state *RateLimitState, exists bool) {
+			result = rl.apply(check.rule, state, exists, now, true)
+		})
+		if err != nil {
+			return decision, err
+		}
+
+		// A rejected request does not count against the remaining limits
+		if !result.Allowed {
+			return result, nil
+		}
+		if i == 0 || result.Remaining < decision.Remaining {
+			decision = result
+		}
+	}
+
+	return decision, nil
+}
+
+// checksFor returns the limits a request is counted against, the route
+// limit first
+func (rl *RateLimiter) checksFor(clientID, method, path, apiKey string) []limitCheck {
+	var checks []limitCheck
+
+	var route *RouteRateLimit
+	for i, candidate := range rl.config.Routes {
+		if candidate.Method != "" && !strings.EqualFold(candidate.Method, method) {
+			continue
+		}
+		if path == "" || !strings.HasPrefix(path, candidate.PathPrefix) {
+			continue
+		}
+		if route == nil || len(candidate.PathPrefix) > len(route.PathPrefix) {
+			route = &rl.config.Routes[i]
+		}
+	}
+	if route != nil && route.RequestsPerMin > 0 {
+		checks = append(checks, limitCheck{
+			key:  "route:" + strings.ToUpper(route.Method) + " " + route.PathPrefix + "|" + clientID,
+			rule: rl.ruleDefaults(route.RateLimitRule),
+		})
+	}
+
+	scope := "global"
+	rule := RateLimitRule{
+		RequestsPerMin: rl.config.RequestsPerMin,
+		BurstSize:      rl.config.BurstSize,
+		WindowSize:     rl.config.WindowSize,
+	}
+	if apiKey != "" {
+		var matched string
+		for prefix, keyRule := range rl.config.APIKeys {
+			if strings.HasPrefix(apiKey, prefix) && len(prefix) > len(matched) {
+				matched = prefix
+				rule = rl.ruleDefaults(keyRule)
+			}
+		}
+		if matched != "" {
+			scope = "apikey:" + matched
+		}
+	}
+	if rule.RequestsPerMin > 0 {
+		checks = append(checks, limitCheck{key: scope + "|" + clientID, rule: rule})
+	}
+
+	return checks
+}
+
+// ruleDefaults gives rule the overall window when it has none
+func (rl *RateLimiter) ruleDefaults(rule RateLimitRule) RateLimitRule {
+	if rule.WindowSize <= 0 {
+		rule.WindowSize = rl.config.WindowSize
+	}
+	return rule
+}
+
+// apply runs the configured algorithm on state at time now. When take is
+// set an allowed request is counted; otherwise the state is only brought
+// up to date.
+func (rl *RateLimiter) apply(rule RateLimitRule, state *RateLimitState, exists bool, now time.Time, take bool) RateLimitDecision {
+	if rl.config.Algorithm == RateLimitTokenBucket {
+		return tokenBucket(rule, state, exists, now, take)
+	}
+	return slidingWindow(rule, state, exists, now, take)
+}
+
+// slidingWindow estimates the requests made in the last window from the
+// count of the current window and the count of the previous one, weighted
+// by how much of it still overlaps. Unlike a fixed window this does not let
+// a client double its rate across a window boundary.
+func slidingWindow(rule RateLimitRule, state *RateLimitState, exists bool, now time.Time, take bool) RateLimitDecision {
+	limit := float64(rule.RequestsPerMin)
+	window := rule.WindowSize
+
 	if !exists {
-		client = &ClientInfo{
-			RequestCount: 0,
-			WindowStart:  time.Now(),
-			LastRequest:  time.Now(),
+		*state = RateLimitState{WindowStart: now}
+	}
+	elapsed := now.Sub(state.WindowStart)
+	switch {
+	case elapsed >= 2*window:
+		state.Previous, state.Count = 0, 0
+		state.WindowStart = now
+		elapsed = 0
+	case elapsed >= window:
+		state.Previous, state.Count = state.Count, 0
+		state.WindowStart = state.WindowStart.Add(window)
+		elapsed -= window
+	}
+
+	estimate := state.Previous*(1-float64(elapsed)/float64(window)) + state.Count
+	decision := RateLimitDecision{Limit: rule.RequestsPerMin}
+	if estimate+1 > limit+1e-9 {
+		decision.RetryAfter = slidingWindowRetry(limit, state.Previous, state.Count, elapsed, window)
+		return decision
+	}
+
+	decision.Allowed = true
+	if take {
+		state.Count++
+		state.LastRequest = now
+		estimate++
+	}
+	decision.Remaining = int(math.Max(0, math.Floor(limit-estimate+1e-9)))
+	return decision
+}
+
+// slidingWindowRetry returns how long until the sliding window estimate
+// leaves room for another request
+func slidingWindowRetry(limit, previous, count float64, elapsed, window time.Duration) time.Duration {
+	room := limit - 1
+
+	// The previous window's share shrinks as the current window advances
+	if previous > 0 && count <= room {
+		at := time.Duration(float64(window) * (1 - (room-count)/previous))
+		if at < window {
+			return maxDuration(at-elapsed, time.Millisecond)
 		}
-		rl.clients[clientID] = client
 	}
-	rl.mutex.Unlock()
 
-	client.mutex.Lock()
-	defer client.mutex.Unlock()
+	// Otherwise wait for the current count to become the previous one
+	var at time.Duration
+	if count > room {
+		at = time.Duration(float64(window) * (1 - room/count))
+	}
+	return maxDuration(window-elapsed+at, time.Millisecond)
+}
 
-	now := time.Now()
-	
-	// Reset window if expired
-	if now.Sub(client.WindowStart) >= rl.config.WindowSize {
-		client.RequestCount = 0
-		client.WindowStart = now
+// tokenBucket allows bursts of up to BurstSize requests (RequestsPerMin
+// when unset), refilled evenly at RequestsPerMin per window
+func tokenBucket(rule RateLimitRule, state *RateLimitState, exists bool, now time.Time, take bool) RateLimitDecision {
+	capacity := float64(rule.BurstSize)
+	if capacity <= 0 {
+		capacity = float64(rule.RequestsPerMin)
 	}
+	rate := float64(rule.RequestsPerMin) / rule.WindowSize.Seconds()
 
-	// Check if limit exceeded
-	if client.RequestCount >= rl.config.RequestsPerMin {
-		resetTime := client.WindowStart.Add(rl.config.WindowSize)
-		return false, rl.config.RequestsPerMin - client.RequestCount, resetTime.Sub(now)
+	if !exists {
+		*state = RateLimitState{WindowStart: now, Count: capacity}
+	}
+	if elapsed := now.Sub(state.WindowStart).Seconds(); elapsed > 0 {
+		state.Count = math.Min(capacity, state.Count+elapsed*rate)
+		state.WindowStart = now
 	}
 
-	// Increment request count
-	client.RequestCount++
-	client.LastRequest = now
+	decision := RateLimitDecision{Limit: rule.RequestsPerMin}
+	if state.Count < 1 {
+		wait := time.Duration((1 - state.Count) / rate * float64(time.Second))
+		decision.RetryAfter = maxDuration(wait, time.Millisecond)
+		return decision
+	}
 
-	return true, rl.config.RequestsPerMin - client.RequestCount, 0
+	decision.Allowed = true
+	if take {
+		state.Count--
+		state.LastRequest = now
+	}
+	decision.Remaining = int(math.Floor(state.Count))
+	return decision
+}
+
+// maxDuration returns the longer of a and b
+func maxDuration(a, b time.Duration) time.Duration {
+	if a > b {
+		return a
+	}
+	return b
 }
 
 // RateLimitMiddleware provides rate limiting functionality
@@ -141,22 +402,29 @@ func (s *Server) RateLimitMiddleware(next http.Handler) http.Handler {
 		}
 
 		// Get client identifier
-		clientID := s.getClientID(r)
-		
+		clientID, apiKey := s.rateLimitIdentity(r)
+
 		// Check rate limit
-		allowed, remaining, resetTime := s.rateLimiter.CheckLimit(clientID)
-		
+		decision, err := s.rateLimiter.CheckRequest(clientID, r.Method, r.URL.Path, apiKey)
+		if err != nil {
+			// Fail open: an unavailable store should not take the API down
+			s.logger.Printf("Rate limit check failed, allowing request: %v", err)
+			next.ServeHTTP(w, r)
+			return
+		}
+
 		// Set rate limit headers
-		w.Header().Set("X-RateLimit-Limit", strconv.Itoa(s.rateLimiter.config.RequestsPerMin))
-		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
-		if resetTime > 0 {
-			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(resetTime).Unix(), 10))
+		w.Header().Set("X-RateLimit-Limit", strconv.Itoa(decision.Limit))
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+		if decision.RetryAfter > 0 {
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(decision.RetryAfter).Unix(), 10))
 		}
 
-		if !allowed {
-			w.Header().Set("Retry-After", strconv.FormatInt(int64(resetTime.Seconds()), 10))
-			s.sendError(w, http.StatusTooManyRequests, "Rate limit exceeded", 
-				fmt.Errorf("too many requests, try again in %v", resetTime.Round(time.Second)))
+		if !decision.Allowed {
+			retryAfter := int64(math.Ceil(decision.RetryAfter.Seconds()))
+			w.Header().Set("Retry-After", strconv.FormatInt(retryAfter, 10))
+			s.sendError(w, http.StatusTooManyRequests, "Rate limit exceeded",
+				fmt.Errorf("too many requests, try again in %v", decision.RetryAfter.Round(time.Second)))
 			return
 		}
 
@@ -164,22 +432,38 @@ func (s *Server) RateLimitMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// requestAPIKey returns the API key a request authenticates with, if any
+func (s *Server) requestAPIKey(r *http.Request) string {
+	if apiKey := r.Header.Get("X-API-Key"); apiKey != "" {
+		return apiKey
+	}
+	if token := s.extractToken(r); strings.HasPrefix(token, "ak_") {
+		return token
+	}
+	return ""
+}
+
 // getClientID extracts a unique identifier for the client
 func (s *Server) getClientID(r *http.Request) string {
-	// Try to get authenticated user first
-	if user, ok := GetUserFromContext(r.Context()); ok {
-		return "user:" + user.ID
-	}
+	clientID, _ := s.rateLimitIdentity(r)
+	return clientID
+}
 
-	// Check for API key
-	apiKey := r.Header.Get("X-API-Key")
-	if apiKey != "" {
-		return "apikey:" + apiKey
+// rateLimitIdentity returns the client a request is counted against and the
+// API key whose limits apply to it. Rate limiting runs before authentication,
+// so a key only counts once it is verified against the user store; otherwise
+// made-up keys could pick a higher limit or a fresh bucket. Other requests
+// are counted by IP address.
+func (s *Server) rateLimitIdentity(r *http.Request) (string, string) {
+	if apiKey := s.requestAPIKey(r); apiKey != "" && s.users != nil {
+		if _, record, err := s.users.ValidateAPIKey(apiKey); err == nil {
+			return "apikey:" + record.ID, apiKey
+		}
 	}
 
 	// Fall back to IP address
 	ip := s.getClientIP(r)
-	return "ip:" + ip
+	return "ip:" + ip, ""
 }
 
 // getClientIP extracts the real client IP address
@@ -226,64 +510,47 @@ func (rl *RateLimiter) GetRateLimitStatus(clientID string) map[string]interface{
 		}
 	}
 
-	rl.mutex.RLock()
-	client, exists := rl.clients[clientID]
-	rl.mutex.RUnlock()
-
-	if !exists {
-		return map[string]interface{}{
-			"enabled":    true,
-			"limit":      rl.config.RequestsPerMin,
-			"remaining":  rl.config.RequestsPerMin,
-			"reset_time": nil,
-		}
+	status := map[string]interface{}{
+		"enabled":     true,
+		"limit":       rl.config.RequestsPerMin,
+		"remaining":   rl.config.RequestsPerMin,
+		"reset_time":  nil,
+		"window_size": rl.config.WindowSize.String(),
+		"algorithm":   rl.config.Algorithm,
 	}
 
-	client.mutex.RLock()
-	defer client.mutex.RUnlock()
+	checks := rl.checksFor(clientID, "", "", "")
+	if len(checks) == 0 {
+		return status
+	}
+	state, exists, err := rl.store.Get(checks[0].key)
+	if err != nil || !exists {
+		return status
+	}
 
+	// Bring a copy of the state up to date without counting a request
 	now := time.Now()
-	var resetTime *time.Time
-	remaining := rl.config.RequestsPerMin - client.RequestCount
-
-	// Calculate reset time if in current window
-	if now.Sub(client.WindowStart) < rl.config.WindowSize {
-		rt := client.WindowStart.Add(rl.config.WindowSize)
-		resetTime = &rt
+	decision := rl.apply(checks[0].rule, &state, exists, now, false)
+	status["remaining"] = decision.Remaining
+	if !decision.Allowed {
+		resetTime := now.Add(decision.RetryAfter)
+		status["reset_time"] = &resetTime
 	}
 
-	return map[string]interface{}{
-		"enabled":    true,
-		"limit":      rl.config.RequestsPerMin,
-		"remaining":  remaining,
-		"reset_time": resetTime,
-		"window_size": rl.config.WindowSize.String(),
-	}
+	return status
 }
 
 // GetStats returns overall rate limiter statistics
 func (rl *RateLimiter) GetStats() map[string]interface{} {
-	rl.mutex.RLock()
-	defer rl.mutex.RUnlock()
-
-	activeClients := 0
-	totalRequests := 0
-	
-	for _, client := range rl.clients {
-		client.mutex.RLock()
-		if time.Since(client.LastRequest) < rl.config.WindowSize {
-			activeClients++
-		}
-		totalRequests += client.RequestCount
-		client.mutex.RUnlock()
-	}
+	total, active, _ := rl.store.Stats(time.Now().Add(-rl.config.WindowSize))
 
 	return map[string]interface{}{
-		"enabled":        rl.config.Enabled,
-		"total_clients":  len(rl.clients),
-		"active_clients": activeClients,
+		"enabled":          rl.config.Enabled,
+		"total_clients":    total,
+		"active_clients":   active,
 		"requests_per_min": rl.config.RequestsPerMin,
-		"burst_size":     rl.config.BurstSize,
-		"window_size":    rl.config.WindowSize.String(),
+		"burst_size":       rl.config.BurstSize,
+		"window_size":      rl.config.WindowSize.String(),
+		"algorithm":        rl.config.Algorithm,
 	}
-}
\ No newline at end of file
+}
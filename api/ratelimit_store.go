@@ -0,0 +1,233 @@
+package api
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrRateLimitConflict is returned when a shared store could not apply an
+// update because other servers kept changing the same key
+var ErrRateLimitConflict = errors.New("rate limit state changed concurrently")
+
+// maxStoreUpdateAttempts bounds the optimistic update retries of the SQL store
+const maxStoreUpdateAttempts = 10
+
+// RateLimitState is the stored state of one rate limit key. Its meaning
+// depends on the algorithm: the sliding window keeps the request counts of
+// the current and previous windows, the token bucket keeps the tokens left
+// in Count and the time of the last refill in WindowStart.
+type RateLimitState struct {
+	WindowStart time.Time
+	Count       float64
+	Previous    float64
+	LastRequest time.Time
+}
+
+// RateLimitStore holds rate limit state. Update must be atomic for a key,
+// so that limits hold when several API servers share one store.
+type RateLimitStore interface {
+	// Update loads the state of key, lets fn modify it and saves the result.
+	// exists is false when the key has no state yet.
+	Update(key string, fn func(state *RateLimitState, exists bool)) error
+	// Get returns the state of key without modifying it
+	Get(key string) (RateLimitState, bool, error)
+	// Cleanup removes keys without requests since before and returns how
+	// many were removed
+	Cleanup(before time.Time) (int, error)
+	// Stats returns the number of keys and how many of them have seen a
+	// request since activeSince
+	Stats(activeSince time.Time) (total, active int, err error)
+}
+
+// MemoryRateLimitStore keeps rate limit state in process memory. Limits only
+// hold within one server.
+type MemoryRateLimitStore struct {
+	mu     sync.Mutex
+	states map[string]RateLimitState
+}
+
+// NewMemoryRateLimitStore creates an empty in-memory store
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{states: make(map[string]RateLimitState)}
+}
+
+// Update implements RateLimitStore
+func (ms *MemoryRateLimitStore) Update(key string, fn func(state *RateLimitState, exists bool)) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	state, exists := ms.states[key]
+	fn(&state, exists)
+	ms.states[key] = state
+	return nil
+}
+
+// Get implements RateLimitStore
+func (ms *MemoryRateLimitStore) Get(key string) (RateLimitState, bool, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	state, exists := ms.states[key]
+	return state, exists, nil
+}
+
+// Cleanup implements RateLimitStore
+func (ms *MemoryRateLimitStore) Cleanup(before time.Time) (int, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	removed := 0
+	for key, state := range ms.states {
+		if state.LastRequest.Before(before) {
+			delete(ms.states, key)
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// Stats implements RateLimitStore
+func (ms *MemoryRateLimitStore) Stats(activeSince time.Time) (int, int, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	active := 0
+	for _, state := range ms.states {
+		if !state.LastRequest.Before(activeSince) {
+			active++
+		}
+	}
+	return len(ms.states), active, nil
+}
+
+// SQLRateLimitStore keeps rate limit state in a database shared by several
+// API servers. Updates use optimistic concurrency: a write only succeeds if
+// the row is unchanged since it was read, and is retried otherwise. Keys are
+// stored as SHA-256 digests, so API keys used as client IDs never reach the
+// database.
+type SQLRateLimitStore struct {
+	db *sql.DB
+}
+
+// NewSQLRateLimitStore creates a rate limit store on db, creating its table
+// if needed
+func NewSQLRateLimitStore(db *sql.DB) (*SQLRateLimitStore, error) {
+	createTable := `
+	CREATE TABLE IF NOT EXISTS api_rate_limits (
+		limit_key TEXT PRIMARY KEY,
+		window_start INTEGER NOT NULL,
+		count REAL NOT NULL,
+		previous REAL NOT NULL,
+		last_request INTEGER NOT NULL,
+		version INTEGER NOT NULL
+	);`
+
+	queries := []string{
+		createTable,
+		`CREATE INDEX IF NOT EXISTS idx_api_rate_limits_last_request ON api_rate_limits(last_request);`,
+	}
+	for _, query := range queries {
+		if _, err := db.Exec(query); err != nil {
+			return nil, fmt.Errorf("failed to initialize rate limit schema: %w", err)
+		}
+	}
+
+	return &SQLRateLimitStore{db: db}, nil
+}
+
+// Update implements RateLimitStore
+func (ss *SQLRateLimitStore) Update(key string, fn func(state *RateLimitState, exists bool)) error {
+	hashed := hashRateLimitKey(key)
+
+	for attempt := 0; attempt < maxStoreUpdateAttempts; attempt++ {
+		state, version, exists, err := ss.load(hashed)
+		if err != nil {
+			return err
+		}
+		fn(&state, exists)
+
+		var result sql.Result
+		if exists {
+			result, err = ss.db.Exec(`UPDATE api_rate_limits
+				SET window_start = ?, count = ?, previous = ?, last_request = ?, version = version + 1
+				WHERE limit_key = ? AND version = ?`,
+				state.WindowStart.UnixNano(), state.Count, state.Previous, state.LastRequest.UnixNano(),
+				hashed, version)
+		} else {
+			result, err = ss.db.Exec(`INSERT INTO api_rate_limits
+				(limit_key, window_start, count, previous, last_request, version)
+				VALUES (?, ?, ?, ?, ?, 1)
+				ON CONFLICT(limit_key) DO NOTHING`,
+				hashed, state.WindowStart.UnixNano(), state.Count, state.Previous, state.LastRequest.UnixNano())
+		}
+		if err != nil {
+			return fmt.Errorf("failed to save rate limit state: %w", err)
+		}
+
+		if rows, err := result.RowsAffected(); err != nil {
+			return fmt.Errorf("failed to save rate limit state: %w", err)
+		} else if rows == 1 {
+			return nil
+		}
+		// Another server changed the key first; start again from its state
+	}
+
+	return ErrRateLimitConflict
+}
+
+// Get implements RateLimitStore
+func (ss *SQLRateLimitStore) Get(key string) (RateLimitState, bool, error) {
+	state, _, exists, err := ss.load(hashRateLimitKey(key))
+	return state, exists, err
+}
+
+// Cleanup implements RateLimitStore
+func (ss *SQLRateLimitStore) Cleanup(before time.Time) (int, error) {
+	result, err := ss.db.Exec(`DELETE FROM api_rate_limits WHERE last_request < ?`, before.UnixNano())
+	if err != nil {
+		return 0, fmt.Errorf("failed to clean up rate limit state: %w", err)
+	}
+	removed, err := result.RowsAffected()
+	return int(removed), err
+}
+
+// Stats implements RateLimitStore
+func (ss *SQLRateLimitStore) Stats(activeSince time.Time) (int, int, error) {
+	var total, active int
+	err := ss.db.QueryRow(`SELECT COUNT(*), COALESCE(SUM(CASE WHEN last_request >= ? THEN 1 ELSE 0 END), 0)
+		FROM api_rate_limits`, activeSince.UnixNano()).Scan(&total, &active)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to count rate limit keys: %w", err)
+	}
+	return total, active, nil
+}
+
+// load reads the state and version of a hashed key
+func (ss *SQLRateLimitStore) load(hashed string) (RateLimitState, int64, bool, error) {
+	var state RateLimitState
+	var windowStart, lastRequest, version int64
+	err := ss.db.QueryRow(`SELECT window_start, count, previous, last_request, version
+		FROM api_rate_limits WHERE limit_key = ?`, hashed).
+		Scan(&windowStart, &state.Count, &state.Previous, &lastRequest, &version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return RateLimitState{}, 0, false, nil
+	}
+	if err != nil {
+		return RateLimitState{}, 0, false, fmt.Errorf("failed to load rate limit state: %w", err)
+	}
+
+	state.WindowStart = time.Unix(0, windowStart)
+	state.LastRequest = time.Unix(0, lastRequest)
+	return state, version, true, nil
+}
+
+// hashRateLimitKey returns the digest a key is stored under
+func hashRateLimitKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -1,8 +1,10 @@
 package api
 
 import (
+	"database/sql"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -228,12 +230,13 @@ func TestGetClientID(t *testing.T) {
 		}
 	})
 
-	t.Run("API key based client ID", func(t *testing.T) {
+	t.Run("Unverified API key", func(t *testing.T) {
 		req := httptest.NewRequest("GET", "/test", nil)
+		req.RemoteAddr = "192.168.1.1:12345"
 		req.Header.Set("X-API-Key", "test-api-key")
 
 		clientID := server.getClientID(req)
-		expected := "apikey:test-api-key"
+		expected := "ip:192.168.1.1"
 
 		if clientID != expected {
 			t.Errorf("Expected client ID %s, got %s", expected, clientID)
@@ -354,4 +357,210 @@ func TestCleanupOldClients(t *testing.T) {
 	if finalClients >= initialClients {
 		t.Errorf("Expected cleanup to reduce client count from %d to less, got %d", initialClients, finalClients)
 	}
-}
\ No newline at end of file
+}
+
+func TestRateLimiterSlidingWindowBoundary(t *testing.T) {
+	rl := NewRateLimiter(RateLimitConfig{
+		Enabled:        true,
+		RequestsPerMin: 4,
+		WindowSize:     200 * time.Millisecond,
+	})
+	defer rl.Stop()
+
+	for i := 0; i < 4; i++ {
+		if allowed, _, _ := rl.CheckLimit("client"); !allowed {
+			t.Fatalf("Request %d should be allowed", i+1)
+		}
+	}
+
+	// Just past the window boundary a fixed window would allow a full new
+	// batch; here almost all of the previous window still counts
+	time.Sleep(220 * time.Millisecond)
+	if allowed, _, retryAfter := rl.CheckLimit("client"); allowed || retryAfter <= 0 {
+		t.Errorf("Expected a request just after the boundary to be rejected with a retry time, got allowed=%v retry=%s", allowed, retryAfter)
+	}
+
+	// Halfway through the next window half of the previous one still counts
+	time.Sleep(80 * time.Millisecond)
+	allowed := 0
+	for i := 0; i < 4; i++ {
+		if ok, _, _ := rl.CheckLimit("client"); ok {
+			allowed++
+		}
+	}
+	if allowed == 0 || allowed >= 4 {
+		t.Errorf("Expected part of the limit to be available mid-window, %d of 4 allowed", allowed)
+	}
+}
+
+func TestRateLimiterTokenBucket(t *testing.T) {
+	rl := NewRateLimiter(RateLimitConfig{
+		Enabled:        true,
+		RequestsPerMin: 10,
+		BurstSize:      2,
+		WindowSize:     time.Second,
+		Algorithm:      RateLimitTokenBucket,
+	})
+	defer rl.Stop()
+
+	for i := 0; i < 2; i++ {
+		if allowed, remaining, _ := rl.CheckLimit("client"); !allowed || remaining != 1-i {
+			t.Fatalf("Burst request %d: allowed=%v remaining=%d", i+1, allowed, remaining)
+		}
+	}
+
+	allowed, _, retryAfter := rl.CheckLimit("client")
+	if allowed {
+		t.Fatal("Expected the request after the burst to be rejected")
+	}
+	if retryAfter <= 0 || retryAfter > 100*time.Millisecond {
+		t.Errorf("Expected a retry within one refill interval (100ms), got %s", retryAfter)
+	}
+
+	time.Sleep(retryAfter + 10*time.Millisecond)
+	if allowed, _, _ := rl.CheckLimit("client"); !allowed {
+		t.Error("Expected a request to be allowed once a token was refilled")
+	}
+}
+
+func TestRateLimiterRoutesAndAPIKeys(t *testing.T) {
+	rl := NewRateLimiter(RateLimitConfig{
+		Enabled:        true,
+		RequestsPerMin: 5,
+		WindowSize:     time.Minute,
+		Routes: []RouteRateLimit{
+			{Method: "POST", PathPrefix: "/api/v1/notify", RateLimitRule: RateLimitRule{RequestsPerMin: 1}},
+		},
+		APIKeys: map[string]RateLimitRule{
+			"ak_premium": {RequestsPerMin: 100},
+		},
+	})
+	defer rl.Stop()
+
+	check := func(clientID, method, path, apiKey string) RateLimitDecision {
+		t.Helper()
+		decision, err := rl.CheckRequest(clientID, method, path, apiKey)
+		if err != nil {
+			t.Fatalf("CheckRequest failed: %v", err)
+		}
+		return decision
+	}
+
+	if d := check("ip:1", "POST", "/api/v1/notify", ""); !d.Allowed || d.Limit != 1 || d.Remaining != 0 {
+		t.Errorf("Expected the route limit to apply, got %+v", d)
+	}
+	if d := check("ip:1", "POST", "/api/v1/notify/bulk", ""); d.Allowed {
+		t.Errorf("Expected the second notify to hit the route limit, got %+v", d)
+	}
+
+	// Other routes only count against the overall limit, which the rejected
+	// notify did not use
+	if d := check("ip:1", "GET", "/api/v1/notify/jobs/1", ""); !d.Allowed || d.Remaining != 3 {
+		t.Errorf("Expected the overall limit with 3 remaining, got %+v", d)
+	}
+
+	for i := 0; i < 10; i++ {
+		if d := check("apikey:ak_premium123", "GET", "/health", "ak_premium123"); !d.Allowed || d.Limit != 100 {
+			t.Fatalf("Expected the API key limit to apply, got %+v", d)
+		}
+	}
+}
+
+func TestSQLRateLimitStoreSharedBetweenLimiters(t *testing.T) {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "ratelimit.db")+"?_journal_mode=WAL&_busy_timeout=5000")
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	// Two replicas, each with its own store on the shared database
+	var limiters []*RateLimiter
+	for i := 0; i < 2; i++ {
+		store, err := NewSQLRateLimitStore(db)
+		if err != nil {
+			t.Fatalf("Failed to create store: %v", err)
+		}
+		rl := NewRateLimiter(RateLimitConfig{
+			Enabled:        true,
+			RequestsPerMin: 3,
+			WindowSize:     time.Minute,
+			Store:          store,
+		})
+		defer rl.Stop()
+		limiters = append(limiters, rl)
+	}
+
+	allowed := 0
+	for i := 0; i < 6; i++ {
+		if ok, _, _ := limiters[i%2].CheckLimit("apikey:ak_secret"); ok {
+			allowed++
+		}
+	}
+	if allowed != 3 {
+		t.Errorf("Expected 3 requests allowed across both replicas, got %d", allowed)
+	}
+
+	var stored int
+	db.QueryRow(`SELECT COUNT(*) FROM api_rate_limits WHERE limit_key LIKE '%ak_secret%'`).Scan(&stored)
+	if stored != 0 {
+		t.Error("Expected client IDs to be stored as digests")
+	}
+
+	if stats := limiters[0].GetStats(); stats["total_clients"].(int) != 1 || stats["active_clients"].(int) != 1 {
+		t.Errorf("Unexpected stats: %v", stats)
+	}
+	if removed, err := limiters[0].store.Cleanup(time.Now().Add(time.Second)); err != nil || removed != 1 {
+		t.Errorf("Expected cleanup to remove 1 key, got %d (%v)", removed, err)
+	}
+}
+
+func TestRateLimitVerifiedAPIKeys(t *testing.T) {
+	config := &ServerConfig{
+		JWTSecret:     "test-secret",
+		AdminPassword: "admin",
+		RateLimit: RateLimitConfig{
+			Enabled:        true,
+			RequestsPerMin: 2,
+			WindowSize:     time.Minute,
+			APIKeys:        map[string]RateLimitRule{"ak_": {RequestsPerMin: 100}},
+		},
+	}
+	server, err := NewServer(config, apprise.New(), nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	user := &User{ID: "user_ci", Username: "ci", Email: "ci@example.com", Roles: []string{"user"}, Enabled: true, Created: time.Now()}
+	if err := server.users.CreateUser(user, "secret"); err != nil {
+		t.Fatal(err)
+	}
+	key := GenerateAPIKey()
+	record := &APIKeyRecord{ID: generateKeyID(), UserID: user.ID, Name: "ci", Prefix: key[:10], Created: time.Now()}
+	if err := server.users.CreateAPIKey(record, key); err != nil {
+		t.Fatal(err)
+	}
+
+	request := func(apiKey string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/health", nil)
+		req.RemoteAddr = "198.51.100.7:1234"
+		req.Header.Set("X-API-Key", apiKey)
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Made-up keys neither get the key limit nor a bucket of their own
+	for i, fake := range []string{"ak_junk1", "ak_junk2"} {
+		w := request(fake)
+		if w.Code != http.StatusOK || w.Header().Get("X-RateLimit-Limit") != "2" {
+			t.Fatalf("Request %d: expected the IP limit, got %d with limit %s", i+1, w.Code, w.Header().Get("X-RateLimit-Limit"))
+		}
+	}
+	if w := request("ak_junk3"); w.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected rotating made-up keys to share the IP limit, got %d", w.Code)
+	}
+
+	if w := request(key); w.Code != http.StatusOK || w.Header().Get("X-RateLimit-Limit") != "100" {
+		t.Errorf("Expected the verified key to get its limit, got %d with limit %s", w.Code, w.Header().Get("X-RateLimit-Limit"))
+	}
+}
@@ -3,12 +3,14 @@ package api
 import (
 	"context"
 	"crypto/rand"
+	"database/sql"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -27,6 +29,25 @@ type ServerConfig struct {
 	RequireAuth    bool            `json:"require_auth"`
 	TokenDuration  int             `json:"token_duration"` // hours
 	RateLimit      RateLimitConfig `json:"rate_limit"`
+	AdminUsername  string          `json:"admin_username"`
+	AdminPassword  string          `json:"-"` // Initial admin password, used only when no users exist
+
+	// Where a generated initial admin password is written; defaults to the
+	// database path plus ".admin-password"
+	AdminPasswordFile string `json:"admin_password_file"`
+	// Let anyone register a user account; otherwise only admins can
+	AllowRegistration bool `json:"allow_registration"`
+	// Directory administrators may load configuration files from; loading
+	// is disabled when empty
+	ConfigDir string `json:"config_dir"`
+	// Let attachment URLs reach loopback, private and link-local addresses,
+	// which are refused by default so clients cannot probe internal services
+	AllowPrivateAttachmentURLs bool `json:"allow_private_attachment_urls"`
+
+	// Notify request limits; zero values use the defaults
+	MaxAttachments    int   `json:"max_attachments"`     // per notification
+	MaxAttachmentSize int64 `json:"max_attachment_size"` // bytes per attachment
+	MaxRequestSize    int64 `json:"max_request_size"`    // bytes per request body
 }
 
 // Server represents the REST API server
@@ -38,6 +59,15 @@ type Server struct {
 	router      *mux.Router
 	server      *http.Server
 	rateLimiter *RateLimiter
+	users       *UserStore
+	configs     *ConfigStore
+	services    *apprise.ServiceSet // Services added through the services API
+	attachmentClient *http.Client // Fetches attachments given by URL
+	db          *sql.DB // Database shared by the stores
+	ownedDB     *sql.DB // Set when the server opened its own database
+
+	loadedConfigsMu sync.Mutex
+	loadedConfigs   map[string]bool // Configuration files loaded, by resolved path
 }
 
 // APIResponse represents a standard API response
@@ -57,21 +87,140 @@ func NewServer(config *ServerConfig, apprise *apprise.Apprise, scheduler *appris
 	}
 	
 	s := &Server{
-		config:    config,
-		apprise:   apprise,
-		scheduler: scheduler,
-		logger:    logger,
+		config:           config,
+		apprise:          apprise,
+		scheduler:        scheduler,
+		logger:           logger,
+		attachmentClient: newAttachmentClient(config.AllowPrivateAttachmentURLs),
+		loadedConfigs:    make(map[string]bool),
+	}
+
+	if err := s.setupStores(); err != nil {
+		return nil, err
 	}
 
 	// Initialize rate limiter if enabled
 	if config.RateLimit.Enabled {
-		s.rateLimiter = NewRateLimiter(config.RateLimit)
+		if err := config.RateLimit.validate(); err != nil {
+			return nil, err
+		}
+		rateLimit := config.RateLimit
+		if rateLimit.Store == nil && rateLimit.Backend == RateLimitBackendDatabase {
+			store, err := NewSQLRateLimitStore(s.db)
+			if err != nil {
+				return nil, err
+			}
+			rateLimit.Store = store
+		}
+		s.rateLimiter = NewRateLimiter(rateLimit)
 	}
 
 	s.setupRoutes()
 	return s, nil
 }
 
+// setupStores opens the user and configuration stores, sharing the
+// scheduler's database when there is one. Without a database both are kept
+// in memory.
+func (s *Server) setupStores() error {
+	var db *sql.DB
+	var err error
+	switch {
+	case s.scheduler != nil:
+		db = s.scheduler.DB()
+	case s.config.DatabasePath != "":
+		db, err = sql.Open("sqlite3", s.config.DatabasePath+"?_foreign_keys=on&_journal_mode=WAL")
+		if err != nil {
+			return fmt.Errorf("failed to open database: %w", err)
+		}
+		s.ownedDB = db
+	default:
+		db, err = sql.Open("sqlite3", "file::memory:?_foreign_keys=on")
+		if err != nil {
+			return fmt.Errorf("failed to open database: %w", err)
+		}
+		// Every connection to :memory: is a separate database
+		db.SetMaxOpenConns(1)
+		s.ownedDB = db
+	}
+
+	s.db = db
+
+	if s.users, err = NewUserStore(db); err != nil {
+		return err
+	}
+	if s.configs, err = NewConfigStore(db); err != nil {
+		return err
+	}
+
+	s.services = s.apprise.NewServiceSet()
+	if err := s.syncServices(); err != nil {
+		s.logger.Printf("Failed to restore stored services: %v", err)
+	}
+
+	return s.createInitialAdmin()
+}
+
+// createInitialAdmin creates an admin user when the user store is empty
+func (s *Server) createInitialAdmin() error {
+	count, err := s.users.CountUsers()
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	username := s.config.AdminUsername
+	if username == "" {
+		username = "admin"
+	}
+	password := s.config.AdminPassword
+	if password == "" {
+		bytes := make([]byte, 12)
+		if _, err := rand.Read(bytes); err != nil {
+			return fmt.Errorf("failed to generate admin password: %w", err)
+		}
+		password = hex.EncodeToString(bytes)
+		if err := s.writeAdminPassword(username, password); err != nil {
+			return err
+		}
+	}
+
+	admin := &User{
+		ID:       generateUserID(),
+		Username: username,
+		Email:    username + "@localhost",
+		Roles:    []string{"admin", "user"},
+		Enabled:  true,
+		Created:  time.Now(),
+	}
+	return s.users.CreateUser(admin, password)
+}
+
+// writeAdminPassword saves a generated initial admin password to a file only
+// its owner can read, so it never shows up in logs
+func (s *Server) writeAdminPassword(username, password string) error {
+	path := s.config.AdminPasswordFile
+	if path == "" && s.config.DatabasePath != "" {
+		path = s.config.DatabasePath + ".admin-password"
+	}
+	if path == "" {
+		s.logger.Printf("Created initial admin user %q with a random password; set an admin password or password file to log in as it", username)
+		return nil
+	}
+
+	if err := os.WriteFile(path, []byte(password+"\n"), 0600); err != nil {
+		return fmt.Errorf("failed to write admin password: %w", err)
+	}
+	// WriteFile keeps the mode of an existing file
+	if err := os.Chmod(path, 0600); err != nil {
+		return fmt.Errorf("failed to write admin password: %w", err)
+	}
+	s.logger.Printf("Created initial admin user %q; its password is in %s", username, path)
+	return nil
+}
+
 // setupRoutes configures all API routes
 func (s *Server) setupRoutes() {
 	s.router = mux.NewRouter()
@@ -84,7 +233,7 @@ func (s *Server) setupRoutes() {
 	s.router.HandleFunc("/version", s.handleVersion).Methods("GET")
 	s.router.HandleFunc("/metrics", s.handleMetrics).Methods("GET")
 
-	// Authentication endpoints (public)
+	// Authentication endpoints (login is public, registration only when allowed)
 	authV1 := apiV1.PathPrefix("/auth").Subrouter()
 	authV1.HandleFunc("/login", s.handleLogin).Methods("POST")
 	authV1.HandleFunc("/register", s.handleRegister).Methods("POST")
@@ -106,6 +255,7 @@ func (s *Server) setupRoutes() {
 	// Notification endpoints
 	apiV1.HandleFunc("/notify", s.handleNotify).Methods("POST")
 	apiV1.HandleFunc("/notify/bulk", s.handleBulkNotify).Methods("POST")
+	apiV1.HandleFunc("/notify/jobs/{job_id}", s.handleNotifyStatus).Methods("GET")
 
 	// Service management endpoints
 	apiV1.HandleFunc("/services", s.handleListServices).Methods("GET")
@@ -120,6 +270,12 @@ func (s *Server) setupRoutes() {
 	apiV1.HandleFunc("/config", s.handleUpdateConfig).Methods("PUT")
 	apiV1.HandleFunc("/config/load", s.handleLoadConfig).Methods("POST")
 
+	// Persistent configuration endpoints, compatible with the Python apprise-api
+	s.router.HandleFunc("/add/{key}", s.handleAddKeyConfig).Methods("POST")
+	s.router.HandleFunc("/get/{key}", s.handleGetKeyConfig).Methods("GET", "POST")
+	s.router.HandleFunc("/del/{key}", s.handleDeleteKeyConfig).Methods("POST")
+	s.router.HandleFunc("/notify/{key}", s.handleNotifyKey).Methods("POST")
+
 	// Scheduler endpoints (if scheduler is available)
 	if s.scheduler != nil {
 		schedulerV1 := apiV1.PathPrefix("/scheduler").Subrouter()
@@ -136,10 +292,13 @@ func (s *Server) setupRoutes() {
 		// Queue management
 		schedulerV1.HandleFunc("/queue", s.handleListQueuedJobs).Methods("GET")
 		schedulerV1.HandleFunc("/queue", s.handleAddToQueue).Methods("POST")
+		schedulerV1.HandleFunc("/queue/stats", s.handleQueueStats).Methods("GET")
+		schedulerV1.HandleFunc("/queue/dead-letter", s.handleListDeadLetterJobs).Methods("GET")
+		schedulerV1.HandleFunc("/queue/dead-letter/requeue", s.handleRequeueDeadLetterJobs).Methods("POST")
+		schedulerV1.HandleFunc("/queue/dead-letter/purge", s.handlePurgeDeadLetterJobs).Methods("POST")
 		schedulerV1.HandleFunc("/queue/{job_id}", s.handleGetQueuedJob).Methods("GET")
 		schedulerV1.HandleFunc("/queue/{job_id}", s.handleUpdateQueuedJob).Methods("PUT")
 		schedulerV1.HandleFunc("/queue/{job_id}/retry", s.handleRetryQueuedJob).Methods("POST")
-		schedulerV1.HandleFunc("/queue/stats", s.handleQueueStats).Methods("GET")
 
 		// Template management
 		schedulerV1.HandleFunc("/templates", s.handleListTemplates).Methods("GET")
@@ -192,6 +351,9 @@ func (s *Server) Shutdown(ctx context.Context) error {
 	if s.rateLimiter != nil {
 		s.rateLimiter.Stop()
 	}
+	if s.ownedDB != nil {
+		defer s.ownedDB.Close()
+	}
 	
 	if s.server != nil {
 		return s.server.Shutdown(ctx)
@@ -2,12 +2,20 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -50,9 +58,27 @@ func TestAPIServer_HealthEndpoint(t *testing.T) {
 	if !response.Success {
 		t.Error("Expected success=true in health response")
 	}
+
+	data, ok := response.Data.(map[string]interface{})
+	if !ok {
+		t.Fatalf("Expected health data object, got %T", response.Data)
+	}
+	if _, ok := data["circuit_breakers"].([]interface{}); !ok {
+		t.Errorf("Expected circuit breaker states in health response, got %v", data["circuit_breakers"])
+	}
+	if _, ok := data["open_circuits"]; !ok {
+		t.Error("Expected open circuit count in health response")
+	}
 }
 
 func TestAPIServer_NotifyEndpoint(t *testing.T) {
+	var received int32
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&received, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
 	// Create test server
 	config := &ServerConfig{
 		Host:         "localhost",
@@ -70,9 +96,9 @@ func TestAPIServer_NotifyEndpoint(t *testing.T) {
 		t.Fatalf("Failed to create server: %v", err)
 	}
 
-	// Test notification endpoint with webhook (safe for testing)
+	// Test notification endpoint with a webhook to a local server
 	notifyReq := NotificationRequest{
-		URLs:  []string{"webhook://httpbin.org/post"},
+		URLs:  []string{"webhook://" + strings.TrimPrefix(target.URL, "http://") + "/post"},
 		Title: "Test Notification",
 		Body:  "This is a test notification from API server",
 		Type:  "info",
@@ -101,6 +127,9 @@ func TestAPIServer_NotifyEndpoint(t *testing.T) {
 	if !response.Success {
 		t.Error("Expected success=true in notification response")
 	}
+	if count := atomic.LoadInt32(&received); count != 1 {
+		t.Errorf("Expected the webhook to be notified once, got %d", count)
+	}
 }
 
 func TestAPIServer_ServicesEndpoint(t *testing.T) {
@@ -319,4 +348,885 @@ func TestGenerateJWTSecret(t *testing.T) {
 func timeTrack(start time.Time, name string, t *testing.T) {
 	elapsed := time.Since(start)
 	t.Logf("%s took %v", name, elapsed)
-}
\ No newline at end of file
+}
+
+func TestFailureStatus(t *testing.T) {
+	rateLimited := &apprise.NotificationError{Class: apprise.ErrorClassRateLimited, StatusCode: 429, RetryAfter: 2 * time.Second}
+	remote := &apprise.NotificationError{Class: apprise.ErrorClassRemote5xx, StatusCode: 503}
+	auth := &apprise.NotificationError{Class: apprise.ErrorClassAuth, StatusCode: 401}
+
+	tests := []struct {
+		name       string
+		errs       []error
+		status     int
+		retryAfter time.Duration
+	}{
+		{"rate limited", []error{rateLimited, rateLimited}, http.StatusTooManyRequests, 2 * time.Second},
+		{"remote", []error{remote}, http.StatusBadGateway, 0},
+		{"auth", []error{auth}, http.StatusUnprocessableEntity, 0},
+		{"mixed", []error{rateLimited, remote}, http.StatusInternalServerError, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			responses := make([]apprise.NotificationResponse, len(tt.errs))
+			for i, err := range tt.errs {
+				responses[i] = apprise.NotificationResponse{Error: err}
+			}
+
+			status, retryAfter := failureStatus(responses)
+			if status != tt.status || retryAfter != tt.retryAfter {
+				t.Errorf("failureStatus() = %d, %v; expected %d, %v", status, retryAfter, tt.status, tt.retryAfter)
+			}
+		})
+	}
+}
+
+// newSchedulerTestServer creates a server backed by a scheduler with a
+// temporary database
+func newSchedulerTestServer(t *testing.T) *Server {
+	t.Helper()
+
+	dbPath := filepath.Join(t.TempDir(), "test_api_scheduler.db")
+
+	config := &ServerConfig{
+		Host:          "localhost",
+		Port:          "8080",
+		DatabasePath:  dbPath,
+		CORSOrigins:   []string{"*"},
+		JWTSecret:     "test-secret",
+		AdminPassword: "admin",
+		LogLevel:      "info",
+	}
+
+	appriseInstance := apprise.New()
+	logger := log.New(os.Stdout, "[test] ", log.LstdFlags)
+	scheduler, err := apprise.NewNotificationScheduler(dbPath, appriseInstance)
+	if err != nil {
+		t.Fatalf("Failed to create scheduler: %v", err)
+	}
+	t.Cleanup(func() { scheduler.Close() })
+
+	server, err := NewServer(config, appriseInstance, scheduler, logger)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	return server
+}
+
+// doJSON sends a JSON request through the server router
+func doJSON(server *Server, method, path string, body interface{}) (*httptest.ResponseRecorder, APIResponse) {
+	return doJSONAs(server, "", method, path, body)
+}
+
+// doJSONAs is doJSON with a bearer token, if token is not empty
+func doJSONAs(server *Server, token, method, path string, body interface{}) (*httptest.ResponseRecorder, APIResponse) {
+	var buf bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&buf).Encode(body)
+	}
+	req := httptest.NewRequest(method, path, &buf)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+
+	var response APIResponse
+	json.Unmarshal(w.Body.Bytes(), &response)
+	return w, response
+}
+
+func TestAPIServer_TemplateEndpoints(t *testing.T) {
+	server := newSchedulerTestServer(t)
+	do := func(method, path string, body interface{}) (*httptest.ResponseRecorder, APIResponse) {
+		return doJSON(server, method, path, body)
+	}
+
+	template := map[string]interface{}{
+		"name":      "disk-alert",
+		"title":     "Disk {{.level}}",
+		"body":      "{{.host}} is at {{.usage}}%",
+		"variables": map[string]string{"level": "warning"},
+	}
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		body     interface{}
+		expected int
+	}{
+		{"create", "POST", "/api/v1/scheduler/templates", template, http.StatusOK},
+		{"create duplicate", "POST", "/api/v1/scheduler/templates", template, http.StatusConflict},
+		{"create missing body", "POST", "/api/v1/scheduler/templates", map[string]string{"name": "empty"}, http.StatusBadRequest},
+		{"create invalid syntax", "POST", "/api/v1/scheduler/templates", map[string]string{"name": "broken", "body": "{{.host"}, http.StatusBadRequest},
+		{"list", "GET", "/api/v1/scheduler/templates", nil, http.StatusOK},
+		{"get", "GET", "/api/v1/scheduler/templates/disk-alert", nil, http.StatusOK},
+		{"get missing", "GET", "/api/v1/scheduler/templates/nope", nil, http.StatusNotFound},
+		{"update", "PUT", "/api/v1/scheduler/templates/disk-alert", map[string]string{"description": "Disk usage"}, http.StatusOK},
+		{"update invalid syntax", "PUT", "/api/v1/scheduler/templates/disk-alert", map[string]string{"body": "{{end}}"}, http.StatusBadRequest},
+		{"update missing", "PUT", "/api/v1/scheduler/templates/nope", map[string]string{"body": "x"}, http.StatusNotFound},
+		{"render missing", "POST", "/api/v1/scheduler/templates/nope/render", nil, http.StatusNotFound},
+		{"delete", "DELETE", "/api/v1/scheduler/templates/disk-alert", nil, http.StatusOK},
+		{"delete missing", "DELETE", "/api/v1/scheduler/templates/disk-alert", nil, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		if tt.name == "delete" {
+			// Render before the template is removed
+			w, response := do("POST", "/api/v1/scheduler/templates/disk-alert/render",
+				map[string]interface{}{"variables": map[string]interface{}{"host": "db1", "usage": 93}})
+			if w.Code != http.StatusOK {
+				t.Fatalf("render: expected status 200, got %d: %s", w.Code, w.Body.String())
+			}
+			data := response.Data.(map[string]interface{})
+			if data["title"] != "Disk warning" || data["body"] != "db1 is at 93%" {
+				t.Errorf("render: unexpected output %v", data)
+			}
+		}
+
+		w, _ := do(tt.method, tt.path, tt.body)
+		if w.Code != tt.expected {
+			t.Errorf("%s: expected status %d, got %d: %s", tt.name, tt.expected, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestAPIServer_QueueEndpoints(t *testing.T) {
+	server := newSchedulerTestServer(t)
+
+	w, response := doJSON(server, "POST", "/api/v1/scheduler/queue", map[string]interface{}{
+		"title":    "Outage",
+		"body":     "Database unreachable",
+		"services": []string{"json://localhost/notify"},
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Failed to queue job: %d %s", w.Code, w.Body.String())
+	}
+	jobID := int64(response.Data.(map[string]interface{})["id"].(float64))
+	jobPath := fmt.Sprintf("/api/v1/scheduler/queue/%d", jobID)
+
+	if w, _ := doJSON(server, "GET", "/api/v1/scheduler/queue/stats", nil); w.Code != http.StatusOK {
+		t.Errorf("stats: expected status 200, got %d", w.Code)
+	}
+
+	w, response = doJSON(server, "PUT", jobPath, map[string]interface{}{"title": "Outage resolved", "priority": 5})
+	if w.Code != http.StatusOK {
+		t.Fatalf("update: expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w, response = doJSON(server, "GET", jobPath, nil)
+	data := response.Data.(map[string]interface{})
+	if w.Code != http.StatusOK || data["title"] != "Outage resolved" || data["priority"].(float64) != 5 {
+		t.Errorf("get: unexpected response %d %v", w.Code, data)
+	}
+
+	if w, _ := doJSON(server, "PUT", jobPath, map[string]interface{}{"retry_delay": "soon"}); w.Code != http.StatusBadRequest {
+		t.Errorf("update invalid retry delay: expected status 400, got %d", w.Code)
+	}
+	if w, _ := doJSON(server, "GET", "/api/v1/scheduler/queue/9999", nil); w.Code != http.StatusNotFound {
+		t.Errorf("get missing: expected status 404, got %d", w.Code)
+	}
+	if w, _ := doJSON(server, "POST", "/api/v1/scheduler/queue/9999/retry", nil); w.Code != http.StatusNotFound {
+		t.Errorf("retry missing: expected status 404, got %d", w.Code)
+	}
+
+	// Dead letters span every user's jobs, so only admins may see them
+	if w, _ := doJSON(server, "GET", "/api/v1/scheduler/queue/dead-letter", nil); w.Code != http.StatusUnauthorized {
+		t.Errorf("dead-letter without credentials: expected status 401, got %d", w.Code)
+	}
+	admin := adminToken(t, server)
+
+	// The job is still pending, so bulk dead-letter operations leave it alone
+	w, response = doJSONAs(server, admin, "GET", "/api/v1/scheduler/queue/dead-letter", nil)
+	if w.Code != http.StatusOK || response.Data.(map[string]interface{})["total"].(float64) != 0 {
+		t.Errorf("dead-letter: expected empty list, got %d %v", w.Code, response.Data)
+	}
+
+	w, response = doJSONAs(server, admin, "POST", "/api/v1/scheduler/queue/dead-letter/requeue", map[string]interface{}{"job_ids": []int64{jobID}})
+	if w.Code != http.StatusOK || response.Data.(map[string]interface{})["requeued"].(float64) != 0 {
+		t.Errorf("requeue: pending jobs should not be requeued, got %d %v", w.Code, response.Data)
+	}
+
+	// Bulk operations need an explicit selection
+	if w, _ := doJSONAs(server, admin, "POST", "/api/v1/scheduler/queue/dead-letter/purge", nil); w.Code != http.StatusBadRequest {
+		t.Errorf("purge without a selection: expected status 400, got %d", w.Code)
+	}
+	if w, _ := doJSONAs(server, admin, "POST", "/api/v1/scheduler/queue/dead-letter/requeue", map[string]interface{}{"job_ids": []int64{}}); w.Code != http.StatusBadRequest {
+		t.Errorf("requeue with an empty selection: expected status 400, got %d", w.Code)
+	}
+
+	w, response = doJSONAs(server, admin, "POST", "/api/v1/scheduler/queue/dead-letter/purge", map[string]interface{}{"all": true})
+	if w.Code != http.StatusOK || response.Data.(map[string]interface{})["purged"].(float64) != 0 {
+		t.Errorf("purge: pending jobs should not be purged, got %d %v", w.Code, response.Data)
+	}
+
+	w, response = doJSON(server, "POST", jobPath+"/retry", nil)
+	if w.Code != http.StatusOK || response.Data.(map[string]interface{})["status"] != "pending" {
+		t.Errorf("retry: expected pending job, got %d %v", w.Code, response.Data)
+	}
+}
+
+func TestAPIServer_PersistentConfigEndpoints(t *testing.T) {
+	var received []string
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+	host := strings.TrimPrefix(target.URL, "http://")
+
+	dbPath := filepath.Join(t.TempDir(), "test_api_configs.db")
+	newServer := func() *Server {
+		server, err := NewServer(&ServerConfig{DatabasePath: dbPath, JWTSecret: "test-secret"},
+			apprise.New(), nil, log.New(os.Stdout, "[test] ", log.LstdFlags))
+		if err != nil {
+			t.Fatalf("Failed to create server: %v", err)
+		}
+		t.Cleanup(func() { server.Shutdown(context.Background()) })
+		return server
+	}
+	server := newServer()
+
+	// Store plain URLs as JSON
+	w, _ := doJSON(server, "POST", "/add/mykey", map[string]string{
+		"urls": fmt.Sprintf("webhook://%s/all, webhook://%s/all2", host, host),
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200 adding URLs, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// Replace them with a tagged text config sent as a form
+	config := fmt.Sprintf("devops=webhook://%s/devops\nops,sales=webhook://%s/sales\n", host, host)
+	form := url.Values{"config": {config}, "format": {"text"}}
+	req := httptest.NewRequest("POST", "/add/mykey", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w = httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200 adding config, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// The configuration survives a restart
+	server = newServer()
+
+	req = httptest.NewRequest("POST", "/get/mykey", nil)
+	w = httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || w.Body.String() != config {
+		t.Errorf("Unexpected /get response %d: %q", w.Code, w.Body.String())
+	}
+	if w.Header().Get("X-Apprise-Config-Type") != "text" {
+		t.Errorf("Expected text config type, got %q", w.Header().Get("X-Apprise-Config-Type"))
+	}
+
+	req = httptest.NewRequest("POST", "/get/mykey", nil)
+	req.Header.Set("Accept", "application/json")
+	w = httptest.NewRecorder()
+	server.router.ServeHTTP(w, req)
+	var stored map[string]string
+	json.Unmarshal(w.Body.Bytes(), &stored)
+	if stored["format"] != "text" || stored["config"] == "" {
+		t.Errorf("Unexpected JSON /get response: %s", w.Body.String())
+	}
+
+	// Notify only the services tagged devops
+	w, _ = doJSON(server, "POST", "/notify/mykey", map[string]interface{}{
+		"body": "Disk full",
+		"tag":  []string{"devops"},
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200 notifying, got %d: %s", w.Code, w.Body.String())
+	}
+	if len(received) != 1 || received[0] != "/devops" {
+		t.Errorf("Expected only the devops service to be notified, got %v", received)
+	}
+
+	w, _ = doJSON(server, "POST", "/notify/mykey", map[string]string{"body": "x", "tag": "finance"})
+	if w.Code != http.StatusFailedDependency {
+		t.Errorf("Expected 424 when no tags match, got %d", w.Code)
+	}
+
+	w, _ = doJSON(server, "POST", "/notify/mykey", map[string]string{"title": "no body"})
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 without a body, got %d", w.Code)
+	}
+	w, _ = doJSON(server, "POST", "/notify/mykey", map[string]string{"body": "x", "type": "urgent"})
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an unknown type, got %d", w.Code)
+	}
+
+	// Invalid keys and configs are rejected
+	w, _ = doJSON(server, "POST", "/add/bad.key", map[string]string{"urls": "json://localhost"})
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for invalid key, got %d", w.Code)
+	}
+	w, _ = doJSON(server, "POST", "/add/other", map[string]string{"urls": "unknown://localhost"})
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for invalid URL, got %d", w.Code)
+	}
+	w, _ = doJSON(server, "POST", "/add/other", map[string]string{})
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 without urls or config, got %d", w.Code)
+	}
+
+	// Deleting removes the configuration
+	w, _ = doJSON(server, "POST", "/del/mykey", nil)
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected 200 deleting, got %d", w.Code)
+	}
+	for _, path := range []string{"/del/mykey", "/get/mykey"} {
+		if w, _ = doJSON(server, "POST", path, nil); w.Code != http.StatusNoContent {
+			t.Errorf("Expected 204 from %s after delete, got %d", path, w.Code)
+		}
+	}
+	if w, _ = doJSON(server, "POST", "/notify/mykey", map[string]string{"body": "x"}); w.Code != http.StatusNoContent {
+		t.Errorf("Expected 204 notifying a missing key, got %d", w.Code)
+	}
+}
+
+func TestAPIServer_ServiceManagement(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "api.db")
+	newServer := func() *Server {
+		server, err := NewServer(&ServerConfig{DatabasePath: dbPath, JWTSecret: "test-secret", AdminPassword: "admin"},
+			apprise.New(), nil, log.New(io.Discard, "", 0))
+		if err != nil {
+			t.Fatalf("Failed to create server: %v", err)
+		}
+		t.Cleanup(func() { server.Shutdown(context.Background()) })
+		return server
+	}
+	server := newServer()
+
+	w, response := doJSON(server, "POST", "/api/v1/services", map[string]interface{}{
+		"url":  "json://localhost/ops",
+		"tags": []string{"ops"},
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200 adding service, got %d: %s", w.Code, w.Body.String())
+	}
+	id, _ := response.Data.(map[string]interface{})["id"].(string)
+	if id == "" {
+		t.Fatalf("Expected the service ID, got %v", response.Data)
+	}
+	if w, _ = doJSON(server, "POST", "/api/v1/services", map[string]string{"url": "unknown://localhost"}); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid URL, got %d", w.Code)
+	}
+
+	// Stored services come back after a restart
+	server = newServer()
+	if server.apprise.CountByTag("ops") != 1 {
+		t.Fatalf("Expected the stored service to be restored, got %d services", server.apprise.Count())
+	}
+	w, response = doJSON(server, "GET", "/api/v1/services/"+id, nil)
+	if w.Code != http.StatusOK || response.Data.(map[string]interface{})["url"] != "json://localhost/ops" {
+		t.Errorf("Unexpected service %d: %s", w.Code, w.Body.String())
+	}
+
+	w, _ = doJSON(server, "PUT", "/api/v1/services/"+id, map[string]interface{}{
+		"url":  "json://localhost/dev",
+		"tags": []string{"dev"},
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200 updating, got %d: %s", w.Code, w.Body.String())
+	}
+	if server.apprise.Count() != 1 || server.apprise.CountByTag("dev") != 1 {
+		t.Errorf("Expected the service to be replaced, got %d services", server.apprise.Count())
+	}
+
+	if w, _ = doJSON(server, "DELETE", "/api/v1/services/"+id, nil); w.Code != http.StatusOK {
+		t.Errorf("Expected 200 deleting, got %d", w.Code)
+	}
+	if server.apprise.Count() != 0 {
+		t.Errorf("Expected no services after deleting, got %d", server.apprise.Count())
+	}
+
+	// Unknown IDs are not found
+	if w, _ = doJSON(server, "DELETE", "/api/v1/services/"+id, nil); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 deleting an unknown service, got %d", w.Code)
+	}
+	if w, _ = doJSON(server, "PUT", "/api/v1/services/svc_missing", map[string]string{"url": "json://localhost"}); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 updating an unknown service, got %d", w.Code)
+	}
+}
+
+func TestSplitURLList(t *testing.T) {
+	urls := splitURLList("json://a, mailto://u:p@host?to=x@y.com,z@y.com  slack://t/c")
+	expected := []string{"json://a", "mailto://u:p@host?to=x@y.com,z@y.com", "slack://t/c"}
+	if strings.Join(urls, " ") != strings.Join(expected, " ") {
+		t.Errorf("Expected %v, got %v", expected, urls)
+	}
+}
+
+// adminToken returns a token for the admin user of a server created with
+// the admin password "admin"
+func adminToken(t *testing.T, server *Server) string {
+	t.Helper()
+	admin, err := server.authenticateUser("admin", "admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, _ := server.CreateToken(admin)
+	return token
+}
+
+// loadConfigAsAdmin asks server to load the configuration file at path with
+// the credentials of the admin user
+func loadConfigAsAdmin(t *testing.T, server *Server, path string) *httptest.ResponseRecorder {
+	t.Helper()
+	w, _ := doJSONAs(server, adminToken(t, server), "POST", "/api/v1/config/load", map[string]string{"path": path})
+	return w
+}
+
+func TestAPIServer_LoadConfigRestrictions(t *testing.T) {
+	configDir := t.TempDir()
+	server, err := NewServer(&ServerConfig{JWTSecret: "test-secret", AdminPassword: "admin", ConfigDir: configDir}, apprise.New(), nil,
+		log.New(io.Discard, "", 0))
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	secret := filepath.Join(t.TempDir(), "secret.txt")
+	if err := os.WriteFile(secret, []byte("top-secret-value\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	invalid := filepath.Join(configDir, "invalid.conf")
+	if err := os.WriteFile(invalid, []byte("top-secret-value\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(secret, filepath.Join(configDir, "link.conf")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Anonymous clients may not load files even with authentication off
+	if w, _ := doJSON(server, "POST", "/api/v1/config/load", map[string]string{"path": invalid}); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for anonymous clients, got %d", w.Code)
+	}
+
+	for _, path := range []string{secret, "../" + filepath.Base(filepath.Dir(secret)) + "/secret.txt", "link.conf"} {
+		if w := loadConfigAsAdmin(t, server, path); w.Code != http.StatusForbidden {
+			t.Errorf("%s: expected 403 outside the configuration directory, got %d", path, w.Code)
+		}
+	}
+
+	w := loadConfigAsAdmin(t, server, "invalid.conf")
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid file, got %d", w.Code)
+	}
+	if strings.Contains(w.Body.String(), "top-secret-value") {
+		t.Errorf("Expected the file contents not to be echoed: %s", w.Body.String())
+	}
+
+	server.config.ConfigDir = ""
+	if w := loadConfigAsAdmin(t, server, invalid); w.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 without a configuration directory, got %d", w.Code)
+	}
+}
+
+func TestAPIServer_NotifyConfiguredServices(t *testing.T) {
+	var received []string
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+	host := strings.TrimPrefix(target.URL, "http://")
+
+	configDir := t.TempDir()
+	server, err := NewServer(&ServerConfig{JWTSecret: "test-secret", AdminPassword: "admin", ConfigDir: configDir}, apprise.New(), nil,
+		log.New(os.Stdout, "[test] ", log.LstdFlags))
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	// Nothing is configured yet
+	w, _ := doJSON(server, "POST", "/api/v1/notify", map[string]interface{}{"body": "hello"})
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 with no services, got %d", w.Code)
+	}
+
+	w, _ = doJSON(server, "POST", "/api/v1/services", map[string]interface{}{
+		"url":  "webhook://" + host + "/ops",
+		"tags": []string{"ops"},
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200 adding service, got %d", w.Code)
+	}
+
+	configPath := filepath.Join(configDir, "apprise.conf")
+	if err := os.WriteFile(configPath, []byte("dev=webhook://"+host+"/dev\n"), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	w = loadConfigAsAdmin(t, server, configPath)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200 loading config, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// The same file, by any path, is only loaded once
+	if w = loadConfigAsAdmin(t, server, "apprise.conf"); w.Code != http.StatusConflict {
+		t.Errorf("Expected 409 loading the config again, got %d", w.Code)
+	}
+	if count := server.apprise.Count(); count != 2 {
+		t.Errorf("Expected 2 services after loading the config twice, got %d", count)
+	}
+
+	w, response := doJSON(server, "POST", "/api/v1/notify", map[string]interface{}{
+		"body": "hello",
+		"tags": []string{"dev"},
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200 notifying, got %d: %s", w.Code, w.Body.String())
+	}
+	if len(received) != 1 || received[0] != "/dev" {
+		t.Errorf("Expected only the dev service to be notified, got %v", received)
+	}
+
+	data := response.Data.(map[string]interface{})
+	results := data["results"].([]interface{})
+	if len(results) != 1 {
+		t.Fatalf("Expected one service result, got %v", results)
+	}
+	result := results[0].(map[string]interface{})
+	if result["service_id"] != "webhook" || result["success"] != true {
+		t.Errorf("Unexpected service result: %v", result)
+	}
+	if _, ok := result["duration_ms"]; !ok {
+		t.Error("Expected service result to include duration_ms")
+	}
+
+	// Without tags every configured service is notified
+	received = nil
+	if w, _ = doJSON(server, "POST", "/api/v1/notify", map[string]interface{}{"body": "all"}); w.Code != http.StatusOK {
+		t.Errorf("Expected 200 notifying all services, got %d", w.Code)
+	}
+	if len(received) != 2 {
+		t.Errorf("Expected both services to be notified, got %v", received)
+	}
+
+	// Explicit URLs are notified whatever the tags
+	received = nil
+	w, _ = doJSON(server, "POST", "/api/v1/notify", map[string]interface{}{
+		"body": "direct",
+		"urls": []string{"webhook://" + host + "/direct"},
+		"tags": []string{"dev"},
+	})
+	if w.Code != http.StatusOK || len(received) != 1 || received[0] != "/direct" {
+		t.Errorf("Expected only the given URL to be notified, got %d %v", w.Code, received)
+	}
+}
+
+func TestAPIServer_AsyncNotify(t *testing.T) {
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer hook.Close()
+	hookURL := "webhook://" + strings.TrimPrefix(hook.URL, "http://") + "/hook"
+
+	server := newSchedulerTestServer(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	if err := server.scheduler.Start(ctx); err != nil {
+		t.Fatalf("Failed to start scheduler: %v", err)
+	}
+
+	w, resp := doJSON(server, "POST", "/api/v1/notify", map[string]interface{}{
+		"body":  "queued",
+		"urls":  []string{hookURL},
+		"async": true,
+	})
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("Expected 202, got %d: %s", w.Code, w.Body.String())
+	}
+	data := resp.Data.(map[string]interface{})
+	statusURL, _ := data["status_url"].(string)
+	if statusURL == "" || w.Header().Get("Location") != statusURL {
+		t.Fatalf("Expected a status URL in the body and Location header, got %v", data)
+	}
+
+	var status NotifyJobStatus
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		w, _ = doJSON(server, "GET", statusURL, nil)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected 200 from the status endpoint, got %d: %s", w.Code, w.Body.String())
+		}
+		var body struct {
+			Data NotifyJobStatus `json:"data"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &body)
+		status = body.Data
+		if status.Status == string(apprise.JobStatusCompleted) || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	if status.Status != string(apprise.JobStatusCompleted) {
+		t.Fatalf("Expected the job to complete, got %+v", status)
+	}
+	if status.Total != 1 || status.Successful != 1 || len(status.Results) != 1 || status.Results[0].ServiceID != "webhook" {
+		t.Errorf("Unexpected per-service results: %+v", status)
+	}
+
+	// The query parameter works too, but attachments cannot be queued
+	w, _ = doJSON(server, "POST", "/api/v1/notify?async=true", map[string]interface{}{
+		"body":        "queued",
+		"urls":        []string{hookURL},
+		"attachments": []string{"aGk="},
+	})
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for queued attachments, got %d", w.Code)
+	}
+
+	if w, _ = doJSON(server, "GET", "/api/v1/notify/jobs/999", nil); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown job, got %d", w.Code)
+	}
+
+	// Without a scheduler there is no queue to add the notification to
+	plain := newAttachmentTestServer(t, &ServerConfig{})
+	w, _ = doJSON(plain, "POST", "/api/v1/notify?async=true", map[string]interface{}{
+		"body": "queued",
+		"urls": []string{hookURL},
+	})
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 without a scheduler, got %d", w.Code)
+	}
+}
+
+func TestAPIServer_BulkNotifyAsync(t *testing.T) {
+	var received int32
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&received, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer hook.Close()
+	hookURL := "webhook://" + strings.TrimPrefix(hook.URL, "http://") + "/hook"
+
+	// The scheduler is not started, so queued entries stay pending
+	server := newSchedulerTestServer(t)
+	w, resp := doJSON(server, "POST", "/api/v1/notify/bulk", map[string]interface{}{
+		"notifications": []map[string]interface{}{
+			{"body": "now", "urls": []string{hookURL}},
+			{"body": "later", "urls": []string{hookURL}, "async": true},
+			{"body": "later", "urls": []string{hookURL}, "async": true, "attachments": []string{"aGk="}},
+		},
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if count := atomic.LoadInt32(&received); count != 1 {
+		t.Errorf("Expected only the synchronous entry to be sent, got %d requests", count)
+	}
+
+	results := resp.Data.(map[string]interface{})["results"].([]interface{})
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, got %v", results)
+	}
+	queued := results[1].(map[string]interface{})
+	statusURL, _ := queued["status_url"].(string)
+	if queued["success"] != true || queued["queued"] != true || statusURL == "" {
+		t.Fatalf("Expected the async entry to be queued, got %v", queued)
+	}
+	if w, _ = doJSON(server, "GET", statusURL, nil); w.Code != http.StatusOK {
+		t.Errorf("Expected 200 from the queued entry's status URL, got %d", w.Code)
+	}
+	if failed := results[2].(map[string]interface{}); failed["success"] != false {
+		t.Errorf("Expected queued attachments to fail, got %v", failed)
+	}
+
+	// Without a scheduler async entries fail on their own
+	plain := newAttachmentTestServer(t, &ServerConfig{})
+	w, resp = doJSON(plain, "POST", "/api/v1/notify/bulk", map[string]interface{}{
+		"notifications": []map[string]interface{}{
+			{"body": "later", "urls": []string{hookURL}, "async": true},
+		},
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", w.Code)
+	}
+	results = resp.Data.(map[string]interface{})["results"].([]interface{})
+	if result := results[0].(map[string]interface{}); result["success"] != false {
+		t.Errorf("Expected the async entry to fail without a scheduler, got %v", result)
+	}
+}
+
+func TestAPIServer_AsyncNotifyStatusAfterRequeue(t *testing.T) {
+	var failing atomic.Bool
+	failing.Store(true)
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer hook.Close()
+
+	server := newSchedulerTestServer(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	if err := server.scheduler.Start(ctx); err != nil {
+		t.Fatalf("Failed to start scheduler: %v", err)
+	}
+
+	w, resp := doJSON(server, "POST", "/api/v1/notify?async=true", map[string]interface{}{
+		"body": "queued",
+		"urls": []string{"webhook://" + strings.TrimPrefix(hook.URL, "http://") + "/hook"},
+	})
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("Expected 202, got %d: %s", w.Code, w.Body.String())
+	}
+	data := resp.Data.(map[string]interface{})
+	statusURL := data["status_url"].(string)
+	jobPath := fmt.Sprintf("/api/v1/scheduler/queue/%d", int64(data["job_id"].(float64)))
+
+	waitFor := func(want apprise.JobStatus) NotifyJobStatus {
+		t.Helper()
+		var body struct {
+			Data NotifyJobStatus `json:"data"`
+		}
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			w, _ := doJSON(server, "GET", statusURL, nil)
+			json.Unmarshal(w.Body.Bytes(), &body)
+			if body.Data.Status == string(want) {
+				return body.Data
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("Expected the job to be %s, got %+v", want, body.Data)
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+	}
+
+	if status := waitFor(apprise.JobStatusRetrying); status.Failed != 1 {
+		t.Fatalf("Expected the first run to fail, got %+v", status)
+	}
+
+	// Requeueing resets the retry count, but the status reports the new run
+	failing.Store(false)
+	if w, _ := doJSON(server, "POST", jobPath+"/retry", nil); w.Code != http.StatusOK {
+		t.Fatalf("Expected 200 from retry, got %d: %s", w.Code, w.Body.String())
+	}
+
+	status := waitFor(apprise.JobStatusCompleted)
+	if status.Total != 1 || status.Successful != 1 || status.Failed != 0 || status.RetryCount != 0 {
+		t.Errorf("Expected only the requeued run to be reported, got %+v", status)
+	}
+}
+
+func TestAPIServer_AsyncNotifyStatusAccess(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test_api_async.db")
+	scheduler, err := apprise.NewNotificationScheduler(dbPath, apprise.New())
+	if err != nil {
+		t.Fatalf("Failed to create scheduler: %v", err)
+	}
+	t.Cleanup(func() { scheduler.Close() })
+
+	config := &ServerConfig{DatabasePath: dbPath, JWTSecret: "test-secret", AdminPassword: "admin", RequireAuth: true}
+	server, err := NewServer(config, apprise.New(), scheduler, nil)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	tokens := make(map[string]string)
+	for _, name := range []string{"ops", "dev", "root"} {
+		roles := []string{"user"}
+		if name == "root" {
+			roles = []string{"admin"}
+		}
+		user := &User{ID: "user_" + name, Username: name, Email: name + "@example.com", Roles: roles, Enabled: true, Created: time.Now()}
+		if err := server.users.CreateUser(user, "secret"); err != nil {
+			t.Fatal(err)
+		}
+		tokens[name], _ = server.CreateToken(user)
+	}
+	do := func(user, method, path string, body interface{}) *httptest.ResponseRecorder {
+		var buf bytes.Buffer
+		if body != nil {
+			json.NewEncoder(&buf).Encode(body)
+		}
+		req := httptest.NewRequest(method, path, &buf)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+tokens[user])
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do("ops", "POST", "/api/v1/notify?async=true", map[string]interface{}{
+		"body":     "queued",
+		"urls":     []string{"webhook://localhost/hook"},
+		"metadata": map[string]string{jobOwnerMetadata: "user_dev"},
+	})
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("Expected 202, got %d: %s", w.Code, w.Body.String())
+	}
+	statusURL := w.Header().Get("Location")
+	jobID, _ := strconv.ParseInt(path.Base(statusURL), 10, 64)
+
+	// A retried job records its service once per attempt
+	for attempt, status := range []string{"failed", "success"} {
+		err := scheduler.GetMetricsCollector().RecordMetrics(apprise.NotificationMetrics{
+			JobID:     &jobID,
+			ServiceID: "webhook",
+			Status:    status,
+			Metadata:  map[string]string{"attempts": "1", "job_run": strconv.Itoa(attempt + 1)},
+			Timestamp: time.Now(),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for user, code := range map[string]int{"ops": http.StatusOK, "root": http.StatusOK, "dev": http.StatusNotFound} {
+		if w := do(user, "GET", statusURL, nil); w.Code != code {
+			t.Errorf("Expected %d for %s, got %d", code, user, w.Code)
+		}
+	}
+
+	// The queue endpoints hide other users' jobs the same way
+	jobPath := fmt.Sprintf("/api/v1/scheduler/queue/%d", jobID)
+	for user, code := range map[string]int{"ops": http.StatusOK, "root": http.StatusOK, "dev": http.StatusNotFound} {
+		if w := do(user, "GET", jobPath, nil); w.Code != code {
+			t.Errorf("get: expected %d for %s, got %d", code, user, w.Code)
+		}
+	}
+	if w := do("dev", "PUT", jobPath, map[string]interface{}{"title": "mine now"}); w.Code != http.StatusNotFound {
+		t.Errorf("update: expected 404 for another user's job, got %d", w.Code)
+	}
+	if w := do("dev", "POST", jobPath+"/retry", nil); w.Code != http.StatusNotFound {
+		t.Errorf("retry: expected 404 for another user's job, got %d", w.Code)
+	}
+	var list struct {
+		Data struct {
+			Jobs []apprise.QueuedJob `json:"jobs"`
+		} `json:"data"`
+	}
+	json.Unmarshal(do("dev", "GET", "/api/v1/scheduler/queue", nil).Body.Bytes(), &list)
+	if len(list.Data.Jobs) != 0 {
+		t.Errorf("list: expected no jobs for another user, got %d", len(list.Data.Jobs))
+	}
+
+	// Owners cannot hand their jobs to someone else by editing metadata
+	if w := do("ops", "PUT", jobPath, map[string]interface{}{"metadata": map[string]string{jobOwnerMetadata: "user_dev"}}); w.Code != http.StatusOK {
+		t.Errorf("update: expected 200 for the owner, got %d", w.Code)
+	}
+	if w := do("dev", "GET", jobPath, nil); w.Code != http.StatusNotFound {
+		t.Errorf("get: expected the job to keep its owner, got %d", w.Code)
+	}
+
+	for user, code := range map[string]int{"ops": http.StatusForbidden, "root": http.StatusOK} {
+		if w := do(user, "GET", "/api/v1/scheduler/queue/dead-letter", nil); w.Code != code {
+			t.Errorf("dead-letter: expected %d for %s, got %d", code, user, w.Code)
+		}
+	}
+
+	var body struct {
+		Data NotifyJobStatus `json:"data"`
+	}
+	json.Unmarshal(do("ops", "GET", statusURL, nil).Body.Bytes(), &body)
+	if status := body.Data; status.Total != 1 || status.Successful != 1 || status.Failed != 0 || len(status.Results) != 1 {
+		t.Errorf("Expected only the latest attempt to be reported, got %+v", status)
+	}
+}
@@ -0,0 +1,346 @@
+package api
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	// ErrUserNotFound is returned when no user matches the lookup
+	ErrUserNotFound = errors.New("user not found")
+	// ErrUserExists is returned when registering a username that is taken
+	ErrUserExists = errors.New("user already exists")
+	// ErrInvalidCredentials is returned when a username or password is wrong
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	// ErrUserDisabled is returned when authenticating as a disabled user
+	ErrUserDisabled = errors.New("user is disabled")
+	// ErrAPIKeyNotFound is returned when an API key does not exist
+	ErrAPIKeyNotFound = errors.New("API key not found")
+	// ErrAPIKeyExpired is returned when authenticating with an expired API key
+	ErrAPIKeyExpired = errors.New("API key has expired")
+)
+
+// UserStore persists API users and API keys in SQLite. Passwords are stored
+// as bcrypt hashes and API keys as SHA-256 digests, so neither can be
+// recovered from the database.
+type UserStore struct {
+	db *sql.DB
+}
+
+// APIKeyRecord is a stored API key. The key itself is never stored.
+type APIKeyRecord struct {
+	ID          string
+	UserID      string
+	Name        string
+	Description string
+	Prefix      string
+	Scopes      []string
+	ExpiresAt   *time.Time
+	Created     time.Time
+	LastUsed    *time.Time
+}
+
+// NewUserStore creates a user store on db, creating its tables if needed
+func NewUserStore(db *sql.DB) (*UserStore, error) {
+	if err := initUserSchema(db); err != nil {
+		return nil, fmt.Errorf("failed to initialize user schema: %w", err)
+	}
+	return &UserStore{db: db}, nil
+}
+
+// initUserSchema creates the user and API key tables
+func initUserSchema(db *sql.DB) error {
+	createUsersTable := `
+	CREATE TABLE IF NOT EXISTS api_users (
+		id TEXT PRIMARY KEY,
+		username TEXT NOT NULL UNIQUE,
+		email TEXT NOT NULL DEFAULT '',
+		password_hash TEXT NOT NULL,
+		roles TEXT NOT NULL DEFAULT '[]',
+		enabled BOOLEAN NOT NULL DEFAULT true,
+		created_at DATETIME NOT NULL,
+		last_seen DATETIME
+	);`
+
+	createAPIKeysTable := `
+	CREATE TABLE IF NOT EXISTS api_keys (
+		id TEXT PRIMARY KEY,
+		user_id TEXT NOT NULL,
+		name TEXT NOT NULL,
+		description TEXT NOT NULL DEFAULT '',
+		key_hash TEXT NOT NULL UNIQUE,
+		key_prefix TEXT NOT NULL,
+		scopes TEXT NOT NULL DEFAULT '[]',
+		expires_at DATETIME,
+		created_at DATETIME NOT NULL,
+		last_used_at DATETIME,
+		FOREIGN KEY (user_id) REFERENCES api_users(id) ON DELETE CASCADE
+	);`
+
+	queries := []string{
+		createUsersTable,
+		createAPIKeysTable,
+		`CREATE INDEX IF NOT EXISTS idx_api_keys_user ON api_keys(user_id);`,
+	}
+
+	for _, query := range queries {
+		if _, err := db.Exec(query); err != nil {
+			return fmt.Errorf("failed to create table: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// CreateUser stores a new user with a bcrypt hash of password
+func (us *UserStore) CreateUser(user *User, password string) error {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	rolesJSON, _ := json.Marshal(user.Roles)
+
+	query := `INSERT INTO api_users (id, username, email, password_hash, roles, enabled, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	_, err = us.db.Exec(query, user.ID, user.Username, user.Email, string(hash),
+		string(rolesJSON), user.Enabled, user.Created)
+	if err != nil {
+		var sqliteErr sqlite3.Error
+		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+			return fmt.Errorf("user '%s': %w", user.Username, ErrUserExists)
+		}
+		return fmt.Errorf("failed to insert user: %w", err)
+	}
+
+	return nil
+}
+
+// Authenticate verifies a username and password and returns the user
+func (us *UserStore) Authenticate(username, password string) (*User, error) {
+	user, hash, err := us.getUser(`WHERE username = ?`, username)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			// Compare against a dummy hash so unknown usernames take as
+			// long as wrong passwords
+			_ = bcrypt.CompareHashAndPassword(dummyPasswordHash(), []byte(password))
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+	if !user.Enabled {
+		return nil, ErrUserDisabled
+	}
+
+	return user, nil
+}
+
+// GetUser returns a user by ID
+func (us *UserStore) GetUser(userID string) (*User, error) {
+	user, _, err := us.getUser(`WHERE id = ?`, userID)
+	return user, err
+}
+
+// UserExists reports whether a username is taken
+func (us *UserStore) UserExists(username string) (bool, error) {
+	var count int
+	if err := us.db.QueryRow(`SELECT COUNT(*) FROM api_users WHERE username = ?`, username).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed to query users: %w", err)
+	}
+	return count > 0, nil
+}
+
+// CountUsers returns the number of stored users
+func (us *UserStore) CountUsers() (int, error) {
+	var count int
+	if err := us.db.QueryRow(`SELECT COUNT(*) FROM api_users`).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count users: %w", err)
+	}
+	return count, nil
+}
+
+// TouchUser records that a user has just logged in
+func (us *UserStore) TouchUser(userID string, seen time.Time) error {
+	if _, err := us.db.Exec(`UPDATE api_users SET last_seen = ? WHERE id = ?`, seen, userID); err != nil {
+		return fmt.Errorf("failed to update last seen: %w", err)
+	}
+	return nil
+}
+
+// CreateAPIKey stores the hash of key for the record's user
+func (us *UserStore) CreateAPIKey(record *APIKeyRecord, key string) error {
+	scopesJSON, _ := json.Marshal(record.Scopes)
+
+	query := `INSERT INTO api_keys (id, user_id, name, description, key_hash, key_prefix, scopes, expires_at, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := us.db.Exec(query, record.ID, record.UserID, record.Name, record.Description,
+		hashAPIKey(key), record.Prefix, string(scopesJSON), record.ExpiresAt, record.Created)
+	if err != nil {
+		return fmt.Errorf("failed to insert API key: %w", err)
+	}
+
+	return nil
+}
+
+// ValidateAPIKey looks up key, checks its expiry and owner, records its use
+// and returns the owning user along with the key record
+func (us *UserStore) ValidateAPIKey(key string) (*User, *APIKeyRecord, error) {
+	query := `SELECT id, user_id, name, description, key_prefix, scopes, expires_at, created_at, last_used_at
+			  FROM api_keys WHERE key_hash = ?`
+
+	record, err := us.scanAPIKey(us.db.QueryRow(query, hashAPIKey(key)))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	if record.ExpiresAt != nil && now.After(*record.ExpiresAt) {
+		return nil, nil, ErrAPIKeyExpired
+	}
+
+	user, err := us.GetUser(record.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !user.Enabled {
+		return nil, nil, ErrUserDisabled
+	}
+
+	if _, err := us.db.Exec(`UPDATE api_keys SET last_used_at = ? WHERE id = ?`, now, record.ID); err != nil {
+		return nil, nil, fmt.Errorf("failed to update API key usage: %w", err)
+	}
+	record.LastUsed = &now
+
+	return user, record, nil
+}
+
+// ListAPIKeys returns the API keys owned by a user
+func (us *UserStore) ListAPIKeys(userID string) ([]*APIKeyRecord, error) {
+	query := `SELECT id, user_id, name, description, key_prefix, scopes, expires_at, created_at, last_used_at
+			  FROM api_keys WHERE user_id = ? ORDER BY created_at`
+
+	rows, err := us.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query API keys: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	records := []*APIKeyRecord{}
+	for rows.Next() {
+		record, err := us.scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+
+	return records, rows.Err()
+}
+
+// DeleteAPIKey removes an API key owned by a user
+func (us *UserStore) DeleteAPIKey(userID, keyID string) error {
+	result, err := us.db.Exec(`DELETE FROM api_keys WHERE id = ? AND user_id = ?`, keyID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete API key: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("key '%s': %w", keyID, ErrAPIKeyNotFound)
+	}
+
+	return nil
+}
+
+// getUser loads a single user matching where, returning the password hash
+// alongside it
+func (us *UserStore) getUser(where string, args ...interface{}) (*User, string, error) {
+	query := `SELECT id, username, email, password_hash, roles, enabled, created_at, last_seen
+			  FROM api_users ` + where
+
+	var user User
+	var hash, rolesJSON string
+	var lastSeen sql.NullTime
+
+	err := us.db.QueryRow(query, args...).Scan(&user.ID, &user.Username, &user.Email, &hash,
+		&rolesJSON, &user.Enabled, &user.Created, &lastSeen)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, "", ErrUserNotFound
+		}
+		return nil, "", fmt.Errorf("failed to scan user: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(rolesJSON), &user.Roles); err != nil {
+		return nil, "", fmt.Errorf("failed to parse roles: %w", err)
+	}
+	if lastSeen.Valid {
+		user.LastSeen = &lastSeen.Time
+	}
+
+	return &user, hash, nil
+}
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanAPIKey scans an API key record
+func (us *UserStore) scanAPIKey(row rowScanner) (*APIKeyRecord, error) {
+	var record APIKeyRecord
+	var scopesJSON string
+	var expiresAt, lastUsed sql.NullTime
+
+	err := row.Scan(&record.ID, &record.UserID, &record.Name, &record.Description, &record.Prefix,
+		&scopesJSON, &expiresAt, &record.Created, &lastUsed)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrAPIKeyNotFound
+		}
+		return nil, fmt.Errorf("failed to scan API key: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(scopesJSON), &record.Scopes); err != nil {
+		return nil, fmt.Errorf("failed to parse scopes: %w", err)
+	}
+	if expiresAt.Valid {
+		record.ExpiresAt = &expiresAt.Time
+	}
+	if lastUsed.Valid {
+		record.LastUsed = &lastUsed.Time
+	}
+
+	return &record, nil
+}
+
+// dummyPasswordHash is compared against when a username does not exist
+var dummyPasswordHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("apprise-dummy-password"), bcrypt.DefaultCost)
+	return hash
+})
+
+// hashAPIKey returns the stored digest of an API key. Keys carry 256 bits
+// of randomness, so a fast hash is sufficient and keeps lookups cheap.
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
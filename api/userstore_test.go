@@ -0,0 +1,71 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestUserStore_PersistsUsers(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "users.db")
+
+	db, err := sql.Open("sqlite3", dbPath+"?_foreign_keys=on")
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	store, err := NewUserStore(db)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+
+	user := &User{ID: generateUserID(), Username: "alice", Roles: []string{"user"}, Enabled: true, Created: time.Now()}
+	if err := store.CreateUser(user, "hunter2"); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	if err := store.CreateUser(user, "hunter2"); !errors.Is(err, ErrUserExists) {
+		t.Errorf("Expected ErrUserExists, got %v", err)
+	}
+
+	key := GenerateAPIKey()
+	if err := store.CreateAPIKey(&APIKeyRecord{ID: generateKeyID(), UserID: user.ID, Name: "ci", Prefix: key[:10], Created: time.Now()}, key); err != nil {
+		t.Fatalf("Failed to create API key: %v", err)
+	}
+	db.Close()
+
+	// Reopen the database to make sure everything was persisted
+	db, err = sql.Open("sqlite3", dbPath+"?_foreign_keys=on")
+	if err != nil {
+		t.Fatalf("Failed to reopen database: %v", err)
+	}
+	defer db.Close()
+	store, err = NewUserStore(db)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+
+	var hash string
+	if err := db.QueryRow(`SELECT password_hash FROM api_users WHERE username = ?`, "alice").Scan(&hash); err != nil || hash == "hunter2" {
+		t.Errorf("Password should be stored hashed, got %q (%v)", hash, err)
+	}
+
+	if _, err := store.Authenticate("alice", "wrong"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Expected ErrInvalidCredentials, got %v", err)
+	}
+	if _, err := store.Authenticate("nobody", "hunter2"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Expected ErrInvalidCredentials for unknown user, got %v", err)
+	}
+	if _, err := store.Authenticate("alice", "hunter2"); err != nil {
+		t.Errorf("Expected persisted user to authenticate: %v", err)
+	}
+
+	found, record, err := store.ValidateAPIKey(key)
+	if err != nil || found.Username != "alice" || record.LastUsed == nil {
+		t.Errorf("Expected persisted API key to validate, got %v %+v (%v)", found, record, err)
+	}
+
+	if err := store.DeleteAPIKey("someone-else", record.ID); !errors.Is(err, ErrAPIKeyNotFound) {
+		t.Errorf("Users should not delete keys they do not own, got %v", err)
+	}
+}
@@ -3,27 +3,55 @@ package apprise
 import (
 	"bytes"
 	"context"
+	"crypto/ecdsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// APNs provider API hosts
+const (
+	apnsProductionHost = "api.push.apple.com"
+	apnsSandboxHost    = "api.sandbox.push.apple.com"
 )
 
+// apnsTokenLifetime is how long a provider token is reused. APNs rejects
+// tokens older than an hour and throttles tokens refreshed more often than
+// every 20 minutes.
+const apnsTokenLifetime = 50 * time.Minute
+
 // APNSService implements Apple Push Notification Service for iOS notifications
 type APNSService struct {
-	keyID           string // Key ID for JWT authentication (.p8 key)
-	teamID          string // Team ID (App Store Connect)
-	bundleID        string // App bundle identifier
-	keyPath         string // Path to .p8 private key file
-	certificatePath string // Path to .p12 certificate (alternative auth)
-	certificatePass string // Password for .p12 certificate
-	environment     string // "production" or "sandbox"
-	webhookURL      string // Webhook proxy URL for secure credential management
-	apiKey          string // API key for webhook authentication
+	keyID           string           // Key ID for JWT authentication (.p8 key)
+	teamID          string           // Team ID (App Store Connect)
+	bundleID        string           // App bundle identifier
+	keyPath         string           // Path to .p8 private key file
+	certificatePath string           // Path to .p12 certificate (alternative auth)
+	certificatePass string           // Password for .p12 certificate
+	environment     string           // "production" or "sandbox"
+	webhookURL      string           // Webhook proxy URL for secure credential management
+	apiKey          string           // API key for webhook authentication
+	deviceTokens    []string         // Device tokens pushed to directly; empty for webhook mode
+	apiURL          string           // APNs provider API base URL in direct mode
+	pushType        string           // apns-push-type: "alert" or "background"
+	priority        string           // apns-priority override
+	collapseID      string           // apns-collapse-id override
+	expiration      *time.Duration   // apns-expiration override, relative to the send time
+	tokens          *apnsTokenSource // Provider token source for JWT authentication
 	client          *http.Client
 }
 
@@ -117,28 +145,19 @@ func (a *APNSService) GetDefaultPort() int {
 	return 443
 }
 
-// ParseURL parses an Apple Push Notification service URL
+// ParseURL parses an Apple Push Notification service URL. Using an APNs
+// provider host pushes straight to the device tokens in the path; any other
+// host is a webhook proxy.
 // Format: apns://webhook.example.com/apns?key_id=KEY&team_id=TEAM&bundle_id=com.app&key_path=path/to/key.p8
 // Format: apns://api-key@webhook.example.com/proxy?team_id=TEAM&bundle_id=com.app&cert_path=cert.p12&cert_pass=pass
 // Format: apns://webhook.example.com/apns?bundle_id=com.app&environment=sandbox&key_id=KEY&team_id=TEAM
+// Format: apns://api.push.apple.com/TOKEN1/TOKEN2?bundle_id=com.app&key_id=KEY&team_id=TEAM&key_path=AuthKey.p8
+// Format: apns://api.sandbox.push.apple.com/TOKEN?bundle_id=com.app&cert_path=cert.p12&cert_pass=pass
 func (a *APNSService) ParseURL(serviceURL *url.URL) error {
 	if serviceURL.Scheme != "apns" {
 		return fmt.Errorf("invalid scheme: expected 'apns', got '%s'", serviceURL.Scheme)
 	}
 
-	// Extract webhook URL components
-	scheme := "https"
-	if serviceURL.Scheme == "apns" && strings.Contains(serviceURL.Host, "127.0.0.1") {
-		// Test mode: use HTTP for localhost
-		scheme = "http"
-	}
-	a.webhookURL = fmt.Sprintf("%s://%s%s", scheme, serviceURL.Host, serviceURL.Path)
-
-	// Extract API key from user info
-	if serviceURL.User != nil {
-		a.apiKey = serviceURL.User.Username()
-	}
-
 	// Parse query parameters
 	query := serviceURL.Query()
 
@@ -175,11 +194,117 @@ func (a *APNSService) ParseURL(serviceURL *url.URL) error {
 		return fmt.Errorf("key_path is required when using JWT authentication")
 	}
 
+	if err := a.parseHeaderOptions(query); err != nil {
+		return err
+	}
+
+	host := strings.ToLower(serviceURL.Hostname())
+	if host == apnsProductionHost || host == apnsSandboxHost {
+		if host == apnsSandboxHost {
+			a.environment = "sandbox"
+		}
+		return a.parseDirectURL(serviceURL, hasJWT)
+	}
+
+	// Extract webhook URL components
+	scheme := "https"
+	if strings.Contains(serviceURL.Host, "127.0.0.1") {
+		// Test mode: use HTTP for localhost
+		scheme = "http"
+	}
+	a.webhookURL = fmt.Sprintf("%s://%s%s", scheme, serviceURL.Host, serviceURL.Path)
+
+	// Extract API key from user info
+	if serviceURL.User != nil {
+		a.apiKey = serviceURL.User.Username()
+	}
+
+	return nil
+}
+
+// parseHeaderOptions reads the optional overrides of the apns-* headers
+func (a *APNSService) parseHeaderOptions(query url.Values) error {
+	a.pushType = "alert"
+	if pushType := query.Get("push_type"); pushType != "" {
+		if pushType != "alert" && pushType != "background" {
+			return fmt.Errorf("push_type must be 'alert' or 'background', got '%s'", pushType)
+		}
+		a.pushType = pushType
+	}
+
+	if priority := query.Get("priority"); priority != "" {
+		if priority != "10" && priority != "5" && priority != "1" {
+			return fmt.Errorf("priority must be 10, 5 or 1, got '%s'", priority)
+		}
+		a.priority = priority
+	}
+
+	if collapseID := query.Get("collapse_id"); collapseID != "" {
+		if len(collapseID) > 64 {
+			return fmt.Errorf("collapse_id must not exceed 64 bytes")
+		}
+		a.collapseID = collapseID
+	}
+
+	if expiration := query.Get("expiration"); expiration != "" {
+		seconds, err := strconv.Atoi(expiration)
+		if err != nil || seconds < 0 {
+			return fmt.Errorf("expiration must be a number of seconds, got '%s'", expiration)
+		}
+		ttl := time.Duration(seconds) * time.Second
+		a.expiration = &ttl
+	}
+
 	return nil
 }
 
-// Send sends a push notification via Apple Push Notification Service
+// parseDirectURL configures pushing to the device tokens in the URL path
+// with the APNs provider API, loading the .p8 key or .p12 certificate
+func (a *APNSService) parseDirectURL(serviceURL *url.URL, hasJWT bool) error {
+	for _, token := range strings.Split(strings.Trim(serviceURL.Path, "/"), "/") {
+		if token == "" {
+			continue
+		}
+		if _, err := hex.DecodeString(token); err != nil {
+			return fmt.Errorf("invalid device token '%s': must be hexadecimal", token)
+		}
+		a.deviceTokens = append(a.deviceTokens, strings.ToLower(token))
+	}
+	if len(a.deviceTokens) == 0 {
+		return fmt.Errorf("at least one device token is required")
+	}
+
+	host := apnsProductionHost
+	if a.environment == "sandbox" {
+		host = apnsSandboxHost
+	}
+	a.apiURL = "https://" + host
+
+	if hasJWT {
+		key, err := loadAPNSKey(a.keyPath)
+		if err != nil {
+			return err
+		}
+		a.tokens = newAPNSTokenSource(a.keyID, a.teamID, key)
+		return nil
+	}
+
+	certificate, err := loadAPNSCertificate(a.certificatePath, a.certificatePass)
+	if err != nil {
+		return err
+	}
+	a.client = newAPNSClient(certificate, nil)
+	return nil
+}
+
+// Send sends a push notification via Apple Push Notification Service. In
+// direct mode each device token gets its own request; failures are reported
+// per token in an *APNSSendError.
 func (a *APNSService) Send(ctx context.Context, req NotificationRequest) error {
+	if a.apiURL != "" {
+		return a.sendDirect(ctx, req)
+	}
+
 	// APNS requires device tokens to be specified in the notification request
 	// Since we use webhook proxy, we'll send the complete configuration
 	// The webhook service will handle device token management
@@ -295,15 +420,39 @@ func (a *APNSService) createAuthentication() APNSAuthentication {
 
 // createHeaders creates APNS HTTP headers
 func (a *APNSService) createHeaders(req NotificationRequest) map[string]string {
+	pushType := a.pushType
+	if pushType == "" {
+		pushType = "alert"
+	}
+
+	priority := a.getPriorityForNotifyType(req.NotifyType)
+	if pushType == "background" {
+		// Background pushes must not use high priority
+		priority = "5"
+	}
+	if a.priority != "" {
+		priority = a.priority
+	}
+
+	expiration := strconv.FormatInt(time.Now().Add(24*time.Hour).Unix(), 10)
+	if a.expiration != nil {
+		expiration = "0" // Deliver once, without storing
+		if *a.expiration > 0 {
+			expiration = strconv.FormatInt(time.Now().Add(*a.expiration).Unix(), 10)
+		}
+	}
+
 	headers := map[string]string{
 		"apns-topic":      a.bundleID,
-		"apns-priority":   a.getPriorityForNotifyType(req.NotifyType),
-		"apns-expiration": strconv.FormatInt(time.Now().Add(24*time.Hour).Unix(), 10),
-		"apns-push-type":  "alert",
+		"apns-priority":   priority,
+		"apns-expiration": expiration,
+		"apns-push-type":  pushType,
 	}
 
 	// Add collapse ID for notification grouping
-	if req.NotifyType != NotifyTypeInfo {
+	if a.collapseID != "" {
+		headers["apns-collapse-id"] = a.collapseID
+	} else if req.NotifyType != NotifyTypeInfo {
 		headers["apns-collapse-id"] = fmt.Sprintf("apprise-%s", req.NotifyType.String())
 	}
 
@@ -346,12 +495,320 @@ func (a *APNSService) sendViaWebhook(ctx context.Context, apnsReq APNSRequest) e
 	// Check response status
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("APNS API error (status %d): %s", resp.StatusCode, string(body))
+		return NewHTTPError(resp, fmt.Errorf("APNS API error (status %d): %s", resp.StatusCode, string(body)))
 	}
 
 	return nil
 }
 
+// APNSDeliveryResult is the outcome of a direct push to one device token
+type APNSDeliveryResult struct {
+	DeviceToken  string
+	APNSID       string    // apns-id APNs assigned to the notification
+	StatusCode   int       // HTTP status, 0 if no response was received
+	Reason       string    // APNs error reason such as "BadDeviceToken"
+	Unregistered time.Time // When the token stopped being valid, for "Unregistered"
+	Err          error     // Classified error, nil if the push was accepted
+}
+
+// APNSSendError reports the device tokens a direct push failed for. Results
+// holds the outcome for every device token, in URL order.
+type APNSSendError struct {
+	Results []APNSDeliveryResult
+}
+
+// Failed returns the results of the device tokens the push failed for
+func (e *APNSSendError) Failed() []APNSDeliveryResult {
+	var failed []APNSDeliveryResult
+	for _, result := range e.Results {
+		if result.Err != nil {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+// Error implements the error interface
+func (e *APNSSendError) Error() string {
+	failed := e.Failed()
+	reasons := make([]string, len(failed))
+	for i, result := range failed {
+		reasons[i] = fmt.Sprintf("%s: %v", result.DeviceToken, result.Err)
+	}
+	return fmt.Sprintf("APNS push failed for %d of %d device tokens: %s", len(failed), len(e.Results), strings.Join(reasons, "; "))
+}
+
+// apnsReasonClasses maps APNs error reasons to error classes where the HTTP
+// status alone is not precise enough
+var apnsReasonClasses = map[string]ErrorClass{
+	"BadDeviceToken":              ErrorClassInvalidRecipient,
+	"DeviceTokenNotForTopic":      ErrorClassInvalidRecipient,
+	"Unregistered":                ErrorClassInvalidRecipient,
+	"BadCertificate":              ErrorClassAuth,
+	"BadCertificateEnvironment":   ErrorClassAuth,
+	"ExpiredProviderToken":        ErrorClassAuth,
+	"InvalidProviderToken":        ErrorClassAuth,
+	"MissingProviderToken":        ErrorClassAuth,
+	"Forbidden":                   ErrorClassAuth,
+	"TooManyRequests":             ErrorClassRateLimited,
+	"TooManyProviderTokenUpdates": ErrorClassRateLimited,
+	"PayloadTooLarge":             ErrorClassPayloadTooLarge,
+	"InternalServerError":         ErrorClassRemote5xx,
+	"ServiceUnavailable":          ErrorClassRemote5xx,
+	"Shutdown":                    ErrorClassRemote5xx,
+}
+
+// sendDirect pushes the notification to every device token with the APNs
+// provider API
+func (a *APNSService) sendDirect(ctx context.Context, req NotificationRequest) error {
+	payload, err := json.Marshal(a.createDirectPayload(req))
+	if err != nil {
+		return fmt.Errorf("failed to marshal APNS payload: %w", err)
+	}
+	if len(payload) > a.GetMaxBodyLength() {
+		return NewNotificationError(ErrorClassPayloadTooLarge, 0,
+			fmt.Errorf("APNS payload of %d bytes exceeds the %d byte limit", len(payload), a.GetMaxBodyLength()))
+	}
+
+	headers := a.createHeaders(req)
+	// Threads are set in the payload; APNs has no such header
+	delete(headers, "apns-thread-id")
+
+	results := make([]APNSDeliveryResult, len(a.deviceTokens))
+	for i, token := range a.deviceTokens {
+		results[i] = a.pushToDevice(ctx, token, payload, headers)
+	}
+
+	sendErr := &APNSSendError{Results: results}
+	failed := sendErr.Failed()
+	if len(failed) == 0 {
+		return nil
+	}
+
+	failures := make([]error, len(failed))
+	for i, result := range failed {
+		failures[i] = result.Err
+	}
+	return newPartialFailureError(sendErr, failures, len(results))
+}
+
+// createDirectPayload creates the body posted to the provider API, with the
+// custom data next to the aps dictionary
+func (a *APNSService) createDirectPayload(req NotificationRequest) map[string]interface{} {
+	payload := a.createPayload(req)
+
+	body := make(map[string]interface{}, len(payload.Data)+1)
+	for key, value := range payload.Data {
+		body[key] = value
+	}
+
+	aps := payload.APS
+	if a.pushType == "background" {
+		// Background pushes wake the app without alerting the user
+		aps = &APSPayload{ContentAvailable: 1}
+	} else {
+		aps.ThreadID = fmt.Sprintf("apprise-%s", a.bundleID)
+	}
+	body["aps"] = aps
+
+	return body
+}
+
+// pushToDevice pushes payload to one device token. A rejected provider
+// token is replaced and the push tried once more.
+func (a *APNSService) pushToDevice(ctx context.Context, deviceToken string, payload []byte, headers map[string]string) APNSDeliveryResult {
+	result, providerToken := a.postToDevice(ctx, deviceToken, payload, headers)
+	if result.Reason == "ExpiredProviderToken" && a.tokens != nil {
+		a.tokens.invalidate(providerToken)
+		result, _ = a.postToDevice(ctx, deviceToken, payload, headers)
+	}
+	return result
+}
+
+// postToDevice makes one provider API request and returns its result along
+// with the provider token it used
+func (a *APNSService) postToDevice(ctx context.Context, deviceToken string, payload []byte, headers map[string]string) (APNSDeliveryResult, string) {
+	result := APNSDeliveryResult{DeviceToken: deviceToken}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", a.apiURL+"/3/device/"+deviceToken, bytes.NewReader(payload))
+	if err != nil {
+		result.Err = fmt.Errorf("failed to create APNS request: %w", err)
+		return result, ""
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("User-Agent", GetUserAgent())
+	for name, value := range headers {
+		httpReq.Header.Set(name, value)
+	}
+
+	var providerToken string
+	if a.tokens != nil {
+		providerToken, err = a.tokens.get(time.Now())
+		if err != nil {
+			result.Err = err
+			return result, ""
+		}
+		httpReq.Header.Set("Authorization", "bearer "+providerToken)
+	}
+
+	resp, err := a.client.Do(httpReq)
+	if err != nil {
+		result.Err = fmt.Errorf("failed to send APNS notification: %w", err)
+		return result, providerToken
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	result.StatusCode = resp.StatusCode
+	result.APNSID = resp.Header.Get("apns-id")
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return result, providerToken
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	var apnsErr struct {
+		Reason    string `json:"reason"`
+		Timestamp int64  `json:"timestamp"`
+	}
+	_ = json.Unmarshal(body, &apnsErr)
+
+	result.Reason = apnsErr.Reason
+	if apnsErr.Timestamp > 0 {
+		result.Unregistered = time.UnixMilli(apnsErr.Timestamp)
+	}
+
+	reason := apnsErr.Reason
+	if reason == "" {
+		reason = string(body)
+	}
+	notifyErr := NewHTTPError(resp, fmt.Errorf("APNS API error (status %d): %s", resp.StatusCode, reason))
+	if class, ok := apnsReasonClasses[apnsErr.Reason]; ok {
+		notifyErr.Class = class
+		notifyErr.Retryable = class.Retryable()
+	}
+	result.Err = notifyErr
+
+	return result, providerToken
+}
+
+// apnsTokenSource signs provider authentication tokens with a .p8 key and
+// reuses each token until it is close to expiring
+type apnsTokenSource struct {
+	keyID  string
+	teamID string
+	key    *ecdsa.PrivateKey
+
+	mu       sync.Mutex
+	token    string
+	issuedAt time.Time
+}
+
+// newAPNSTokenSource creates a provider token source for the key
+func newAPNSTokenSource(keyID, teamID string, key *ecdsa.PrivateKey) *apnsTokenSource {
+	return &apnsTokenSource{keyID: keyID, teamID: teamID, key: key}
+}
+
+// get returns the current provider token, signing a new one when there is
+// none or it is older than apnsTokenLifetime
+func (s *apnsTokenSource) get(now time.Time) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && now.Sub(s.issuedAt) < apnsTokenLifetime {
+		return s.token, nil
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"iss": s.teamID,
+		"iat": now.Unix(),
+	})
+	token.Header["kid"] = s.keyID
+
+	signed, err := token.SignedString(s.key)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign APNS provider token: %w", err)
+	}
+
+	s.token = signed
+	s.issuedAt = now
+	return signed, nil
+}
+
+// invalidate discards token so the next request signs a new one. Tokens
+// already replaced by another request are left alone.
+func (s *apnsTokenSource) invalidate(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token == token {
+		s.token = ""
+	}
+}
+
+// loadAPNSKey reads the ES256 signing key from a .p8 file
+func loadAPNSKey(path string) (*ecdsa.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read APNS key: %w", err)
+	}
+
+	key, err := jwt.ParseECPrivateKeyFromPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid APNS key %s: %w", path, err)
+	}
+	return key, nil
+}
+
+// loadAPNSCertificate reads the client certificate and key from a .p12 file
+func loadAPNSCertificate(path, password string) (tls.Certificate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to read APNS certificate: %w", err)
+	}
+
+	key, leaf, chain, err := pkcs12.DecodeChain(data, password)
+	if err != nil {
+		if errors.Is(err, pkcs12.ErrIncorrectPassword) {
+			return tls.Certificate{}, fmt.Errorf("invalid APNS certificate %s: incorrect cert_pass", path)
+		}
+		return tls.Certificate{}, fmt.Errorf("invalid APNS certificate %s: %w", path, err)
+	}
+
+	certificate := tls.Certificate{
+		Certificate: [][]byte{leaf.Raw},
+		PrivateKey:  key,
+		Leaf:        leaf,
+	}
+	for _, cert := range chain {
+		certificate.Certificate = append(certificate.Certificate, cert.Raw)
+	}
+	return certificate, nil
+}
+
+// newAPNSClient creates an HTTP/2 client authenticating with certificate.
+// rootCAs may be nil to trust the system roots.
+func newAPNSClient(certificate tls.Certificate, rootCAs *x509.CertPool) *http.Client {
+	config := CloudHTTPClientConfig()
+
+	transport := &http.Transport{
+		MaxIdleConnsPerHost: config.MaxIdleConnsPerHost,
+		IdleConnTimeout:     config.IdleConnTimeout,
+		TLSHandshakeTimeout: config.TLSHandshakeTimeout,
+		TLSClientConfig: &tls.Config{
+			Certificates: []tls.Certificate{certificate},
+			RootCAs:      rootCAs,
+			MinVersion:   tls.VersionTLS12,
+		},
+		// APNs only speaks HTTP/2
+		ForceAttemptHTTP2: true,
+	}
+
+	return &http.Client{
+		Transport: &endpointTransport{next: transport},
+		Timeout:   config.Timeout,
+	}
+}
+
 // Helper methods for APNS configuration
 
 func (a *APNSService) getSoundForNotifyType(notifyType NotifyType) interface{} {
@@ -452,5 +909,7 @@ func (a *APNSService) GetMaxBodyLength() int {
 
 // Example usage and URL formats:
 // apns://webhook.example.com/apns?key_id=ABC123&team_id=DEF456&bundle_id=com.example.app&key_path=path/to/AuthKey.p8
+// apns://api.push.apple.com/DEVICE_TOKEN?key_id=ABC123&team_id=DEF456&bundle_id=com.example.app&key_path=AuthKey.p8&priority=10
+// apns://api.sandbox.push.apple.com/TOKEN1/TOKEN2?bundle_id=com.example.app&cert_path=cert.p12&cert_pass=password
 // apns://api-key@webhook.example.com/proxy?bundle_id=com.example.app&cert_path=cert.p12&cert_pass=password
 // apns://webhook.example.com/apns?bundle_id=com.example.app&environment=sandbox&key_id=KEY&team_id=TEAM&key_path=path/to/key.p8
@@ -2,13 +2,28 @@ package apprise
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"software.sslmate.com/src/go-pkcs12"
 )
 
 func TestAPNSService_GetServiceID(t *testing.T) {
@@ -625,3 +640,303 @@ func TestAPNSService_WithAttachments(t *testing.T) {
 		t.Errorf("Expected MIME type 'image/jpeg', got '%s'", attachmentList[0]["mime_type"])
 	}
 }
+
+// answerAPNS returns a handler standing in for the APNs provider API. respond
+// picks the status and reason for each device token; an empty reason
+// accepts the push.
+func answerAPNS(respond func(deviceToken string) (int, string)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		deviceToken := strings.TrimPrefix(r.URL.Path, "/3/device/")
+		status, reason := respond(deviceToken)
+		w.Header().Set("apns-id", "id-"+deviceToken)
+		w.WriteHeader(status)
+		switch reason {
+		case "":
+		case "Unregistered":
+			_, _ = w.Write([]byte(`{"reason":"Unregistered","timestamp":1700000000000}`))
+		default:
+			_, _ = w.Write([]byte(`{"reason":"` + reason + `"}`))
+		}
+	}
+}
+
+// writeAPNSKey writes a .p8 signing key and returns its path and public key
+func writeAPNSKey(t *testing.T) (string, *ecdsa.PublicKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "AuthKey_KEY123.p8")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path, &key.PublicKey
+}
+
+// writeAPNSCertificate writes a self-signed .p12 client certificate
+func writeAPNSCertificate(t *testing.T, password string) string {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "Apple Push Services: com.test.app"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+
+	data, err := pkcs12.Modern2023.Encode(key, cert, nil, password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "cert.p12")
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// useAPNSStub points the service at the stub, trusting its certificate, and
+// returns the context to send with
+func useAPNSStub(t *testing.T, service *APNSService, server *stubAPI) context.Context {
+	t.Helper()
+	endpoint, err := ParseEndpoint(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	service.client = &http.Client{Transport: &endpointTransport{next: server.Client().Transport}}
+	return withEndpoint(context.Background(), endpoint)
+}
+
+func TestAPNSService_ParseDirectURL(t *testing.T) {
+	keyPath, _ := writeAPNSKey(t)
+	certPath := writeAPNSCertificate(t, "secret")
+
+	service := NewAPNSService().(*APNSService)
+	serviceURL, _ := url.Parse("apns://api.sandbox.push.apple.com/ABCDEF01/abcdef02?bundle_id=com.test.app&key_id=KEY123&team_id=TEAM&key_path=" + keyPath)
+	if err := service.ParseURL(serviceURL); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if service.apiURL != "https://api.sandbox.push.apple.com" || service.environment != "sandbox" {
+		t.Errorf("Expected the sandbox provider API, got %s (%s)", service.apiURL, service.environment)
+	}
+	if strings.Join(service.deviceTokens, ",") != "abcdef01,abcdef02" {
+		t.Errorf("Unexpected device tokens %v", service.deviceTokens)
+	}
+	if service.tokens == nil {
+		t.Error("Expected a provider token source")
+	}
+
+	service = NewAPNSService().(*APNSService)
+	serviceURL, _ = url.Parse("apns://api.push.apple.com/abcdef01?bundle_id=com.test.app&environment=sandbox&cert_path=" + certPath + "&cert_pass=secret")
+	if err := service.ParseURL(serviceURL); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if service.apiURL != "https://api.sandbox.push.apple.com" {
+		t.Errorf("Expected environment=sandbox to select the sandbox host, got %s", service.apiURL)
+	}
+
+	invalid := map[string]string{
+		"no device tokens":     "apns://api.push.apple.com/?bundle_id=com.test.app&key_id=K&team_id=T&key_path=" + keyPath,
+		"non-hex device token": "apns://api.push.apple.com/not-a-token?bundle_id=com.test.app&key_id=K&team_id=T&key_path=" + keyPath,
+		"missing key file":     "apns://api.push.apple.com/abcdef01?bundle_id=com.test.app&key_id=K&team_id=T&key_path=/nonexistent/key.p8",
+		"key is not a .p8":     "apns://api.push.apple.com/abcdef01?bundle_id=com.test.app&key_id=K&team_id=T&key_path=" + certPath,
+		"wrong cert_pass":      "apns://api.push.apple.com/abcdef01?bundle_id=com.test.app&cert_path=" + certPath + "&cert_pass=wrong",
+		"invalid push_type":    "apns://api.push.apple.com/abcdef01?bundle_id=com.test.app&push_type=voip&key_id=K&team_id=T&key_path=" + keyPath,
+		"invalid priority":     "apns://api.push.apple.com/abcdef01?bundle_id=com.test.app&priority=7&key_id=K&team_id=T&key_path=" + keyPath,
+		"invalid expiration":   "apns://api.push.apple.com/abcdef01?bundle_id=com.test.app&expiration=soon&key_id=K&team_id=T&key_path=" + keyPath,
+	}
+	for name, rawURL := range invalid {
+		serviceURL, _ := url.Parse(rawURL)
+		if err := NewAPNSService().(*APNSService).ParseURL(serviceURL); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestAPNSService_SendDirectJWT(t *testing.T) {
+	keyPath, publicKey := writeAPNSKey(t)
+	server := newTLSStubAPI(t, tls.NoClientCert, answerAPNS(func(deviceToken string) (int, string) {
+		switch deviceToken {
+		case "bbbb":
+			return http.StatusBadRequest, "BadDeviceToken"
+		case "cccc":
+			return http.StatusGone, "Unregistered"
+		}
+		return http.StatusOK, ""
+	}))
+
+	service := NewAPNSService().(*APNSService)
+	serviceURL, _ := url.Parse("apns://api.push.apple.com/aaaa/bbbb/cccc?bundle_id=com.test.app&key_id=KEY123&team_id=TEAM456&key_path=" + keyPath + "&collapse_id=deploys&expiration=0")
+	if err := service.ParseURL(serviceURL); err != nil {
+		t.Fatalf("Failed to parse URL: %v", err)
+	}
+	ctx := useAPNSStub(t, service, server)
+
+	err := service.Send(ctx, NotificationRequest{Title: "Deploy", Body: "Finished", NotifyType: NotifyTypeWarning})
+
+	var sendErr *APNSSendError
+	if !errors.As(err, &sendErr) {
+		t.Fatalf("Expected an APNSSendError, got %v", err)
+	}
+	if !errors.Is(err, ErrInvalidRecipient) || IsRetryable(err) {
+		t.Errorf("Expected a non-retryable invalid recipient error, got %v", err)
+	}
+	if len(sendErr.Results) != 3 || len(sendErr.Failed()) != 2 {
+		t.Fatalf("Expected 2 of 3 tokens to fail, got %+v", sendErr.Results)
+	}
+	if result := sendErr.Results[0]; result.Err != nil || result.APNSID != "id-aaaa" {
+		t.Errorf("Expected aaaa to be accepted, got %+v", result)
+	}
+	if result := sendErr.Results[1]; result.Reason != "BadDeviceToken" || ClassifyError(result.Err) != ErrorClassInvalidRecipient {
+		t.Errorf("Expected BadDeviceToken for bbbb, got %+v", result)
+	}
+	if result := sendErr.Results[2]; result.Reason != "Unregistered" || result.StatusCode != http.StatusGone ||
+		!result.Unregistered.Equal(time.UnixMilli(1700000000000)) {
+		t.Errorf("Expected Unregistered for cccc, got %+v", result)
+	}
+
+	received := server.received()
+	if len(received) != 3 {
+		t.Fatalf("Expected 3 pushes, got %d", len(received))
+	}
+	push := received[0]
+	var payload map[string]interface{}
+	push.decode(&payload)
+	if push.proto != 2 {
+		t.Errorf("Expected HTTP/2, got HTTP/%d", push.proto)
+	}
+	for name, want := range map[string]string{
+		"apns-topic":       "com.test.app",
+		"apns-push-type":   "alert",
+		"apns-priority":    "10",
+		"apns-collapse-id": "deploys",
+		"apns-expiration":  "0",
+	} {
+		if got := push.header.Get(name); got != want {
+			t.Errorf("Expected %s %q, got %q", name, want, got)
+		}
+	}
+	aps, _ := payload["aps"].(map[string]interface{})
+	alert, _ := aps["alert"].(map[string]interface{})
+	if alert["title"] != "Deploy" || alert["body"] != "Finished" || payload["notification_type"] != "warning" {
+		t.Errorf("Unexpected payload %v", payload)
+	}
+
+	// The provider token is an ES256 JWT from the team, reused across pushes
+	token, err := jwt.Parse(strings.TrimPrefix(push.authorization, "bearer "), func(token *jwt.Token) (interface{}, error) {
+		return publicKey, nil
+	}, jwt.WithValidMethods([]string{"ES256"}))
+	if err != nil {
+		t.Fatalf("Invalid provider token: %v", err)
+	}
+	if token.Header["kid"] != "KEY123" {
+		t.Errorf("Expected kid KEY123, got %v", token.Header["kid"])
+	}
+	if issuer, _ := token.Claims.GetIssuer(); issuer != "TEAM456" {
+		t.Errorf("Expected iss TEAM456, got %s", issuer)
+	}
+	for _, push := range received[1:] {
+		if push.authorization != received[0].authorization {
+			t.Error("Expected the provider token to be reused")
+		}
+	}
+}
+
+func TestAPNSService_ProviderTokenRotation(t *testing.T) {
+	keyPath, _ := writeAPNSKey(t)
+	var mu sync.Mutex
+	expired := false
+	server := newTLSStubAPI(t, tls.NoClientCert, answerAPNS(func(string) (int, string) {
+		mu.Lock()
+		defer mu.Unlock()
+		if !expired {
+			expired = true
+			return http.StatusForbidden, "ExpiredProviderToken"
+		}
+		return http.StatusOK, ""
+	}))
+
+	service := NewAPNSService().(*APNSService)
+	serviceURL, _ := url.Parse("apns://api.push.apple.com/aaaa?bundle_id=com.test.app&key_id=KEY123&team_id=TEAM&key_path=" + keyPath)
+	if err := service.ParseURL(serviceURL); err != nil {
+		t.Fatalf("Failed to parse URL: %v", err)
+	}
+	ctx := useAPNSStub(t, service, server)
+
+	if err := service.Send(ctx, NotificationRequest{Body: "test", NotifyType: NotifyTypeInfo}); err != nil {
+		t.Fatalf("Expected the push to succeed with a new token, got %v", err)
+	}
+	received := server.received()
+	if len(received) != 2 || received[0].authorization == received[1].authorization {
+		t.Fatalf("Expected a retry with a new provider token, got %d pushes", len(received))
+	}
+
+	// Tokens are rotated once they near the one hour limit
+	first, _ := service.tokens.get(time.Now())
+	if again, _ := service.tokens.get(time.Now().Add(time.Minute)); again != first {
+		t.Error("Expected the token to be cached")
+	}
+	if later, _ := service.tokens.get(time.Now().Add(apnsTokenLifetime)); later == first {
+		t.Error("Expected the token to be rotated")
+	}
+}
+
+func TestAPNSService_SendDirectCertificate(t *testing.T) {
+	certPath := writeAPNSCertificate(t, "secret")
+	server := newTLSStubAPI(t, tls.RequireAnyClientCert, answerAPNS(func(string) (int, string) {
+		return http.StatusOK, ""
+	}))
+
+	service := NewAPNSService().(*APNSService)
+	serviceURL, _ := url.Parse("apns://api.push.apple.com/aaaa?bundle_id=com.test.app&cert_path=" + certPath + "&cert_pass=secret&push_type=background")
+	if err := service.ParseURL(serviceURL); err != nil {
+		t.Fatalf("Failed to parse URL: %v", err)
+	}
+
+	// Trust the stub, keeping the client certificate loaded from the .p12
+	ctx := useAPNSStub(t, service, server)
+	certificate, err := loadAPNSCertificate(certPath, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+	service.client = newAPNSClient(certificate, roots)
+
+	if err := service.Send(ctx, NotificationRequest{Title: "Sync", Body: "New data", NotifyType: NotifyTypeError}); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	received := server.received()
+	if len(received) != 1 {
+		t.Fatalf("Expected 1 push, got %d", len(received))
+	}
+	push := received[0]
+	if push.proto != 2 || push.clientCN != "Apple Push Services: com.test.app" || push.authorization != "" {
+		t.Errorf("Expected an HTTP/2 push authenticated by certificate, got %+v", push)
+	}
+	if push.header.Get("apns-push-type") != "background" || push.header.Get("apns-priority") != "5" {
+		t.Errorf("Expected a low priority background push, got %v", push.header)
+	}
+	var payload map[string]interface{}
+	push.decode(&payload)
+	aps, _ := payload["aps"].(map[string]interface{})
+	if aps["content-available"] != float64(1) || aps["alert"] != nil {
+		t.Errorf("Expected a content-available only aps, got %v", aps)
+	}
+}
@@ -62,6 +62,8 @@ type NotificationResponse struct {
 	Error      error
 	Duration   time.Duration
 	ServiceID  string
+	Attempts   int    // number of Send calls made, including retries
+	Failover   string // failover group the service was tried in, if any
 }
 
 // Service interface that all notification services must implement
@@ -133,14 +135,39 @@ func (r *ServiceRegistry) GetSupportedServices() []string {
 	return services
 }
 
+// serviceEntry pairs a configured service with the per-URL settings it was
+// added with
+type serviceEntry struct {
+	service     Service
+	url         string
+	tags        []string
+	retry       retryOverrides
+	overflow    OverflowMode
+	endpoint    *url.URL
+	throttle    *ThrottleLimit
+	throttleKey string
+	breaker     breakerOverrides
+	circuitKey  string
+	failover    string
+	origin      interface{}   // *ConfigLoader or *ServiceSet that added the service, if any
+	asset       *AppriseAsset // asset of the configuration the service came from
+}
+
 // Apprise is the main notification manager
 type Apprise struct {
-	services      []Service
+	mu            sync.RWMutex // guards services, endpoints, throttles and asset
+	services      []serviceEntry
 	registry      *ServiceRegistry
 	timeout       time.Duration
 	tags          []string
 	attachmentMgr *AttachmentManager
 	metrics       *MetricsManager
+	retryPolicy   RetryPolicy
+	overflowMode  OverflowMode
+	endpoints     map[string]*url.URL
+	throttles     map[string]ThrottleLimit
+	breakerPolicy CircuitBreakerPolicy
+	asset         AppriseAsset
 }
 
 // New creates a new Apprise instance
@@ -154,36 +181,160 @@ func New() *Apprise {
 	metrics.Register()
 
 	return &Apprise{
-		services:      make([]Service, 0),
+		services:      make([]serviceEntry, 0),
 		registry:      registry,
 		timeout:       30 * time.Second,
 		attachmentMgr: NewAttachmentManager(),
 		metrics:       metrics,
+		retryPolicy:   DefaultRetryPolicy(),
+		overflowMode:  OverflowUpstream,
+		endpoints:     make(map[string]*url.URL),
+		throttles:     make(map[string]ThrottleLimit),
+		breakerPolicy: DefaultCircuitBreakerPolicy(),
 	}
 }
 
-// Add adds a notification service by URL
+// Add adds a notification service by URL. The optional tags are remembered
+// with the service and used to filter notifications (see MatchTags).
 func (a *Apprise) Add(serviceURL string, tags ...string) error {
+	return a.add(serviceURL, "", tags)
+}
+
+// add adds a service to the given failover group, or ungrouped when group
+// and the URL's failover parameter are empty
+func (a *Apprise) add(serviceURL, group string, tags []string) error {
+	entry, err := a.newEntry(serviceURL, group, tags)
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	a.services = append(a.services, entry)
+	count := len(a.services)
+	a.mu.Unlock()
+
+	// Update metrics
+	a.metrics.UpdateServicesConfigured(count)
+	
+	return nil
+}
+
+// replaceServices atomically replaces the services added by origin with
+// entries, keeping every other service. It returns the new service count.
+func (a *Apprise) replaceServices(origin interface{}, entries []serviceEntry) int {
+	a.mu.Lock()
+	services := make([]serviceEntry, 0, len(a.services)+len(entries))
+	for _, entry := range a.services {
+		if entry.origin != origin {
+			services = append(services, entry)
+		}
+	}
+	for _, entry := range entries {
+		entry.origin = origin
+		services = append(services, entry)
+	}
+	a.services = services
+	count := len(services)
+	a.mu.Unlock()
+
+	a.metrics.UpdateServicesConfigured(count)
+	return count
+}
+
+// ServiceSet is a group of an Apprise instance's services that is replaced
+// as a whole, such as the services an API server keeps in its database
+type ServiceSet struct {
+	apprise *Apprise
+}
+
+// NewServiceSet creates an empty service set adding its services to a
+func (a *Apprise) NewServiceSet() *ServiceSet {
+	return &ServiceSet{apprise: a}
+}
+
+// Replace atomically replaces the services of the set with urls, keeping
+// every other service. Nothing changes when any of the URLs is invalid.
+func (s *ServiceSet) Replace(urls []URLConfig) error {
+	entries := make([]serviceEntry, 0, len(urls))
+	for _, urlConfig := range urls {
+		group, err := normalizeFailoverGroup(urlConfig.Failover)
+		if err != nil {
+			return fmt.Errorf("failed to add URL %s: %w", urlConfig.URL, err)
+		}
+		entry, err := s.apprise.newEntry(urlConfig.URL, group, urlConfig.Tags)
+		if err != nil {
+			return fmt.Errorf("failed to add URL %s: %w", urlConfig.URL, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	s.apprise.replaceServices(s, entries)
+	return nil
+}
+
+// newEntry creates and configures the service for a URL without adding it
+func (a *Apprise) newEntry(serviceURL, group string, tags []string) (serviceEntry, error) {
 	parsedURL, err := url.Parse(serviceURL)
 	if err != nil {
-		return fmt.Errorf("invalid service URL: %w", err)
+		return serviceEntry{}, fmt.Errorf("invalid service URL: %w", err)
 	}
 
 	service, err := a.registry.Create(parsedURL.Scheme)
 	if err != nil {
-		return err
+		return serviceEntry{}, err
+	}
+
+	// Strip Apprise-level options before the service sees the URL
+	query := parsedURL.Query()
+	retry, err := parseRetryOverrides(query)
+	if err != nil {
+		return serviceEntry{}, err
+	}
+	overflow, err := parseOverflowOverride(query)
+	if err != nil {
+		return serviceEntry{}, err
+	}
+	endpoint, err := parseEndpointOverride(query)
+	if err != nil {
+		return serviceEntry{}, err
+	}
+	throttle, err := parseThrottleOverride(query)
+	if err != nil {
+		return serviceEntry{}, err
+	}
+	breaker, err := parseBreakerOverrides(query)
+	if err != nil {
+		return serviceEntry{}, err
+	}
+	failover, err := parseFailoverOverride(query)
+	if err != nil {
+		return serviceEntry{}, err
+	}
+	if failover != "" && group != "" && failover != group {
+		return serviceEntry{}, fmt.Errorf("service URL names failover group %q, expected %q", failover, group)
 	}
+	if group == "" {
+		group = failover
+	}
+	parsedURL.RawQuery = query.Encode()
 
 	if err := service.ParseURL(parsedURL); err != nil {
-		return fmt.Errorf("failed to configure service: %w", err)
+		return serviceEntry{}, fmt.Errorf("failed to configure service: %w", err)
 	}
 
-	a.services = append(a.services, service)
-	
-	// Update metrics
-	a.metrics.UpdateServicesConfigured(len(a.services))
-	
-	return nil
+	return serviceEntry{
+		service:     service,
+		url:         serviceURL,
+		tags:        normalizeTags(tags),
+		retry:       retry,
+		overflow:    overflow,
+		endpoint:    endpoint,
+		throttle:    throttle,
+		throttleKey: throttleKey(service, parsedURL),
+		breaker:     breaker,
+		circuitKey:  circuitKey(parsedURL),
+		failover:    group,
+	}, nil
 }
 
 // Notify sends a notification to all configured services
@@ -204,50 +355,116 @@ func (a *Apprise) Notify(title, body string, notifyType NotifyType, options ...N
 	return a.NotifyAll(req)
 }
 
-// NotifyAll sends a notification request to all services
+// NotifyAll sends a notification request to all services whose tags match
+// req.Tags. An empty req.Tags notifies every service. The members of a
+// failover group are tried in order until one succeeds; only the services
+// tried have a response. Each service tried gets the full timeout, so a
+// member that hangs does not leave the next one without time.
 func (a *Apprise) NotifyAll(req NotificationRequest) []NotificationResponse {
-	ctx, cancel := context.WithTimeout(context.Background(), a.timeout)
-	defer cancel()
-
-	responses := make([]NotificationResponse, len(a.services))
+	targets := a.servicesForTags(req.Tags)
+	chains := failoverChains(targets)
+	results := make([][]NotificationResponse, len(chains))
 	var wg sync.WaitGroup
 
-	for i, service := range a.services {
+	for i, chain := range chains {
 		wg.Add(1)
-		go func(idx int, svc Service) {
+		go func(idx int, chain []serviceEntry) {
 			defer wg.Done()
 
-			start := time.Now()
-			err := svc.Send(ctx, req)
-			duration := time.Since(start)
-
-			responses[idx] = NotificationResponse{
-				ServiceURL: req.URL,
-				Success:    err == nil,
-				Error:      err,
-				Duration:   duration,
-				ServiceID:  svc.GetServiceID(),
-			}
-			
-			// Record metrics
-			status := "success"
-			if err != nil {
-				status = "failed"
-				a.metrics.RecordNotificationError(svc.GetServiceID(), "send_failed", "unknown")
+			for _, entry := range chain {
+				ctx, cancel := context.WithTimeout(context.Background(), a.timeout)
+				resp := a.notifyEntry(ctx, entry, req)
+				cancel()
+				results[idx] = append(results[idx], resp)
+				if resp.Success {
+					return
+				}
 			}
-			a.metrics.RecordNotification(svc.GetServiceID(), req.NotifyType.String(), status, duration)
-		}(i, service)
+		}(i, chain)
 	}
 
 	wg.Wait()
-	
+
+	responses := make([]NotificationResponse, 0, len(targets))
+	for _, chainResponses := range results {
+		responses = append(responses, chainResponses...)
+	}
+
 	// Record batch size
-	a.metrics.RecordBatchSize(len(a.services))
+	a.metrics.RecordBatchSize(len(targets))
 	
 	return responses
 }
 
-// SetTimeout sets the timeout for notification requests
+// notifyEntry sends req to one configured service, applying its retry,
+// throttle, circuit breaker and overflow settings
+func (a *Apprise) notifyEntry(ctx context.Context, entry serviceEntry, req NotificationRequest) NotificationResponse {
+	svc := entry.service
+	ctx = withEndpoint(ctx, a.endpointFor(entry))
+	policy := entry.retry.apply(a.retryPolicy)
+	throttle := a.throttleFor(entry)
+	breaker, breakerPolicy := a.breakerFor(entry)
+	req = formatForService(a.assetFor(entry).apply(req), svc)
+
+	overflow := entry.overflow
+	if overflow == "" {
+		overflow = a.overflowMode
+	}
+
+	start := time.Now()
+	var attempts int
+	var err error
+	for _, part := range applyOverflow(req, svc.GetMaxBodyLength(), overflow) {
+		var partAttempts int
+		partAttempts, err = a.sendWithRetry(ctx, svc, part, policy, throttle, breaker, breakerPolicy)
+		attempts += partAttempts
+		if err != nil {
+			break
+		}
+	}
+	duration := time.Since(start)
+
+	// Record metrics
+	status := "success"
+	if err != nil {
+		status = "failed"
+		a.metrics.RecordNotificationError(svc.GetServiceID(), "send_failed", string(ClassifyError(err)))
+	}
+	a.metrics.RecordNotification(svc.GetServiceID(), req.NotifyType.String(), status, duration)
+	a.metrics.RecordNotificationAttempts(svc.GetServiceID(), attempts)
+
+	return NotificationResponse{
+		ServiceURL: req.URL,
+		Success:    err == nil,
+		Error:      err,
+		Duration:   duration,
+		ServiceID:  svc.GetServiceID(),
+		Attempts:   attempts,
+		Failover:   entry.failover,
+	}
+}
+
+// servicesForTags returns the configured services matching the tag filter
+func (a *Apprise) servicesForTags(filter []string) []serviceEntry {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	targets := make([]serviceEntry, 0, len(a.services))
+	for _, entry := range a.services {
+		if MatchTags(entry.tags, filter) {
+			targets = append(targets, entry)
+		}
+	}
+	return targets
+}
+
+// CountByTag returns the number of configured services matching the tag filter
+func (a *Apprise) CountByTag(filter ...string) int {
+	return len(a.servicesForTags(filter))
+}
+
+// SetTimeout sets the timeout for sending to one service, including its
+// retries. Each member of a failover group tried gets its own timeout.
 func (a *Apprise) SetTimeout(timeout time.Duration) {
 	a.timeout = timeout
 }
@@ -259,13 +476,17 @@ func (a *Apprise) SetTags(tags ...string) {
 
 // Clear removes all configured services
 func (a *Apprise) Clear() {
-	a.services = a.services[:0]
+	a.mu.Lock()
+	a.services = nil
+	a.mu.Unlock()
 	// Update metrics
 	a.metrics.UpdateServicesConfigured(0)
 }
 
 // Count returns the number of configured services
 func (a *Apprise) Count() int {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
 	return len(a.services)
 }
 
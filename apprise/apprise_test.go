@@ -221,6 +221,41 @@ func TestAppriseAddService(t *testing.T) {
 	}
 }
 
+func TestServiceSetReplace(t *testing.T) {
+	app := New()
+	if err := app.Add("discord://webhook_id/webhook_token"); err != nil {
+		t.Fatal(err)
+	}
+
+	set := app.NewServiceSet()
+	err := set.Replace([]URLConfig{
+		{URL: "json://localhost/a", Tags: []string{"ops"}},
+		{URL: "json://localhost/b"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if app.Count() != 3 || app.CountByTag("ops") != 1 {
+		t.Errorf("Expected the set's services to be added, got %d", app.Count())
+	}
+
+	// An invalid URL leaves the set as it was
+	if err := set.Replace([]URLConfig{{URL: "invalid://service/url"}}); err == nil {
+		t.Error("Expected an error for an invalid URL")
+	}
+	if app.Count() != 3 {
+		t.Errorf("Expected 3 services after a failed replace, got %d", app.Count())
+	}
+
+	// Replacing only touches the set's own services
+	if err := set.Replace([]URLConfig{{URL: "json://localhost/c"}}); err != nil {
+		t.Fatal(err)
+	}
+	if app.Count() != 2 || app.CountByTag("ops") != 0 {
+		t.Errorf("Expected the set's services to be replaced, got %d", app.Count())
+	}
+}
+
 func TestAppriseSetTags(t *testing.T) {
 	app := New()
 
@@ -0,0 +1,84 @@
+package apprise
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AppriseAsset holds the application settings that Python Apprise calls the
+// asset. It can be set for an Apprise instance with SetAsset or for the
+// services of one configuration file with its asset section:
+//
+//	asset:
+//	  app_id: MyApp
+//	  body_format: markdown
+//	  interpret_escapes: yes
+//
+// Settings Python Apprise supports that have no meaning here, such as image
+// masks and themes, are accepted in configuration files and ignored.
+type AppriseAsset struct {
+	AppID            string `yaml:"app_id,omitempty"`
+	AppDesc          string `yaml:"app_desc,omitempty"`
+	AppURL           string `yaml:"app_url,omitempty"`
+	BodyFormat       string `yaml:"body_format,omitempty"`       // format of bodies sent without one
+	InterpretEscapes bool   `yaml:"interpret_escapes,omitempty"` // turn \n, \t and friends in titles and bodies into the characters
+}
+
+// validate checks the asset's settings
+func (as AppriseAsset) validate() error {
+	switch strings.ToLower(as.BodyFormat) {
+	case "", BodyFormatText, BodyFormatMarkdown, BodyFormatHTML:
+		return nil
+	default:
+		return fmt.Errorf("invalid asset body_format %q: must be text, markdown or html", as.BodyFormat)
+	}
+}
+
+// apply returns req with the asset's defaults applied
+func (as AppriseAsset) apply(req NotificationRequest) NotificationRequest {
+	if req.BodyFormat == "" && as.BodyFormat != "" {
+		req.BodyFormat = strings.ToLower(as.BodyFormat)
+	}
+	if as.InterpretEscapes {
+		req.Title = escapeReplacer.Replace(req.Title)
+		req.Body = escapeReplacer.Replace(req.Body)
+	}
+	return req
+}
+
+// escapeReplacer turns the escape sequences Python Apprise interprets into
+// the characters they stand for
+var escapeReplacer = strings.NewReplacer(
+	`\\`, `\`,
+	`\n`, "\n",
+	`\r`, "\r",
+	`\t`, "\t",
+	`\"`, `"`,
+	`\'`, `'`,
+)
+
+// SetAsset sets the asset applied to services without one of their own
+func (a *Apprise) SetAsset(asset AppriseAsset) error {
+	if err := asset.validate(); err != nil {
+		return err
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.asset = asset
+	return nil
+}
+
+// GetAsset returns the asset applied to services without one of their own
+func (a *Apprise) GetAsset() AppriseAsset {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.asset
+}
+
+// assetFor returns the asset applied to an entry's notifications
+func (a *Apprise) assetFor(entry serviceEntry) AppriseAsset {
+	if entry.asset != nil {
+		return *entry.asset
+	}
+	return a.GetAsset()
+}
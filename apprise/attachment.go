@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"io"
 	"mime"
+	"mime/multipart"
 	"net/http"
+	"net/textproto"
 	"os"
 	"path/filepath"
 	"strings"
@@ -151,6 +153,71 @@ func (am *AttachmentManager) TotalSize() int64 {
 	return total
 }
 
+// readAttachment reads the full content of an attachment into memory,
+// enforcing maxSize (0 = unlimited) on the bytes actually read
+func readAttachment(attachment AttachmentInterface, maxSize int64) ([]byte, error) {
+	if !attachment.Exists() {
+		return nil, fmt.Errorf("attachment %s is not available", attachment.GetName())
+	}
+
+	if maxSize > 0 && attachment.GetSize() > maxSize {
+		return nil, fmt.Errorf("attachment %s (%d bytes) exceeds maximum size (%d bytes)",
+			attachment.GetName(), attachment.GetSize(), maxSize)
+	}
+
+	reader, err := attachment.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open attachment %s: %w", attachment.GetName(), err)
+	}
+	defer func() { _ = reader.Close() }()
+
+	var limited io.Reader = reader
+	if maxSize > 0 {
+		// Read one extra byte so attachments of unknown size can be rejected
+		limited = io.LimitReader(reader, maxSize+1)
+	}
+
+	data, err := io.ReadAll(limited)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attachment %s: %w", attachment.GetName(), err)
+	}
+
+	if maxSize > 0 && int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("attachment %s exceeds maximum size (%d bytes)", attachment.GetName(), maxSize)
+	}
+
+	return data, nil
+}
+
+// isImageAttachment reports whether the attachment carries an image MIME type
+func isImageAttachment(attachment AttachmentInterface) bool {
+	return strings.HasPrefix(attachment.GetMimeType(), "image/")
+}
+
+// writeMultipartFile writes a file part with an explicit Content-Type header
+func writeMultipartFile(writer *multipart.Writer, fieldName, filename, mimeType string, data []byte) error {
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+		escapeMultipartQuotes(fieldName), escapeMultipartQuotes(filename)))
+	if mimeType == "" {
+		mimeType = "application/octet-stream"
+	}
+	header.Set("Content-Type", mimeType)
+
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return err
+	}
+
+	_, err = part.Write(data)
+	return err
+}
+
+// escapeMultipartQuotes escapes quotes and backslashes in multipart header values
+func escapeMultipartQuotes(s string) string {
+	return strings.NewReplacer("\\", "\\\\", `"`, "\\\"").Replace(s)
+}
+
 // FileAttachment represents a file-based attachment
 type FileAttachment struct {
 	path       string
@@ -538,3 +538,64 @@ func TestAWSSESService_ParseEmailList(t *testing.T) {
 		})
 	}
 }
+
+func TestAWSSESService_SendDirect(t *testing.T) {
+	isolateAWSCredentials(t)
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDENV")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "envsecret")
+	server := newStubAPI(t, answerStatus(http.StatusOK))
+
+	service := NewAWSSESService().(*AWSSESService)
+	serviceURL, _ := url.Parse("ses://us-west-2/?from=alerts@example.com&name=Alerts&to=ops@example.com&bcc=audit@example.com&endpoint_url=" + server.URL)
+	if err := service.ParseURL(serviceURL); err != nil {
+		t.Fatalf("Failed to parse URL: %v", err)
+	}
+
+	if err := service.Send(context.Background(), NotificationRequest{Title: "Disk full", Body: "on db1"}); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	attachments := NewAttachmentManager()
+	if err := attachments.AddData([]byte("log line"), "db1.log", "text/plain"); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.Send(context.Background(), NotificationRequest{Title: "Logs", Body: "attached", AttachmentMgr: attachments}); err != nil {
+		t.Fatalf("Send with attachment failed: %v", err)
+	}
+
+	received := server.received()
+	if len(received) != 2 {
+		t.Fatalf("Expected 2 SendEmail calls, got %d", len(received))
+	}
+	for _, req := range received {
+		if req.escapedPath != "/v2/email/outbound-emails" || !strings.Contains(req.authorization, "/us-west-2/ses/aws4_request") {
+			t.Errorf("Expected a signed SendEmail call, got %s with %q", req.escapedPath, req.authorization)
+		}
+	}
+
+	var simple struct {
+		FromEmailAddress string
+		Destination      struct{ ToAddresses, BccAddresses []string }
+		Content          struct {
+			Simple struct{ Subject struct{ Data string } }
+		}
+	}
+	if err := json.Unmarshal(received[0].body, &simple); err != nil {
+		t.Fatal(err)
+	}
+	if simple.FromEmailAddress != "Alerts <alerts@example.com>" || simple.Content.Simple.Subject.Data != "Disk full" ||
+		len(simple.Destination.BccAddresses) != 1 {
+		t.Errorf("Unexpected simple message: %+v", simple)
+	}
+
+	var raw struct {
+		Content struct{ Raw struct{ Data []byte } }
+	}
+	if err := json.Unmarshal(received[1].body, &raw); err != nil {
+		t.Fatal(err)
+	}
+	message := string(raw.Content.Raw.Data)
+	if !strings.Contains(message, "Subject: Logs") || !strings.Contains(message, `filename=db1.log`) || strings.Contains(message, "audit@example.com") {
+		t.Errorf("Unexpected raw message:\n%s", message)
+	}
+}
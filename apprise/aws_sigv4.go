@@ -0,0 +1,372 @@
+package apprise
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AWSCredentials are the keys used to sign requests to AWS APIs. The session
+// token is only set for temporary credentials.
+type AWSCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// LoadAWSCredentials finds credentials the way the AWS CLI does: from the
+// AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN environment
+// variables, then from a profile of the shared credentials file
+// (AWS_SHARED_CREDENTIALS_FILE or ~/.aws/credentials). An empty profile means
+// AWS_PROFILE, or "default" when that is not set either.
+func LoadAWSCredentials(profile string) (AWSCredentials, error) {
+	if profile == "" {
+		if accessKey := os.Getenv("AWS_ACCESS_KEY_ID"); accessKey != "" {
+			creds := AWSCredentials{
+				AccessKeyID:     accessKey,
+				SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+				SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+			}
+			if creds.SecretAccessKey == "" {
+				return AWSCredentials{}, fmt.Errorf("AWS_ACCESS_KEY_ID is set but AWS_SECRET_ACCESS_KEY is not")
+			}
+			return creds, nil
+		}
+
+		profile = os.Getenv("AWS_PROFILE")
+		if profile == "" {
+			profile = "default"
+		}
+	}
+
+	path := os.Getenv("AWS_SHARED_CREDENTIALS_FILE")
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return AWSCredentials{}, fmt.Errorf("no AWS credentials found: %w", err)
+		}
+		path = filepath.Join(home, ".aws", "credentials")
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return AWSCredentials{}, fmt.Errorf("no AWS credentials found in the environment or %s", path)
+	}
+	defer func() { _ = file.Close() }()
+
+	creds, err := readAWSCredentialsProfile(file, profile)
+	if err != nil {
+		return AWSCredentials{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return creds, nil
+}
+
+// readAWSCredentialsProfile reads one profile of a shared credentials file
+func readAWSCredentialsProfile(r io.Reader, profile string) (AWSCredentials, error) {
+	var creds AWSCredentials
+	found := false
+	current := ""
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			current = strings.TrimSpace(line[1 : len(line)-1])
+			if current == profile {
+				found = true
+			}
+			continue
+		}
+		if current != profile {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "aws_access_key_id":
+			creds.AccessKeyID = strings.TrimSpace(value)
+		case "aws_secret_access_key":
+			creds.SecretAccessKey = strings.TrimSpace(value)
+		case "aws_session_token":
+			creds.SessionToken = strings.TrimSpace(value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return AWSCredentials{}, err
+	}
+
+	if !found {
+		return AWSCredentials{}, fmt.Errorf("profile %q not found", profile)
+	}
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		return AWSCredentials{}, fmt.Errorf("profile %q has no aws_access_key_id and aws_secret_access_key", profile)
+	}
+	return creds, nil
+}
+
+// AWSSigner signs HTTP requests to an AWS API with Signature Version 4
+type AWSSigner struct {
+	credentials AWSCredentials
+	region      string
+	service     string // signing name, such as "sns" or "iotdata"
+}
+
+// NewAWSSigner creates a signer for one service in one region
+func NewAWSSigner(credentials AWSCredentials, region, service string) *AWSSigner {
+	return &AWSSigner{
+		credentials: credentials,
+		region:      region,
+		service:     service,
+	}
+}
+
+// Sign adds the X-Amz-Date, X-Amz-Security-Token and Authorization headers
+// to req. body must be the request's body, which Sign does not read. The
+// Host, Content-Type and X-Amz-* headers are signed.
+func (s *AWSSigner) Sign(req *http.Request, body []byte) error {
+	return s.signAt(req, body, time.Now())
+}
+
+// signAt signs req as if at time now
+func (s *AWSSigner) signAt(req *http.Request, body []byte, now time.Time) error {
+	if s.credentials.AccessKeyID == "" || s.credentials.SecretAccessKey == "" {
+		return fmt.Errorf("AWS Signature V4 signing requires an access key and secret key")
+	}
+
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	if s.credentials.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.credentials.SessionToken)
+	}
+
+	signedHeaders, canonicalHeaders := awsCanonicalHeaders(req)
+	payloadHash := sha256.Sum256(body)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		awsCanonicalURI(req.URL),
+		awsCanonicalQuery(req.URL),
+		canonicalHeaders,
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+
+	scope := strings.Join([]string{date, s.region, s.service, "aws4_request"}, "/")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hex.EncodeToString(requestHash[:]),
+	}, "\n")
+
+	key := awsHMAC([]byte("AWS4"+s.credentials.SecretAccessKey), date)
+	key = awsHMAC(key, s.region)
+	key = awsHMAC(key, s.service)
+	key = awsHMAC(key, "aws4_request")
+	signature := hex.EncodeToString(awsHMAC(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.credentials.AccessKeyID, scope, signedHeaders, signature))
+	return nil
+}
+
+// awsHMAC returns the HMAC-SHA256 of data
+func awsHMAC(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// awsCanonicalHeaders returns the signed header names and the canonical
+// header block: host, content-type and x-amz-* headers, lowercased, with
+// values trimmed and inner spaces collapsed
+func awsCanonicalHeaders(req *http.Request) (signed, canonical string) {
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	headers := map[string]string{"host": host}
+
+	for name, values := range req.Header {
+		lower := strings.ToLower(name)
+		if lower != "content-type" && !strings.HasPrefix(lower, "x-amz-") {
+			continue
+		}
+		trimmed := make([]string, len(values))
+		for i, value := range values {
+			trimmed[i] = strings.Join(strings.Fields(value), " ")
+		}
+		headers[lower] = strings.Join(trimmed, ",")
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var block strings.Builder
+	for _, name := range names {
+		block.WriteString(name + ":" + headers[name] + "\n")
+	}
+	return strings.Join(names, ";"), block.String()
+}
+
+// awsCanonicalURI returns the request path with every segment encoded once
+// more, as all services but S3 expect
+func awsCanonicalURI(u *url.URL) string {
+	path := u.EscapedPath()
+	if path == "" {
+		return "/"
+	}
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = awsURIEncode(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// awsCanonicalQuery returns the query parameters encoded and sorted
+func awsCanonicalQuery(u *url.URL) string {
+	query, _ := url.ParseQuery(u.RawQuery)
+
+	var pairs []string
+	for key, values := range query {
+		for _, value := range values {
+			pairs = append(pairs, awsURIEncode(key)+"="+awsURIEncode(value))
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+// awsURIEncode percent-encodes every byte but the unreserved characters
+func awsURIEncode(value string) string {
+	var encoded strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' {
+			encoded.WriteByte(c)
+		} else {
+			fmt.Fprintf(&encoded, "%%%02X", c)
+		}
+	}
+	return encoded.String()
+}
+
+// awsRegionPattern matches AWS region names such as us-east-1 and
+// us-gov-west-1
+var awsRegionPattern = regexp.MustCompile(`^[a-z]{2}(-gov|-iso[a-z]?)?-[a-z]+-\d+$`)
+
+// awsRegionFromHost returns the region a service URL's host names, either
+// directly ("us-east-1") or as an AWS API host ("sns.us-east-1.amazonaws.com").
+// Hosts that are neither return an empty region.
+func awsRegionFromHost(host string) string {
+	host = strings.ToLower(host)
+	if awsRegionPattern.MatchString(host) {
+		return host
+	}
+	if !strings.HasSuffix(host, ".amazonaws.com") {
+		return ""
+	}
+	for _, part := range strings.Split(host, ".") {
+		if awsRegionPattern.MatchString(part) {
+			return part
+		}
+	}
+	return ""
+}
+
+// parseAWSCredentials reads credentials from a service URL: the user info
+// holds the access key and secret key, and the session_token parameter the
+// token of temporary credentials. URLs without user info use
+// LoadAWSCredentials, with the profile parameter choosing the profile.
+func parseAWSCredentials(serviceURL *url.URL) (AWSCredentials, error) {
+	query := serviceURL.Query()
+
+	if serviceURL.User == nil {
+		creds, err := LoadAWSCredentials(query.Get("profile"))
+		if err != nil {
+			return AWSCredentials{}, fmt.Errorf("authentication required: %w", err)
+		}
+		return creds, nil
+	}
+
+	creds := AWSCredentials{
+		AccessKeyID:  serviceURL.User.Username(),
+		SessionToken: query.Get("session_token"),
+	}
+	if creds.AccessKeyID == "" {
+		return AWSCredentials{}, fmt.Errorf("AWS access key is required")
+	}
+	creds.SecretAccessKey, _ = serviceURL.User.Password()
+	if creds.SecretAccessKey == "" {
+		return AWSCredentials{}, fmt.Errorf("AWS secret key is required")
+	}
+	return creds, nil
+}
+
+// parseAWSEndpoint returns the base URL of an AWS API: the endpoint_url
+// parameter, the AWS_ENDPOINT_URL environment variable (for local stand-ins
+// such as LocalStack) or, when neither is set, https://host
+func parseAWSEndpoint(serviceURL *url.URL, host string) (string, error) {
+	endpoint := serviceURL.Query().Get("endpoint_url")
+	if endpoint == "" {
+		endpoint = os.Getenv("AWS_ENDPOINT_URL")
+	}
+	if endpoint == "" {
+		return "https://" + host, nil
+	}
+
+	parsed, err := url.Parse(endpoint)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return "", fmt.Errorf("invalid endpoint_url %q: must be an http or https URL", endpoint)
+	}
+	return strings.TrimSuffix(endpoint, "/"), nil
+}
+
+// sendAWSRequest signs and sends a request to an AWS API, returning the
+// body of a successful response. api names the API in errors.
+func sendAWSRequest(client *http.Client, signer *AWSSigner, req *http.Request, body []byte, api string) ([]byte, error) {
+	req.Header.Set("User-Agent", GetUserAgent())
+	if err := signer.Sign(req, body); err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send %s request: %w", api, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s response: %w", api, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, NewHTTPError(resp, fmt.Errorf("%s API error (status %d): %s", api, resp.StatusCode, string(respBody)))
+	}
+	return respBody, nil
+}
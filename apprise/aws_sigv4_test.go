@@ -0,0 +1,145 @@
+package apprise
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// isolateAWSCredentials hides any AWS credentials of the machine running the
+// tests, so lookups only see what the test sets up
+func isolateAWSCredentials(t *testing.T) {
+	t.Helper()
+	for _, name := range []string{"AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY", "AWS_SESSION_TOKEN", "AWS_PROFILE", "AWS_ENDPOINT_URL"} {
+		t.Setenv(name, "")
+	}
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "credentials"))
+}
+
+func TestAWSSignerTestSuite(t *testing.T) {
+	// Requests and signatures from the AWS Signature Version 4 test suite
+	signer := NewAWSSigner(AWSCredentials{
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+	}, "us-east-1", "service")
+	when := time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		method        string
+		url           string
+		contentType   string
+		body          string
+		signedHeaders string
+		signature     string
+	}{
+		{"get-vanilla", "GET", "https://example.amazonaws.com/", "", "",
+			"host;x-amz-date", "5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"},
+		{"get-vanilla-query-order-key-case", "GET", "https://example.amazonaws.com/?Param2=value2&Param1=value1", "", "",
+			"host;x-amz-date", "b97d918cfa904a5beff61c982a1b6f458b799221646efd99d3219ec94cdf2500"},
+		{"post-x-www-form-urlencoded", "POST", "https://example.amazonaws.com/", "application/x-www-form-urlencoded", "Param1=value1",
+			"content-type;host;x-amz-date", "ff11897932ad3f4e8b18135d722051e5ac45fc38421b1da7b9d196a0fe09473a"},
+	}
+
+	for _, tt := range tests {
+		req, err := http.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tt.contentType != "" {
+			req.Header.Set("Content-Type", tt.contentType)
+		}
+		if err := signer.signAt(req, []byte(tt.body), when); err != nil {
+			t.Fatalf("%s: failed to sign: %v", tt.name, err)
+		}
+
+		want := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+			"SignedHeaders=" + tt.signedHeaders + ", Signature=" + tt.signature
+		if got := req.Header.Get("Authorization"); got != want {
+			t.Errorf("%s:\nexpected %s\n     got %s", tt.name, want, got)
+		}
+	}
+}
+
+func TestAWSSignerSessionToken(t *testing.T) {
+	signer := NewAWSSigner(AWSCredentials{AccessKeyID: "AKID", SecretAccessKey: "secret", SessionToken: "token"}, "eu-west-1", "sns")
+	req, _ := http.NewRequest("POST", "https://sns.eu-west-1.amazonaws.com/", nil)
+	if err := signer.Sign(req, nil); err != nil {
+		t.Fatalf("Failed to sign: %v", err)
+	}
+	if req.Header.Get("X-Amz-Security-Token") != "token" {
+		t.Error("Expected the session token header")
+	}
+	if !strings.Contains(req.Header.Get("Authorization"), "SignedHeaders=host;x-amz-date;x-amz-security-token,") {
+		t.Errorf("Expected the session token to be signed, got %s", req.Header.Get("Authorization"))
+	}
+
+	if err := NewAWSSigner(AWSCredentials{}, "eu-west-1", "sns").Sign(req, nil); err == nil {
+		t.Error("Expected signing without credentials to fail")
+	}
+}
+
+func TestLoadAWSCredentials(t *testing.T) {
+	isolateAWSCredentials(t)
+
+	if _, err := LoadAWSCredentials(""); err == nil {
+		t.Error("Expected an error without credentials")
+	}
+
+	path := os.Getenv("AWS_SHARED_CREDENTIALS_FILE")
+	content := `
+[default]
+aws_access_key_id = AKIDDEFAULT
+aws_secret_access_key = defaultsecret
+
+[ci]
+aws_access_key_id=AKIDCI
+aws_secret_access_key=cisecret
+aws_session_token=citoken
+`
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	creds, err := LoadAWSCredentials("")
+	if err != nil || creds.AccessKeyID != "AKIDDEFAULT" || creds.SecretAccessKey != "defaultsecret" {
+		t.Errorf("Expected the default profile, got %+v (%v)", creds, err)
+	}
+
+	t.Setenv("AWS_PROFILE", "ci")
+	creds, err = LoadAWSCredentials("")
+	if err != nil || creds.AccessKeyID != "AKIDCI" || creds.SessionToken != "citoken" {
+		t.Errorf("Expected the ci profile, got %+v (%v)", creds, err)
+	}
+
+	if _, err := LoadAWSCredentials("missing"); err == nil {
+		t.Error("Expected an error for a missing profile")
+	}
+
+	// The environment comes before the file
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDENV")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "envsecret")
+	creds, err = LoadAWSCredentials("")
+	if err != nil || creds.AccessKeyID != "AKIDENV" {
+		t.Errorf("Expected the environment credentials, got %+v (%v)", creds, err)
+	}
+}
+
+func TestAWSRegionFromHost(t *testing.T) {
+	tests := map[string]string{
+		"us-east-1":                            "us-east-1",
+		"us-gov-west-1":                        "us-gov-west-1",
+		"sns.eu-west-2.amazonaws.com":          "eu-west-2",
+		"abc-ats.iot.ap-south-1.amazonaws.com": "ap-south-1",
+		"webhook.example.com":                  "",
+		"127.0.0.1:8080":                       "",
+	}
+	for host, want := range tests {
+		if got := awsRegionFromHost(host); got != want {
+			t.Errorf("%s: expected %q, got %q", host, want, got)
+		}
+	}
+}
@@ -129,11 +129,16 @@ func TestAWSSNSSMSService_GetMaxBodyLength(t *testing.T) {
 }
 
 func TestAWSSNSSMSService_Send(t *testing.T) {
+	isolateAWSCredentials(t)
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDENV")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "envsecret")
+	server := newStubAPI(t, answerStatus(200))
+
 	service := NewAWSSNSSMSService().(*AWSSNSSMSService)
-	service.accessKey = "test_access_key"
-	service.secretKey = "test_secret_key"
-	service.region = "us-east-1"
-	service.to = []string{"+1234567890"}
+	serviceURL, _ := url.Parse("aws-sns-sms://eu-west-1/+1234567890/+1987654321?endpoint_url=" + server.URL)
+	if err := service.ParseURL(serviceURL); err != nil {
+		t.Fatalf("Failed to parse URL: %v", err)
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -142,15 +147,20 @@ func TestAWSSNSSMSService_Send(t *testing.T) {
 		Title: "Test Title",
 		Body:  "Test message body",
 	}
-
-	err := service.Send(ctx, notification)
-
-	// Expect error due to SDK requirement
-	if err == nil {
-		t.Error("Expected error due to SDK requirement, but got none")
+	if err := service.Send(ctx, notification); err != nil {
+		t.Fatalf("Send failed: %v", err)
 	}
 
-	if err != nil && !strings.Contains(err.Error(), "AWS SDK") {
-		t.Errorf("Expected error to mention AWS SDK, got: %v", err)
+	received := server.received()
+	if len(received) != 2 {
+		t.Fatalf("Expected a Publish call per recipient, got %d", len(received))
 	}
-}
\ No newline at end of file
+	params, _ := url.ParseQuery(string(received[0].body))
+	if params.Get("Action") != "Publish" || params.Get("PhoneNumber") != "+1234567890" || params.Get("Message") != "Test Title\nTest message body" {
+		t.Errorf("Unexpected Publish parameters: %v", params)
+	}
+	if !strings.HasPrefix(received[0].authorization, "AWS4-HMAC-SHA256 Credential=AKIDENV/") ||
+		!strings.Contains(received[0].authorization, "/eu-west-1/sns/aws4_request") {
+		t.Errorf("Expected a SigV4 signature for SNS in eu-west-1, got %q", received[0].authorization)
+	}
+}
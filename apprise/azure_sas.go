@@ -0,0 +1,92 @@
+package apprise
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// azureSASTokenLifetime is how long generated Shared Access Signature tokens
+// are valid
+const azureSASTokenLifetime = time.Hour
+
+// AzureConnectionString holds the parts of an Azure Service Bus connection
+// string such as
+// "Endpoint=sb://mybus.servicebus.windows.net/;SharedAccessKeyName=RootManageSharedAccessKey;SharedAccessKey=...;EntityPath=alerts"
+type AzureConnectionString struct {
+	Endpoint              string // Namespace host, e.g. mybus.servicebus.windows.net
+	SharedAccessKeyName   string
+	SharedAccessKey       string
+	SharedAccessSignature string // Ready-made token used instead of a key
+	EntityPath            string // Queue or topic the connection string is scoped to
+}
+
+// ParseAzureConnectionString parses a connection string the way the Azure
+// SDKs do: Key=Value pairs separated by semicolons, with case-insensitive
+// keys and unknown keys ignored. An Endpoint is required, along with either a
+// shared access key and its name or a shared access signature.
+func ParseAzureConnectionString(connStr string) (*AzureConnectionString, error) {
+	var conn AzureConnectionString
+	for _, part := range strings.Split(connStr, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		// Values such as base64 keys may contain '=' themselves
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid connection string: unmatched key value separated by '='")
+		}
+
+		switch {
+		case strings.EqualFold(key, "Endpoint"):
+			endpoint, err := url.Parse(value)
+			if err != nil || endpoint.Host == "" {
+				return nil, fmt.Errorf("invalid connection string: incorrectly formatted Endpoint value")
+			}
+			conn.Endpoint = endpoint.Host
+		case strings.EqualFold(key, "SharedAccessKeyName"):
+			conn.SharedAccessKeyName = value
+		case strings.EqualFold(key, "SharedAccessKey"):
+			conn.SharedAccessKey = value
+		case strings.EqualFold(key, "SharedAccessSignature"):
+			conn.SharedAccessSignature = value
+		case strings.EqualFold(key, "EntityPath"):
+			conn.EntityPath = value
+		}
+	}
+
+	if conn.Endpoint == "" {
+		return nil, fmt.Errorf("invalid connection string: key \"Endpoint\" must not be empty")
+	}
+	if conn.SharedAccessSignature == "" {
+		if conn.SharedAccessKeyName == "" {
+			return nil, fmt.Errorf("invalid connection string: key \"SharedAccessKeyName\" must not be empty")
+		}
+		if conn.SharedAccessKey == "" {
+			return nil, fmt.Errorf("invalid connection string: key \"SharedAccessKey\" or \"SharedAccessSignature\" must not be empty")
+		}
+	}
+
+	return &conn, nil
+}
+
+// azureSASToken returns a Shared Access Signature token granting access to
+// resourceURI until expiry. The signature is the HMAC-SHA256, keyed with the
+// shared access key, of the encoded resource URI and the expiry time.
+func azureSASToken(resourceURI, keyName, key string, expiry time.Time) string {
+	audience := strings.ToLower(url.QueryEscape(resourceURI))
+	expires := strconv.FormatInt(expiry.Unix(), 10)
+
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(audience + "\n" + expires))
+	signature := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	return fmt.Sprintf("SharedAccessSignature sr=%s&sig=%s&se=%s&skn=%s", audience, url.QueryEscape(signature), expires, keyName)
+}
@@ -0,0 +1,50 @@
+package apprise
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseAzureConnectionString(t *testing.T) {
+	conn, err := ParseAzureConnectionString("Endpoint=sb://mybus.servicebus.windows.net/;SharedAccessKeyName=SendOnly;SharedAccessKey=c2VjcmV0LWtleQ==;EntityPath=alerts")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if conn.Endpoint != "mybus.servicebus.windows.net" || conn.SharedAccessKeyName != "SendOnly" ||
+		conn.SharedAccessKey != "c2VjcmV0LWtleQ==" || conn.EntityPath != "alerts" {
+		t.Errorf("Unexpected connection string %+v", conn)
+	}
+
+	// Keys are case-insensitive, unknown keys are ignored and a signature
+	// replaces the key
+	conn, err = ParseAzureConnectionString("endpoint=sb://mybus.servicebus.windows.net;sharedaccesssignature=SharedAccessSignature sr=x&sig=y&se=1&skn=z;TransportType=Amqp;")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if conn.SharedAccessSignature != "SharedAccessSignature sr=x&sig=y&se=1&skn=z" {
+		t.Errorf("Unexpected signature %q", conn.SharedAccessSignature)
+	}
+
+	invalid := []string{
+		"",
+		"SharedAccessKeyName=SendOnly;SharedAccessKey=key",
+		"Endpoint=sb://mybus.servicebus.windows.net/;SharedAccessKey=key",
+		"Endpoint=sb://mybus.servicebus.windows.net/;SharedAccessKeyName=SendOnly",
+		"Endpoint=sb://mybus.servicebus.windows.net/;SharedAccessKeyName",
+		"Endpoint=mybus;SharedAccessKeyName=SendOnly;SharedAccessKey=key",
+	}
+	for _, connStr := range invalid {
+		if _, err := ParseAzureConnectionString(connStr); err == nil {
+			t.Errorf("Expected an error for %q", connStr)
+		}
+	}
+}
+
+func TestAzureSASToken(t *testing.T) {
+	token := azureSASToken("https://mybus.servicebus.windows.net/alerts", "SendOnly", "c2VjcmV0LWtleQ==", time.Unix(1700000000, 0))
+	want := "SharedAccessSignature sr=https%3a%2f%2fmybus.servicebus.windows.net%2falerts" +
+		"&sig=UHDeHzIFcz%2Bx7kXdj5%2FwDRfI0TBUc2%2BS1gkjDAvRaUE%3D&se=1700000000&skn=SendOnly"
+	if token != want {
+		t.Errorf("Expected %s\n     got %s", want, token)
+	}
+}
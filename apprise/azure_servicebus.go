@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
 	"io"
 	"net/http"
@@ -12,7 +13,16 @@ import (
 	"time"
 )
 
-// AzureServiceBusService implements Azure Service Bus notifications via webhook
+// azureServiceBusDomains are the domains of Service Bus namespaces in the
+// Azure public, China and US Government clouds
+var azureServiceBusDomains = []string{
+	"servicebus.windows.net",
+	"servicebus.chinacloudapi.cn",
+	"servicebus.usgovcloudapi.net",
+}
+
+// AzureServiceBusService implements Azure Service Bus notifications, either
+// through a webhook proxy or directly with the Service Bus REST API
 type AzureServiceBusService struct {
 	webhookURL        string
 	namespace         string
@@ -26,6 +36,14 @@ type AzureServiceBusService struct {
 	timeToLive        int // TTL in seconds
 	apiKey            string
 	client            *http.Client
+
+	apiURL        string        // Namespace base URL in direct mode
+	entityPath    string        // Queue or topic messages are sent to in direct mode
+	sasToken      string        // Ready-made SAS token, used instead of sasKey
+	label         string        // Message label, the title when empty
+	sessionID     string        // Session of session-enabled queues and subscriptions
+	scheduleAt    time.Time     // Scheduled enqueue time
+	scheduleAfter time.Duration // Scheduled enqueue delay from the time of sending
 }
 
 // NewAzureServiceBusService creates a new Azure Service Bus service instance
@@ -47,10 +65,14 @@ func (a *AzureServiceBusService) GetDefaultPort() int {
 	return 443
 }
 
-// ParseURL parses an Azure Service Bus service URL
+// ParseURL parses an Azure Service Bus service URL. Using a Service Bus
+// namespace host sends directly with the REST API; any other host is a
+// webhook proxy.
 // Format: azuresb://webhook.url/servicebus?namespace=mybus&queue=notifications&sas_key_name=RootManageSharedAccessKey&sas_key=base64key
 // Format: azuresb://api-key@webhook.url/sb?namespace=company-bus&topic=alerts&subscription=email-processor
 // Format: azuresb://webhook.url/proxy?connection_string=Endpoint%3Dsb%3A//...&queue=messages
+// Format: azuresb://mybus.servicebus.windows.net/?queue=notifications&sas_key_name=SendOnly&sas_key=base64key
+// Format: azuresb://servicebus.windows.net/?connection_string=Endpoint%3Dsb%3A//...%3BEntityPath%3Dalerts&session_id=ops
 func (a *AzureServiceBusService) ParseURL(serviceURL *url.URL) error {
 	if serviceURL.Scheme != "azuresb" {
 		return fmt.Errorf("invalid scheme: expected 'azuresb', got '%s'", serviceURL.Scheme)
@@ -65,6 +87,10 @@ func (a *AzureServiceBusService) ParseURL(serviceURL *url.URL) error {
 		return fmt.Errorf("webhook host is required")
 	}
 
+	if isAzureServiceBusHost(serviceURL.Hostname()) {
+		return a.parseDirectURL(serviceURL.Hostname(), serviceURL.Query())
+	}
+
 	// Build webhook URL - use HTTP for testing if specified
 	scheme := "https" // Default to HTTPS for production
 	if serviceURL.Query().Get("test_mode") == "true" {
@@ -118,20 +144,104 @@ func (a *AzureServiceBusService) ParseURL(serviceURL *url.URL) error {
 		return fmt.Errorf("queue or topic parameter is required")
 	}
 
+	a.parseMessageOptions(queryParams)
+
+	return nil
+}
+
+// parseMessageOptions reads the time to live and the custom message
+// properties given as prop_NAME=VALUE
+func (a *AzureServiceBusService) parseMessageOptions(query url.Values) {
 	// Optional: Time to Live (TTL)
-	if ttl := queryParams.Get("ttl"); ttl != "" {
+	if ttl := query.Get("ttl"); ttl != "" {
 		if ttlSeconds, err := parseInt(ttl); err == nil && ttlSeconds > 0 {
 			a.timeToLive = ttlSeconds
 		}
 	}
 
 	// Parse message properties (prefix: prop_)
-	for key, values := range queryParams {
+	for key, values := range query {
 		if strings.HasPrefix(key, "prop_") && len(values) > 0 {
 			propKey := strings.TrimPrefix(key, "prop_")
 			a.messageProperties[propKey] = values[0]
 		}
 	}
+}
+
+// isAzureServiceBusHost reports whether host is a Service Bus namespace or
+// the bare domain of one
+func isAzureServiceBusHost(host string) bool {
+	host = strings.ToLower(host)
+	for _, domain := range azureServiceBusDomains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseDirectURL configures sending with the Service Bus REST API. The
+// namespace is the URL host, the Endpoint of connection_string or, with the
+// bare Service Bus domain as host, the namespace parameter. Messages go to
+// queue, topic or the connection string's EntityPath, authorized with SAS
+// tokens signed by sas_key or the connection string's key.
+func (a *AzureServiceBusService) parseDirectURL(host string, query url.Values) error {
+	host = strings.ToLower(host)
+	if namespace := query.Get("namespace"); namespace != "" && !strings.Contains(host, ".servicebus.") {
+		host = namespace + "." + host
+	}
+
+	a.sasKeyName = query.Get("sas_key_name")
+	a.sasKey = query.Get("sas_key")
+	if connStr := query.Get("connection_string"); connStr != "" {
+		conn, err := ParseAzureConnectionString(connStr)
+		if err != nil {
+			return err
+		}
+		a.connectionString = connStr
+		host = strings.ToLower(conn.Endpoint)
+		a.sasKeyName = conn.SharedAccessKeyName
+		a.sasKey = conn.SharedAccessKey
+		a.sasToken = conn.SharedAccessSignature
+		a.entityPath = conn.EntityPath
+	}
+	if !strings.Contains(host, ".servicebus.") {
+		return fmt.Errorf("namespace or connection_string parameter is required")
+	}
+	a.namespace, _, _ = strings.Cut(host, ".")
+	a.apiURL = "https://" + host
+
+	if a.sasKey == "" && a.sasToken == "" {
+		return fmt.Errorf("sas_key or connection_string parameter is required")
+	}
+	if a.sasKeyName == "" {
+		a.sasKeyName = "RootManageSharedAccessKey" // Default key name
+	}
+
+	a.queueName = query.Get("queue")
+	a.topicName = query.Get("topic")
+	switch {
+	case a.queueName != "":
+		a.entityPath = a.queueName
+	case a.topicName != "":
+		a.entityPath = a.topicName
+	case a.entityPath == "":
+		return fmt.Errorf("queue or topic parameter is required")
+	}
+
+	a.label = query.Get("label")
+	a.sessionID = query.Get("session_id")
+	if value := query.Get("scheduled_enqueue_time"); value != "" {
+		if at, err := time.Parse(time.RFC3339, value); err == nil {
+			a.scheduleAt = at
+		} else if after, err := time.ParseDuration(value); err == nil && after >= 0 {
+			a.scheduleAfter = after
+		} else {
+			return fmt.Errorf("invalid scheduled_enqueue_time value %q: must be an RFC 3339 time or a duration such as 10m", value)
+		}
+	}
+
+	a.parseMessageOptions(query)
 
 	return nil
 }
@@ -164,7 +274,8 @@ func (a *AzureServiceBusService) GetMaxBodyLength() int {
 	return 256 * 1024 // 256KB
 }
 
-// Send sends a notification via Azure Service Bus webhook
+// Send sends a notification via Azure Service Bus, directly with the REST API
+// or through the webhook proxy
 func (a *AzureServiceBusService) Send(ctx context.Context, req NotificationRequest) error {
 	// Prepare the message
 	message := a.formatMessage(req.Title, req.Body, req.NotifyType)
@@ -175,6 +286,10 @@ func (a *AzureServiceBusService) Send(ctx context.Context, req NotificationReque
 		message = message[:maxLength-3] + "..."
 	}
 
+	if a.apiURL != "" {
+		return a.sendDirect(ctx, message, req)
+	}
+
 	// Create payload for Service Bus webhook
 	payload := map[string]interface{}{
 		"namespace":         a.namespace,
@@ -345,6 +460,93 @@ func (a *AzureServiceBusService) buildMessageProperties(notifyType NotifyType) m
 	return properties
 }
 
+// sendDirect sends the message to the queue or topic with the Service Bus
+// REST API. Broker properties such as the label and time to live go in the
+// BrokerProperties header and custom properties in headers of their own.
+func (a *AzureServiceBusService) sendDirect(ctx context.Context, message string, req NotificationRequest) error {
+	now := time.Now()
+
+	label := a.label
+	if label == "" {
+		label = req.Title
+	}
+	if label == "" {
+		label = fmt.Sprintf("Apprise Notification (%s)", req.NotifyType.String())
+	}
+	brokerProperties := map[string]interface{}{
+		"Label":      label,
+		"TimeToLive": a.timeToLive,
+	}
+	if a.sessionID != "" {
+		brokerProperties["SessionId"] = a.sessionID
+	}
+	if scheduleAt := a.scheduledEnqueueTime(now); !scheduleAt.IsZero() {
+		brokerProperties["ScheduledEnqueueTimeUtc"] = scheduleAt.UTC().Format(http.TimeFormat)
+	}
+	brokerJSON, err := json.Marshal(brokerProperties)
+	if err != nil {
+		return fmt.Errorf("failed to marshal broker properties: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/%s/messages", a.apiURL, a.entityPath)
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", endpoint, strings.NewReader(message))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("User-Agent", GetUserAgent())
+	httpReq.Header.Set("BrokerProperties", string(brokerJSON))
+
+	if a.sasToken != "" {
+		httpReq.Header.Set("Authorization", a.sasToken)
+	} else {
+		resourceURI := a.apiURL + "/" + a.entityPath
+		httpReq.Header.Set("Authorization", azureSASToken(resourceURI, a.sasKeyName, a.sasKey, now.Add(azureSASTokenLifetime)))
+	}
+
+	// Custom properties are headers, named as given, whose string values are
+	// quoted
+	for key, value := range a.buildMessageProperties(req.NotifyType) {
+		quoted, err := json.Marshal(value)
+		if err != nil {
+			continue
+		}
+		httpReq.Header[key] = []string{string(quoted)}
+	}
+
+	resp, err := a.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to send to Service Bus: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		var apiErr struct {
+			Detail string `xml:"Detail"`
+		}
+		reason := string(body)
+		if xml.Unmarshal(body, &apiErr) == nil && apiErr.Detail != "" {
+			reason = apiErr.Detail
+		}
+		return NewHTTPError(resp, fmt.Errorf("Service Bus API error (status %d): %s", resp.StatusCode, reason))
+	}
+
+	return nil
+}
+
+// scheduledEnqueueTime returns when a message sent at now becomes visible, or
+// the zero time for right away
+func (a *AzureServiceBusService) scheduledEnqueueTime(now time.Time) time.Time {
+	if !a.scheduleAt.IsZero() {
+		return a.scheduleAt
+	}
+	if a.scheduleAfter > 0 {
+		return now.Add(a.scheduleAfter)
+	}
+	return time.Time{}
+}
+
 // sendWebhookRequest sends the webhook request to the Service Bus gateway
 func (a *AzureServiceBusService) sendWebhookRequest(ctx context.Context, payload map[string]interface{}) error {
 	// Convert payload to JSON
@@ -383,7 +585,7 @@ func (a *AzureServiceBusService) sendWebhookRequest(ctx context.Context, payload
 	// Check response status
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("Service Bus webhook error (status %d): %s", resp.StatusCode, string(body))
+		return NewHTTPError(resp, fmt.Errorf("Service Bus webhook error (status %d): %s", resp.StatusCode, string(body)))
 	}
 
 	return nil
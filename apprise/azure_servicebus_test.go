@@ -3,9 +3,11 @@ package apprise
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -628,3 +630,195 @@ func TestAzureServiceBusService_ParseInt(t *testing.T) {
 		})
 	}
 }
+
+func TestAzureServiceBusService_ParseDirectURL(t *testing.T) {
+	connStr := url.QueryEscape("Endpoint=sb://OtherBus.servicebus.windows.net/;SharedAccessKeyName=SendOnly;SharedAccessKey=c2VjcmV0+a2V5;EntityPath=alerts")
+
+	tests := []struct {
+		name       string
+		url        string
+		apiURL     string
+		entityPath string
+		keyName    string
+		key        string
+	}{
+		{
+			name:       "namespace host",
+			url:        "azuresb://mybus.servicebus.windows.net/?queue=notifications&sas_key_name=SendOnly&sas_key=secret",
+			apiURL:     "https://mybus.servicebus.windows.net",
+			entityPath: "notifications",
+			keyName:    "SendOnly",
+			key:        "secret",
+		},
+		{
+			name:       "namespace parameter",
+			url:        "azuresb://servicebus.chinacloudapi.cn/?namespace=mybus&topic=alerts&sas_key=secret",
+			apiURL:     "https://mybus.servicebus.chinacloudapi.cn",
+			entityPath: "alerts",
+			keyName:    "RootManageSharedAccessKey",
+			key:        "secret",
+		},
+		{
+			name:       "connection string",
+			url:        "azuresb://servicebus.windows.net/?connection_string=" + connStr,
+			apiURL:     "https://otherbus.servicebus.windows.net",
+			entityPath: "alerts",
+			keyName:    "SendOnly",
+			key:        "c2VjcmV0+a2V5",
+		},
+		{
+			name:       "queue overrides the entity path",
+			url:        "azuresb://servicebus.windows.net/?queue=audit&connection_string=" + connStr,
+			apiURL:     "https://otherbus.servicebus.windows.net",
+			entityPath: "audit",
+			keyName:    "SendOnly",
+			key:        "c2VjcmV0+a2V5",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewAzureServiceBusService().(*AzureServiceBusService)
+			serviceURL, _ := url.Parse(tt.url)
+			if err := service.ParseURL(serviceURL); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if service.apiURL != tt.apiURL || service.entityPath != tt.entityPath ||
+				service.sasKeyName != tt.keyName || service.sasKey != tt.key {
+				t.Errorf("Unexpected configuration: apiURL=%s entityPath=%s keyName=%s key=%s",
+					service.apiURL, service.entityPath, service.sasKeyName, service.sasKey)
+			}
+		})
+	}
+
+	invalid := []string{
+		"azuresb://servicebus.windows.net/?queue=notifications&sas_key=secret",
+		"azuresb://mybus.servicebus.windows.net/?queue=notifications",
+		"azuresb://mybus.servicebus.windows.net/?sas_key=secret",
+		"azuresb://mybus.servicebus.windows.net/?queue=notifications&sas_key=secret&scheduled_enqueue_time=tomorrow",
+		"azuresb://servicebus.windows.net/?queue=notifications&connection_string=Endpoint%3Dsb%3A%2F%2Fmybus.servicebus.windows.net%2F",
+	}
+	for _, rawURL := range invalid {
+		if err := NewAzureServiceBusService().TestURL(rawURL); err == nil {
+			t.Errorf("Expected an error for %s", rawURL)
+		}
+	}
+}
+
+func TestAzureServiceBusService_SendDirect(t *testing.T) {
+	server := newStubAPI(t, answerStatus(http.StatusCreated))
+	endpoint, _ := ParseEndpoint(server.URL)
+	ctx := withEndpoint(context.Background(), endpoint)
+
+	service := NewAzureServiceBusService().(*AzureServiceBusService)
+	serviceURL, _ := url.Parse("azuresb://mybus.servicebus.windows.net/?queue=notifications&sas_key_name=SendOnly&sas_key=secret" +
+		"&ttl=600&session_id=ops&scheduled_enqueue_time=2030-01-02T03:04:05Z&prop_environment=prod")
+	if err := service.ParseURL(serviceURL); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	err := service.Send(ctx, NotificationRequest{Title: "Deploy", Body: "Finished", NotifyType: NotifyTypeSuccess})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	received := server.received()
+	if len(received) != 1 {
+		t.Fatalf("Expected 1 request, got %d", len(received))
+	}
+	request := received[0]
+	if request.path != "/notifications/messages" {
+		t.Errorf("Unexpected path %s", request.path)
+	}
+
+	// The token must be signed with the key for the queue's URI
+	fields, err := url.ParseQuery(strings.TrimPrefix(request.authorization, "SharedAccessSignature "))
+	if err != nil {
+		t.Fatalf("Invalid authorization %q", request.authorization)
+	}
+	expiry, _ := strconv.ParseInt(fields.Get("se"), 10, 64)
+	if request.authorization != azureSASToken("https://mybus.servicebus.windows.net/notifications", "SendOnly", "secret", time.Unix(expiry, 0)) {
+		t.Errorf("Unexpected authorization %q", request.authorization)
+	}
+	if until := time.Until(time.Unix(expiry, 0)); until < 50*time.Minute || until > time.Hour {
+		t.Errorf("Expected the token to expire in an hour, got %v", until)
+	}
+
+	var properties map[string]interface{}
+	_ = json.Unmarshal([]byte(request.header.Get("BrokerProperties")), &properties)
+	want := map[string]interface{}{
+		"Label":                   "Deploy",
+		"TimeToLive":              float64(600),
+		"SessionId":               "ops",
+		"ScheduledEnqueueTimeUtc": "Wed, 02 Jan 2030 03:04:05 GMT",
+	}
+	for key, value := range want {
+		if properties[key] != value {
+			t.Errorf("Expected broker property %s=%v, got %v", key, value, properties[key])
+		}
+	}
+
+	if request.header.Get("environment") != `"prod"` || request.header.Get("NotificationType") != `"success"` {
+		t.Errorf("Expected quoted custom properties, got %v", request.header)
+	}
+
+	var message map[string]interface{}
+	if err := json.Unmarshal(request.body, &message); err != nil || message["body"] != "Finished" {
+		t.Errorf("Unexpected message body %s", request.body)
+	}
+}
+
+func TestAzureServiceBusService_SendDirectSignature(t *testing.T) {
+	server := newStubAPI(t, answerStatus(http.StatusCreated))
+	endpoint, _ := ParseEndpoint(server.URL)
+	ctx := withEndpoint(context.Background(), endpoint)
+
+	signature := "SharedAccessSignature sr=https%3a%2f%2fmybus.servicebus.windows.net&sig=abc&se=1&skn=SendOnly"
+	connStr := url.QueryEscape("Endpoint=sb://mybus.servicebus.windows.net/;SharedAccessSignature=" + signature + ";EntityPath=alerts")
+	service := NewAzureServiceBusService().(*AzureServiceBusService)
+	serviceURL, _ := url.Parse("azuresb://servicebus.windows.net/?connection_string=" + connStr + "&scheduled_enqueue_time=5m")
+	if err := service.ParseURL(serviceURL); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := service.Send(ctx, NotificationRequest{Body: "Disk almost full", NotifyType: NotifyTypeWarning}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	request := server.received()[0]
+	if request.path != "/alerts/messages" || request.authorization != signature {
+		t.Errorf("Unexpected request to %s with %q", request.path, request.authorization)
+	}
+	var properties map[string]interface{}
+	_ = json.Unmarshal([]byte(request.header.Get("BrokerProperties")), &properties)
+	if properties["Label"] != "Apprise Notification (warning)" {
+		t.Errorf("Expected the default label, got %v", properties["Label"])
+	}
+	scheduled, err := time.Parse(http.TimeFormat, properties["ScheduledEnqueueTimeUtc"].(string))
+	if until := time.Until(scheduled); err != nil || until < 4*time.Minute || until > 5*time.Minute {
+		t.Errorf("Expected the message to be scheduled in 5 minutes, got %v (%v)", until, err)
+	}
+}
+
+func TestAzureServiceBusService_SendDirectError(t *testing.T) {
+	server := newStubAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte("<Error><Code>401</Code><Detail>InvalidSignature: The token has an invalid signature.</Detail></Error>"))
+	})
+	endpoint, _ := ParseEndpoint(server.URL)
+	ctx := withEndpoint(context.Background(), endpoint)
+
+	service := NewAzureServiceBusService().(*AzureServiceBusService)
+	serviceURL, _ := url.Parse("azuresb://mybus.servicebus.windows.net/?queue=notifications&sas_key=wrong")
+	if err := service.ParseURL(serviceURL); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	err := service.Send(ctx, NotificationRequest{Body: "Test"})
+	if !errors.Is(err, ErrAuthFailure) || IsRetryable(err) {
+		t.Errorf("Expected a permanent authentication failure, got %v", err)
+	}
+	if err == nil || !strings.Contains(err.Error(), "InvalidSignature") {
+		t.Errorf("Expected the error detail in %v", err)
+	}
+}
@@ -84,7 +84,7 @@ func BenchmarkApprise_Notify(b *testing.B) {
 
 	// Add mock services
 	mockService := NewMockService("mock", 0)
-	app.services = append(app.services, mockService)
+	app.services = append(app.services, serviceEntry{service: mockService})
 
 	title := "Benchmark Test"
 	body := "This is a benchmark notification message"
@@ -109,7 +109,7 @@ func BenchmarkApprise_NotifyMultipleServices(b *testing.B) {
 			// Add multiple mock services
 			for i := 0; i < count; i++ {
 				mockService := NewMockService(fmt.Sprintf("mock_%d", i), 0)
-				app.services = append(app.services, mockService)
+				app.services = append(app.services, serviceEntry{service: mockService})
 			}
 
 			title := "Benchmark Test"
@@ -134,7 +134,7 @@ func BenchmarkApprise_NotifyWithDelay(b *testing.B) {
 		b.Run(fmt.Sprintf("Delay_%v", delay), func(b *testing.B) {
 			app := New()
 			mockService := NewMockService("mock", delay)
-			app.services = append(app.services, mockService)
+			app.services = append(app.services, serviceEntry{service: mockService})
 
 			title := "Benchmark Test"
 			body := "This is a benchmark notification message"
@@ -154,7 +154,7 @@ func BenchmarkApprise_NotifyWithDelay(b *testing.B) {
 func BenchmarkApprise_ConcurrentNotify(b *testing.B) {
 	app := New()
 	mockService := NewMockService("mock", 1*time.Millisecond)
-	app.services = append(app.services, mockService)
+	app.services = append(app.services, serviceEntry{service: mockService})
 
 	title := "Benchmark Test"
 	body := "This is a benchmark notification message"
@@ -331,7 +331,7 @@ func BenchmarkWebhookService_RealHTTP(b *testing.B) {
 func BenchmarkApprise_NotifyWithAttachments(b *testing.B) {
 	app := New()
 	mockService := NewMockService("mock", 0)
-	app.services = append(app.services, mockService)
+	app.services = append(app.services, serviceEntry{service: mockService})
 
 	// Add test attachments
 	smallData := bytes.Repeat([]byte("test"), 250)   // 1KB
@@ -365,7 +365,7 @@ func BenchmarkApprise_MemoryAllocation(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		app := New()
 		mockService := NewMockService("mock", 0)
-		app.services = append(app.services, mockService)
+		app.services = append(app.services, serviceEntry{service: mockService})
 
 		responses := app.Notify(title, body, NotifyTypeInfo)
 		if !responses[0].Success {
@@ -381,7 +381,7 @@ func BenchmarkApprise_Timeout(b *testing.B) {
 
 	// Add a slow mock service that will timeout
 	slowService := NewMockService("slow", 100*time.Millisecond)
-	app.services = append(app.services, slowService)
+	app.services = append(app.services, serviceEntry{service: slowService})
 
 	title := "Benchmark Test"
 	body := "This is a benchmark notification for timeout testing"
@@ -0,0 +1,332 @@
+package apprise
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// CircuitState is the state of a service URL's circuit breaker
+type CircuitState string
+
+const (
+	// CircuitClosed lets every send through
+	CircuitClosed CircuitState = "closed"
+	// CircuitOpen rejects sends until the cooldown has passed
+	CircuitOpen CircuitState = "open"
+	// CircuitHalfOpen lets a single probe through to test the provider
+	CircuitHalfOpen CircuitState = "half_open"
+)
+
+// CircuitBreakerPolicy controls when the circuit of a service URL opens.
+// After FailureThreshold consecutive failures in one of the FailOn classes
+// the circuit opens and sends fail immediately with ErrCircuitOpen. Once
+// Cooldown has passed a single probe is let through: success closes the
+// circuit, failure opens it for another cooldown.
+type CircuitBreakerPolicy struct {
+	FailureThreshold int           // consecutive failures that open the circuit (0 = no breaker)
+	Cooldown         time.Duration // time an open circuit waits before a probe
+	FailOn           []ErrorClass  // error classes that count as a failing provider
+}
+
+// DefaultCircuitBreakerPolicy returns the policy used when none is
+// configured. Only failures that point at an unavailable provider count;
+// errors the provider answered deliberately, such as bad credentials, do not.
+func DefaultCircuitBreakerPolicy() CircuitBreakerPolicy {
+	return CircuitBreakerPolicy{
+		FailureThreshold: 5,
+		Cooldown:         30 * time.Second,
+		FailOn: []ErrorClass{
+			ErrorClassTimeout,
+			ErrorClassTransientNetwork,
+			ErrorClassRemote5xx,
+		},
+	}
+}
+
+// Enabled reports whether the policy opens circuits at all
+func (p CircuitBreakerPolicy) Enabled() bool {
+	return p.FailureThreshold > 0
+}
+
+// countsFailure reports whether err counts towards opening the circuit
+func (p CircuitBreakerPolicy) countsFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	class := ClassifyError(err)
+	for _, failing := range p.FailOn {
+		if class == failing {
+			return true
+		}
+	}
+	return false
+}
+
+// SetCircuitBreakerPolicy sets the default circuit breaker policy for all
+// services. Services added with breaker URL parameters keep their own
+// threshold and cooldown.
+func (a *Apprise) SetCircuitBreakerPolicy(policy CircuitBreakerPolicy) {
+	a.breakerPolicy = policy
+}
+
+// GetCircuitBreakerPolicy returns the default circuit breaker policy
+func (a *Apprise) GetCircuitBreakerPolicy() CircuitBreakerPolicy {
+	return a.breakerPolicy
+}
+
+// breakerOverrides holds per-URL circuit breaker settings parsed from query
+// parameters
+type breakerOverrides struct {
+	threshold *int
+	cooldown  time.Duration
+}
+
+// parseBreakerOverrides extracts breaker and breaker_cooldown from the URL
+// query and removes them so services never see them:
+//
+//	breaker=3              open after 3 consecutive failures
+//	breaker=off            never open the circuit for this URL
+//	breaker_cooldown=1m    wait a minute before probing an open circuit
+func parseBreakerOverrides(query url.Values) (breakerOverrides, error) {
+	var overrides breakerOverrides
+
+	if value := query.Get("breaker"); value != "" {
+		threshold := 0
+		if value != "off" {
+			var err error
+			threshold, err = strconv.Atoi(value)
+			if err != nil || threshold < 0 {
+				return overrides, fmt.Errorf("invalid breaker value %q: must be a non-negative integer or off", value)
+			}
+		}
+		overrides.threshold = &threshold
+	}
+	query.Del("breaker")
+
+	if value := query.Get("breaker_cooldown"); value != "" {
+		cooldown, err := time.ParseDuration(value)
+		if err != nil || cooldown <= 0 {
+			return overrides, fmt.Errorf("invalid breaker_cooldown value %q: must be a duration such as 30s or 5m", value)
+		}
+		overrides.cooldown = cooldown
+	}
+	query.Del("breaker_cooldown")
+
+	return overrides, nil
+}
+
+// apply returns policy with the per-URL overrides applied
+func (o breakerOverrides) apply(policy CircuitBreakerPolicy) CircuitBreakerPolicy {
+	if o.threshold != nil {
+		policy.FailureThreshold = *o.threshold
+	}
+	if o.cooldown > 0 {
+		policy.Cooldown = o.cooldown
+	}
+	return policy
+}
+
+// circuitKey identifies a configured service URL. The key is a digest of
+// the URL, which keeps secrets out of the breaker table, metrics and the
+// health report.
+func circuitKey(serviceURL *url.URL) string {
+	sum := sha256.Sum256([]byte(serviceURL.String()))
+	return hex.EncodeToString(sum[:8])
+}
+
+// breakerFor returns the circuit breaker guarding an entry's sends, or nil
+// when the entry has no breaker
+func (a *Apprise) breakerFor(entry serviceEntry) (*circuitBreaker, CircuitBreakerPolicy) {
+	policy := entry.breaker.apply(a.breakerPolicy)
+	if !policy.Enabled() {
+		return nil, policy
+	}
+	return circuitBreakers.get(entry.service.GetServiceID(), entry.circuitKey), policy
+}
+
+// CircuitBreakerStatus reports the state of one service URL's circuit
+type CircuitBreakerStatus struct {
+	ServiceID           string       `json:"service_id"`
+	Circuit             string       `json:"circuit"`
+	State               CircuitState `json:"state"`
+	ConsecutiveFailures int          `json:"consecutive_failures"`
+	OpenedAt            *time.Time   `json:"opened_at,omitempty"`
+	RetryAt             *time.Time   `json:"retry_at,omitempty"`
+}
+
+// CircuitBreakerStates returns the state of every circuit in the process
+// that has seen a send, ordered by service ID. Circuits are identified by a
+// digest of the service URL.
+func CircuitBreakerStates() []CircuitBreakerStatus {
+	return circuitBreakers.states()
+}
+
+// circuitBreakers holds the circuit breakers of every Apprise instance in
+// the process, so a provider that is down is skipped however many instances
+// send to it
+var circuitBreakers = &breakerRegistry{breakers: make(map[string]*circuitBreaker)}
+
+// maxIdleBreakers is the table size above which healthy breakers are pruned
+const maxIdleBreakers = 1024
+
+// breakerRegistry maps service URLs to their circuit breakers
+type breakerRegistry struct {
+	mu       sync.Mutex
+	breakers map[string]*circuitBreaker
+}
+
+// get returns the breaker for a service URL, creating it if needed
+func (r *breakerRegistry) get(serviceID, key string) *circuitBreaker {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := serviceID + "|" + key
+	if breaker, ok := r.breakers[id]; ok {
+		return breaker
+	}
+
+	// A closed breaker without failures is the same as a new one, so
+	// dropping it loses nothing
+	if len(r.breakers) >= maxIdleBreakers {
+		for k, breaker := range r.breakers {
+			if breaker.idle() {
+				delete(r.breakers, k)
+			}
+		}
+	}
+
+	breaker := &circuitBreaker{serviceID: serviceID, key: key, state: CircuitClosed}
+	r.breakers[id] = breaker
+	return breaker
+}
+
+// states returns a snapshot of every breaker
+func (r *breakerRegistry) states() []CircuitBreakerStatus {
+	r.mu.Lock()
+	breakers := make([]*circuitBreaker, 0, len(r.breakers))
+	for _, breaker := range r.breakers {
+		breakers = append(breakers, breaker)
+	}
+	r.mu.Unlock()
+
+	states := make([]CircuitBreakerStatus, len(breakers))
+	for i, breaker := range breakers {
+		states[i] = breaker.status()
+	}
+	sort.Slice(states, func(i, j int) bool {
+		if states[i].ServiceID != states[j].ServiceID {
+			return states[i].ServiceID < states[j].ServiceID
+		}
+		return states[i].Circuit < states[j].Circuit
+	})
+	return states
+}
+
+// circuitBreaker tracks the consecutive failures of one service URL
+type circuitBreaker struct {
+	mu        sync.Mutex
+	serviceID string
+	key       string
+	state     CircuitState
+	failures  int
+	probing   bool // a half-open probe is in flight
+	openedAt  time.Time
+	retryAt   time.Time
+}
+
+// allow reports whether a send may go ahead. An open circuit whose
+// cooldown has passed turns half-open and lets the caller probe; everyone
+// else gets a circuit open error until the probe has finished.
+func (b *circuitBreaker) allow(now time.Time) (CircuitState, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == CircuitOpen && !now.Before(b.retryAt) {
+		b.state = CircuitHalfOpen
+		b.probing = false
+	}
+
+	switch {
+	case b.state == CircuitClosed:
+		return b.state, nil
+	case b.state == CircuitHalfOpen && !b.probing:
+		b.probing = true
+		return b.state, nil
+	}
+
+	err := NewNotificationError(ErrorClassCircuitOpen, 0,
+		fmt.Errorf("circuit open after %d consecutive failures", b.failures))
+	if b.state == CircuitOpen {
+		err.RetryAfter = b.retryAt.Sub(now)
+	}
+	return b.state, err
+}
+
+// release gives up a half-open probe that was allowed but never sent
+func (b *circuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+// record updates the breaker with the result of a send and returns the new
+// state and whether it changed. Errors outside the policy's FailOn classes
+// show the provider is answering, so they count as success.
+func (b *circuitBreaker) record(err error, policy CircuitBreakerPolicy, now time.Time) (CircuitState, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	previous := b.state
+	b.probing = false
+
+	if !policy.countsFailure(err) {
+		b.failures = 0
+		b.state = CircuitClosed
+		return b.state, b.state != previous
+	}
+
+	b.failures++
+	if b.state == CircuitHalfOpen || b.failures >= policy.FailureThreshold {
+		b.state = CircuitOpen
+		b.openedAt = now
+		b.retryAt = now.Add(policy.Cooldown)
+	}
+	return b.state, b.state != previous
+}
+
+// idle reports whether the breaker is closed without recent failures
+func (b *circuitBreaker) idle() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state == CircuitClosed && b.failures == 0
+}
+
+// status returns a snapshot of the breaker
+func (b *circuitBreaker) status() CircuitBreakerStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	status := CircuitBreakerStatus{
+		ServiceID:           b.serviceID,
+		Circuit:             b.key,
+		State:               b.state,
+		ConsecutiveFailures: b.failures,
+	}
+	if b.state == CircuitOpen && !time.Now().Before(b.retryAt) {
+		// The next send will probe
+		status.State = CircuitHalfOpen
+	}
+	if b.state != CircuitClosed {
+		openedAt, retryAt := b.openedAt, b.retryAt
+		status.OpenedAt = &openedAt
+		status.RetryAt = &retryAt
+	}
+	return status
+}
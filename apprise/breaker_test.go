@@ -0,0 +1,136 @@
+package apprise
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseBreakerOverrides(t *testing.T) {
+	app := New()
+	for _, value := range []string{"breaker=-1", "breaker=many", "breaker_cooldown=soon", "breaker_cooldown=0s"} {
+		if err := app.Add("webhook://localhost/hook?" + value); err == nil {
+			t.Errorf("Expected %s to be rejected", value)
+		}
+	}
+
+	if err := app.Add("webhook://localhost/hook?breaker=off&breaker_cooldown=1m"); err != nil {
+		t.Fatalf("Failed to add service: %v", err)
+	}
+	entry := app.services[0]
+	if policy := entry.breaker.apply(app.GetCircuitBreakerPolicy()); policy.Enabled() || policy.Cooldown != time.Minute {
+		t.Errorf("Expected a disabled breaker with a 1m cooldown, got %+v", policy)
+	}
+	if strings.Contains(entry.service.(*WebhookService).webhookURL, "breaker") {
+		t.Error("Expected breaker parameters to be stripped from the service URL")
+	}
+}
+
+func TestCircuitBreakerOpensAndRecovers(t *testing.T) {
+	// The provider answers 503 while failing is set
+	var failing atomic.Bool
+	failing.Store(true)
+	server := newStubAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	app := New()
+	if err := app.Add("webhook://" + strings.TrimPrefix(server.URL, "http://") + "/breaker?breaker=2&breaker_cooldown=100ms"); err != nil {
+		t.Fatalf("Failed to add service: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		resp := app.NotifyAll(NotificationRequest{Body: "down"})
+		if !errors.Is(resp[0].Error, ErrRemote5xx) {
+			t.Fatalf("Send %d: expected a remote error, got %v", i+1, resp[0].Error)
+		}
+	}
+
+	// The circuit is open: no request reaches the provider
+	resp := app.NotifyAll(NotificationRequest{Body: "skipped"})
+	if !errors.Is(resp[0].Error, ErrCircuitOpen) || ClassifyError(resp[0].Error) != ErrorClassCircuitOpen {
+		t.Fatalf("Expected a circuit open error, got %v", resp[0].Error)
+	}
+	if GetRetryAfter(resp[0].Error) <= 0 {
+		t.Error("Expected the circuit open error to carry the remaining cooldown")
+	}
+	if got := len(server.received()); got != 2 {
+		t.Errorf("Expected 2 requests to reach the provider, got %d", got)
+	}
+
+	circuit := app.services[0].circuitKey
+	if status := findCircuit(t, circuit); status.State != CircuitOpen || status.ConsecutiveFailures != 2 || status.RetryAt == nil {
+		t.Errorf("Unexpected open circuit status: %+v", status)
+	}
+
+	// A failed probe after the cooldown opens the circuit again
+	time.Sleep(120 * time.Millisecond)
+	if resp := app.NotifyAll(NotificationRequest{Body: "probe"}); !errors.Is(resp[0].Error, ErrRemote5xx) {
+		t.Fatalf("Expected the probe to reach the provider, got %v", resp[0].Error)
+	}
+	if resp := app.NotifyAll(NotificationRequest{Body: "skipped"}); !errors.Is(resp[0].Error, ErrCircuitOpen) {
+		t.Fatalf("Expected the circuit to reopen after a failed probe, got %v", resp[0].Error)
+	}
+
+	// A successful probe closes it
+	failing.Store(false)
+	time.Sleep(120 * time.Millisecond)
+	for i := 0; i < 2; i++ {
+		if resp := app.NotifyAll(NotificationRequest{Body: "up"}); !resp[0].Success {
+			t.Fatalf("Send %d after recovery failed: %v", i+1, resp[0].Error)
+		}
+	}
+	if status := findCircuit(t, circuit); status.State != CircuitClosed || status.ConsecutiveFailures != 0 {
+		t.Errorf("Expected the circuit to be closed, got %+v", status)
+	}
+}
+
+func TestCircuitBreakerIgnoresClientErrors(t *testing.T) {
+	breaker := &circuitBreaker{state: CircuitClosed}
+	policy := CircuitBreakerPolicy{FailureThreshold: 1, Cooldown: time.Minute, FailOn: DefaultCircuitBreakerPolicy().FailOn}
+
+	authErr := NewNotificationError(ErrorClassAuth, http.StatusUnauthorized, errors.New("bad token"))
+	if state, _ := breaker.record(authErr, policy, time.Now()); state != CircuitClosed {
+		t.Errorf("Expected an auth failure to leave the circuit closed, got %s", state)
+	}
+
+	timeoutErr := NewNotificationError(ErrorClassTimeout, 0, errors.New("slow"))
+	if state, changed := breaker.record(timeoutErr, policy, time.Now()); state != CircuitOpen || !changed {
+		t.Errorf("Expected a timeout to open the circuit, got %s", state)
+	}
+}
+
+func TestCircuitBreakerSingleProbe(t *testing.T) {
+	now := time.Now()
+	breaker := &circuitBreaker{state: CircuitOpen, failures: 3, retryAt: now}
+
+	if state, err := breaker.allow(now); err != nil || state != CircuitHalfOpen {
+		t.Fatalf("Expected the first send after the cooldown to probe, got %s (%v)", state, err)
+	}
+	if _, err := breaker.allow(now); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Expected concurrent sends to be rejected during the probe, got %v", err)
+	}
+
+	breaker.release()
+	if _, err := breaker.allow(now); err != nil {
+		t.Errorf("Expected a released probe to be available again, got %v", err)
+	}
+}
+
+func findCircuit(t *testing.T, circuit string) CircuitBreakerStatus {
+	t.Helper()
+	for _, status := range CircuitBreakerStates() {
+		if status.Circuit == circuit {
+			return status
+		}
+	}
+	t.Fatalf("Circuit %s not found", circuit)
+	return CircuitBreakerStatus{}
+}
@@ -29,7 +29,7 @@ type BulkSMSRequest struct {
 // NewBulkSMSService creates a new BulkSMS service instance
 func NewBulkSMSService() Service {
 	return &BulkSMSService{
-		client: &http.Client{},
+		client: GetCloudHTTPClient("bulksms"),
 	}
 }
 
@@ -169,7 +169,7 @@ func (s *BulkSMSService) sendSMS(ctx context.Context, to, message string) error
 
 	// Check response status
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("BulkSMS API returned status %d", resp.StatusCode)
+		return NewHTTPError(resp, fmt.Errorf("BulkSMS API returned status %d", resp.StatusCode))
 	}
 
 	return nil
@@ -183,4 +183,9 @@ func (s *BulkSMSService) SupportsAttachments() bool {
 // GetMaxBodyLength returns max body length (0 = unlimited)
 func (s *BulkSMSService) GetMaxBodyLength() int {
 	return 160 // Standard SMS length
+}
+
+// GetNativeFormat returns plain text, as SMS does not render markup
+func (s *BulkSMSService) GetNativeFormat() string {
+	return BodyFormatText
 }
\ No newline at end of file
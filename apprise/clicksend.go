@@ -34,7 +34,7 @@ type ClickSendRequest struct {
 // NewClickSendService creates a new ClickSend service instance
 func NewClickSendService() Service {
 	return &ClickSendService{
-		client: &http.Client{},
+		client: GetCloudHTTPClient("clicksend"),
 	}
 }
 
@@ -165,7 +165,7 @@ func (s *ClickSendService) sendSMS(ctx context.Context, payload ClickSendRequest
 
 	// Check response status
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("ClickSend API returned status %d", resp.StatusCode)
+		return NewHTTPError(resp, fmt.Errorf("ClickSend API returned status %d", resp.StatusCode))
 	}
 
 	return nil
@@ -179,4 +179,9 @@ func (s *ClickSendService) SupportsAttachments() bool {
 // GetMaxBodyLength returns max body length (0 = unlimited)
 func (s *ClickSendService) GetMaxBodyLength() int {
 	return 1600 // ClickSend supports up to 1600 characters
+}
+
+// GetNativeFormat returns plain text, as SMS does not render markup
+func (s *ClickSendService) GetNativeFormat() string {
+	return BodyFormatText
 }
\ No newline at end of file
@@ -0,0 +1,348 @@
+package apprise
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Reload reads every configuration source again and atomically replaces the
+// services this loader applied with the ones now configured. If a source
+// cannot be read or parsed, or a URL is invalid, the current services stay
+// active and the error is returned. It returns the number of services
+// loaded from the configuration.
+func (cl *ConfigLoader) Reload() (int, error) {
+	cl.reloadMu.Lock()
+	defer cl.reloadMu.Unlock()
+
+	count, err := cl.reload()
+	status := "success"
+	if err != nil {
+		status = "failed"
+	}
+	cl.apprise.metrics.RecordConfigReload(status)
+	return count, err
+}
+
+// reload does the work of Reload
+func (cl *ConfigLoader) reload() (int, error) {
+	cl.mu.Lock()
+	sources := append([]configSource(nil), cl.sources...)
+	fresh := &ConfigLoader{apprise: cl.apprise, includeDepth: cl.includeDepth}
+	cl.mu.Unlock()
+
+	for _, source := range sources {
+		if err := fresh.addSource(source); err != nil {
+			return 0, err
+		}
+	}
+
+	entries, err := cl.entries(fresh.configs)
+	if err != nil {
+		return 0, err
+	}
+
+	cl.mu.Lock()
+	cl.configs = fresh.configs
+	cl.files = fresh.files
+	cl.mu.Unlock()
+	cl.apprise.replaceServices(cl, entries)
+	return len(entries), nil
+}
+
+// Reload triggers
+const (
+	ReloadTriggerFSNotify = "fsnotify"
+	ReloadTriggerPoll     = "poll"
+)
+
+// ReloadEvent describes a configuration reload done by a ConfigWatcher
+type ReloadEvent struct {
+	Time     time.Time
+	Trigger  string   // ReloadTriggerFSNotify or ReloadTriggerPoll
+	Files    []string // changed files that caused the reload
+	Services int      // services loaded from the configuration
+	Err      error    // why the reload failed; the previous services stay active
+}
+
+// ConfigWatcher reloads a ConfigLoader when one of its configuration files
+// changes. It uses file system notifications where available and falls back
+// to polling; either way a file only counts as changed when its contents do.
+//
+//	watcher := apprise.NewConfigWatcher(loader)
+//	watcher.OnReload(func(event apprise.ReloadEvent) { ... })
+//	if err := watcher.Start(); err != nil { ... }
+//	defer watcher.Stop()
+type ConfigWatcher struct {
+	files        func() []string                     // files to watch
+	reloadFiles  func(changed []string) (int, error) // reloads after files changed
+	pollInterval time.Duration
+	debounce     time.Duration
+	forcePolling bool
+
+	mu       sync.Mutex
+	handlers []func(ReloadEvent)
+	trigger  string
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewConfigWatcher creates a watcher for the files loaded by loader
+func NewConfigWatcher(loader *ConfigLoader) *ConfigWatcher {
+	return newFileWatcher(loader.Files, func([]string) (int, error) {
+		return loader.Reload()
+	})
+}
+
+// newFileWatcher creates a watcher calling reload with the changed files
+// whenever some of the given files change
+func newFileWatcher(files func() []string, reload func(changed []string) (int, error)) *ConfigWatcher {
+	return &ConfigWatcher{
+		files:        files,
+		reloadFiles:  reload,
+		pollInterval: 2 * time.Second,
+		debounce:     100 * time.Millisecond,
+	}
+}
+
+// SetPollInterval sets how often files are checked when polling
+func (w *ConfigWatcher) SetPollInterval(interval time.Duration) {
+	w.pollInterval = interval
+}
+
+// SetForcePolling makes the watcher poll even when file system
+// notifications are available, for file systems that do not deliver them
+// (such as some network mounts)
+func (w *ConfigWatcher) SetForcePolling(force bool) {
+	w.forcePolling = force
+}
+
+// OnReload registers a function called after every reload, successful or
+// not. Handlers run on the watcher's goroutine.
+func (w *ConfigWatcher) OnReload(handler func(ReloadEvent)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.handlers = append(w.handlers, handler)
+}
+
+// Trigger returns how changes are detected, ReloadTriggerFSNotify or
+// ReloadTriggerPoll, once the watcher has started
+func (w *ConfigWatcher) Trigger() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.trigger
+}
+
+// Start begins watching the loader's configuration files
+func (w *ConfigWatcher) Start() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.stop != nil {
+		return fmt.Errorf("config watcher already started")
+	}
+
+	files := w.files()
+	if len(files) == 0 {
+		return fmt.Errorf("no configuration files to watch")
+	}
+	if w.pollInterval <= 0 {
+		return fmt.Errorf("poll interval must be positive")
+	}
+
+	w.stop = make(chan struct{})
+	w.done = make(chan struct{})
+
+	// Take the initial states now, so changes right after Start are seen
+	states := make(map[string]fileState, len(files))
+	for _, file := range files {
+		states[file] = statFile(file)
+	}
+
+	if !w.forcePolling {
+		if notifier, err := newFileNotifier(files); err == nil {
+			w.trigger = ReloadTriggerFSNotify
+			go w.watchNotifications(notifier, states, w.stop, w.done)
+			return nil
+		}
+	}
+
+	w.trigger = ReloadTriggerPoll
+	go w.poll(states, w.stop, w.done)
+	return nil
+}
+
+// Stop stops watching and waits for a reload in progress to finish
+func (w *ConfigWatcher) Stop() {
+	w.mu.Lock()
+	stop, done := w.stop, w.done
+	w.stop, w.done = nil, nil
+	w.mu.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+}
+
+// newFileNotifier watches the directories holding files. Directories rather
+// than the files themselves are watched, so files replaced by renaming, as
+// editors and configuration management tools do, keep being watched.
+func newFileNotifier(files []string) (*fsnotify.Watcher, error) {
+	notifier, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	dirs := make(map[string]bool)
+	for _, file := range files {
+		dir := filepath.Dir(file)
+		if dirs[dir] {
+			continue
+		}
+		dirs[dir] = true
+		if err := notifier.Add(dir); err != nil {
+			_ = notifier.Close()
+			return nil, err
+		}
+	}
+	return notifier, nil
+}
+
+// watchNotifications checks the watched files once file system events in
+// their directories have settled for the debounce period, and reloads when
+// one of them differs from the states given. Besides events on the files
+// themselves, any file created, renamed or removed in the directories counts:
+// a Kubernetes ConfigMap update, for one, only swaps the ..data symlink the
+// files point through.
+func (w *ConfigWatcher) watchNotifications(notifier *fsnotify.Watcher, states map[string]fileState, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	defer func() { _ = notifier.Close() }()
+
+	files := make([]string, 0, len(states))
+	watched := make(map[string]bool, len(states))
+	for file := range states {
+		files = append(files, file)
+		watched[filepath.Clean(file)] = true
+	}
+	sort.Strings(files)
+
+	timer := time.NewTimer(w.debounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+
+		case event, ok := <-notifier.Events:
+			if !ok {
+				return
+			}
+			if event.Op == fsnotify.Chmod {
+				continue
+			}
+			if !watched[filepath.Clean(event.Name)] && !event.Has(fsnotify.Create) && !event.Has(fsnotify.Rename) && !event.Has(fsnotify.Remove) {
+				continue
+			}
+			timer.Reset(w.debounce)
+
+		case _, ok := <-notifier.Errors:
+			if !ok {
+				return
+			}
+
+		case <-timer.C:
+			if changed := changedFiles(files, states); len(changed) > 0 {
+				w.reload(ReloadTriggerFSNotify, changed)
+			}
+		}
+	}
+}
+
+// fileState is what is compared to detect a changed file
+type fileState struct {
+	exists bool
+	sum    [sha256.Size]byte
+}
+
+// statFile returns the current state of a file, following symbolic links
+func statFile(path string) fileState {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fileState{}
+	}
+	return fileState{exists: true, sum: sha256.Sum256(data)}
+}
+
+// equal reports whether two states describe the same file contents
+func (s fileState) equal(other fileState) bool {
+	return s.exists == other.exists && s.sum == other.sum
+}
+
+// changedFiles returns the files, in order, whose contents differ from
+// their states, updating the states
+func changedFiles(files []string, states map[string]fileState) []string {
+	var changed []string
+	for _, file := range files {
+		if state := statFile(file); !state.equal(states[file]) {
+			states[file] = state
+			changed = append(changed, file)
+		}
+	}
+	return changed
+}
+
+// poll reloads when the contents of a watched file change from the states
+// given
+func (w *ConfigWatcher) poll(states map[string]fileState, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	files := make([]string, 0, len(states))
+	for file := range states {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if changed := changedFiles(files, states); len(changed) > 0 {
+				w.reload(ReloadTriggerPoll, changed)
+			}
+		}
+	}
+}
+
+// reload reloads the configuration and notifies the handlers
+func (w *ConfigWatcher) reload(trigger string, changed []string) {
+	count, err := w.reloadFiles(changed)
+	event := ReloadEvent{
+		Time:     time.Now(),
+		Trigger:  trigger,
+		Files:    changed,
+		Services: count,
+		Err:      err,
+	}
+
+	w.mu.Lock()
+	handlers := make([]func(ReloadEvent), len(w.handlers))
+	copy(handlers, w.handlers)
+	w.mu.Unlock()
+
+	for _, handler := range handlers {
+		handler(event)
+	}
+}
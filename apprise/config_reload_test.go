@@ -0,0 +1,218 @@
+package apprise
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeConfigFile(t *testing.T, path, content string) {
+	t.Helper()
+	// Write to a temporary file and rename it into place, as editors do
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		t.Fatalf("Failed to replace config: %v", err)
+	}
+}
+
+func TestConfigLoaderReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "apprise.yml")
+	writeConfigFile(t, path, `
+urls:
+  - url: webhook://localhost/one
+  - url: webhook://localhost/two
+`)
+
+	app := New()
+	loader := NewConfigLoader(app)
+	if err := loader.AddFromFile(path); err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if err := loader.ApplyToApprise(); err != nil {
+		t.Fatalf("Failed to apply config: %v", err)
+	}
+	if err := loader.ApplyToApprise(); err != nil {
+		t.Fatalf("Failed to apply config again: %v", err)
+	}
+	if err := app.Add("webhook://localhost/manual"); err != nil {
+		t.Fatalf("Failed to add service: %v", err)
+	}
+	if got := app.Count(); got != 3 {
+		t.Fatalf("Expected 3 services, got %d", got)
+	}
+
+	writeConfigFile(t, path, "urls:\n  - webhook://localhost/three\n")
+	count, err := loader.Reload()
+	if err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if count != 1 || app.Count() != 2 {
+		t.Errorf("Expected 1 configured service plus the manual one, got %d of %d", count, app.Count())
+	}
+
+	// A broken configuration keeps the services already loaded
+	writeConfigFile(t, path, "urls:\n  - webhook://localhost/four\n  - unknown://nowhere\n")
+	if _, err := loader.Reload(); err == nil {
+		t.Fatal("Expected reload of an invalid configuration to fail")
+	}
+	if got := app.Count(); got != 2 {
+		t.Errorf("Expected the previous 2 services to stay active, got %d", got)
+	}
+	if urls := loader.URLs(); len(urls) != 1 || urls[0].URL != "webhook://localhost/three" {
+		t.Errorf("Expected the loader to keep the last good configuration, got %+v", urls)
+	}
+}
+
+func TestConfigWatcherReloadsChangedFile(t *testing.T) {
+	for _, polling := range []bool{false, true} {
+		name := ReloadTriggerFSNotify
+		if polling {
+			name = ReloadTriggerPoll
+		}
+
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "apprise.txt")
+			writeConfigFile(t, path, "webhook://localhost/one\n")
+
+			app := New()
+			loader := NewConfigLoader(app)
+			if err := loader.AddFromFile(path); err != nil {
+				t.Fatalf("Failed to load config: %v", err)
+			}
+			if err := loader.ApplyToApprise(); err != nil {
+				t.Fatalf("Failed to apply config: %v", err)
+			}
+
+			watcher := NewConfigWatcher(loader)
+			watcher.SetPollInterval(20 * time.Millisecond)
+			watcher.SetForcePolling(polling)
+			events := make(chan ReloadEvent, 10)
+			watcher.OnReload(func(event ReloadEvent) { events <- event })
+			if err := watcher.Start(); err != nil {
+				t.Fatalf("Failed to start watcher: %v", err)
+			}
+			defer watcher.Stop()
+
+			if polling && watcher.Trigger() != ReloadTriggerPoll {
+				t.Errorf("Expected the watcher to poll, got %s", watcher.Trigger())
+			}
+
+			writeConfigFile(t, path, "webhook://localhost/one\nwebhook://localhost/two\n")
+			event := waitForReload(t, events)
+			if event.Err != nil || event.Services != 2 || app.Count() != 2 {
+				t.Fatalf("Expected a reload with 2 services, got %+v (count %d)", event, app.Count())
+			}
+			if len(event.Files) != 1 || event.Files[0] != path {
+				t.Errorf("Expected the changed file to be reported, got %v", event.Files)
+			}
+
+			writeConfigFile(t, path, "not a url at all://\n")
+			if event := waitForReload(t, events); event.Err == nil {
+				t.Error("Expected the reload of a broken file to report an error")
+			}
+			if got := app.Count(); got != 2 {
+				t.Errorf("Expected the previous services to stay active, got %d", got)
+			}
+		})
+	}
+}
+
+func waitForReload(t *testing.T, events <-chan ReloadEvent) ReloadEvent {
+	t.Helper()
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for a reload")
+		return ReloadEvent{}
+	}
+}
+
+func TestConfigWatcherReloadsConfigMapUpdate(t *testing.T) {
+	// Kubernetes mounts a ConfigMap as files linking through a ..data
+	// symlink, which an update swaps for one to a new directory
+	dir := t.TempDir()
+	writeVersion := func(version, content string) {
+		t.Helper()
+		if err := os.Mkdir(filepath.Join(dir, version), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, version, "apprise.txt"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Symlink(version, filepath.Join(dir, "..data_tmp")); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Rename(filepath.Join(dir, "..data_tmp"), filepath.Join(dir, "..data")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeVersion("..2026_01_01", "webhook://localhost/one\n")
+	path := filepath.Join(dir, "apprise.txt")
+	if err := os.Symlink(filepath.Join("..data", "apprise.txt"), path); err != nil {
+		t.Fatal(err)
+	}
+
+	app := New()
+	loader := NewConfigLoader(app)
+	if err := loader.AddFromFile(path); err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if err := loader.ApplyToApprise(); err != nil {
+		t.Fatalf("Failed to apply config: %v", err)
+	}
+
+	watcher := NewConfigWatcher(loader)
+	events := make(chan ReloadEvent, 10)
+	watcher.OnReload(func(event ReloadEvent) { events <- event })
+	if err := watcher.Start(); err != nil {
+		t.Fatalf("Failed to start watcher: %v", err)
+	}
+	defer watcher.Stop()
+	if watcher.Trigger() != ReloadTriggerFSNotify {
+		t.Skip("File system notifications are not available")
+	}
+
+	writeVersion("..2026_01_02", "webhook://localhost/one\nwebhook://localhost/two\n")
+	event := waitForReload(t, events)
+	if event.Err != nil || event.Services != 2 || app.Count() != 2 {
+		t.Fatalf("Expected a reload with 2 services, got %+v (count %d)", event, app.Count())
+	}
+	if len(event.Files) != 1 || event.Files[0] != path {
+		t.Errorf("Expected the changed file to be reported, got %v", event.Files)
+	}
+}
+
+func TestConfigWatcherIgnoresUnchangedContents(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "apprise.txt")
+	writeConfigFile(t, path, "webhook://localhost/one\n")
+
+	loader := NewConfigLoader(New())
+	if err := loader.AddFromFile(path); err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	watcher := NewConfigWatcher(loader)
+	watcher.SetPollInterval(10 * time.Millisecond)
+	watcher.SetForcePolling(true)
+	events := make(chan ReloadEvent, 10)
+	watcher.OnReload(func(event ReloadEvent) { events <- event })
+	if err := watcher.Start(); err != nil {
+		t.Fatalf("Failed to start watcher: %v", err)
+	}
+	defer watcher.Stop()
+
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case event := <-events:
+		t.Errorf("Expected no reload for a file with the same contents, got %+v", event)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -2,12 +2,15 @@ package apprise
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
+	"sync"
 	"text/template"
 	"time"
 )
@@ -381,12 +384,14 @@ type ConfigManager struct {
 	fileWatcher   *FileWatcher
 	templates     map[string]*ConfigTemplate
 	autoReload    bool
+	watcher       *ConfigWatcher // reloads changed templates while autoReload is on
+	mu            sync.Mutex
 }
 
-// FileWatcher watches for file changes
+// FileWatcher tracks the template files CheckForChanges compares
 type FileWatcher struct {
-	files     map[string]time.Time
-	callbacks map[string]func(string)
+	files     map[string]fileState
+	callbacks map[string]func(string) error
 }
 
 // NewConfigManager creates a new configuration manager
@@ -397,8 +402,8 @@ func NewConfigManager(configDir string) *ConfigManager {
 		outputDir:   filepath.Join(configDir, "generated"),
 		templates:   make(map[string]*ConfigTemplate),
 		fileWatcher: &FileWatcher{
-			files:     make(map[string]time.Time),
-			callbacks: make(map[string]func(string)),
+			files:     make(map[string]fileState),
+			callbacks: make(map[string]func(string) error),
 		},
 		autoReload: false,
 	}
@@ -410,9 +415,13 @@ func NewConfigManager(configDir string) *ConfigManager {
 	return cm
 }
 
-// SetAutoReload enables or disables automatic template reloading
-func (cm *ConfigManager) SetAutoReload(enabled bool) {
+// SetAutoReload enables or disables automatic template reloading. While
+// enabled, a ConfigWatcher reloads loaded templates whose files change.
+func (cm *ConfigManager) SetAutoReload(enabled bool) error {
+	cm.mu.Lock()
 	cm.autoReload = enabled
+	cm.mu.Unlock()
+	return cm.restartWatcher()
 }
 
 // LoadTemplates loads all templates from the template directory
@@ -423,28 +432,91 @@ func (cm *ConfigManager) LoadTemplates() error {
 		return fmt.Errorf("failed to find template files: %w", err)
 	}
 	
+	cm.mu.Lock()
 	for _, file := range files {
 		name := strings.TrimSuffix(filepath.Base(file), ".tmpl")
 		template := NewConfigTemplate()
 		
 		if err := template.LoadTemplateFile(file); err != nil {
+			cm.mu.Unlock()
 			return fmt.Errorf("failed to load template %s: %w", name, err)
 		}
 		
 		cm.templates[name] = template
-		
-		if cm.autoReload {
-			cm.watchFile(file, func(path string) {
-				template.LoadTemplateFile(path)
-			})
-		}
+		cm.watchFile(file, func(path string) error {
+			return template.LoadTemplateFile(path)
+		})
 	}
+	cm.mu.Unlock()
 	
+	return cm.restartWatcher()
+}
+
+// restartWatcher replaces the template watcher, if any, with one for the
+// files now loaded when auto reload is enabled
+func (cm *ConfigManager) restartWatcher() error {
+	// Stopping waits for a reload in progress, which needs cm.mu
+	cm.mu.Lock()
+	previous := cm.watcher
+	cm.watcher = nil
+	cm.mu.Unlock()
+	if previous != nil {
+		previous.Stop()
+	}
+
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	if !cm.autoReload || cm.watcher != nil || len(cm.fileWatcher.files) == 0 {
+		return nil
+	}
+
+	watcher := newFileWatcher(cm.watchedFiles, cm.reloadFiles)
+	if err := watcher.Start(); err != nil {
+		return fmt.Errorf("failed to watch templates: %w", err)
+	}
+	cm.watcher = watcher
 	return nil
 }
 
+// watchedFiles returns the template files being tracked; cm.mu must be held
+func (cm *ConfigManager) watchedFiles() []string {
+	files := make([]string, 0, len(cm.fileWatcher.files))
+	for path := range cm.fileWatcher.files {
+		files = append(files, path)
+	}
+	sort.Strings(files)
+	return files
+}
+
+// reloadFiles reloads the templates of the changed files, keeping a template
+// as it was when its file no longer parses. It returns the number of
+// templates reloaded.
+func (cm *ConfigManager) reloadFiles(changed []string) (int, error) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	reloaded := 0
+	var errs []error
+	for _, path := range changed {
+		cm.fileWatcher.files[path] = statFile(path)
+		callback, exists := cm.fileWatcher.callbacks[path]
+		if !exists {
+			continue
+		}
+		if err := callback(path); err != nil {
+			errs = append(errs, fmt.Errorf("failed to reload template %s: %w", path, err))
+			continue
+		}
+		reloaded++
+	}
+	return reloaded, errors.Join(errs...)
+}
+
 // SetVariableOnAllTemplates sets a variable on all loaded templates
 func (cm *ConfigManager) SetVariableOnAllTemplates(key string, value interface{}) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
 	for _, template := range cm.templates {
 		template.SetVariable(key, value)
 	}
@@ -452,6 +524,8 @@ func (cm *ConfigManager) SetVariableOnAllTemplates(key string, value interface{}
 
 // SetDefaultOnAllTemplates sets a default value on all loaded templates
 func (cm *ConfigManager) SetDefaultOnAllTemplates(key, value string) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
 	for _, template := range cm.templates {
 		template.SetDefault(key, value)
 	}
@@ -459,12 +533,16 @@ func (cm *ConfigManager) SetDefaultOnAllTemplates(key, value string) {
 
 // GetTemplate returns a template by name
 func (cm *ConfigManager) GetTemplate(name string) (*ConfigTemplate, bool) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
 	template, exists := cm.templates[name]
 	return template, exists
 }
 
 // GenerateConfig generates a configuration file from a template
 func (cm *ConfigManager) GenerateConfig(templateName, outputName string) error {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
 	template, exists := cm.templates[templateName]
 	if !exists {
 		return fmt.Errorf("template %s not found", templateName)
@@ -482,6 +560,8 @@ func (cm *ConfigManager) GenerateConfig(templateName, outputName string) error {
 
 // GenerateAllConfigs generates all configuration files
 func (cm *ConfigManager) GenerateAllConfigs() error {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
 	for name, template := range cm.templates {
 		outputName := name + ".conf"
 		outputPath := filepath.Join(cm.outputDir, outputName)
@@ -502,29 +582,24 @@ func (cm *ConfigManager) GenerateAllConfigs() error {
 	return nil
 }
 
-// watchFile watches a file for changes
-func (cm *ConfigManager) watchFile(path string, callback func(string)) {
-	cm.fileWatcher.files[path] = time.Now()
+// watchFile tracks a loaded template file; cm.mu must be held
+func (cm *ConfigManager) watchFile(path string, callback func(string) error) {
+	cm.fileWatcher.files[path] = statFile(path)
 	cm.fileWatcher.callbacks[path] = callback
 }
 
-// CheckForChanges checks for file changes and triggers callbacks
+// CheckForChanges reloads the templates whose files changed since they were
+// loaded. With auto reload enabled this happens by itself.
 func (cm *ConfigManager) CheckForChanges() error {
-	for path, lastMod := range cm.fileWatcher.files {
-		stat, err := os.Stat(path)
-		if err != nil {
-			continue
-		}
-		
-		if stat.ModTime().After(lastMod) {
-			cm.fileWatcher.files[path] = stat.ModTime()
-			if callback, exists := cm.fileWatcher.callbacks[path]; exists {
-				callback(path)
-			}
-		}
+	cm.mu.Lock()
+	changed := changedFiles(cm.watchedFiles(), cm.fileWatcher.files)
+	cm.mu.Unlock()
+
+	if len(changed) == 0 {
+		return nil
 	}
-	
-	return nil
+	_, err := cm.reloadFiles(changed)
+	return err
 }
 
 // EnvironmentLoader provides environment-specific configuration loading
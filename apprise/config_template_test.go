@@ -548,4 +548,47 @@ Missing Secret: {{index .Secrets "MISSING_SECRET"}}`
 	if !strings.Contains(result, "Missing Secret: ") {
 		t.Errorf("Expected missing secret to be empty, got: %s", result)
 	}
-}
\ No newline at end of file
+}
+
+func TestConfigManager_AutoReload(t *testing.T) {
+	tempDir := t.TempDir()
+	manager := NewConfigManager(tempDir)
+
+	templateFile := filepath.Join(tempDir, "templates", "service.tmpl")
+	if err := os.WriteFile(templateFile, []byte("version = 1"), 0644); err != nil {
+		t.Fatalf("Failed to create template file: %v", err)
+	}
+	if err := manager.LoadTemplates(); err != nil {
+		t.Fatalf("Failed to load templates: %v", err)
+	}
+	if err := manager.SetAutoReload(true); err != nil {
+		t.Fatalf("Failed to enable auto reload: %v", err)
+	}
+	defer manager.SetAutoReload(false)
+
+	render := func() string {
+		if err := manager.GenerateConfig("service", "service.conf"); err != nil {
+			t.Fatalf("Failed to generate config: %v", err)
+		}
+		content, _ := os.ReadFile(filepath.Join(tempDir, "generated", "service.conf"))
+		return string(content)
+	}
+
+	writeConfigFile(t, templateFile, "version = 2")
+	deadline := time.Now().Add(5 * time.Second)
+	for render() != "version = 2" {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the changed template to be reloaded, got %q", render())
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	// A template that no longer parses keeps the previous one
+	writeConfigFile(t, templateFile, "version = {{")
+	if err := manager.CheckForChanges(); err == nil {
+		t.Error("Expected an error for a broken template")
+	}
+	if got := render(); got != "version = 2" {
+		t.Errorf("Expected the previous template to stay, got %q", got)
+	}
+}
@@ -172,13 +172,81 @@ func TestAppriseConfigDefaultPaths(t *testing.T) {
 }
 
 func TestTextConfigLineParsing(t *testing.T) {
-	// Skip this test - parseTextLine is an internal method that's not exposed
-	t.Skip("This test accesses internal ConfigLoader methods - needs refactoring")
+	cl := NewConfigLoader(New())
+
+	tests := []struct {
+		line string
+		url  string
+		tags []string
+	}{
+		{"json://localhost", "json://localhost", nil},
+		{"discord://id/token [team, alerts]", "discord://id/token", []string{"team", "alerts"}},
+		{"devops, alerts=json://localhost", "json://localhost", []string{"devops", "alerts"}},
+		{"team ops = json://localhost", "json://localhost", []string{"team", "ops"}},
+		{"json://localhost/?token=abc", "json://localhost/?token=abc", nil},
+	}
+
+	for _, tt := range tests {
+		urlConfig := cl.parseTextLine(tt.line)
+		if urlConfig.URL != tt.url {
+			t.Errorf("%q: expected URL %q, got %q", tt.line, tt.url, urlConfig.URL)
+		}
+		if !stringSlicesEqual(urlConfig.Tags, tt.tags) {
+			t.Errorf("%q: expected tags %v, got %v", tt.line, tt.tags, urlConfig.Tags)
+		}
+	}
+}
+
+func TestConfigLoaderAddFromString(t *testing.T) {
+	app := New()
+	cl := NewConfigLoader(app)
+
+	text := "# comment\n; another comment\nteam=json://localhost\nwebhook://localhost [ops]\n"
+	if err := cl.AddFromString(text, ConfigFormatText); err != nil {
+		t.Fatalf("Failed to load text config: %v", err)
+	}
+
+	yamlContent := "urls:\n  - url: json://example.com\n    tag: [team]\n"
+	if err := cl.AddFromString(yamlContent, ConfigFormatYAML); err != nil {
+		t.Fatalf("Failed to load YAML config: %v", err)
+	}
+
+	if urls := cl.URLs(); len(urls) != 3 {
+		t.Fatalf("Expected 3 URLs, got %d", len(urls))
+	}
+
+	if err := cl.ApplyToApprise(); err != nil {
+		t.Fatalf("Failed to apply config: %v", err)
+	}
+	if count := app.CountByTag("team"); count != 2 {
+		t.Errorf("Expected 2 services tagged team, got %d", count)
+	}
+
+	if err := cl.AddFromString(text, "toml"); err == nil {
+		t.Error("Expected error for unsupported format")
+	}
 }
 
 func TestYAMLFormatDetection(t *testing.T) {
-	// Skip this test - isYAMLFormat is an internal method that's not exposed
-	t.Skip("This test accesses internal ConfigLoader methods - needs refactoring")
+	tests := []struct {
+		content string
+		format  string
+	}{
+		{"version: 1\nurls:\n  - json://localhost", ConfigFormatYAML},
+		{"# comment\n\nurls:\n  - json://localhost", ConfigFormatYAML},
+		{"---\nurls: []", ConfigFormatYAML},
+		{"json://localhost", ConfigFormatText},
+		{"; comment\ndevops, alerts=json://localhost", ConfigFormatText},
+		{"discord://id/token [team]", ConfigFormatText},
+		{"include https://example.com/apprise.txt", ConfigFormatText},
+		{"", ConfigFormatText},
+	}
+
+	for _, tt := range tests {
+		if got := DetectConfigFormat(tt.content); got != tt.format {
+			t.Errorf("%q: expected %s, got %s", tt.content, tt.format, got)
+		}
+	}
 }
 
 // Helper functions
@@ -0,0 +1,259 @@
+package apprise
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestYAMLConfigSchema(t *testing.T) {
+	content := `
+version: 1
+tag: global, all
+asset:
+  app_id: MyApp
+  body_format: markdown
+urls:
+  - webhook://localhost/bare
+  - webhook://localhost/options?method=GET:
+      method: POST
+      verify: no
+      tag: ops
+  - webhook://localhost/list:
+      - header: [a, b]
+      - tag: [dev, qa]
+        failover: pager
+  - webhook://localhost/empty:
+  - url: webhook://localhost/legacy
+    tag:
+      - legacy
+`
+
+	cl := NewConfigLoader(New())
+	if err := cl.AddFromString(content, ""); err != nil {
+		t.Fatalf("Failed to parse config: %v", err)
+	}
+
+	want := []URLConfig{
+		{URL: "webhook://localhost/bare", Tags: []string{"global", "all"}},
+		{URL: "webhook://localhost/options?method=POST&verify=no", Tags: []string{"global", "all", "ops"}},
+		{URL: "webhook://localhost/list?header=a%2Cb", Tags: []string{"global", "all"}},
+		{URL: "webhook://localhost/list", Tags: []string{"global", "all", "dev", "qa"}, Failover: "pager"},
+		{URL: "webhook://localhost/empty", Tags: []string{"global", "all"}},
+		{URL: "webhook://localhost/legacy", Tags: []string{"global", "all", "legacy"}},
+	}
+	urls := cl.URLs()
+	if len(urls) != len(want) {
+		t.Fatalf("Expected %d URLs, got %d: %+v", len(want), len(urls), urls)
+	}
+	for i := range want {
+		if urls[i].URL != want[i].URL || urls[i].Failover != want[i].Failover || !stringSlicesEqual(urls[i].Tags, want[i].Tags) {
+			t.Errorf("URL %d: expected %+v, got %+v", i, want[i], urls[i])
+		}
+	}
+
+	asset := cl.configs[0].Asset
+	if asset == nil || asset.AppID != "MyApp" || asset.BodyFormat != BodyFormatMarkdown {
+		t.Errorf("Expected the asset to be read, got %+v", asset)
+	}
+}
+
+func TestYAMLConfigSchemaErrors(t *testing.T) {
+	tests := map[string]string{
+		"nested option":     "urls:\n  - webhook://localhost:\n      header:\n        a: b\n",
+		"two keys":          "urls:\n  - webhook://localhost/a: {}\n    webhook://localhost/b: {}\n",
+		"scalar options":    "urls:\n  - webhook://localhost: yes\n",
+		"invalid asset":     "asset:\n  body_format: rtf\nurls:\n  - webhook://localhost\n",
+		"tag of maps":       "tag:\n  - a: b\nurls:\n  - webhook://localhost\n",
+		"invalid URL entry": "urls:\n  - [webhook://localhost]\n",
+	}
+
+	for name, content := range tests {
+		cl := NewConfigLoader(New())
+		if err := cl.AddFromString(content, ConfigFormatYAML); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestConfigAssetApplied(t *testing.T) {
+	var messages []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			Message string `json:"message"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		messages = append(messages, payload.Message)
+	}))
+	defer server.Close()
+	host := "webhook://" + strings.TrimPrefix(server.URL, "http://")
+
+	app := New()
+	cl := NewConfigLoader(app)
+	content := "asset:\n  interpret_escapes: yes\nurls:\n  - " + host + "/hook\n"
+	if err := cl.AddFromString(content, ConfigFormatYAML); err != nil {
+		t.Fatalf("Failed to parse config: %v", err)
+	}
+	if err := cl.ApplyToApprise(); err != nil {
+		t.Fatalf("Failed to apply config: %v", err)
+	}
+
+	responses := app.Notify("", `line one\nline two`, NotifyTypeInfo)
+	if len(responses) != 1 || !responses[0].Success {
+		t.Fatalf("Expected a successful notification, got %+v", responses)
+	}
+	if len(messages) != 1 || messages[0] != "line one\nline two" {
+		t.Errorf("Expected the escape to become a newline, got %q", messages)
+	}
+}
+
+func TestConfigIncludes(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigFile(t, filepath.Join(dir, "main.yml"), `
+asset:
+  app_id: Main
+include:
+  - sub/extra.txt
+urls:
+  - webhook://localhost/main
+`)
+	if err := os.Mkdir(filepath.Join(dir, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeConfigFile(t, filepath.Join(dir, "sub", "extra.txt"), "extra=webhook://localhost/extra\n")
+
+	cl := NewConfigLoader(New())
+	if err := cl.AddFromFile(filepath.Join(dir, "main.yml")); err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	urls := cl.URLs()
+	if len(urls) != 2 || urls[1].URL != "webhook://localhost/extra" {
+		t.Fatalf("Expected the included URL after the main one, got %+v", urls)
+	}
+	if asset := cl.configs[1].Asset; asset == nil || asset.AppID != "Main" {
+		t.Errorf("Expected the included config to inherit the asset, got %+v", asset)
+	}
+	if files := cl.Files(); len(files) != 2 || files[1] != filepath.Join(dir, "sub", "extra.txt") {
+		t.Errorf("Expected both files to be watched, got %v", files)
+	}
+
+	// Includes nested deeper than the limit are refused
+	writeConfigFile(t, filepath.Join(dir, "sub", "extra.txt"), "include deeper.txt\n")
+	writeConfigFile(t, filepath.Join(dir, "sub", "deeper.txt"), "webhook://localhost/deeper\n")
+	if err := NewConfigLoader(New()).AddFromFile(filepath.Join(dir, "main.yml")); err == nil {
+		t.Error("Expected includes nested too deeply to be refused")
+	}
+	deep := NewConfigLoader(New())
+	deep.SetIncludeDepth(2)
+	if err := deep.AddFromFile(filepath.Join(dir, "main.yml")); err != nil {
+		t.Errorf("Expected two levels of includes to be allowed: %v", err)
+	}
+
+	// Cycles are reported rather than followed
+	writeConfigFile(t, filepath.Join(dir, "sub", "deeper.txt"), "include ../main.yml\n")
+	cyclic := NewConfigLoader(New())
+	cyclic.SetIncludeDepth(10)
+	if err := cyclic.AddFromFile(filepath.Join(dir, "main.yml")); err == nil || !strings.Contains(err.Error(), "includes itself") {
+		t.Errorf("Expected an include cycle error, got %v", err)
+	}
+
+	// Configurations that are not files may not include local files
+	if err := NewConfigLoader(New()).AddFromString("include "+filepath.Join(dir, "main.yml"), ""); err == nil {
+		t.Error("Expected a string config including a local file to be refused")
+	}
+
+	// Nor may they make the loader fetch URLs on a client's behalf
+	if err := NewConfigLoader(New()).AddFromString("include http://127.0.0.1/apprise.txt", ""); err == nil || !strings.Contains(err.Error(), "not allowed") {
+		t.Errorf("Expected a string config including a URL to be refused, got %v", err)
+	}
+}
+
+func TestConfigFormatSelection(t *testing.T) {
+	yamlContent := "urls:\n  - webhook://localhost/yaml\n"
+	// Text that detection alone would read as YAML
+	textContent := "hooks: are listed below\nwebhook://localhost/text\n"
+
+	dir := t.TempDir()
+	writeConfigFile(t, filepath.Join(dir, "apprise.conf"), yamlContent)
+	writeConfigFile(t, filepath.Join(dir, "apprise.yaml"), yamlContent)
+	writeConfigFile(t, filepath.Join(dir, "notes.yml"), textContent)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Has("format") {
+			t.Errorf("Expected the format parameter not to be sent")
+		}
+		switch r.URL.Path {
+		case "/yaml":
+			w.Header().Set("Content-Type", "application/x-yaml")
+			_, _ = w.Write([]byte(yamlContent))
+		default:
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = w.Write([]byte(textContent))
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name  string
+		add   func(cl *ConfigLoader) error
+		count int
+	}{
+		{"detected file", func(cl *ConfigLoader) error { return cl.AddFromFile(filepath.Join(dir, "apprise.conf")) }, 1},
+		{"extension", func(cl *ConfigLoader) error { return cl.AddFromFile(filepath.Join(dir, "apprise.yaml")) }, 1},
+		{"explicit file format", func(cl *ConfigLoader) error {
+			return cl.AddFromFile("file://" + filepath.Join(dir, "notes.yml") + "?format=text")
+		}, 2},
+		{"content type", func(cl *ConfigLoader) error { return cl.AddFromURL(server.URL + "/yaml") }, 1},
+		{"explicit URL format", func(cl *ConfigLoader) error { return cl.AddFromURL(server.URL + "/text?format=text") }, 2},
+	}
+
+	for _, tt := range tests {
+		cl := NewConfigLoader(New())
+		if err := tt.add(cl); err != nil {
+			t.Errorf("%s: failed to load config: %v", tt.name, err)
+			continue
+		}
+		if got := len(cl.URLs()); got != tt.count {
+			t.Errorf("%s: expected %d URLs, got %d: %+v", tt.name, tt.count, got, cl.URLs())
+		}
+	}
+
+	// The extension is trusted over the content
+	if err := NewConfigLoader(New()).AddFromFile(filepath.Join(dir, "notes.yml")); err == nil {
+		t.Error("Expected a .yml file holding text to fail to parse as YAML")
+	}
+}
+
+func TestFetchConfigLimits(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/large":
+			_, _ = w.Write([]byte(strings.Repeat("#", maxRemoteConfigSize+1)))
+		case "/hang":
+			<-release
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	timeout := configFetchTimeout
+	configFetchTimeout = 100 * time.Millisecond
+	defer func() { configFetchTimeout = timeout }()
+
+	for _, path := range []string{"/large", "/hang"} {
+		start := time.Now()
+		if err := NewConfigLoader(New()).AddFromURL(server.URL + path); err == nil {
+			t.Errorf("%s: expected an error", path)
+		}
+		if elapsed := time.Since(start); elapsed > 5*time.Second {
+			t.Errorf("%s: expected the fetch to give up, took %s", path, elapsed)
+		}
+	}
+}
@@ -304,7 +304,7 @@ func (d *DatadogService) sendViaWebhook(ctx context.Context, event *DatadogEvent
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("datadog webhook error (status %d): %s", resp.StatusCode, string(body))
+		return NewHTTPError(resp, fmt.Errorf("datadog webhook error (status %d): %s", resp.StatusCode, string(body)))
 	}
 
 	return nil
@@ -357,7 +357,7 @@ func (d *DatadogService) sendEvent(ctx context.Context, baseURL string, event *D
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("datadog event API error (status %d): %s", resp.StatusCode, string(body))
+		return NewHTTPError(resp, fmt.Errorf("datadog event API error (status %d): %s", resp.StatusCode, string(body)))
 	}
 
 	return nil
@@ -391,7 +391,7 @@ func (d *DatadogService) sendMetric(ctx context.Context, baseURL string, metric
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("datadog metrics API error (status %d): %s", resp.StatusCode, string(body))
+		return NewHTTPError(resp, fmt.Errorf("datadog metrics API error (status %d): %s", resp.StatusCode, string(body)))
 	}
 
 	return nil
@@ -422,7 +422,7 @@ func (d *DatadogService) sendLogToURL(ctx context.Context, logsBaseURL string, l
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("datadog logs API error (status %d): %s", resp.StatusCode, string(body))
+		return NewHTTPError(resp, fmt.Errorf("datadog logs API error (status %d): %s", resp.StatusCode, string(body)))
 	}
 
 	return nil
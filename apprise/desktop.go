@@ -369,7 +369,7 @@ func (g *GotifyService) Send(ctx context.Context, req NotificationRequest) error
 	httpReq.Header.Set("User-Agent", GetUserAgent())
 
 	// Send request
-	client := &http.Client{}
+	client := GetWebhookHTTPClient("gotify")
 	resp, err := client.Do(httpReq)
 	if err != nil {
 		return fmt.Errorf("failed to send Gotify notification: %w", err)
@@ -377,7 +377,7 @@ func (g *GotifyService) Send(ctx context.Context, req NotificationRequest) error
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("gotify API error: %s", resp.Status)
+		return NewHTTPError(resp, fmt.Errorf("gotify API error: %s", resp.Status))
 	}
 
 	return nil
@@ -6,23 +6,34 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"mime/multipart"
 	"net/http"
 	"net/url"
 	"strings"
 )
 
+const (
+	// discordMaxFilesPerMessage is the number of files a webhook message may carry
+	discordMaxFilesPerMessage = 10
+
+	// discordMaxUploadSize is the combined upload limit for a webhook message
+	discordMaxUploadSize = 25 * 1024 * 1024
+)
+
 // DiscordService implements Discord webhook notifications
 type DiscordService struct {
 	webhookID    string
 	webhookToken string
 	avatar       string
 	username     string
+	apiURL       string
 	client       *http.Client
 }
 
 // NewDiscordService creates a new Discord service instance
 func NewDiscordService() Service {
 	return &DiscordService{
+		apiURL: "https://discord.com/api",
 		client: GetWebhookHTTPClient("discord"),
 	}
 }
@@ -115,7 +126,7 @@ type DiscordEmbedField struct {
 
 // Send sends a notification to Discord
 func (d *DiscordService) Send(ctx context.Context, req NotificationRequest) error {
-	webhookURL := fmt.Sprintf("https://discord.com/api/webhooks/%s/%s", d.webhookID, d.webhookToken)
+	webhookURL := fmt.Sprintf("%s/webhooks/%s/%s", d.apiURL, d.webhookID, d.webhookToken)
 
 	// Determine embed color based on notification type
 	color := d.getColorForNotifyType(req.NotifyType)
@@ -144,19 +155,106 @@ func (d *DiscordService) Send(ctx context.Context, req NotificationRequest) erro
 		payload.Content = req.Body
 	}
 
+	if req.AttachmentMgr != nil && req.AttachmentMgr.Count() > 0 {
+		return d.sendWithAttachments(ctx, webhookURL, payload, req.AttachmentMgr.GetAll())
+	}
+
 	// Convert payload to JSON
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
 		return fmt.Errorf("failed to marshal Discord payload: %w", err)
 	}
 
+	return d.post(ctx, webhookURL, "application/json", bytes.NewBuffer(jsonData))
+}
+
+// discordFile is an attachment read into memory for upload
+type discordFile struct {
+	name     string
+	mimeType string
+	data     []byte
+}
+
+// sendWithAttachments uploads attachments as multipart files[n] parts. The
+// message payload travels with the first batch; further batches carry only
+// files so that the per-message file count and size limits are respected.
+func (d *DiscordService) sendWithAttachments(ctx context.Context, webhookURL string, payload DiscordWebhookPayload, attachments []AttachmentInterface) error {
+	var batches [][]discordFile
+	var batch []discordFile
+	var batchSize int64
+
+	for _, attachment := range attachments {
+		data, err := readAttachment(attachment, discordMaxUploadSize)
+		if err != nil {
+			return fmt.Errorf("discord attachment error: %w", err)
+		}
+
+		size := int64(len(data))
+		if len(batch) == discordMaxFilesPerMessage || (len(batch) > 0 && batchSize+size > discordMaxUploadSize) {
+			batches = append(batches, batch)
+			batch = nil
+			batchSize = 0
+		}
+
+		batch = append(batch, discordFile{
+			name:     attachment.GetName(),
+			mimeType: attachment.GetMimeType(),
+			data:     data,
+		})
+		batchSize += size
+	}
+	batches = append(batches, batch)
+
+	for i, files := range batches {
+		batchPayload := DiscordWebhookPayload{Username: payload.Username, AvatarURL: payload.AvatarURL}
+		if i == 0 {
+			batchPayload = payload
+		}
+
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+
+		jsonData, err := json.Marshal(batchPayload)
+		if err != nil {
+			return fmt.Errorf("failed to marshal Discord payload: %w", err)
+		}
+		if err := writer.WriteField("payload_json", string(jsonData)); err != nil {
+			return fmt.Errorf("failed to write Discord payload: %w", err)
+		}
+
+		for n, file := range files {
+			field := fmt.Sprintf("files[%d]", n)
+			if err := writeMultipartFile(writer, field, file.name, file.mimeType, file.data); err != nil {
+				return fmt.Errorf("failed to write Discord attachment %s: %w", file.name, err)
+			}
+		}
+
+		if err := writer.Close(); err != nil {
+			return fmt.Errorf("failed to finalize Discord upload: %w", err)
+		}
+
+		if err := d.post(ctx, webhookURL, writer.FormDataContentType(), body); err != nil {
+			// Earlier batches carried the message, so resending the whole
+			// send would post it twice
+			if i > 0 {
+				return newPartialDeliveryError(err)
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// post sends a request body to the Discord webhook
+func (d *DiscordService) post(ctx context.Context, webhookURL, contentType string, body io.Reader) error {
 	// Create HTTP request
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", webhookURL, bytes.NewBuffer(jsonData))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", webhookURL, body)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
-	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Content-Type", contentType)
 	httpReq.Header.Set("User-Agent", GetUserAgent())
 
 	// Send request
@@ -169,7 +267,7 @@ func (d *DiscordService) Send(ctx context.Context, req NotificationRequest) erro
 	// Check response status
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("discord API error (status %d): %s", resp.StatusCode, string(body))
+		return NewHTTPError(resp, fmt.Errorf("discord API error (status %d): %s", resp.StatusCode, string(body)))
 	}
 
 	return nil
@@ -186,6 +284,7 @@ func (d *DiscordService) TestURL(serviceURL string) error {
 }
 
 // SupportsAttachments returns true since Discord supports file attachments
+// (uploaded as multipart files[n], up to 10 files and 25MB per message)
 func (d *DiscordService) SupportsAttachments() bool {
 	return true
 }
@@ -195,6 +294,11 @@ func (d *DiscordService) GetMaxBodyLength() int {
 	return 2000 // Discord's character limit for messages
 }
 
+// GetNativeFormat returns markdown, which Discord renders natively
+func (d *DiscordService) GetNativeFormat() string {
+	return BodyFormatMarkdown
+}
+
 // getColorForNotifyType returns appropriate color for notification type
 func (d *DiscordService) getColorForNotifyType(notifyType NotifyType) int {
 	switch notifyType {
@@ -0,0 +1,117 @@
+package apprise
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDiscordService_SendWithAttachments(t *testing.T) {
+	var requests int
+	var payload DiscordWebhookPayload
+	files := make(map[string]string)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/webhooks/webhook_id/webhook_token" {
+			t.Errorf("Unexpected path: %s", r.URL.Path)
+		}
+		if !strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+			t.Errorf("Expected multipart request, got %s", r.Header.Get("Content-Type"))
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatalf("Failed to parse multipart form: %v", err)
+		}
+		if err := json.Unmarshal([]byte(r.FormValue("payload_json")), &payload); err != nil {
+			t.Errorf("Failed to decode payload_json: %v", err)
+		}
+		for field, headers := range r.MultipartForm.File {
+			f, _ := headers[0].Open()
+			data, _ := io.ReadAll(f)
+			_ = f.Close()
+			files[field] = headers[0].Filename + ":" + string(data)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	service := NewDiscordService().(*DiscordService)
+	if err := service.TestURL("discord://webhook_id/webhook_token"); err != nil {
+		t.Fatalf("Failed to parse URL: %v", err)
+	}
+	service.apiURL = server.URL
+
+	mgr := NewAttachmentManager()
+	_ = mgr.AddData([]byte("log output"), "build.log", "text/plain")
+	_ = mgr.AddData([]byte("png-bytes"), "screenshot.png", "image/png")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := service.Send(ctx, NotificationRequest{Body: "Build finished", AttachmentMgr: mgr})
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	if requests != 1 {
+		t.Errorf("Expected 1 request, got %d", requests)
+	}
+	if payload.Content != "Build finished" {
+		t.Errorf("Expected content in payload_json, got %q", payload.Content)
+	}
+	if files["files[0]"] != "build.log:log output" {
+		t.Errorf("Unexpected files[0]: %q", files["files[0]"])
+	}
+	if files["files[1]"] != "screenshot.png:png-bytes" {
+		t.Errorf("Unexpected files[1]: %q", files["files[1]"])
+	}
+}
+
+func TestDiscordService_AttachmentBatching(t *testing.T) {
+	var fileCounts []int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatalf("Failed to parse multipart form: %v", err)
+		}
+		fileCounts = append(fileCounts, len(r.MultipartForm.File))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	service := NewDiscordService().(*DiscordService)
+	_ = service.TestURL("discord://webhook_id/webhook_token")
+	service.apiURL = server.URL
+
+	mgr := NewAttachmentManager()
+	for i := 0; i < discordMaxFilesPerMessage+2; i++ {
+		_ = mgr.AddData([]byte("x"), "file.txt", "text/plain")
+	}
+
+	if err := service.Send(context.Background(), NotificationRequest{Body: "Many files", AttachmentMgr: mgr}); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	if len(fileCounts) != 2 || fileCounts[0] != discordMaxFilesPerMessage || fileCounts[1] != 2 {
+		t.Errorf("Expected batches of [%d 2], got %v", discordMaxFilesPerMessage, fileCounts)
+	}
+}
+
+func TestDiscordService_AttachmentTooLarge(t *testing.T) {
+	service := NewDiscordService().(*DiscordService)
+	_ = service.TestURL("discord://webhook_id/webhook_token")
+	service.apiURL = "http://127.0.0.1:0"
+
+	mgr := NewAttachmentManager()
+	_ = mgr.AddData(make([]byte, discordMaxUploadSize+1), "huge.bin", "application/octet-stream")
+
+	err := service.Send(context.Background(), NotificationRequest{Body: "Too big", AttachmentMgr: mgr})
+	if err == nil || !strings.Contains(err.Error(), "exceeds maximum size") {
+		t.Errorf("Expected size limit error, got %v", err)
+	}
+}
@@ -2,9 +2,13 @@ package apprise
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestDiscordServiceURLParsing(t *testing.T) {
@@ -229,3 +233,43 @@ func TestDiscordSendMethodExists(t *testing.T) {
 		t.Errorf("Error should be network-related or API validation error, got: %v", err)
 	}
 }
+
+func TestDiscordFailedBatchDoesNotRepostMessage(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests > 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	service := NewDiscordService().(*DiscordService)
+	parsedURL, _ := url.Parse("discord://test_id/test_token")
+	if err := service.ParseURL(parsedURL); err != nil {
+		t.Fatalf("Failed to parse URL: %v", err)
+	}
+	service.apiURL = server.URL
+
+	// One file more than a message may carry makes a second batch
+	attachments := NewAttachmentManager()
+	for i := 0; i <= discordMaxFilesPerMessage; i++ {
+		if err := attachments.AddData([]byte("log"), fmt.Sprintf("build-%d.log", i), "text/plain"); err != nil {
+			t.Fatalf("Failed to add attachment: %v", err)
+		}
+	}
+
+	policy := DefaultRetryPolicy()
+	policy.MaxAttempts = 3
+	policy.BaseDelay = time.Millisecond
+
+	attempts, err := New().sendWithRetry(context.Background(), service,
+		NotificationRequest{Body: "Build done", NotifyType: NotifyTypeInfo, AttachmentMgr: attachments},
+		policy, nil, nil, CircuitBreakerPolicy{})
+	if !IsPartialDelivery(err) {
+		t.Errorf("Expected a partial delivery error, got %v", err)
+	}
+	if attempts != 1 || requests != 2 {
+		t.Errorf("Expected 2 requests in 1 attempt, got %d requests in %d attempts", requests, attempts)
+	}
+}
@@ -201,7 +201,7 @@ func (e *EmailService) Send(ctx context.Context, req NotificationRequest) error
 	if e.username != "" && e.password != "" {
 		auth := smtp.PlainAuth("", e.username, e.password, e.smtpHost)
 		if err := client.Auth(auth); err != nil {
-			return fmt.Errorf("SMTP authentication failed: %w", err)
+			return NewNotificationError(ErrorClassAuth, 0, fmt.Errorf("SMTP authentication failed: %w", err))
 		}
 	}
 
@@ -213,7 +213,7 @@ func (e *EmailService) Send(ctx context.Context, req NotificationRequest) error
 	// Set recipients
 	for _, recipient := range allRecipients {
 		if err := client.Rcpt(recipient); err != nil {
-			return fmt.Errorf("failed to set recipient %s: %w", recipient, err)
+			return NewNotificationError(ErrorClassInvalidRecipient, 0, fmt.Errorf("failed to set recipient %s: %w", recipient, err))
 		}
 	}
 
@@ -450,6 +450,11 @@ func (e *EmailService) GetMaxBodyLength() int {
 	return 0 // No practical limit for email body
 }
 
+// GetNativeFormat returns HTML; plain-text bodies are sent as escaped HTML
+func (e *EmailService) GetNativeFormat() string {
+	return BodyFormatHTML
+}
+
 // generateBoundary generates a random MIME boundary string
 func (e *EmailService) generateBoundary() (string, error) {
 	boundary := make([]byte, 16)
@@ -0,0 +1,147 @@
+package apprise
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// EndpointOverrideAll is the SetEndpointOverride key that applies to every
+// service without an override of its own
+const EndpointOverrideAll = "*"
+
+// endpointContextKey carries the base URL that outbound requests are
+// redirected to while a service sends
+type endpointContextKey struct{}
+
+// ParseEndpoint parses a replacement API base URL such as
+// "http://telegram-bot-api:8081" or "http://mock:8080/discord"
+func ParseEndpoint(value string) (*url.URL, error) {
+	endpoint, err := url.Parse(strings.TrimSpace(value))
+	if err != nil {
+		return nil, fmt.Errorf("invalid api_url %q: %w", value, err)
+	}
+	if endpoint.Scheme != "http" && endpoint.Scheme != "https" {
+		return nil, fmt.Errorf("invalid api_url %q: scheme must be http or https", value)
+	}
+	if endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid api_url %q: missing host", value)
+	}
+	endpoint.Path = strings.TrimSuffix(endpoint.Path, "/")
+	endpoint.RawQuery = ""
+	endpoint.Fragment = ""
+	return endpoint, nil
+}
+
+// SetEndpointOverride redirects every HTTP request made by services with
+// the given service ID (e.g. "telegram", "discord") to baseURL, keeping the
+// request path. Use EndpointOverrideAll to redirect all services, and an
+// empty baseURL to remove an override. Services added with an api_url URL
+// parameter keep their own endpoint.
+func (a *Apprise) SetEndpointOverride(serviceID, baseURL string) error {
+	if baseURL == "" {
+		a.mu.Lock()
+		delete(a.endpoints, serviceID)
+		a.mu.Unlock()
+		return nil
+	}
+
+	endpoint, err := ParseEndpoint(baseURL)
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	a.endpoints[serviceID] = endpoint
+	a.mu.Unlock()
+	return nil
+}
+
+// GetEndpointOverrides returns the configured endpoint overrides keyed by
+// service ID
+func (a *Apprise) GetEndpointOverrides() map[string]string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	overrides := make(map[string]string, len(a.endpoints))
+	for serviceID, endpoint := range a.endpoints {
+		overrides[serviceID] = endpoint.String()
+	}
+	return overrides
+}
+
+// parseEndpointOverride extracts the api_url parameter from the URL query
+// and removes it so services never see it
+func parseEndpointOverride(query url.Values) (*url.URL, error) {
+	value := query.Get("api_url")
+	query.Del("api_url")
+
+	if value == "" {
+		return nil, nil
+	}
+	return ParseEndpoint(value)
+}
+
+// endpointFor returns the base URL the entry's requests are redirected to,
+// or nil to leave them alone
+func (a *Apprise) endpointFor(entry serviceEntry) *url.URL {
+	if entry.endpoint != nil {
+		return entry.endpoint
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if endpoint, ok := a.endpoints[entry.service.GetServiceID()]; ok {
+		return endpoint
+	}
+	return a.endpoints[EndpointOverrideAll]
+}
+
+// withEndpoint returns a context whose outbound service requests are
+// redirected to endpoint
+func withEndpoint(ctx context.Context, endpoint *url.URL) context.Context {
+	if endpoint == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, endpointContextKey{}, endpoint)
+}
+
+// withoutEndpoint returns a context whose requests go where they are
+// addressed even while the service's requests are redirected. Token requests
+// use it so credentials and signed assertions never reach a stand-in API.
+func withoutEndpoint(ctx context.Context) context.Context {
+	return context.WithValue(ctx, endpointContextKey{}, (*url.URL)(nil))
+}
+
+// endpointTransport rewrites requests to the endpoint carried by their
+// context. Requests already addressed to the endpoint's host, such as upload
+// URLs handed back by a stand-in, are sent unchanged.
+type endpointTransport struct {
+	next http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *endpointTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := t.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	endpoint, ok := req.Context().Value(endpointContextKey{}).(*url.URL)
+	if !ok || endpoint == nil || req.URL.Host == endpoint.Host {
+		return next.RoundTrip(req)
+	}
+
+	rewritten := req.Clone(req.Context())
+	rewritten.URL.Scheme = endpoint.Scheme
+	rewritten.URL.Host = endpoint.Host
+	rewritten.URL.Path = endpoint.Path + req.URL.Path
+	if req.URL.RawPath != "" {
+		rewritten.URL.RawPath = endpoint.EscapedPath() + req.URL.RawPath
+	}
+	rewritten.Host = ""
+
+	return next.RoundTrip(rewritten)
+}
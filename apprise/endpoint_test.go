@@ -0,0 +1,167 @@
+package apprise
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// recordingServer is a stand-in API that records the paths it was sent
+type recordingServer struct {
+	*httptest.Server
+	mu    sync.Mutex
+	paths []string
+}
+
+func newRecordingServer(t *testing.T, response string) *recordingServer {
+	t.Helper()
+	rs := &recordingServer{}
+	rs.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rs.mu.Lock()
+		rs.paths = append(rs.paths, r.URL.Path)
+		rs.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(rs.Close)
+	return rs
+}
+
+func (rs *recordingServer) received() []string {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	return append([]string(nil), rs.paths...)
+}
+
+func TestParseEndpoint(t *testing.T) {
+	endpoint, err := ParseEndpoint("http://mock:8080/telegram/?x=1")
+	if err != nil {
+		t.Fatalf("ParseEndpoint returned error: %v", err)
+	}
+	if endpoint.String() != "http://mock:8080/telegram" {
+		t.Errorf("Expected trailing slash and query to be dropped, got %q", endpoint.String())
+	}
+
+	for _, value := range []string{"mock:8080", "ftp://mock", "http://"} {
+		if _, err := ParseEndpoint(value); err == nil {
+			t.Errorf("Expected error for %q", value)
+		}
+	}
+}
+
+func TestAddWithAPIURLParameter(t *testing.T) {
+	server := newRecordingServer(t, `{"ok":true,"result":{}}`)
+
+	app := New()
+	if err := app.Add("tgram://123abc/456?api_url=" + server.URL + "/tg"); err != nil {
+		t.Fatalf("Failed to add service: %v", err)
+	}
+
+	responses := app.Notify("Title", "Body", NotifyTypeInfo)
+	if !responses[0].Success {
+		t.Fatalf("Notification failed: %v", responses[0].Error)
+	}
+
+	paths := server.received()
+	if len(paths) != 1 || paths[0] != "/tg/bot123abc/sendMessage" {
+		t.Errorf("Expected request to the stand-in, got %v", paths)
+	}
+
+	if err := app.Add("tgram://123abc/456?api_url=mock"); err == nil {
+		t.Error("Expected error for invalid api_url parameter")
+	}
+}
+
+func TestSetEndpointOverride(t *testing.T) {
+	telegram := newRecordingServer(t, `{"ok":true,"result":{}}`)
+	fallback := newRecordingServer(t, `{}`)
+
+	app := New()
+	if err := app.SetEndpointOverride("telegram", telegram.URL); err != nil {
+		t.Fatalf("SetEndpointOverride returned error: %v", err)
+	}
+	if err := app.SetEndpointOverride(EndpointOverrideAll, fallback.URL); err != nil {
+		t.Fatalf("SetEndpointOverride returned error: %v", err)
+	}
+	if err := app.SetEndpointOverride("discord", "not a url"); err == nil {
+		t.Error("Expected error for invalid base URL")
+	}
+
+	for _, serviceURL := range []string{"tgram://123abc/456", "discord://789/token"} {
+		if err := app.Add(serviceURL); err != nil {
+			t.Fatalf("Failed to add %s: %v", serviceURL, err)
+		}
+	}
+
+	for i, resp := range app.Notify("Title", "Body", NotifyTypeInfo) {
+		if !resp.Success {
+			t.Errorf("Notification %d failed: %v", i, resp.Error)
+		}
+	}
+
+	if paths := telegram.received(); len(paths) != 1 || !strings.HasSuffix(paths[0], "/sendMessage") {
+		t.Errorf("Expected Telegram request on its override, got %v", paths)
+	}
+	if paths := fallback.received(); len(paths) != 1 || paths[0] != "/api/webhooks/789/token" {
+		t.Errorf("Expected Discord request on the wildcard override, got %v", paths)
+	}
+
+	if overrides := app.GetEndpointOverrides(); overrides["telegram"] != telegram.URL {
+		t.Errorf("Unexpected overrides: %v", overrides)
+	}
+	_ = app.SetEndpointOverride("telegram", "")
+	if _, ok := app.GetEndpointOverrides()["telegram"]; ok {
+		t.Error("Expected override to be removed")
+	}
+}
+
+func TestSetEndpointOverrideWhileNotifying(t *testing.T) {
+	stand := newRecordingServer(t, `{}`)
+
+	app := New()
+	if err := app.Add("discord://789/token"); err != nil {
+		t.Fatalf("Failed to add service: %v", err)
+	}
+	if err := app.SetEndpointOverride("discord", stand.URL); err != nil {
+		t.Fatalf("SetEndpointOverride returned error: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 50; i++ {
+			_ = app.SetEndpointOverride("discord", stand.URL)
+			_ = app.SetEndpointOverride(EndpointOverrideAll, "")
+		}
+	}()
+	for i := 0; i < 10; i++ {
+		app.Notify("Title", "Body", NotifyTypeInfo)
+	}
+	wg.Wait()
+}
+
+func TestEndpointTransportLeavesStandInHostAlone(t *testing.T) {
+	server := newRecordingServer(t, `{}`)
+	endpoint, _ := ParseEndpoint(server.URL + "/prefix")
+
+	client := &http.Client{Transport: &endpointTransport{}}
+	ctx := withEndpoint(context.Background(), endpoint)
+
+	for _, target := range []string{"https://api.example.com/v1/send", server.URL + "/upload/1"} {
+		req, _ := http.NewRequestWithContext(ctx, "POST", target, nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("Request to %s failed: %v", target, err)
+		}
+		resp.Body.Close()
+	}
+
+	paths := server.received()
+	if len(paths) != 2 || paths[0] != "/prefix/v1/send" || paths[1] != "/upload/1" {
+		t.Errorf("Unexpected paths: %v", paths)
+	}
+}
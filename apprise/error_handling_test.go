@@ -110,7 +110,7 @@ func TestEmptyNotification(t *testing.T) {
 	responses := app.Notify("", "", NotifyTypeInfo)
 
 	if len(responses) != 1 {
-		t.Errorf("Expected 1 response, got %d", len(responses))
+		t.Fatalf("Expected 1 response, got %d", len(responses))
 	}
 
 	// Should still attempt to send even with empty content
@@ -233,14 +233,22 @@ func TestInvalidNotifyOptions(t *testing.T) {
 	app := New()
 	_ = app.Add("discord://test_id/test_token")
 
-	// Test with various notify options
+	// A tag the service doesn't carry filters it out
 	responses := app.Notify("Test", "Testing options", NotifyTypeInfo,
-		WithTags("non-existent-tag"),     // Service doesn't have this tag
+		WithTags("non-existent-tag"),
+	)
+
+	if len(responses) != 0 {
+		t.Errorf("Expected 0 responses for unmatched tag, got %d", len(responses))
+	}
+
+	// Test with various notify options
+	responses = app.Notify("Test", "Testing options", NotifyTypeInfo,
 		WithBodyFormat("invalid-format"), // Invalid format
 	)
 
 	if len(responses) != 1 {
-		t.Errorf("Expected 1 response, got %d", len(responses))
+		t.Fatalf("Expected 1 response, got %d", len(responses))
 	}
 
 	// Should still attempt to send despite invalid options
@@ -269,7 +277,7 @@ func TestContextCancellation(t *testing.T) {
 
 	// Get the service and test cancellation
 	if len(app.services) > 0 {
-		err := app.services[0].Send(ctx, req)
+		err := app.services[0].service.Send(ctx, req)
 		duration := time.Since(start)
 
 		// Should complete quickly due to context cancellation
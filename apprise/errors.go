@@ -0,0 +1,191 @@
+package apprise
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ErrorClass categorizes a failed notification so callers can decide how to
+// react to it (retry, back off, give up)
+type ErrorClass string
+
+const (
+	ErrorClassUnknown          ErrorClass = "unknown"
+	ErrorClassTimeout          ErrorClass = "timeout"
+	ErrorClassAuth             ErrorClass = "auth_failure"
+	ErrorClassRateLimited      ErrorClass = "rate_limited"
+	ErrorClassInvalidRecipient ErrorClass = "invalid_recipient"
+	ErrorClassPayloadTooLarge  ErrorClass = "payload_too_large"
+	ErrorClassTransientNetwork ErrorClass = "transient_network"
+	ErrorClassRemote5xx        ErrorClass = "remote_5xx"
+	ErrorClassCircuitOpen      ErrorClass = "circuit_open"
+)
+
+// Sentinel errors for each class, for use with errors.Is:
+//
+//	if errors.Is(err, apprise.ErrAuthFailure) { ... }
+var (
+	ErrAuthFailure      = errors.New("authentication failed")
+	ErrRateLimited      = errors.New("rate limited")
+	ErrInvalidRecipient = errors.New("invalid recipient")
+	ErrPayloadTooLarge  = errors.New("payload too large")
+	ErrTransientNetwork = errors.New("transient network error")
+	ErrRemote5xx        = errors.New("remote server error")
+	ErrTimeout          = errors.New("timeout")
+	ErrCircuitOpen      = errors.New("circuit breaker open")
+)
+
+// classSentinels maps each error class to its sentinel error
+var classSentinels = map[ErrorClass]error{
+	ErrorClassAuth:             ErrAuthFailure,
+	ErrorClassRateLimited:      ErrRateLimited,
+	ErrorClassInvalidRecipient: ErrInvalidRecipient,
+	ErrorClassPayloadTooLarge:  ErrPayloadTooLarge,
+	ErrorClassTransientNetwork: ErrTransientNetwork,
+	ErrorClassRemote5xx:        ErrRemote5xx,
+	ErrorClassTimeout:          ErrTimeout,
+	ErrorClassCircuitOpen:      ErrCircuitOpen,
+}
+
+// Retryable reports whether errors of this class are usually worth retrying
+func (c ErrorClass) Retryable() bool {
+	switch c {
+	case ErrorClassRateLimited, ErrorClassTransientNetwork, ErrorClassRemote5xx, ErrorClassTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// NotificationError is a classified error returned by services. It carries
+// the HTTP status of the failed call and, when the remote end asked for it,
+// how long to wait before trying again.
+type NotificationError struct {
+	Class      ErrorClass
+	StatusCode int
+	Retryable  bool
+	RetryAfter time.Duration
+	Partial    bool // some targets received the notification before the failure
+	Err        error
+}
+
+// NewNotificationError creates a classified error. Retryable defaults to
+// what is usual for the class.
+func NewNotificationError(class ErrorClass, statusCode int, err error) *NotificationError {
+	return &NotificationError{
+		Class:      class,
+		StatusCode: statusCode,
+		Retryable:  class.Retryable(),
+		Err:        err,
+	}
+}
+
+// Error implements the error interface
+func (e *NotificationError) Error() string {
+	if e.Err != nil {
+		return e.Err.Error()
+	}
+	if e.StatusCode != 0 {
+		return string(e.Class) + " (status " + strconv.Itoa(e.StatusCode) + ")"
+	}
+	return string(e.Class)
+}
+
+// Unwrap returns the underlying error
+func (e *NotificationError) Unwrap() error {
+	return e.Err
+}
+
+// Is matches the sentinel error of the error's class
+func (e *NotificationError) Is(target error) bool {
+	sentinel, ok := classSentinels[e.Class]
+	return ok && sentinel == target
+}
+
+// NewHTTPError classifies a non-2xx HTTP response. The Retry-After header, if
+// present, is captured so retries can honor it.
+func NewHTTPError(resp *http.Response, err error) *NotificationError {
+	notifyErr := NewNotificationError(classForStatus(resp.StatusCode), resp.StatusCode, err)
+
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		notifyErr.RetryAfter = ParseRetryAfter(retryAfter)
+	}
+
+	return notifyErr
+}
+
+// classForStatus maps an HTTP status code to an error class
+func classForStatus(statusCode int) ErrorClass {
+	switch {
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+		return ErrorClassAuth
+	case statusCode == http.StatusTooManyRequests:
+		return ErrorClassRateLimited
+	case statusCode == http.StatusNotFound || statusCode == http.StatusGone:
+		return ErrorClassInvalidRecipient
+	case statusCode == http.StatusRequestEntityTooLarge:
+		return ErrorClassPayloadTooLarge
+	case statusCode == http.StatusRequestTimeout:
+		return ErrorClassTimeout
+	case statusCode >= 500:
+		return ErrorClassRemote5xx
+	default:
+		return ErrorClassUnknown
+	}
+}
+
+// ClassifyError returns the error class of err. Classified errors report
+// their own class; context deadlines and network errors are recognized
+// without help from the service.
+func ClassifyError(err error) ErrorClass {
+	if err == nil {
+		return ""
+	}
+
+	var notifyErr *NotificationError
+	if errors.As(err, &notifyErr) {
+		return notifyErr.Class
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrorClassTimeout
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		if netErr.Timeout() {
+			return ErrorClassTimeout
+		}
+		return ErrorClassTransientNetwork
+	}
+
+	return ErrorClassUnknown
+}
+
+// IsRetryable reports whether err is worth retrying. Classified errors carry
+// their own flag; other errors are judged by their class.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var notifyErr *NotificationError
+	if errors.As(err, &notifyErr) {
+		return notifyErr.Retryable
+	}
+
+	return ClassifyError(err).Retryable()
+}
+
+// GetStatusCode returns the HTTP status carried by err, or 0 if there is none
+func GetStatusCode(err error) int {
+	var notifyErr *NotificationError
+	if errors.As(err, &notifyErr) {
+		return notifyErr.StatusCode
+	}
+	return 0
+}
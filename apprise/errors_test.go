@@ -0,0 +1,104 @@
+package apprise
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestNewHTTPError(t *testing.T) {
+	resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+	resp.Header.Set("Retry-After", "3")
+
+	err := NewHTTPError(resp, fmt.Errorf("rate limited"))
+	if err.Class != ErrorClassRateLimited {
+		t.Errorf("Expected class %s, got %s", ErrorClassRateLimited, err.Class)
+	}
+	if err.RetryAfter != 3*time.Second {
+		t.Errorf("Expected RetryAfter 3s, got %v", err.RetryAfter)
+	}
+	if err.Error() != "rate limited" {
+		t.Errorf("Expected wrapped message, got %q", err.Error())
+	}
+
+	resp = &http.Response{StatusCode: http.StatusBadGateway, Header: http.Header{}}
+	if class := NewHTTPError(resp, nil).Class; class != ErrorClassRemote5xx {
+		t.Errorf("Expected class %s, got %s", ErrorClassRemote5xx, class)
+	}
+}
+
+func TestNewHTTPErrorClasses(t *testing.T) {
+	tests := []struct {
+		status    int
+		class     ErrorClass
+		retryable bool
+	}{
+		{http.StatusUnauthorized, ErrorClassAuth, false},
+		{http.StatusForbidden, ErrorClassAuth, false},
+		{http.StatusNotFound, ErrorClassInvalidRecipient, false},
+		{http.StatusRequestEntityTooLarge, ErrorClassPayloadTooLarge, false},
+		{http.StatusTooManyRequests, ErrorClassRateLimited, true},
+		{http.StatusServiceUnavailable, ErrorClassRemote5xx, true},
+		{http.StatusBadRequest, ErrorClassUnknown, false},
+	}
+
+	for _, tt := range tests {
+		err := NewHTTPError(&http.Response{StatusCode: tt.status, Header: http.Header{}}, nil)
+		if err.Class != tt.class || err.Retryable != tt.retryable || err.StatusCode != tt.status {
+			t.Errorf("status %d: got class=%s retryable=%v, expected class=%s retryable=%v",
+				tt.status, err.Class, err.Retryable, tt.class, tt.retryable)
+		}
+	}
+}
+
+func TestNotificationErrorIs(t *testing.T) {
+	err := fmt.Errorf("send failed: %w", NewNotificationError(ErrorClassAuth, 401, errors.New("bad token")))
+
+	if !errors.Is(err, ErrAuthFailure) {
+		t.Error("Expected errors.Is to match ErrAuthFailure")
+	}
+	if errors.Is(err, ErrRateLimited) {
+		t.Error("Did not expect errors.Is to match ErrRateLimited")
+	}
+	if GetStatusCode(err) != 401 {
+		t.Errorf("Expected status 401, got %d", GetStatusCode(err))
+	}
+	if IsRetryable(err) {
+		t.Error("Auth failures should not be retryable")
+	}
+	if !IsRetryable(&net.OpError{Op: "dial", Err: errors.New("connection refused")}) {
+		t.Error("Network errors should be retryable")
+	}
+}
+
+func TestClassifyError(t *testing.T) {
+	wrapped := fmt.Errorf("send failed: %w", &NotificationError{Class: ErrorClassRemote5xx, StatusCode: 503})
+
+	tests := []struct {
+		name     string
+		err      error
+		expected ErrorClass
+	}{
+		{"nil", nil, ""},
+		{"plain error", errors.New("boom"), ErrorClassUnknown},
+		{"wrapped classified error", wrapped, ErrorClassRemote5xx},
+		{"deadline", context.DeadlineExceeded, ErrorClassTimeout},
+		{"network error", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, ErrorClassTransientNetwork},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClassifyError(tt.err); got != tt.expected {
+				t.Errorf("ClassifyError() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+
+	if GetRetryAfter(fmt.Errorf("wrap: %w", &NotificationError{RetryAfter: time.Second})) != time.Second {
+		t.Error("GetRetryAfter should see through wrapped errors")
+	}
+}
@@ -36,7 +36,7 @@ type FacebookResponse struct {
 // NewFacebookService creates a new Facebook service instance
 func NewFacebookService() Service {
 	return &FacebookService{
-		client: &http.Client{},
+		client: GetCloudHTTPClient("facebook"),
 	}
 }
 
@@ -134,7 +134,7 @@ func (s *FacebookService) createPost(ctx context.Context, req NotificationReques
 		if json.NewDecoder(resp.Body).Decode(&fbResp) == nil && fbResp.Error.Message != "" {
 			return fmt.Errorf("Facebook API error: %s (code %d)", fbResp.Error.Message, fbResp.Error.Code)
 		}
-		return fmt.Errorf("Facebook API returned status %d", resp.StatusCode)
+		return NewHTTPError(resp, fmt.Errorf("Facebook API returned status %d", resp.StatusCode))
 	}
 	
 	return nil
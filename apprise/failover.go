@@ -0,0 +1,85 @@
+package apprise
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// AddToFailoverGroup adds a service to the named failover group. Services in
+// a group are tried one at a time, in the order they were added, until one of
+// them succeeds; the rest of the group is not notified. Each group is sent to
+// in parallel with the other groups and ungrouped services.
+//
+// The same is possible in configuration with the failover URL parameter:
+//
+//	pagerduty://key@token?failover=oncall
+//	opsgenie://apikey?failover=oncall
+func (a *Apprise) AddToFailoverGroup(group, serviceURL string, tags ...string) error {
+	group, err := normalizeFailoverGroup(group)
+	if err != nil {
+		return err
+	}
+	if group == "" {
+		return fmt.Errorf("failover group name must not be empty")
+	}
+	return a.add(serviceURL, group, tags)
+}
+
+// FailoverGroups returns the service URLs of each failover group, in the
+// order they are tried
+func (a *Apprise) FailoverGroups() map[string][]string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	groups := make(map[string][]string)
+	for _, entry := range a.services {
+		if entry.failover != "" {
+			groups[entry.failover] = append(groups[entry.failover], entry.url)
+		}
+	}
+	return groups
+}
+
+// parseFailoverOverride extracts the failover parameter from the URL query
+// and removes it so services never see it:
+//
+//	failover=oncall   try this URL as part of the "oncall" failover group
+func parseFailoverOverride(query url.Values) (string, error) {
+	value := query.Get("failover")
+	query.Del("failover")
+	return normalizeFailoverGroup(value)
+}
+
+// normalizeFailoverGroup validates a group name and returns it in lower case.
+// Names follow the same rules as tags.
+func normalizeFailoverGroup(group string) (string, error) {
+	group = strings.ToLower(strings.TrimSpace(group))
+	if strings.ContainsAny(group, ", \t") {
+		return "", fmt.Errorf("invalid failover group %q: must not contain commas or whitespace", group)
+	}
+	return group, nil
+}
+
+// failoverChains splits the services to notify into chains that are sent
+// to in parallel. An ungrouped service is a chain of its own; the members of
+// a failover group form one chain, in the order they were added. Chains are
+// ordered by their first service.
+func failoverChains(targets []serviceEntry) [][]serviceEntry {
+	chains := make([][]serviceEntry, 0, len(targets))
+	groupIndex := make(map[string]int)
+
+	for _, entry := range targets {
+		if entry.failover == "" {
+			chains = append(chains, []serviceEntry{entry})
+			continue
+		}
+		if i, ok := groupIndex[entry.failover]; ok {
+			chains[i] = append(chains[i], entry)
+			continue
+		}
+		groupIndex[entry.failover] = len(chains)
+		chains = append(chains, []serviceEntry{entry})
+	}
+	return chains
+}
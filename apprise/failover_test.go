@@ -0,0 +1,167 @@
+package apprise
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFailoverGroupStopsAtFirstSuccess(t *testing.T) {
+	server := newStubAPI(t, answerByPath(map[string]int{"/pager-a": http.StatusBadGateway}))
+	host := "webhook://" + strings.TrimPrefix(server.URL, "http://")
+
+	app := New()
+	for _, path := range []string{"/pager-a", "/pager-b", "/pager-c"} {
+		if err := app.AddToFailoverGroup("OnCall", host+path); err != nil {
+			t.Fatalf("Failed to add %s: %v", path, err)
+		}
+	}
+
+	responses := app.NotifyAll(NotificationRequest{Body: "page"})
+	if len(responses) != 2 {
+		t.Fatalf("Expected responses for the two services tried, got %d", len(responses))
+	}
+	if responses[0].Success || !errors.Is(responses[0].Error, ErrRemote5xx) {
+		t.Errorf("Expected the first pager to fail, got %+v", responses[0])
+	}
+	if !responses[1].Success || responses[1].Failover != "oncall" {
+		t.Errorf("Expected the second pager to succeed in group oncall, got %+v", responses[1])
+	}
+	if got := strings.Join(server.paths(), " "); got != "/pager-a /pager-b" {
+		t.Errorf("Expected pagers to be tried in order and the third skipped, got %q", got)
+	}
+
+	groups := app.FailoverGroups()
+	if len(groups["oncall"]) != 3 {
+		t.Errorf("Expected 3 members in the oncall group, got %v", groups)
+	}
+}
+
+func TestFailoverGroupAllFail(t *testing.T) {
+	server := newStubAPI(t, answerByPath(map[string]int{
+		"/all-fail-a": http.StatusInternalServerError,
+		"/all-fail-b": http.StatusServiceUnavailable,
+	}))
+	host := "webhook://" + strings.TrimPrefix(server.URL, "http://")
+
+	app := New()
+	if err := app.Add(host + "/all-fail-a?failover=pager"); err != nil {
+		t.Fatalf("Failed to add service: %v", err)
+	}
+	if err := app.Add(host + "/all-fail-b?failover=pager"); err != nil {
+		t.Fatalf("Failed to add service: %v", err)
+	}
+	if err := app.Add(host + "/chat"); err != nil {
+		t.Fatalf("Failed to add service: %v", err)
+	}
+
+	responses := app.NotifyAll(NotificationRequest{Body: "page"})
+	if len(responses) != 3 {
+		t.Fatalf("Expected 3 responses, got %d", len(responses))
+	}
+	for i, resp := range responses[:2] {
+		if resp.Success || resp.Failover != "pager" {
+			t.Errorf("Response %d: expected a failure in group pager, got %+v", i, resp)
+		}
+	}
+	if !responses[2].Success || responses[2].Failover != "" {
+		t.Errorf("Expected the ungrouped service to be notified as well, got %+v", responses[2])
+	}
+	if got := len(server.paths()); got != 3 {
+		t.Errorf("Expected 3 requests, got %d", got)
+	}
+}
+
+func TestFailoverGroupValidation(t *testing.T) {
+	app := New()
+	if err := app.AddToFailoverGroup("", "webhook://localhost/hook"); err == nil {
+		t.Error("Expected an empty group name to be rejected")
+	}
+	if err := app.Add("webhook://localhost/hook?failover=on+call"); err == nil {
+		t.Error("Expected a group name with whitespace to be rejected")
+	}
+	if err := app.AddToFailoverGroup("oncall", "webhook://localhost/hook?failover=other"); err == nil {
+		t.Error("Expected conflicting group names to be rejected")
+	}
+}
+
+func TestConfigFailoverGroups(t *testing.T) {
+	server := newStubAPI(t, answerByPath(map[string]int{"/cfg-primary": http.StatusServiceUnavailable}))
+	host := "webhook://" + strings.TrimPrefix(server.URL, "http://")
+
+	configs := map[string]string{
+		ConfigFormatYAML: `
+urls:
+  - url: ` + host + `/cfg-primary
+    failover: oncall
+  - url: ` + host + `/cfg-secondary
+    failover: oncall
+  - url: ` + host + `/cfg-tertiary
+    failover: oncall
+`,
+		ConfigFormatText: host + "/cfg-primary?failover=oncall\n" +
+			host + "/cfg-secondary?failover=oncall\n" +
+			host + "/cfg-tertiary?failover=oncall\n",
+	}
+
+	for format, content := range configs {
+		t.Run(format, func(t *testing.T) {
+			before := len(server.paths())
+
+			app := New()
+			loader := NewConfigLoader(app)
+			if err := loader.AddFromString(content, format); err != nil {
+				t.Fatalf("Failed to parse config: %v", err)
+			}
+			if err := loader.ApplyToApprise(); err != nil {
+				t.Fatalf("Failed to apply config: %v", err)
+			}
+
+			responses := app.NotifyAll(NotificationRequest{Body: "page"})
+			if len(responses) != 2 || !responses[1].Success {
+				t.Fatalf("Expected the secondary to take over, got %+v", responses)
+			}
+			if got := strings.Join(server.paths()[before:], " "); got != "/cfg-primary /cfg-secondary" {
+				t.Errorf("Unexpected requests: %q", got)
+			}
+		})
+	}
+}
+
+func TestFailoverAfterMemberHangs(t *testing.T) {
+	release := make(chan struct{})
+	hung := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer hung.Close()
+	defer close(release)
+	server := newStubAPI(t, answerStatus(http.StatusOK))
+
+	app := New()
+	app.SetTimeout(200 * time.Millisecond)
+	for _, host := range []string{hung.URL, server.URL} {
+		if err := app.AddToFailoverGroup("oncall", "webhook://"+strings.TrimPrefix(host, "http://")+"/page"); err != nil {
+			t.Fatalf("Failed to add %s: %v", host, err)
+		}
+	}
+
+	responses := app.NotifyAll(NotificationRequest{Body: "page"})
+	if len(responses) != 2 {
+		t.Fatalf("Expected both members to be tried, got %d responses", len(responses))
+	}
+	if responses[0].Success {
+		t.Errorf("Expected the hung member to time out, got %+v", responses[0])
+	}
+	if !responses[1].Success {
+		t.Errorf("Expected the next member to get its own deadline and succeed, got %+v", responses[1])
+	}
+	if len(server.paths()) != 1 {
+		t.Errorf("Expected the next member to be called once, got %v", server.paths())
+	}
+}
@@ -12,6 +12,12 @@ import (
 	"time"
 )
 
+// fcmAPIHost is the host of the FCM HTTP v1 API
+const fcmAPIHost = "fcm.googleapis.com"
+
+// fcmScope is the OAuth2 scope for sending FCM messages
+const fcmScope = "https://www.googleapis.com/auth/firebase.messaging"
+
 // FCMService implements Firebase Cloud Messaging push notifications
 type FCMService struct {
 	projectID      string
@@ -19,6 +25,11 @@ type FCMService struct {
 	serviceAccount string // Service account JSON for OAuth2
 	webhookURL     string // Webhook proxy URL for secure credential management
 	apiKey         string // API key for webhook authentication
+	apiURL         string // FCM HTTP v1 API base URL in direct mode
+	deviceTokens   []string
+	topic          string
+	condition      string
+	tokens         *googleTokenSource // Access token source in direct mode
 	client         *http.Client
 }
 
@@ -167,18 +178,31 @@ func (f *FCMService) GetDefaultPort() int {
 	return 443
 }
 
-// ParseURL parses a Firebase Cloud Messaging service URL
+// ParseURL parses a Firebase Cloud Messaging service URL. Using the FCM API
+// host sends straight to the HTTP v1 API with a service account; any other
+// host is a webhook proxy.
 // Format: fcm://webhook.example.com/firebase?project_id=my-project&server_key=key
 // Format: fcm://api-key@webhook.example.com/proxy?project_id=my-project&service_account=path/to/sa.json
+// Format: fcm://fcm.googleapis.com/TOKEN1/TOKEN2?service_account=path/to/sa.json
+// Format: fcm://fcm.googleapis.com/?service_account=sa.json&project_id=my-project&topic=news
+// Format: fcm://fcm.googleapis.com/?service_account=sa.json&condition='news' in topics && 'eu' in topics
+// Format: fcm://fcm.googleapis.com/TOKEN?service_account=sa.json&endpoint_url=http://localhost:9099
 func (f *FCMService) ParseURL(serviceURL *url.URL) error {
 	if serviceURL.Scheme != "fcm" {
 		return fmt.Errorf("invalid scheme: expected 'fcm', got '%s'", serviceURL.Scheme)
 	}
 
+	// Parse query parameters
+	query := serviceURL.Query()
+
+	if strings.EqualFold(serviceURL.Hostname(), fcmAPIHost) {
+		return f.parseDirectURL(serviceURL, query)
+	}
+
 	// Extract webhook URL components
 	// For testing, preserve the original scheme if it's http
 	scheme := "https"
-	if serviceURL.Scheme == "fcm" && strings.Contains(serviceURL.Host, "127.0.0.1") {
+	if strings.Contains(serviceURL.Host, "127.0.0.1") {
 		// Test mode: use HTTP for localhost
 		scheme = "http"
 	}
@@ -189,9 +213,6 @@ func (f *FCMService) ParseURL(serviceURL *url.URL) error {
 		f.apiKey = serviceURL.User.Username()
 	}
 
-	// Parse query parameters
-	query := serviceURL.Query()
-
 	// Required: project_id
 	f.projectID = query.Get("project_id")
 	if f.projectID == "" {
@@ -215,11 +236,60 @@ func (f *FCMService) ParseURL(serviceURL *url.URL) error {
 	return nil
 }
 
-// Send sends a push notification via Firebase Cloud Messaging
+// parseDirectURL configures sending to the device tokens in the URL path and
+// the topic and condition parameters with the HTTP v1 API. The service
+// account comes from the service_account parameter or
+// GOOGLE_APPLICATION_CREDENTIALS, and also supplies the default project. The
+// endpoint_url parameter replaces the HTTP v1 API base URL.
+func (f *FCMService) parseDirectURL(serviceURL *url.URL, query url.Values) error {
+	for _, token := range strings.Split(strings.Trim(serviceURL.Path, "/"), "/") {
+		if token != "" {
+			f.deviceTokens = append(f.deviceTokens, token)
+		}
+	}
+	f.topic = strings.TrimPrefix(strings.TrimPrefix(query.Get("topic"), "/topics/"), "#")
+	f.condition = query.Get("condition")
+	if len(f.deviceTokens) == 0 && f.topic == "" && f.condition == "" {
+		return fmt.Errorf("at least one device token, a topic or a condition is required")
+	}
+
+	if query.Get("server_key") != "" {
+		return fmt.Errorf("server_key is not supported by the FCM HTTP v1 API, use service_account")
+	}
+
+	f.serviceAccount = query.Get("service_account")
+	account, err := LoadGoogleServiceAccount(f.serviceAccount)
+	if err != nil {
+		return err
+	}
+	f.tokens, err = newGoogleTokenSource(account, fcmScope)
+	if err != nil {
+		return err
+	}
+
+	f.projectID = query.Get("project_id")
+	if f.projectID == "" {
+		f.projectID = account.ProjectID
+	}
+	if f.projectID == "" {
+		return fmt.Errorf("project_id parameter is required when the service account has no project_id")
+	}
+
+	f.apiURL, err = parseGoogleEndpoint(query, fcmAPIHost)
+	return err
+}
+
+// Send sends a push notification via Firebase Cloud Messaging. In direct
+// mode the topic, the condition and each device token get their own message;
+// failures are reported per target in an *FCMSendError.
 func (f *FCMService) Send(ctx context.Context, req NotificationRequest) error {
 	// Create FCM message
 	message := f.createMessage(req)
 
+	if f.apiURL != "" {
+		return f.sendToTargets(ctx, message)
+	}
+
 	// Create request payload
 	payload := FCMPayload{
 		Message: message,
@@ -383,12 +453,203 @@ func (f *FCMService) sendViaWebhook(ctx context.Context, payload FCMPayload) err
 	// Check response status
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("FCM API error (status %d): %s", resp.StatusCode, string(body))
+		return NewHTTPError(resp, fmt.Errorf("FCM API error (status %d): %s", resp.StatusCode, string(body)))
 	}
 
 	return nil
 }
 
+// FCMDeliveryResult is the outcome of sending a message to one target
+type FCMDeliveryResult struct {
+	Target      string // Device token, "topic:NAME" or "condition:EXPRESSION"
+	MessageName string // Message name FCM assigned, e.g. projects/p/messages/1
+	StatusCode  int    // HTTP status, 0 if no response was received
+	ErrorCode   string // FCM error code such as "UNREGISTERED"
+	Err         error  // Classified error, nil if the message was accepted
+}
+
+// FCMSendError reports the targets a direct send failed for. Results holds
+// the outcome for every target: the topic, the condition, then the device
+// tokens in URL order.
+type FCMSendError struct {
+	Results []FCMDeliveryResult
+}
+
+// Failed returns the results of the targets the send failed for
+func (e *FCMSendError) Failed() []FCMDeliveryResult {
+	var failed []FCMDeliveryResult
+	for _, result := range e.Results {
+		if result.Err != nil {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+// InvalidTokens returns the device tokens FCM reported as invalid or no
+// longer registered, which should not be sent to again
+func (e *FCMSendError) InvalidTokens() []string {
+	var tokens []string
+	for _, result := range e.Failed() {
+		if ClassifyError(result.Err) == ErrorClassInvalidRecipient &&
+			!strings.HasPrefix(result.Target, "topic:") && !strings.HasPrefix(result.Target, "condition:") {
+			tokens = append(tokens, result.Target)
+		}
+	}
+	return tokens
+}
+
+// Error implements the error interface
+func (e *FCMSendError) Error() string {
+	failed := e.Failed()
+	reasons := make([]string, len(failed))
+	for i, result := range failed {
+		reasons[i] = fmt.Sprintf("%s: %v", result.Target, result.Err)
+	}
+	return fmt.Sprintf("FCM send failed for %d of %d targets: %s", len(failed), len(e.Results), strings.Join(reasons, "; "))
+}
+
+// fcmErrorClasses maps FCM error codes to error classes where the HTTP
+// status alone is not precise enough
+var fcmErrorClasses = map[string]ErrorClass{
+	"UNREGISTERED":           ErrorClassInvalidRecipient,
+	"SENDER_ID_MISMATCH":     ErrorClassInvalidRecipient,
+	"THIRD_PARTY_AUTH_ERROR": ErrorClassAuth,
+	"QUOTA_EXCEEDED":         ErrorClassRateLimited,
+	"UNAVAILABLE":            ErrorClassRemote5xx,
+	"INTERNAL":               ErrorClassRemote5xx,
+}
+
+// sendToTargets sends message to the topic, the condition and each device
+// token with the HTTP v1 API
+func (f *FCMService) sendToTargets(ctx context.Context, message FCMMessage) error {
+	message.Token, message.Topic, message.Condition = "", "", ""
+
+	var results []FCMDeliveryResult
+	if f.topic != "" {
+		target := message
+		target.Topic = f.topic
+		results = append(results, f.sendMessage(ctx, "topic:"+f.topic, target))
+	}
+	if f.condition != "" {
+		target := message
+		target.Condition = f.condition
+		results = append(results, f.sendMessage(ctx, "condition:"+f.condition, target))
+	}
+	for _, token := range f.deviceTokens {
+		target := message
+		target.Token = token
+		results = append(results, f.sendMessage(ctx, token, target))
+	}
+
+	sendErr := &FCMSendError{Results: results}
+	failed := sendErr.Failed()
+	if len(failed) == 0 {
+		return nil
+	}
+
+	failures := make([]error, len(failed))
+	for i, result := range failed {
+		failures[i] = result.Err
+	}
+	return newPartialFailureError(sendErr, failures, len(results))
+}
+
+// sendMessage sends one message. A rejected access token is replaced and
+// the message sent once more.
+func (f *FCMService) sendMessage(ctx context.Context, target string, message FCMMessage) FCMDeliveryResult {
+	result, accessToken := f.postMessage(ctx, target, message)
+	if result.StatusCode == http.StatusUnauthorized && accessToken != "" {
+		f.tokens.invalidate(accessToken)
+		result, _ = f.postMessage(ctx, target, message)
+	}
+	return result
+}
+
+// postMessage makes one messages:send request and returns its result along
+// with the access token it used
+func (f *FCMService) postMessage(ctx context.Context, target string, message FCMMessage) (FCMDeliveryResult, string) {
+	result := FCMDeliveryResult{Target: target}
+
+	jsonData, err := json.Marshal(FCMPayload{Message: message})
+	if err != nil {
+		result.Err = fmt.Errorf("failed to marshal FCM message: %w", err)
+		return result, ""
+	}
+
+	accessToken, err := f.tokens.get(ctx, f.client)
+	if err != nil {
+		result.Err = fmt.Errorf("failed to get FCM access token: %w", err)
+		return result, ""
+	}
+
+	endpoint := fmt.Sprintf("%s/v1/projects/%s/messages:send", f.apiURL, url.PathEscape(f.projectID))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(jsonData))
+	if err != nil {
+		result.Err = fmt.Errorf("failed to create FCM request: %w", err)
+		return result, accessToken
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("User-Agent", GetUserAgent())
+	httpReq.Header.Set("Authorization", "Bearer "+accessToken)
+
+	resp, err := f.client.Do(httpReq)
+	if err != nil {
+		result.Err = fmt.Errorf("failed to send FCM notification: %w", err)
+		return result, accessToken
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	result.StatusCode = resp.StatusCode
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		var sent struct {
+			Name string `json:"name"`
+		}
+		_ = json.Unmarshal(body, &sent)
+		result.MessageName = sent.Name
+		return result, accessToken
+	}
+
+	var apiErr struct {
+		Error struct {
+			Message string `json:"message"`
+			Status  string `json:"status"`
+			Details []struct {
+				Type      string `json:"@type"`
+				ErrorCode string `json:"errorCode"`
+			} `json:"details"`
+		} `json:"error"`
+	}
+	_ = json.Unmarshal(body, &apiErr)
+
+	result.ErrorCode = apiErr.Error.Status
+	for _, detail := range apiErr.Error.Details {
+		if strings.HasSuffix(detail.Type, "google.firebase.fcm.v1.FcmError") && detail.ErrorCode != "" {
+			result.ErrorCode = detail.ErrorCode
+		}
+	}
+
+	reason := apiErr.Error.Message
+	if reason == "" {
+		reason = string(body)
+	}
+	notifyErr := NewHTTPError(resp, fmt.Errorf("FCM API error (status %d): %s", resp.StatusCode, reason))
+	if class, ok := fcmErrorClasses[result.ErrorCode]; ok {
+		notifyErr.Class = class
+		notifyErr.Retryable = class.Retryable()
+	} else if result.ErrorCode == "INVALID_ARGUMENT" && message.Token != "" &&
+		strings.Contains(strings.ToLower(reason), "registration token") {
+		// FCM reports malformed device tokens as invalid arguments
+		notifyErr.Class = ErrorClassInvalidRecipient
+		notifyErr.Retryable = false
+	}
+	result.Err = notifyErr
+
+	return result, accessToken
+}
+
 // Helper methods for platform-specific configurations
 
 func (f *FCMService) getPriorityForNotifyType(notifyType NotifyType) string {
@@ -502,3 +763,5 @@ func (f *FCMService) GetMaxBodyLength() int {
 // fcm://webhook.example.com/firebase?project_id=my-project&server_key=AAAA...
 // fcm://api-key@webhook.example.com/proxy?project_id=my-project&service_account=path/to/service-account.json
 // fcm://webhook.example.com/fcm?project_id=my-firebase-project&server_key=legacy-server-key
+// fcm://fcm.googleapis.com/DEVICE_TOKEN1/DEVICE_TOKEN2?service_account=path/to/service-account.json
+// fcm://fcm.googleapis.com/?service_account=path/to/service-account.json&topic=alerts
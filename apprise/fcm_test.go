@@ -3,10 +3,13 @@ package apprise
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -543,3 +546,211 @@ func TestFCMService_APNSPriorityMapping(t *testing.T) {
 		})
 	}
 }
+
+// answerFCM returns a handler standing in for the FCM API. respond picks the
+// status and FCM error code for each message; an empty code accepts it.
+func answerFCM(respond func(message FCMMessage) (int, string)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var payload FCMPayload
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+
+		status, code := respond(payload.Message)
+		w.WriteHeader(status)
+		if code == "" {
+			_, _ = w.Write([]byte(`{"name":"projects/test-project/messages/0:1"}`))
+			return
+		}
+		message := "Requested entity was not found."
+		if code == "INVALID_ARGUMENT" {
+			message = "The registration token is not a valid FCM registration token"
+		}
+		_, _ = fmt.Fprintf(w, `{"error":{"code":%d,"message":%q,"status":"%s","details":[{"@type":"type.googleapis.com/google.firebase.fcm.v1.FcmError","errorCode":"%s"}]}}`,
+			status, message, code, code)
+	}
+}
+
+// fcmMessage returns the message a messages:send request carried
+func fcmMessage(req stubRequest) FCMMessage {
+	var payload FCMPayload
+	req.decode(&payload)
+	return payload.Message
+}
+
+// useFCMAPIServer points the service at the fake API and returns the context
+// to send with
+func useFCMAPIServer(t *testing.T, api *googleAPIServer) context.Context {
+	t.Helper()
+	endpoint, err := ParseEndpoint(api.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return withEndpoint(context.Background(), endpoint)
+}
+
+func TestFCMService_ParseDirectURL(t *testing.T) {
+	accountPath := writeGoogleServiceAccount(t, "")
+
+	service := NewFCMService().(*FCMService)
+	serviceURL, _ := url.Parse("fcm://fcm.googleapis.com/token-a/token-b?service_account=" + accountPath + "&topic=/topics/news")
+	if err := service.ParseURL(serviceURL); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if service.projectID != "test-project" {
+		t.Errorf("Expected the project of the service account, got %s", service.projectID)
+	}
+	if strings.Join(service.deviceTokens, ",") != "token-a,token-b" || service.topic != "news" {
+		t.Errorf("Unexpected targets %v, %q", service.deviceTokens, service.topic)
+	}
+
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", accountPath)
+	service = NewFCMService().(*FCMService)
+	serviceURL, _ = url.Parse("fcm://fcm.googleapis.com/?project_id=other&condition=" + url.QueryEscape("'a' in topics"))
+	if err := service.ParseURL(serviceURL); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if service.projectID != "other" || service.condition != "'a' in topics" {
+		t.Errorf("Unexpected project %q or condition %q", service.projectID, service.condition)
+	}
+
+	invalid := map[string]string{
+		"no targets":            "fcm://fcm.googleapis.com/?service_account=" + accountPath,
+		"legacy server key":     "fcm://fcm.googleapis.com/token-a?server_key=AAAA",
+		"missing account file":  "fcm://fcm.googleapis.com/token-a?service_account=/nonexistent/sa.json",
+		"account is not a file": "fcm://fcm.googleapis.com/token-a?service_account=" + t.TempDir(),
+	}
+	for name, rawURL := range invalid {
+		serviceURL, _ := url.Parse(rawURL)
+		if err := NewFCMService().(*FCMService).ParseURL(serviceURL); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestFCMService_SendDirect(t *testing.T) {
+	api := newGoogleAPIServer(t, answerFCM(func(message FCMMessage) (int, string) {
+		switch message.Token {
+		case "stale-token":
+			return http.StatusNotFound, "UNREGISTERED"
+		case "bad-token":
+			return http.StatusBadRequest, "INVALID_ARGUMENT"
+		}
+		return http.StatusOK, ""
+	}))
+	accountPath := writeGoogleServiceAccount(t, api.URL+"/token")
+
+	service := NewFCMService().(*FCMService)
+	serviceURL, _ := url.Parse("fcm://fcm.googleapis.com/good-token/stale-token/bad-token?service_account=" + accountPath + "&topic=alerts")
+	if err := service.ParseURL(serviceURL); err != nil {
+		t.Fatalf("Failed to parse URL: %v", err)
+	}
+	ctx := useFCMAPIServer(t, api)
+
+	err := service.Send(ctx, NotificationRequest{Title: "Disk", Body: "Almost full", NotifyType: NotifyTypeWarning})
+
+	var sendErr *FCMSendError
+	if !errors.As(err, &sendErr) {
+		t.Fatalf("Expected an FCMSendError, got %v", err)
+	}
+	if !errors.Is(err, ErrInvalidRecipient) || IsRetryable(err) {
+		t.Errorf("Expected a non-retryable invalid recipient error, got %v", err)
+	}
+	if len(sendErr.Results) != 4 || len(sendErr.Failed()) != 2 {
+		t.Fatalf("Expected 2 of 4 targets to fail, got %+v", sendErr.Results)
+	}
+	if invalid := strings.Join(sendErr.InvalidTokens(), ","); invalid != "stale-token,bad-token" {
+		t.Errorf("Expected stale-token and bad-token to be reported invalid, got %s", invalid)
+	}
+	if result := sendErr.Results[0]; result.Target != "topic:alerts" || result.MessageName == "" {
+		t.Errorf("Expected the topic message to be accepted, got %+v", result)
+	}
+	if result := sendErr.Results[2]; result.ErrorCode != "UNREGISTERED" || result.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected UNREGISTERED for stale-token, got %+v", result)
+	}
+
+	received := api.receivedAt("/v1/")
+	if len(received) != 4 {
+		t.Fatalf("Expected 4 messages, got %d", len(received))
+	}
+	for _, request := range received {
+		if request.path != "/v1/projects/test-project/messages:send" {
+			t.Errorf("Unexpected path %s", request.path)
+		}
+		if request.authorization != "Bearer token-1" {
+			t.Errorf("Expected the cached access token, got %q", request.authorization)
+		}
+	}
+	if message := fcmMessage(received[0]); message.Topic != "alerts" || message.Token != "" {
+		t.Errorf("Expected the first message to go to the topic, got %+v", message)
+	}
+	if message := fcmMessage(received[1]); message.Token != "good-token" || message.Notification.Title != "Disk" ||
+		message.Android.Priority != "high" || message.Data["notification_type"] != "warning" {
+		t.Errorf("Unexpected message %+v", message)
+	}
+	if api.tokensMinted() != 1 {
+		t.Errorf("Expected one access token to be minted, got %d", api.tokensMinted())
+	}
+}
+
+func TestFCMService_SendDirectRefreshesRejectedToken(t *testing.T) {
+	var mu sync.Mutex
+	rejected := false
+	api := newGoogleAPIServer(t, answerFCM(func(FCMMessage) (int, string) {
+		mu.Lock()
+		defer mu.Unlock()
+		if !rejected {
+			rejected = true
+			return http.StatusUnauthorized, "THIRD_PARTY_AUTH_ERROR"
+		}
+		return http.StatusOK, ""
+	}))
+	accountPath := writeGoogleServiceAccount(t, api.URL+"/token")
+
+	service := NewFCMService().(*FCMService)
+	serviceURL, _ := url.Parse("fcm://fcm.googleapis.com/device?service_account=" + accountPath)
+	if err := service.ParseURL(serviceURL); err != nil {
+		t.Fatalf("Failed to parse URL: %v", err)
+	}
+
+	if err := service.Send(useFCMAPIServer(t, api), NotificationRequest{Body: "test"}); err != nil {
+		t.Fatalf("Expected the message to be sent with a new access token, got %v", err)
+	}
+	received := api.receivedAt("/v1/")
+	if len(received) != 2 || received[1].authorization != "Bearer token-2" {
+		t.Errorf("Expected a retry with a new access token, got %+v", received)
+	}
+}
+
+func TestFCMService_EndpointOverrideKeepsTokenRequests(t *testing.T) {
+	tokens := newGoogleAPIServer(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Unexpected request to the token endpoint: %s", r.URL.Path)
+		w.WriteHeader(http.StatusNotFound)
+	})
+	api := newGoogleAPIServer(t, answerFCM(func(FCMMessage) (int, string) { return http.StatusOK, "" }))
+	accountPath := writeGoogleServiceAccount(t, tokens.URL+"/token")
+
+	service := NewFCMService().(*FCMService)
+	serviceURL, _ := url.Parse("fcm://fcm.googleapis.com/device?service_account=" + accountPath + "&endpoint_url=" + api.URL)
+	if err := service.ParseURL(serviceURL); err != nil {
+		t.Fatalf("Failed to parse URL: %v", err)
+	}
+	if service.apiURL != api.URL {
+		t.Errorf("Expected the API base URL %s, got %s", api.URL, service.apiURL)
+	}
+
+	// An api_url override redirects the messages but not the token exchange
+	if err := service.Send(useFCMAPIServer(t, api), NotificationRequest{Body: "test"}); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if tokens.tokensMinted() != 1 || api.tokensMinted() != 0 {
+		t.Errorf("Expected the signed assertion to go to the token endpoint only, got %d and %d tokens",
+			tokens.tokensMinted(), api.tokensMinted())
+	}
+	if received := api.receivedAt("/v1/"); len(received) != 1 || received[0].authorization != "Bearer token-1" {
+		t.Errorf("Expected one message sent with the token, got %+v", received)
+	}
+
+	serviceURL, _ = url.Parse("fcm://fcm.googleapis.com/device?service_account=" + accountPath + "&endpoint_url=ftp://example.com")
+	if err := NewFCMService().(*FCMService).ParseURL(serviceURL); err == nil {
+		t.Error("Expected an error for a non-HTTP endpoint_url")
+	}
+}
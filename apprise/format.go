@@ -0,0 +1,549 @@
+package apprise
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+// Body formats understood by ConvertBody
+const (
+	BodyFormatText     = "text"
+	BodyFormatMarkdown = "markdown"
+	BodyFormatHTML     = "html"
+	// BodyFormatMarkdownV2 is Telegram's MarkdownV2 dialect, in which every
+	// reserved character outside of markup must be escaped
+	BodyFormatMarkdownV2 = "markdownv2"
+	// BodyFormatMarkdownLegacy is Telegram's legacy Markdown: *bold*,
+	// _italic_, code and links only, never nested, with _ * ` [ escaped
+	// outside of markup
+	BodyFormatMarkdownLegacy = "markdownlegacy"
+	// BodyFormatMrkdwn is Slack's mrkdwn: *bold*, _italic_, ~strike~, code
+	// and <url|label> links, no headings, with & < > escaped as entities
+	BodyFormatMrkdwn = "mrkdwn"
+)
+
+// NativeFormatter is implemented by services that expect the body in a
+// particular format. NotifyAll converts bodies with a declared BodyFormat to
+// the service's native format before calling Send.
+type NativeFormatter interface {
+	// GetNativeFormat returns the body format the service renders
+	GetNativeFormat() string
+}
+
+// formatForService converts req's body to svc's native format. Requests
+// without a BodyFormat and services without a native format are left alone.
+func formatForService(req NotificationRequest, svc Service) NotificationRequest {
+	formatter, ok := svc.(NativeFormatter)
+	if !ok || req.BodyFormat == "" {
+		return req
+	}
+
+	native := formatter.GetNativeFormat()
+	if native == "" {
+		return req
+	}
+
+	req.Body = ConvertBody(req.Body, req.BodyFormat, native)
+	req.BodyFormat = native
+	return req
+}
+
+// ConvertBody converts body from one format to another. Unknown formats and
+// conversions without a meaningful mapping (text to markdown) return body
+// unchanged.
+//
+// HTML output keeps line breaks as newlines and only uses tags Telegram
+// accepts; services rendering into an HTML document turn newlines into <br>
+// themselves.
+func ConvertBody(body, from, to string) string {
+	from, to = strings.ToLower(from), strings.ToLower(to)
+	if from == to {
+		return body
+	}
+
+	switch from {
+	case BodyFormatText:
+		switch to {
+		case BodyFormatHTML:
+			return html.EscapeString(body)
+		case BodyFormatMarkdownV2:
+			return escapeMarkdownV2(body)
+		case BodyFormatMarkdownLegacy:
+			return escapeChars(body, markdownLegacyReserved)
+		case BodyFormatMrkdwn:
+			return escapeMrkdwn(body)
+		}
+	case BodyFormatMarkdown:
+		switch to {
+		case BodyFormatHTML:
+			return renderMarkdown(body, htmlRenderer{})
+		case BodyFormatText:
+			return renderMarkdown(body, textRenderer{})
+		case BodyFormatMarkdownV2:
+			return renderMarkdown(body, markdownV2Renderer{})
+		case BodyFormatMarkdownLegacy:
+			return renderMarkdown(body, markdownLegacyRenderer{})
+		case BodyFormatMrkdwn:
+			return renderMarkdown(body, mrkdwnRenderer{})
+		}
+	case BodyFormatHTML:
+		switch to {
+		case BodyFormatText:
+			return htmlToText(body)
+		case BodyFormatMarkdown:
+			return htmlToMarkdown(body)
+		case BodyFormatMarkdownV2:
+			return renderMarkdown(htmlToMarkdown(body), markdownV2Renderer{})
+		case BodyFormatMarkdownLegacy:
+			return renderMarkdown(htmlToMarkdown(body), markdownLegacyRenderer{})
+		case BodyFormatMrkdwn:
+			return renderMarkdown(htmlToMarkdown(body), mrkdwnRenderer{})
+		}
+	}
+
+	return body
+}
+
+// markdownV2Reserved are the characters Telegram's MarkdownV2 requires to be
+// escaped outside of markup
+const markdownV2Reserved = "_*[]()~`>#+-=|{}.!\\"
+
+// escapeMarkdownV2 escapes text for Telegram's MarkdownV2
+func escapeMarkdownV2(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		if strings.ContainsRune(markdownV2Reserved, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// markdownRenderer turns parsed markdown elements into an output format
+type markdownRenderer interface {
+	text(s string) string
+	bold(inner string) string
+	italic(inner string) string
+	strike(inner string) string
+	code(s string) string
+	link(label, target string) string
+	heading(inner string) string
+	listItem(marker, inner string) string
+	quote(inner string) string
+	codeBlock(code string) string
+}
+
+// htmlRenderer renders markdown as Telegram-safe HTML
+type htmlRenderer struct{}
+
+func (htmlRenderer) text(s string) string        { return html.EscapeString(s) }
+func (htmlRenderer) bold(inner string) string    { return "<b>" + inner + "</b>" }
+func (htmlRenderer) italic(inner string) string  { return "<i>" + inner + "</i>" }
+func (htmlRenderer) strike(inner string) string  { return "<s>" + inner + "</s>" }
+func (htmlRenderer) code(s string) string        { return "<code>" + html.EscapeString(s) + "</code>" }
+func (htmlRenderer) heading(inner string) string { return "<b>" + inner + "</b>" }
+func (htmlRenderer) quote(inner string) string   { return "<blockquote>" + inner + "</blockquote>" }
+func (htmlRenderer) codeBlock(code string) string {
+	return "<pre>" + html.EscapeString(code) + "</pre>"
+}
+
+func (htmlRenderer) link(label, target string) string {
+	return `<a href="` + html.EscapeString(target) + `">` + label + "</a>"
+}
+
+func (htmlRenderer) listItem(marker, inner string) string {
+	return html.EscapeString(marker) + " " + inner
+}
+
+// textRenderer strips markdown down to readable plain text
+type textRenderer struct{}
+
+func (textRenderer) text(s string) string         { return s }
+func (textRenderer) bold(inner string) string     { return inner }
+func (textRenderer) italic(inner string) string   { return inner }
+func (textRenderer) strike(inner string) string   { return inner }
+func (textRenderer) code(s string) string         { return s }
+func (textRenderer) heading(inner string) string  { return inner }
+func (textRenderer) quote(inner string) string    { return inner }
+func (textRenderer) codeBlock(code string) string { return code }
+
+func (textRenderer) link(label, target string) string {
+	if label == "" || label == target {
+		return target
+	}
+	return label + " (" + target + ")"
+}
+
+func (textRenderer) listItem(marker, inner string) string {
+	return marker + " " + inner
+}
+
+// markdownV2Renderer renders markdown in Telegram's MarkdownV2 dialect
+type markdownV2Renderer struct{}
+
+func (markdownV2Renderer) text(s string) string        { return escapeMarkdownV2(s) }
+func (markdownV2Renderer) bold(inner string) string    { return "*" + inner + "*" }
+func (markdownV2Renderer) italic(inner string) string  { return "_" + inner + "_" }
+func (markdownV2Renderer) strike(inner string) string  { return "~" + inner + "~" }
+func (markdownV2Renderer) heading(inner string) string { return "*" + inner + "*" }
+func (markdownV2Renderer) quote(inner string) string   { return ">" + inner }
+
+// Inside code and link targets only backslash and the closing delimiter
+// need escaping
+func (markdownV2Renderer) code(s string) string {
+	return "`" + escapeChars(s, "\\`") + "`"
+}
+
+func (markdownV2Renderer) codeBlock(code string) string {
+	return "```\n" + escapeChars(code, "\\`") + "\n```"
+}
+
+func (markdownV2Renderer) link(label, target string) string {
+	return "[" + label + "](" + escapeChars(target, "\\)") + ")"
+}
+
+func (markdownV2Renderer) listItem(marker, inner string) string {
+	return escapeMarkdownV2(marker) + " " + inner
+}
+
+// markdownLegacyReserved are the characters Telegram's legacy Markdown
+// requires to be escaped outside of markup
+const markdownLegacyReserved = "_*`["
+
+// markdownLegacyRenderer renders markdown as Telegram's legacy Markdown.
+// Escapes are not allowed inside markup, so marked up text is unescaped and
+// loses the characters that would end it early. There is no strikethrough,
+// heading or quote markup.
+type markdownLegacyRenderer struct{}
+
+func (markdownLegacyRenderer) strike(inner string) string { return inner }
+func (markdownLegacyRenderer) quote(inner string) string  { return "> " + inner }
+
+func (markdownLegacyRenderer) text(s string) string {
+	return escapeChars(s, markdownLegacyReserved)
+}
+
+func (markdownLegacyRenderer) bold(inner string) string {
+	return "*" + markdownLegacyEntity(inner, "*") + "*"
+}
+
+func (markdownLegacyRenderer) italic(inner string) string {
+	return "_" + markdownLegacyEntity(inner, "_") + "_"
+}
+
+func (markdownLegacyRenderer) heading(inner string) string {
+	return "*" + markdownLegacyEntity(inner, "*") + "*"
+}
+
+func (markdownLegacyRenderer) code(s string) string {
+	return "`" + strings.ReplaceAll(s, "`", "'") + "`"
+}
+
+func (markdownLegacyRenderer) codeBlock(code string) string {
+	return "```\n" + strings.ReplaceAll(code, "`", "'") + "\n```"
+}
+
+func (markdownLegacyRenderer) link(label, target string) string {
+	return "[" + markdownLegacyEntity(label, "]") + "](" + strings.ReplaceAll(target, ")", "%29") + ")"
+}
+
+func (markdownLegacyRenderer) listItem(marker, inner string) string {
+	return marker + " " + inner
+}
+
+// markdownLegacyEntity turns rendered legacy Markdown into the content of
+// markup ended by end: escapes are removed, as are the characters of end
+func markdownLegacyEntity(rendered, end string) string {
+	var b strings.Builder
+	for i := 0; i < len(rendered); i++ {
+		if rendered[i] == '\\' && i+1 < len(rendered) && strings.IndexByte(markdownLegacyReserved, rendered[i+1]) >= 0 {
+			i++
+		}
+		if strings.IndexByte(end, rendered[i]) >= 0 {
+			continue
+		}
+		b.WriteByte(rendered[i])
+	}
+	return b.String()
+}
+
+// mrkdwnEscaper replaces the characters Slack's mrkdwn reserves for its
+// control sequences with entities
+var mrkdwnEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// escapeMrkdwn escapes text for Slack's mrkdwn
+func escapeMrkdwn(text string) string {
+	return mrkdwnEscaper.Replace(text)
+}
+
+// mrkdwnRenderer renders markdown as Slack's mrkdwn. Slack has no headings,
+// so they are shown in bold.
+type mrkdwnRenderer struct{}
+
+func (mrkdwnRenderer) text(s string) string        { return escapeMrkdwn(s) }
+func (mrkdwnRenderer) bold(inner string) string    { return "*" + inner + "*" }
+func (mrkdwnRenderer) italic(inner string) string  { return "_" + inner + "_" }
+func (mrkdwnRenderer) strike(inner string) string  { return "~" + inner + "~" }
+func (mrkdwnRenderer) heading(inner string) string { return "*" + inner + "*" }
+func (mrkdwnRenderer) quote(inner string) string   { return "> " + inner }
+func (mrkdwnRenderer) code(s string) string        { return "`" + escapeMrkdwn(s) + "`" }
+func (mrkdwnRenderer) codeBlock(code string) string {
+	return "```\n" + escapeMrkdwn(code) + "\n```"
+}
+
+// Link targets end at | or >, so those are percent-encoded
+func (mrkdwnRenderer) link(label, target string) string {
+	target = strings.NewReplacer("|", "%7C", "<", "%3C", ">", "%3E").Replace(target)
+	if label == "" || label == escapeMrkdwn(target) {
+		return "<" + target + ">"
+	}
+	return "<" + target + "|" + label + ">"
+}
+
+func (mrkdwnRenderer) listItem(marker, inner string) string {
+	return escapeMrkdwn(marker) + " " + inner
+}
+
+// escapeChars backslash-escapes every occurrence of the given characters
+func escapeChars(s, chars string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(chars, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+var (
+	markdownHeading   = regexp.MustCompile(`^#{1,6}\s+(.*?)\s*#*\s*$`)
+	markdownBullet    = regexp.MustCompile(`^\s*[-*+]\s+(.*)$`)
+	markdownNumbered  = regexp.MustCompile(`^\s*(\d+[.)])\s+(.*)$`)
+	markdownQuote     = regexp.MustCompile(`^\s*>\s?(.*)$`)
+	markdownRule      = regexp.MustCompile(`^\s*([-*_])(\s*[-*_]){2,}\s*$`)
+	markdownFenceLine = regexp.MustCompile("^\\s*(```|~~~)")
+)
+
+// renderMarkdown renders a markdown body line by line with r. Block
+// structure is kept as newlines; inline markup is handed to r.
+func renderMarkdown(body string, r markdownRenderer) string {
+	lines := strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n")
+	out := make([]string, 0, len(lines))
+
+	var fence string
+	var code []string
+	for _, line := range lines {
+		if fence != "" {
+			if strings.HasPrefix(strings.TrimSpace(line), fence) {
+				out = append(out, r.codeBlock(strings.Join(code, "\n")))
+				fence, code = "", nil
+			} else {
+				code = append(code, line)
+			}
+			continue
+		}
+
+		if m := markdownFenceLine.FindStringSubmatch(line); m != nil {
+			fence = m[1]
+			continue
+		}
+
+		switch {
+		case markdownRule.MatchString(line):
+			out = append(out, r.text("――――――――"))
+		case markdownHeading.MatchString(line):
+			out = append(out, r.heading(renderInline(markdownHeading.FindStringSubmatch(line)[1], r)))
+		case markdownBullet.MatchString(line):
+			out = append(out, r.listItem("•", renderInline(markdownBullet.FindStringSubmatch(line)[1], r)))
+		case markdownNumbered.MatchString(line):
+			m := markdownNumbered.FindStringSubmatch(line)
+			out = append(out, r.listItem(m[1], renderInline(m[2], r)))
+		case markdownQuote.MatchString(line):
+			out = append(out, r.quote(renderInline(markdownQuote.FindStringSubmatch(line)[1], r)))
+		default:
+			out = append(out, renderInline(line, r))
+		}
+	}
+
+	// An unterminated fence still renders its content as code
+	if fence != "" {
+		out = append(out, r.codeBlock(strings.Join(code, "\n")))
+	}
+
+	return strings.Join(out, "\n")
+}
+
+// renderInline renders the inline markup of a single line with r
+func renderInline(s string, r markdownRenderer) string {
+	var out strings.Builder
+	var plain strings.Builder
+	flush := func() {
+		if plain.Len() > 0 {
+			out.WriteString(r.text(plain.String()))
+			plain.Reset()
+		}
+	}
+
+	for i := 0; i < len(s); {
+		rest := s[i:]
+
+		// Backslash escapes a following punctuation character
+		if rest[0] == '\\' && len(rest) > 1 && strings.ContainsRune("\\`*_{}[]()#+-.!~>|", rune(rest[1])) {
+			plain.WriteByte(rest[1])
+			i += 2
+			continue
+		}
+
+		if rest[0] == '`' {
+			if end := strings.IndexByte(rest[1:], '`'); end >= 0 {
+				flush()
+				out.WriteString(r.code(rest[1 : 1+end]))
+				i += end + 2
+				continue
+			}
+		}
+
+		if label, target, n := parseMarkdownLink(rest); n > 0 {
+			flush()
+			out.WriteString(r.link(renderInline(label, r), target))
+			i += n
+			continue
+		}
+
+		if inner, n, kind := parseEmphasis(rest); n > 0 {
+			flush()
+			rendered := renderInline(inner, r)
+			switch kind {
+			case "bold":
+				out.WriteString(r.bold(rendered))
+			case "strike":
+				out.WriteString(r.strike(rendered))
+			default:
+				out.WriteString(r.italic(rendered))
+			}
+			i += n
+			continue
+		}
+
+		plain.WriteByte(rest[0])
+		i++
+	}
+	flush()
+
+	return out.String()
+}
+
+// parseMarkdownLink parses [label](target) or ![alt](target) at the start
+// of s, returning the number of bytes consumed (0 if there is no link)
+func parseMarkdownLink(s string) (label, target string, n int) {
+	start := 0
+	if strings.HasPrefix(s, "![") {
+		start = 1
+	}
+	if start >= len(s) || s[start] != '[' {
+		return "", "", 0
+	}
+
+	closeLabel := strings.Index(s[start:], "](")
+	if closeLabel < 0 {
+		return "", "", 0
+	}
+	closeLabel += start
+
+	closeTarget := strings.IndexByte(s[closeLabel+2:], ')')
+	if closeTarget < 0 {
+		return "", "", 0
+	}
+	closeTarget += closeLabel + 2
+
+	return s[start+1 : closeLabel], strings.TrimSpace(s[closeLabel+2 : closeTarget]), closeTarget + 1
+}
+
+// parseEmphasis parses **bold**, __bold__, *italic*, _italic_ or
+// ~~strike~~ at the start of s, returning the inner text, the number of
+// bytes consumed (0 if there is no emphasis) and its kind
+func parseEmphasis(s string) (inner string, n int, kind string) {
+	for _, d := range []struct{ delim, kind string }{
+		{"**", "bold"}, {"__", "bold"}, {"~~", "strike"}, {"*", "italic"}, {"_", "italic"},
+	} {
+		if !strings.HasPrefix(s, d.delim) {
+			continue
+		}
+
+		body := s[len(d.delim):]
+		end := strings.Index(body, d.delim)
+		// Emphasis can't be empty or start/end with a space
+		if end <= 0 || body[0] == ' ' || body[end-1] == ' ' {
+			continue
+		}
+		// Single delimiters must not be the start of a double one
+		if len(d.delim) == 1 && strings.HasPrefix(body, d.delim) {
+			continue
+		}
+
+		return body[:end], len(d.delim)*2 + end, d.kind
+	}
+	return "", 0, ""
+}
+
+var (
+	htmlAnchor     = regexp.MustCompile(`(?is)<a\s[^>]*href\s*=\s*["']([^"']*)["'][^>]*>(.*?)</a>`)
+	htmlBold       = regexp.MustCompile(`(?is)<(?:b|strong)(?:\s[^>]*)?>(.*?)</(?:b|strong)>`)
+	htmlItalic     = regexp.MustCompile(`(?is)<(?:i|em)(?:\s[^>]*)?>(.*?)</(?:i|em)>`)
+	htmlStrike     = regexp.MustCompile(`(?is)<(?:s|strike|del)(?:\s[^>]*)?>(.*?)</(?:s|strike|del)>`)
+	htmlCode       = regexp.MustCompile(`(?is)<code(?:\s[^>]*)?>(.*?)</code>`)
+	htmlPre        = regexp.MustCompile(`(?is)<pre(?:\s[^>]*)?>(.*?)</pre>`)
+	htmlHeading    = regexp.MustCompile(`(?is)<h[1-6](?:\s[^>]*)?>(.*?)</h[1-6]>`)
+	htmlListItem   = regexp.MustCompile(`(?is)<li(?:\s[^>]*)?>`)
+	htmlBreak      = regexp.MustCompile(`(?i)<br\s*/?>`)
+	htmlBlockEnd   = regexp.MustCompile(`(?i)</(?:p|div|ul|ol|table|tr|blockquote|h[1-6])>`)
+	htmlInvisible  = regexp.MustCompile(`(?is)<(script|style|head)(?:\s[^>]*)?>.*?</(?:script|style|head)>`)
+	htmlTag        = regexp.MustCompile(`(?s)<[^>]*>`)
+	excessNewlines = regexp.MustCompile(`\n{3,}`)
+	trailingSpace  = regexp.MustCompile(`[ \t]+\n`)
+)
+
+// htmlToText strips HTML down to plain text, keeping the line structure of
+// block elements and list items
+func htmlToText(body string) string {
+	body = htmlInvisible.ReplaceAllString(body, "")
+	body = htmlAnchor.ReplaceAllStringFunc(body, func(match string) string {
+		m := htmlAnchor.FindStringSubmatch(match)
+		label := strings.TrimSpace(htmlTag.ReplaceAllString(m[2], ""))
+		return textRenderer{}.link(label, m[1])
+	})
+	return finishHTMLConversion(body)
+}
+
+// htmlToMarkdown converts the common inline and block HTML elements to
+// markdown and strips the rest
+func htmlToMarkdown(body string) string {
+	body = htmlInvisible.ReplaceAllString(body, "")
+	body = htmlPre.ReplaceAllStringFunc(body, func(match string) string {
+		code := htmlTag.ReplaceAllString(htmlPre.FindStringSubmatch(match)[1], "")
+		return "\n```\n" + code + "\n```\n"
+	})
+	body = htmlCode.ReplaceAllString(body, "`$1`")
+	body = htmlAnchor.ReplaceAllString(body, "[$2]($1)")
+	body = htmlBold.ReplaceAllString(body, "**$1**")
+	body = htmlItalic.ReplaceAllString(body, "*$1*")
+	body = htmlStrike.ReplaceAllString(body, "~~$1~~")
+	body = htmlHeading.ReplaceAllString(body, "\n## $1\n")
+	return finishHTMLConversion(body)
+}
+
+// finishHTMLConversion turns line-level HTML into newlines, drops the
+// remaining tags and decodes entities
+func finishHTMLConversion(body string) string {
+	body = htmlBreak.ReplaceAllString(body, "\n")
+	body = htmlBlockEnd.ReplaceAllString(body, "\n\n")
+	body = htmlListItem.ReplaceAllString(body, "\n- ")
+	body = htmlTag.ReplaceAllString(body, "")
+	body = html.UnescapeString(body)
+	body = trailingSpace.ReplaceAllString(body, "\n")
+	body = excessNewlines.ReplaceAllString(body, "\n\n")
+	return strings.TrimSpace(body)
+}
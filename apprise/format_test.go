@@ -0,0 +1,235 @@
+package apprise
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestConvertBody(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		from     string
+		to       string
+		expected string
+	}{
+		{
+			name:     "same format",
+			body:     "**bold**",
+			from:     BodyFormatMarkdown,
+			to:       BodyFormatMarkdown,
+			expected: "**bold**",
+		},
+		{
+			name:     "markdown to html",
+			body:     "# Alert\n**Disk** is _full_ on `db1`, see [runbook](https://example.com/?a=1&b=2)",
+			from:     BodyFormatMarkdown,
+			to:       BodyFormatHTML,
+			expected: "<b>Alert</b>\n<b>Disk</b> is <i>full</i> on <code>db1</code>, see <a href=\"https://example.com/?a=1&amp;b=2\">runbook</a>",
+		},
+		{
+			name:     "markdown list and code block to html",
+			body:     "- one\n- two\n```\nx < y\n```",
+			from:     BodyFormatMarkdown,
+			to:       BodyFormatHTML,
+			expected: "• one\n• two\n<pre>x &lt; y</pre>",
+		},
+		{
+			name:     "markdown to text",
+			body:     "## Deploy\n**Done** in ~~5~~ 4 minutes: [logs](https://ci.example.com)",
+			from:     BodyFormatMarkdown,
+			to:       BodyFormatText,
+			expected: "Deploy\nDone in 5 4 minutes: logs (https://ci.example.com)",
+		},
+		{
+			name:     "markdown escapes kept literal",
+			body:     `2 \* 3 = 6`,
+			from:     BodyFormatMarkdown,
+			to:       BodyFormatText,
+			expected: "2 * 3 = 6",
+		},
+		{
+			name:     "html to text",
+			body:     "<p>Hello &amp; welcome</p><ul><li>one</li><li>two</li></ul>Line<br/>break <a href=\"https://x.io\">site</a>",
+			from:     BodyFormatHTML,
+			to:       BodyFormatText,
+			expected: "Hello & welcome\n\n- one\n- two\n\nLine\nbreak site (https://x.io)",
+		},
+		{
+			name:     "html to markdown",
+			body:     "<b>Build</b> <i>failed</i>: <a href=\"https://ci.io/1\">details</a>",
+			from:     BodyFormatHTML,
+			to:       BodyFormatMarkdown,
+			expected: "**Build** *failed*: [details](https://ci.io/1)",
+		},
+		{
+			name:     "text to html",
+			body:     "a < b && c > d",
+			from:     BodyFormatText,
+			to:       BodyFormatHTML,
+			expected: "a &lt; b &amp;&amp; c &gt; d",
+		},
+		{
+			name:     "text to markdown passes through",
+			body:     "50% *off*",
+			from:     BodyFormatText,
+			to:       BodyFormatMarkdown,
+			expected: "50% *off*",
+		},
+		{
+			name:     "text to markdownv2",
+			body:     "v1.2 (beta)!",
+			from:     BodyFormatText,
+			to:       BodyFormatMarkdownV2,
+			expected: `v1\.2 \(beta\)\!`,
+		},
+		{
+			name:     "markdown to markdownv2",
+			body:     "**Done** in 1.5s, see [log](https://x.io/a_b)",
+			from:     BodyFormatMarkdown,
+			to:       BodyFormatMarkdownV2,
+			expected: `*Done* in 1\.5s, see [log](https://x.io/a_b)`,
+		},
+		{
+			name:     "markdown to legacy markdown",
+			body:     "## Deploy\n**Done** on db_1 in ~~5~~ 4s, see [the log](https://x.io/logs)",
+			from:     BodyFormatMarkdown,
+			to:       BodyFormatMarkdownLegacy,
+			expected: "*Deploy*\n*Done* on db\\_1 in 5 4s, see [the log](https://x.io/logs)",
+		},
+		{
+			name:     "html to legacy markdown",
+			body:     "<b>Disk full</b> on <i>db_1</i>: <code>df -h</code>",
+			from:     BodyFormatHTML,
+			to:       BodyFormatMarkdownLegacy,
+			expected: "*Disk full* on _db1_: `df -h`",
+		},
+		{
+			name:     "text to legacy markdown",
+			body:     "a_b * c [d]",
+			from:     BodyFormatText,
+			to:       BodyFormatMarkdownLegacy,
+			expected: "a\\_b \\* c \\[d]",
+		},
+		{
+			name:     "markdown to slack mrkdwn",
+			body:     "# Alert\n**Disk** is _full_ on ~~db1~~ `db2`, see [runbook](https://example.com/?a=1&b=2) & <https://x.io>\n> a > b",
+			from:     BodyFormatMarkdown,
+			to:       BodyFormatMrkdwn,
+			expected: "*Alert*\n*Disk* is _full_ on ~db1~ `db2`, see <https://example.com/?a=1&b=2|runbook> &amp; &lt;https://x.io&gt;\n> a &gt; b",
+		},
+		{
+			name:     "html to slack mrkdwn",
+			body:     "<h1>Deploy</h1><p><b>Done</b> <a href=\"https://ci.example.com\">logs</a></p><pre>x < y</pre>",
+			from:     BodyFormatHTML,
+			to:       BodyFormatMrkdwn,
+			expected: "*Deploy*\n*Done* <https://ci.example.com|logs>\n\n```\nx &lt; y\n```",
+		},
+		{
+			name:     "text to slack mrkdwn",
+			body:     "a < b & c",
+			from:     BodyFormatText,
+			to:       BodyFormatMrkdwn,
+			expected: "a &lt; b &amp; c",
+		},
+		{
+			name:     "unknown format",
+			body:     "<b>x</b>",
+			from:     "rtf",
+			to:       BodyFormatText,
+			expected: "<b>x</b>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ConvertBody(tt.body, tt.from, tt.to); got != tt.expected {
+				t.Errorf("ConvertBody() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNotifyAllConvertsBodyFormat(t *testing.T) {
+	app := New()
+
+	sms := NewTwilioService()
+	slack := NewSlackService()
+
+	textSvc := newLimitedService(0)
+	app.services = append(app.services, serviceEntry{service: textSvc})
+
+	responses := app.NotifyAll(NotificationRequest{Body: "**hi**", BodyFormat: BodyFormatMarkdown})
+	if !responses[0].Success || textSvc.received[0].Body != "**hi**" {
+		t.Errorf("Services without a native format should get the body unchanged, got %q", textSvc.received[0].Body)
+	}
+
+	if formatForService(NotificationRequest{Body: "**hi**", BodyFormat: BodyFormatMarkdown}, sms).Body != "hi" {
+		t.Error("Markdown should be stripped for SMS services")
+	}
+	if req := formatForService(NotificationRequest{Body: "**hi** [docs](https://x.io)", BodyFormat: BodyFormatMarkdown}, slack); req.Body != "*hi* <https://x.io|docs>" || req.BodyFormat != BodyFormatMrkdwn {
+		t.Errorf("Markdown should be converted to Slack mrkdwn, got %+v", req)
+	}
+	if req := formatForService(NotificationRequest{Body: "**hi**"}, sms); req.Body != "**hi**" {
+		t.Error("Bodies without a declared format should not be converted")
+	}
+}
+
+func TestTelegramNativeFormat(t *testing.T) {
+	service := NewTelegramService().(*TelegramService)
+
+	service.parseMode = "MarkdownV2"
+	req := formatForService(NotificationRequest{Body: "**Done** 1.5s", BodyFormat: BodyFormatMarkdown}, service)
+	if req.BodyFormat != BodyFormatMarkdownV2 {
+		t.Fatalf("Expected MarkdownV2 body format, got %q", req.BodyFormat)
+	}
+
+	message := service.formatMessage("", req.Body, NotifyTypeInfo, req.BodyFormat)
+	if message != "ℹ️ *Done* 1\\.5s" {
+		t.Errorf("Converted MarkdownV2 bodies should not be escaped twice, got %q", message)
+	}
+
+	service.parseMode = "HTML"
+	if service.GetNativeFormat() != BodyFormatHTML {
+		t.Errorf("Expected HTML native format, got %q", service.GetNativeFormat())
+	}
+}
+
+func TestTelegramFormatsHTMLBodies(t *testing.T) {
+	var texts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var message TelegramMessage
+		_ = json.NewDecoder(r.Body).Decode(&message)
+		texts = append(texts, message.ParseMode+": "+message.Text)
+		_, _ = w.Write([]byte(`{"ok":true,"result":{}}`))
+	}))
+	defer server.Close()
+
+	app := New()
+	for _, query := range []string{"", "&format=markdownv2"} {
+		if err := app.Add("tgram://123abc/12345?api_url=" + server.URL + query); err != nil {
+			t.Fatalf("Failed to add service: %v", err)
+		}
+	}
+
+	for _, resp := range app.NotifyAll(NotificationRequest{
+		Title:      "db_1",
+		Body:       "<b>Disk</b> at 95% on <i>db_1</i>.",
+		BodyFormat: BodyFormatHTML,
+		NotifyType: NotifyTypeWarning,
+	}) {
+		if !resp.Success {
+			t.Fatalf("Send failed: %v", resp.Error)
+		}
+	}
+
+	expected := map[string]bool{
+		"Markdown: ⚠️ *db_1*\n*Disk* at 95% on _db1_.":          true,
+		"MarkdownV2: ⚠️ *db\\_1*\n*Disk* at 95% on _db\\_1_\\.": true,
+	}
+	if len(texts) != 2 || !expected[texts[0]] || !expected[texts[1]] || texts[0] == texts[1] {
+		t.Errorf("Unexpected messages %q", texts)
+	}
+}
@@ -297,7 +297,7 @@ func (g *GCPIoTService) sendViaWebhook(ctx context.Context, message GCPIoTMessag
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("gcp iot webhook error (status %d): %s", resp.StatusCode, string(body))
+		return NewHTTPError(resp, fmt.Errorf("gcp iot webhook error (status %d): %s", resp.StatusCode, string(body)))
 	}
 
 	return nil
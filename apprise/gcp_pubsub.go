@@ -3,16 +3,36 @@ package apprise
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"os"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
-// GCPPubSubService implements Google Cloud Pub/Sub notifications via webhook
+// Pub/Sub REST API host and OAuth2 scope
+const (
+	pubsubAPIHost = "pubsub.googleapis.com"
+	pubsubScope   = "https://www.googleapis.com/auth/pubsub"
+)
+
+// Publish batching defaults, as in the Google Cloud client libraries, and the
+// limits of one publish request
+const (
+	pubsubBatchDelay    = 10 * time.Millisecond
+	pubsubBatchSize     = 100
+	pubsubMaxBatchSize  = 1000
+	pubsubMaxBatchBytes = 10 * 1000 * 1000
+)
+
+// GCPPubSubService implements Google Cloud Pub/Sub notifications, either
+// through a webhook proxy or directly with the Pub/Sub REST API
 type GCPPubSubService struct {
 	webhookURL     string
 	projectID      string
@@ -22,6 +42,37 @@ type GCPPubSubService struct {
 	attributes     map[string]string
 	apiKey         string
 	client         *http.Client
+
+	apiURL     string             // Pub/Sub REST API base URL in direct mode
+	tokens     *googleTokenSource // Access token source, nil for an emulator
+	batchDelay time.Duration      // How long a batch collects messages
+	batchSize  int                // Messages that make a batch full
+
+	batchMu   sync.Mutex
+	batch     *pubsubBatch // Batch collecting messages, nil when there is none
+	lastBatch *pubsubBatch // Last batch sent with an ordering key
+	lastSeq   uint64       // Sequence number of lastBatch
+	failedSeq uint64       // Batches up to this sequence number follow a failed one
+	failedErr error        // Error of the last failed batch with an ordering key
+}
+
+// pubsubMessage is a message of a topics.publish request
+type pubsubMessage struct {
+	Data        string            `json:"data"`
+	Attributes  map[string]string `json:"attributes,omitempty"`
+	OrderingKey string            `json:"orderingKey,omitempty"`
+}
+
+// pubsubBatch is a set of messages published together in one request. done
+// is closed once the request finished, with err holding its outcome.
+type pubsubBatch struct {
+	ctx      context.Context // Context of the first message, without its cancellation
+	messages []pubsubMessage
+	size     int
+	prev     *pubsubBatch // Batch that must be published first, for ordering keys
+	seq      uint64       // Position among the batches with an ordering key
+	done     chan struct{}
+	err      error
 }
 
 // NewGCPPubSubService creates a new Google Cloud Pub/Sub service instance
@@ -42,10 +93,15 @@ func (g *GCPPubSubService) GetDefaultPort() int {
 	return 443
 }
 
-// ParseURL parses a Google Cloud Pub/Sub service URL
+// ParseURL parses a Google Cloud Pub/Sub service URL. Using the Pub/Sub API
+// host publishes directly with the REST API; any other host is a webhook
+// proxy.
 // Format: pubsub://webhook.url/pubsub-proxy?project_id=my-project&topic=notifications&service_account=sa@project.iam.gserviceaccount.com
 // Format: pubsub://api-key@webhook.url/gcp?project_id=company-project&topic=alerts&ordering_key=region
 // Format: pubsub://webhook.url/proxy?project_id=my-project&topic=events&attr_environment=prod&attr_service=api
+// Format: pubsub://pubsub.googleapis.com/?topic=alerts&service_account=path/to/sa.json
+// Format: pubsub://pubsub.googleapis.com/?project_id=my-project&topic=alerts&emulator_host=localhost:8085
+// Format: pubsub://pubsub.googleapis.com/?topic=alerts&service_account=sa.json&endpoint_url=https://pubsub.example.internal
 func (g *GCPPubSubService) ParseURL(serviceURL *url.URL) error {
 	if serviceURL.Scheme != "pubsub" {
 		return fmt.Errorf("invalid scheme: expected 'pubsub', got '%s'", serviceURL.Scheme)
@@ -60,6 +116,10 @@ func (g *GCPPubSubService) ParseURL(serviceURL *url.URL) error {
 		return fmt.Errorf("webhook host is required")
 	}
 
+	if strings.EqualFold(serviceURL.Hostname(), pubsubAPIHost) {
+		return g.parseDirectURL(serviceURL.Query())
+	}
+
 	// Build webhook URL - use HTTP for testing if specified
 	scheme := "https" // Default to HTTPS for production
 	if serviceURL.Query().Get("test_mode") == "true" {
@@ -95,13 +155,93 @@ func (g *GCPPubSubService) ParseURL(serviceURL *url.URL) error {
 	}
 
 	// Parse message attributes (prefix: attr_)
-	for key, values := range queryParams {
+	g.parseAttributes(queryParams)
+
+	return nil
+}
+
+// parseAttributes reads the message attributes given as attr_NAME=VALUE
+func (g *GCPPubSubService) parseAttributes(query url.Values) {
+	for key, values := range query {
 		if strings.HasPrefix(key, "attr_") && len(values) > 0 {
 			attrKey := strings.TrimPrefix(key, "attr_")
 			g.attributes[attrKey] = values[0]
 		}
 	}
+}
+
+// parseDirectURL configures publishing with the Pub/Sub REST API. An
+// emulator, named by the emulator_host parameter or PUBSUB_EMULATOR_HOST, is
+// used without authentication. Otherwise service_account is a key file or,
+// when it is an email, an account of the metadata server; without it the
+// GOOGLE_APPLICATION_CREDENTIALS key file or the metadata server's default
+// account is used, and endpoint_url replaces the API base URL.
+func (g *GCPPubSubService) parseDirectURL(query url.Values) error {
+	g.projectID = query.Get("project_id")
+	g.topicName = query.Get("topic")
+	if g.topicName == "" {
+		return fmt.Errorf("topic parameter is required")
+	}
+	g.serviceAccount = query.Get("service_account")
+	g.orderingKey = query.Get("ordering_key")
+	g.parseAttributes(query)
+
+	g.batchDelay = pubsubBatchDelay
+	if value := query.Get("batch_delay"); value != "" {
+		delay, err := time.ParseDuration(value)
+		if err != nil || delay < 0 {
+			return fmt.Errorf("invalid batch_delay value %q: must be a duration such as 10ms", value)
+		}
+		g.batchDelay = delay
+	}
+
+	g.batchSize = pubsubBatchSize
+	if value := query.Get("batch_size"); value != "" {
+		size, err := strconv.Atoi(value)
+		if err != nil || size < 1 || size > pubsubMaxBatchSize {
+			return fmt.Errorf("invalid batch_size value %q: must be between 1 and %d", value, pubsubMaxBatchSize)
+		}
+		g.batchSize = size
+	}
 
+	emulatorHost := query.Get("emulator_host")
+	if emulatorHost == "" {
+		emulatorHost = os.Getenv("PUBSUB_EMULATOR_HOST")
+	}
+	if emulatorHost != "" {
+		g.apiURL = "http://" + emulatorHost
+		if g.projectID == "" {
+			return fmt.Errorf("project_id parameter is required")
+		}
+		return nil
+	}
+	apiURL, err := parseGoogleEndpoint(query, pubsubAPIHost)
+	if err != nil {
+		return err
+	}
+	g.apiURL = apiURL
+
+	if strings.Contains(g.serviceAccount, "@") && !strings.HasSuffix(g.serviceAccount, ".json") {
+		g.tokens = newGoogleMetadataTokenSource(g.serviceAccount, pubsubScope)
+	} else if g.serviceAccount != "" || os.Getenv("GOOGLE_APPLICATION_CREDENTIALS") != "" {
+		account, err := LoadGoogleServiceAccount(g.serviceAccount)
+		if err != nil {
+			return err
+		}
+		g.tokens, err = newGoogleTokenSource(account, pubsubScope)
+		if err != nil {
+			return err
+		}
+		if g.projectID == "" {
+			g.projectID = account.ProjectID
+		}
+	} else {
+		g.tokens = newGoogleMetadataTokenSource("default", pubsubScope)
+	}
+
+	if g.projectID == "" {
+		return fmt.Errorf("project_id parameter is required")
+	}
 	return nil
 }
 
@@ -126,7 +266,8 @@ func (g *GCPPubSubService) GetMaxBodyLength() int {
 	return 10 * 1024 * 1024 // 10MB
 }
 
-// Send sends a notification via Google Cloud Pub/Sub webhook
+// Send sends a notification via Google Cloud Pub/Sub. In direct mode
+// messages sent close together are published in one request.
 func (g *GCPPubSubService) Send(ctx context.Context, req NotificationRequest) error {
 	// Prepare the message
 	messageData := g.formatMessage(req.Title, req.Body, req.NotifyType)
@@ -137,6 +278,14 @@ func (g *GCPPubSubService) Send(ctx context.Context, req NotificationRequest) er
 		messageData = messageData[:maxLength-3] + "..."
 	}
 
+	if g.apiURL != "" {
+		return g.publish(ctx, pubsubMessage{
+			Data:        base64.StdEncoding.EncodeToString([]byte(messageData)),
+			Attributes:  g.buildAttributes(req.NotifyType),
+			OrderingKey: g.orderingKey,
+		})
+	}
+
 	// Create payload for Pub/Sub webhook
 	payload := map[string]interface{}{
 		"projectId":  g.projectID,
@@ -293,6 +442,168 @@ func (g *GCPPubSubService) buildAttributes(notifyType NotifyType) map[string]str
 	return attributes
 }
 
+// publish adds message to a batch and waits for the batch to be published
+func (g *GCPPubSubService) publish(ctx context.Context, message pubsubMessage) error {
+	batch := g.enqueue(ctx, message)
+
+	select {
+	case <-batch.done:
+		return batch.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// enqueue adds message to the collecting batch, starting a new batch when
+// there is none or the message does not fit, and returns the message's batch.
+// Batches are sent once full or after the batch delay.
+func (g *GCPPubSubService) enqueue(ctx context.Context, message pubsubMessage) *pubsubBatch {
+	size := len(message.Data) + len(message.OrderingKey)
+	for key, value := range message.Attributes {
+		size += len(key) + len(value)
+	}
+
+	g.batchMu.Lock()
+	defer g.batchMu.Unlock()
+
+	if g.batch != nil && g.batch.size+size > pubsubMaxBatchBytes {
+		g.flushLocked()
+	}
+
+	if g.batch == nil {
+		batch := &pubsubBatch{ctx: context.WithoutCancel(ctx), done: make(chan struct{})}
+		g.batch = batch
+		if g.batchDelay > 0 {
+			time.AfterFunc(g.batchDelay, func() {
+				g.batchMu.Lock()
+				defer g.batchMu.Unlock()
+				if g.batch == batch {
+					g.flushLocked()
+				}
+			})
+		}
+	}
+
+	batch := g.batch
+	batch.messages = append(batch.messages, message)
+	batch.size += size
+	if len(batch.messages) >= g.batchSize || g.batchDelay == 0 {
+		g.flushLocked()
+	}
+
+	return batch
+}
+
+// flushLocked sends the collecting batch in the background. Batches with an
+// ordering key wait for the one before them so their messages stay in
+// order. As in the Google Cloud client libraries, a failed batch fails the
+// batches already queued behind it without publishing them, so a retry
+// cannot deliver their messages ahead of the failed ones. Batches flushed
+// after the failure publish again. batchMu must be held.
+func (g *GCPPubSubService) flushLocked() {
+	batch := g.batch
+	g.batch = nil
+
+	if g.orderingKey != "" {
+		g.lastSeq++
+		batch.seq = g.lastSeq
+		batch.prev = g.lastBatch
+		g.lastBatch = batch
+	}
+
+	go func() {
+		defer close(batch.done)
+		if batch.prev != nil {
+			<-batch.prev.done
+			batch.prev = nil
+		}
+
+		if err := g.orderingFailure(batch); err != nil {
+			batch.err = err
+			return
+		}
+
+		batch.err = g.publishMessages(batch.ctx, batch.messages)
+		if batch.err != nil && batch.seq > 0 {
+			g.batchMu.Lock()
+			g.failedSeq = g.lastSeq
+			g.failedErr = batch.err
+			g.batchMu.Unlock()
+		}
+	}()
+}
+
+// orderingFailure returns an error when batch was queued behind a batch with
+// the same ordering key that failed
+func (g *GCPPubSubService) orderingFailure(batch *pubsubBatch) error {
+	g.batchMu.Lock()
+	defer g.batchMu.Unlock()
+
+	if batch.seq == 0 || batch.seq > g.failedSeq {
+		return nil
+	}
+	return fmt.Errorf("not published after an earlier message with ordering key %q failed: %w", g.orderingKey, g.failedErr)
+}
+
+// publishMessages publishes messages with one topics.publish request. A
+// rejected access token is replaced and the request made once more.
+func (g *GCPPubSubService) publishMessages(ctx context.Context, messages []pubsubMessage) error {
+	body, err := json.Marshal(map[string]interface{}{"messages": messages})
+	if err != nil {
+		return fmt.Errorf("failed to marshal Pub/Sub messages: %w", err)
+	}
+
+	accessToken, err := g.postPublish(ctx, body)
+	if accessToken != "" && GetStatusCode(err) == http.StatusUnauthorized {
+		g.tokens.invalidate(accessToken)
+		_, err = g.postPublish(ctx, body)
+	}
+	return err
+}
+
+// postPublish makes one topics.publish request and returns the access token
+// it used
+func (g *GCPPubSubService) postPublish(ctx context.Context, body []byte) (string, error) {
+	endpoint := fmt.Sprintf("%s/v1/projects/%s/topics/%s:publish", g.apiURL, url.PathEscape(g.projectID), url.PathEscape(g.topicName))
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", GetUserAgent())
+
+	var accessToken string
+	if g.tokens != nil {
+		accessToken, err = g.tokens.get(ctx, g.client)
+		if err != nil {
+			return "", fmt.Errorf("failed to get Pub/Sub access token: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return accessToken, fmt.Errorf("failed to publish to Pub/Sub: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		var apiErr struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		reason := string(respBody)
+		if json.Unmarshal(respBody, &apiErr) == nil && apiErr.Error.Message != "" {
+			reason = apiErr.Error.Message
+		}
+		return accessToken, NewHTTPError(resp, fmt.Errorf("Pub/Sub API error (status %d): %s", resp.StatusCode, reason))
+	}
+
+	return accessToken, nil
+}
+
 // sendWebhookRequest sends the webhook request to the Pub/Sub gateway
 func (g *GCPPubSubService) sendWebhookRequest(ctx context.Context, payload map[string]interface{}) error {
 	// Convert payload to JSON
@@ -331,7 +642,7 @@ func (g *GCPPubSubService) sendWebhookRequest(ctx context.Context, payload map[s
 	// Check response status
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("Pub/Sub webhook error (status %d): %s", resp.StatusCode, string(body))
+		return NewHTTPError(resp, fmt.Errorf("Pub/Sub webhook error (status %d): %s", resp.StatusCode, string(body)))
 	}
 
 	return nil
@@ -2,11 +2,17 @@ package apprise
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -597,3 +603,281 @@ func TestGCPPubSubService_GetSeverityLevel(t *testing.T) {
 		})
 	}
 }
+
+// answerPubSub returns a handler standing in for the Pub/Sub API, or the
+// emulator, that also serves metadata server tokens. Publish requests are
+// answered with status after waiting delay(n) for the nth request.
+func answerPubSub(status int, delay func(n int) time.Duration) http.HandlerFunc {
+	var published atomic.Int32
+	return func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/computeMetadata/v1/instance/service-accounts/") {
+			if r.Header.Get("Metadata-Flavor") != "Google" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			_, _ = w.Write([]byte(`{"access_token":"metadata-token","expires_in":3599,"token_type":"Bearer"}`))
+			return
+		}
+
+		var payload struct {
+			Messages []pubsubMessage `json:"messages"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+
+		n := int(published.Add(1)) - 1
+		if delay != nil {
+			time.Sleep(delay(n))
+		}
+
+		w.WriteHeader(status)
+		if status == http.StatusNotFound {
+			_, _ = w.Write([]byte(`{"error":{"code":404,"message":"Topic not found","status":"NOT_FOUND"}}`))
+			return
+		}
+		ids := make([]string, len(payload.Messages))
+		for i := range ids {
+			ids[i] = strconv.Itoa(i + 1)
+		}
+		_ = json.NewEncoder(w).Encode(map[string][]string{"messageIds": ids})
+	}
+}
+
+// pubsubMessages returns the messages a topics.publish request carried
+func pubsubMessages(req stubRequest) []pubsubMessage {
+	var payload struct {
+		Messages []pubsubMessage `json:"messages"`
+	}
+	req.decode(&payload)
+	return payload.Messages
+}
+
+// newDirectPubSubService parses a direct mode Pub/Sub URL
+func newDirectPubSubService(t *testing.T, rawURL string) *GCPPubSubService {
+	t.Helper()
+	service := NewGCPPubSubService().(*GCPPubSubService)
+	serviceURL, _ := url.Parse(rawURL)
+	if err := service.ParseURL(serviceURL); err != nil {
+		t.Fatalf("Failed to parse %s: %v", rawURL, err)
+	}
+	return service
+}
+
+func TestGCPPubSubService_ParseDirectURL(t *testing.T) {
+	t.Setenv("PUBSUB_EMULATOR_HOST", "")
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", "")
+	accountPath := writeGoogleServiceAccount(t, "")
+
+	service := newDirectPubSubService(t, "pubsub://pubsub.googleapis.com/?topic=alerts&service_account="+accountPath)
+	if service.apiURL != "https://pubsub.googleapis.com" || service.projectID != "test-project" || service.tokens.account == nil {
+		t.Errorf("Expected the key file's project and credentials, got %s %s", service.apiURL, service.projectID)
+	}
+	if service.batchDelay != pubsubBatchDelay || service.batchSize != pubsubBatchSize {
+		t.Errorf("Expected the default batching, got %v and %d", service.batchDelay, service.batchSize)
+	}
+
+	service = newDirectPubSubService(t, "pubsub://pubsub.googleapis.com/?project_id=p&topic=alerts&service_account=notifier@p.iam.gserviceaccount.com")
+	if service.tokens.metadataAccount != "notifier@p.iam.gserviceaccount.com" {
+		t.Errorf("Expected metadata server tokens of the account, got %+v", service.tokens)
+	}
+
+	service = newDirectPubSubService(t, "pubsub://pubsub.googleapis.com/?project_id=p&topic=alerts&batch_delay=1s&batch_size=500")
+	if service.tokens.metadataAccount != "default" || service.batchDelay != time.Second || service.batchSize != 500 {
+		t.Errorf("Unexpected configuration %+v", service)
+	}
+
+	service = newDirectPubSubService(t, "pubsub://pubsub.googleapis.com/?project_id=p&topic=alerts&emulator_host=localhost:8085")
+	if service.apiURL != "http://localhost:8085" || service.tokens != nil {
+		t.Errorf("Expected the emulator without authentication, got %s", service.apiURL)
+	}
+
+	t.Setenv("PUBSUB_EMULATOR_HOST", "pubsub-emulator:8681")
+	service = newDirectPubSubService(t, "pubsub://pubsub.googleapis.com/?project_id=p&topic=alerts")
+	if service.apiURL != "http://pubsub-emulator:8681" {
+		t.Errorf("Expected PUBSUB_EMULATOR_HOST to be honored, got %s", service.apiURL)
+	}
+
+	invalid := map[string]string{
+		"missing topic":      "pubsub://pubsub.googleapis.com/?project_id=p",
+		"missing project":    "pubsub://pubsub.googleapis.com/?topic=alerts",
+		"invalid batch_size": "pubsub://pubsub.googleapis.com/?project_id=p&topic=alerts&batch_size=5000",
+		"invalid delay":      "pubsub://pubsub.googleapis.com/?project_id=p&topic=alerts&batch_delay=soon",
+	}
+	for name, rawURL := range invalid {
+		serviceURL, _ := url.Parse(rawURL)
+		if err := NewGCPPubSubService().(*GCPPubSubService).ParseURL(serviceURL); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestGCPPubSubService_PublishBatch(t *testing.T) {
+	server := newStubAPI(t, answerPubSub(http.StatusOK, nil))
+	t.Setenv("PUBSUB_EMULATOR_HOST", strings.TrimPrefix(server.URL, "http://"))
+
+	// A full batch is published at once, long before the delay
+	service := newDirectPubSubService(t, "pubsub://pubsub.googleapis.com/?project_id=local&topic=alerts&attr_env=test&batch_size=5&batch_delay=1h")
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 5)
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- service.Send(context.Background(), NotificationRequest{Title: "Job", Body: fmt.Sprintf("run %d", i), NotifyType: NotifyTypeError})
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Send failed: %v", err)
+		}
+	}
+
+	received := server.receivedAt("/v1/")
+	if len(received) != 1 || len(pubsubMessages(received[0])) != 5 {
+		t.Fatalf("Expected one request with 5 messages, got %+v", received)
+	}
+	request := received[0]
+	if request.path != "/v1/projects/local/topics/alerts:publish" || request.authorization != "" {
+		t.Errorf("Unexpected request to %s with authorization %q", request.path, request.authorization)
+	}
+
+	message := pubsubMessages(request)[0]
+	data, err := base64.StdEncoding.DecodeString(message.Data)
+	if err != nil {
+		t.Fatalf("Expected base64 data: %v", err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded["title"] != "Job" {
+		t.Errorf("Unexpected message data %s", data)
+	}
+	if message.Attributes["env"] != "test" || message.Attributes["severity"] != "ERROR" {
+		t.Errorf("Unexpected attributes %v", message.Attributes)
+	}
+}
+
+func TestGCPPubSubService_PublishOrderingKey(t *testing.T) {
+	// The first request is slow; later batches must still arrive after it
+	server := newStubAPI(t, answerPubSub(http.StatusOK, func(n int) time.Duration {
+		if n == 0 {
+			return 50 * time.Millisecond
+		}
+		return 0
+	}))
+
+	service := newDirectPubSubService(t, "pubsub://pubsub.googleapis.com/?project_id=local&topic=alerts&ordering_key=deploys&batch_delay=0&emulator_host="+strings.TrimPrefix(server.URL, "http://"))
+
+	var batches []*pubsubBatch
+	for i := 0; i < 3; i++ {
+		batches = append(batches, service.enqueue(context.Background(), pubsubMessage{Data: strconv.Itoa(i), OrderingKey: service.orderingKey}))
+	}
+	for _, batch := range batches {
+		<-batch.done
+		if batch.err != nil {
+			t.Fatalf("Publish failed: %v", batch.err)
+		}
+	}
+
+	received := server.receivedAt("/v1/")
+	if len(received) != 3 {
+		t.Fatalf("Expected 3 requests, got %d", len(received))
+	}
+	for i, request := range received {
+		if message := pubsubMessages(request)[0]; message.Data != strconv.Itoa(i) || message.OrderingKey != "deploys" {
+			t.Errorf("Expected message %d in position %d, got %+v", i, i, message)
+		}
+	}
+}
+
+func TestGCPPubSubService_PublishOrderingKeyFailure(t *testing.T) {
+	// The first request fails once the batches behind it are queued
+	var mu sync.Mutex
+	var published []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			Messages []pubsubMessage `json:"messages"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if len(published) == 0 {
+			time.Sleep(50 * time.Millisecond)
+			published = append(published, "failed")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		published = append(published, payload.Messages[0].Data)
+		_, _ = w.Write([]byte(`{"messageIds":["1"]}`))
+	}))
+	defer server.Close()
+
+	service := newDirectPubSubService(t, "pubsub://pubsub.googleapis.com/?project_id=local&topic=alerts&ordering_key=deploys&batch_delay=0&emulator_host="+strings.TrimPrefix(server.URL, "http://"))
+
+	var batches []*pubsubBatch
+	for i := 0; i < 3; i++ {
+		batches = append(batches, service.enqueue(context.Background(), pubsubMessage{Data: strconv.Itoa(i), OrderingKey: service.orderingKey}))
+	}
+	for i, batch := range batches {
+		<-batch.done
+		if !IsRetryable(batch.err) {
+			t.Errorf("Expected batch %d to fail with a retryable error, got %v", i, batch.err)
+		}
+	}
+
+	// Once the failures are reported, retries publish again
+	if err := service.publish(context.Background(), pubsubMessage{Data: "0", OrderingKey: service.orderingKey}); err != nil {
+		t.Fatalf("Publish after the failure failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(published) != 2 || published[1] != "0" {
+		t.Errorf("Expected the queued batches not to be published, got %v", published)
+	}
+}
+
+func TestGCPPubSubService_PublishMetadataToken(t *testing.T) {
+	t.Setenv("PUBSUB_EMULATOR_HOST", "")
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", "")
+	server := newStubAPI(t, answerPubSub(http.StatusNotFound, nil))
+	t.Setenv("GCE_METADATA_HOST", strings.TrimPrefix(server.URL, "http://"))
+
+	service := newDirectPubSubService(t, "pubsub://pubsub.googleapis.com/?project_id=prod&topic=missing&batch_delay=0")
+	endpoint, _ := ParseEndpoint(server.URL)
+
+	err := service.Send(withEndpoint(context.Background(), endpoint), NotificationRequest{Body: "test"})
+	if !errors.Is(err, ErrInvalidRecipient) || !strings.Contains(err.Error(), "Topic not found") {
+		t.Errorf("Expected a missing topic error, got %v", err)
+	}
+
+	received := server.receivedAt("/v1/")
+	if len(received) != 1 || received[0].authorization != "Bearer metadata-token" {
+		t.Errorf("Expected the metadata server token, got %+v", received)
+	}
+}
+
+func TestGCPPubSubService_EndpointOverrideKeepsTokenRequests(t *testing.T) {
+	t.Setenv("PUBSUB_EMULATOR_HOST", "")
+	tokens := newGoogleAPIServer(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Unexpected request to the token endpoint: %s", r.URL.Path)
+		w.WriteHeader(http.StatusNotFound)
+	})
+	server := newStubAPI(t, answerPubSub(http.StatusOK, nil))
+	accountPath := writeGoogleServiceAccount(t, tokens.URL+"/token")
+
+	service := newDirectPubSubService(t, "pubsub://pubsub.googleapis.com/?topic=alerts&batch_delay=0&service_account="+accountPath+"&endpoint_url="+server.URL)
+	endpoint, _ := ParseEndpoint(server.URL)
+
+	if err := service.Send(withEndpoint(context.Background(), endpoint), NotificationRequest{Body: "test"}); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if tokens.tokensMinted() != 1 {
+		t.Errorf("Expected the token to come from the token endpoint, got %d tokens", tokens.tokensMinted())
+	}
+	received := server.receivedAt("/v1/")
+	if len(received) != 1 || received[0].path != "/v1/projects/test-project/topics/alerts:publish" || received[0].authorization != "Bearer token-1" {
+		t.Errorf("Expected one publish request with the token, got %+v", received)
+	}
+}
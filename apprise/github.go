@@ -272,7 +272,7 @@ func (g *GitHubService) sendViaWebhook(ctx context.Context, event *GitHubEvent)
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("gitHub webhook error (status %d): %s", resp.StatusCode, string(body))
+		return NewHTTPError(resp, fmt.Errorf("gitHub webhook error (status %d): %s", resp.StatusCode, string(body)))
 	}
 
 	return nil
@@ -316,7 +316,7 @@ func (g *GitHubService) sendDirectly(ctx context.Context, event *GitHubEvent) er
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("gitHub API error (status %d): %s", resp.StatusCode, string(body))
+		return NewHTTPError(resp, fmt.Errorf("gitHub API error (status %d): %s", resp.StatusCode, string(body)))
 	}
 
 	return nil
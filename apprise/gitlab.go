@@ -281,7 +281,7 @@ func (g *GitLabService) sendViaWebhook(ctx context.Context, event *GitLabEvent)
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("gitLab webhook error (status %d): %s", resp.StatusCode, string(body))
+		return NewHTTPError(resp, fmt.Errorf("gitLab webhook error (status %d): %s", resp.StatusCode, string(body)))
 	}
 
 	return nil
@@ -321,7 +321,7 @@ func (g *GitLabService) sendDirectly(ctx context.Context, event *GitLabEvent) er
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("gitLab API error (status %d): %s", resp.StatusCode, string(body))
+		return NewHTTPError(resp, fmt.Errorf("gitLab API error (status %d): %s", resp.StatusCode, string(body)))
 	}
 
 	return nil
@@ -0,0 +1,251 @@
+package apprise
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// googleTokenURI is the OAuth2 token endpoint used when a service-account
+// file does not name one
+const googleTokenURI = "https://oauth2.googleapis.com/token"
+
+// googleMetadataHost is the metadata server of Google Cloud compute
+// environments, overridable with GCE_METADATA_HOST
+const googleMetadataHost = "metadata.google.internal"
+
+// GoogleServiceAccount holds the fields of a Google Cloud service-account key
+// file needed to mint access tokens
+type GoogleServiceAccount struct {
+	Type         string `json:"type"`
+	ProjectID    string `json:"project_id"`
+	PrivateKeyID string `json:"private_key_id"`
+	PrivateKey   string `json:"private_key"`
+	ClientEmail  string `json:"client_email"`
+	TokenURI     string `json:"token_uri"`
+}
+
+// LoadGoogleServiceAccount reads a service-account key file. An empty path
+// means the file named by GOOGLE_APPLICATION_CREDENTIALS.
+func LoadGoogleServiceAccount(path string) (*GoogleServiceAccount, error) {
+	if path == "" {
+		path = os.Getenv("GOOGLE_APPLICATION_CREDENTIALS")
+		if path == "" {
+			return nil, fmt.Errorf("no service account given and GOOGLE_APPLICATION_CREDENTIALS is not set")
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read service account: %w", err)
+	}
+
+	var account GoogleServiceAccount
+	if err := json.Unmarshal(data, &account); err != nil {
+		return nil, fmt.Errorf("invalid service account %s: %w", path, err)
+	}
+	if account.Type != "service_account" {
+		return nil, fmt.Errorf("invalid service account %s: type is %q, expected \"service_account\"", path, account.Type)
+	}
+	if account.ClientEmail == "" || account.PrivateKey == "" {
+		return nil, fmt.Errorf("invalid service account %s: client_email and private_key are required", path)
+	}
+	if account.TokenURI == "" {
+		account.TokenURI = googleTokenURI
+	}
+
+	return &account, nil
+}
+
+// googleTokenSource provides OAuth2 access tokens and reuses each token until
+// shortly before it expires. Tokens come from exchanging a self-signed JWT of
+// a service account at its token endpoint or, without an account, from the
+// metadata server.
+type googleTokenSource struct {
+	account *GoogleServiceAccount
+	key     *rsa.PrivateKey
+	scope   string
+
+	// metadataAccount is the metadata server service account, "default" for
+	// the one attached to the instance
+	metadataAccount string
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// newGoogleTokenSource creates a token source for the account, granting the
+// given OAuth2 scopes
+func newGoogleTokenSource(account *GoogleServiceAccount, scopes ...string) (*googleTokenSource, error) {
+	key, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(account.PrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("invalid service account private key: %w", err)
+	}
+
+	return &googleTokenSource{
+		account: account,
+		key:     key,
+		scope:   strings.Join(scopes, " "),
+	}, nil
+}
+
+// newGoogleMetadataTokenSource creates a token source asking the metadata
+// server for tokens of serviceAccount, an email or "default"
+func newGoogleMetadataTokenSource(serviceAccount string, scopes ...string) *googleTokenSource {
+	if serviceAccount == "" {
+		serviceAccount = "default"
+	}
+	return &googleTokenSource{
+		metadataAccount: serviceAccount,
+		scope:           strings.Join(scopes, " "),
+	}
+}
+
+// get returns a valid access token, fetching a new one when there is none or
+// it expires within a minute. Token requests are never redirected by an
+// api_url override.
+func (s *googleTokenSource) get(ctx context.Context, client *http.Client) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ctx = withoutEndpoint(ctx)
+
+	now := time.Now()
+	if s.token != "" && now.Add(time.Minute).Before(s.expiry) {
+		return s.token, nil
+	}
+
+	var req *http.Request
+	var err error
+	if s.account != nil {
+		req, err = s.exchangeRequest(ctx, now)
+	} else {
+		req, err = s.metadataRequest(ctx)
+	}
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", GetUserAgent())
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to request access token: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		notifyErr := NewHTTPError(resp, fmt.Errorf("token endpoint error (status %d): %s", resp.StatusCode, string(body)))
+		if resp.StatusCode == http.StatusBadRequest {
+			// invalid_grant and friends: the account or its key is not accepted
+			notifyErr.Class = ErrorClassAuth
+			notifyErr.Retryable = false
+		}
+		return "", notifyErr
+	}
+
+	var result struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil || result.AccessToken == "" {
+		return "", fmt.Errorf("invalid token endpoint response: %s", string(body))
+	}
+	if result.ExpiresIn <= 0 {
+		result.ExpiresIn = 3600
+	}
+
+	s.token = result.AccessToken
+	s.expiry = now.Add(time.Duration(result.ExpiresIn) * time.Second)
+	return s.token, nil
+}
+
+// exchangeRequest creates the request exchanging a JWT signed with the
+// service account's key for an access token
+func (s *googleTokenSource) exchangeRequest(ctx context.Context, now time.Time) (*http.Request, error) {
+	assertion := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   s.account.ClientEmail,
+		"scope": s.scope,
+		"aud":   s.account.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	if s.account.PrivateKeyID != "" {
+		assertion.Header["kid"] = s.account.PrivateKeyID
+	}
+
+	signed, err := assertion.SignedString(s.key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign token request: %w", err)
+	}
+
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {signed},
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", s.account.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req, nil
+}
+
+// metadataRequest creates the request asking the metadata server for an
+// access token
+func (s *googleTokenSource) metadataRequest(ctx context.Context) (*http.Request, error) {
+	host := os.Getenv("GCE_METADATA_HOST")
+	if host == "" {
+		host = googleMetadataHost
+	}
+
+	endpoint := fmt.Sprintf("http://%s/computeMetadata/v1/instance/service-accounts/%s/token", host, url.PathEscape(s.metadataAccount))
+	if s.scope != "" {
+		endpoint += "?" + url.Values{"scopes": {strings.ReplaceAll(s.scope, " ", ",")}}.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create metadata token request: %w", err)
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+	return req, nil
+}
+
+// parseGoogleEndpoint returns the base URL of a Google API: the endpoint_url
+// parameter, for private endpoints and local stand-ins, or https://host.
+// Unlike api_url it only applies to the API itself, not to token requests.
+func parseGoogleEndpoint(query url.Values, host string) (string, error) {
+	endpoint := query.Get("endpoint_url")
+	if endpoint == "" {
+		return "https://" + host, nil
+	}
+
+	parsed, err := url.Parse(endpoint)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return "", fmt.Errorf("invalid endpoint_url %q: must be an http or https URL", endpoint)
+	}
+	return strings.TrimSuffix(endpoint, "/"), nil
+}
+
+// invalidate discards token so the next call fetches a new one. Tokens
+// already replaced by another request are left alone.
+func (s *googleTokenSource) invalidate(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token == token {
+		s.token = ""
+	}
+}
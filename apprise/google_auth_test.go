@@ -0,0 +1,160 @@
+package apprise
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// writeGoogleServiceAccount writes a service-account key file whose token
+// endpoint is tokenURI and returns its path
+func writeGoogleServiceAccount(t *testing.T, tokenURI string) string {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	account, _ := json.Marshal(map[string]string{
+		"type":           "service_account",
+		"project_id":     "test-project",
+		"private_key_id": "key-1",
+		"private_key":    string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"client_email":   "notifier@test-project.iam.gserviceaccount.com",
+		"token_uri":      tokenURI,
+	})
+	path := filepath.Join(t.TempDir(), "service-account.json")
+	if err := os.WriteFile(path, account, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// googleAPIServer is a fake Google API. POST /token exchanges service-account
+// assertions for access tokens "token-1", "token-2" and so on; every other
+// request goes to the handler.
+type googleAPIServer struct {
+	*stubAPI
+	mu     sync.Mutex
+	minted int
+	scope  string
+}
+
+// newGoogleAPIServer starts a fake Google API serving handler
+func newGoogleAPIServer(t *testing.T, handler http.HandlerFunc) *googleAPIServer {
+	t.Helper()
+	api := &googleAPIServer{}
+	api.stubAPI = newStubAPI(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/token" {
+			handler(w, r)
+			return
+		}
+
+		_ = r.ParseForm()
+		claims := jwt.MapClaims{}
+		// Only the claims are checked; verifying the signature is left to Google
+		_, _, err := jwt.NewParser().ParseUnverified(r.PostForm.Get("assertion"), claims)
+		if err != nil || r.PostForm.Get("grant_type") != "urn:ietf:params:oauth:grant-type:jwt-bearer" ||
+			claims["iss"] != "notifier@test-project.iam.gserviceaccount.com" || claims["aud"] != api.URL+"/token" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+			return
+		}
+
+		api.mu.Lock()
+		api.minted++
+		api.scope, _ = claims["scope"].(string)
+		token := fmt.Sprintf("token-%d", api.minted)
+		api.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"access_token":%q,"expires_in":3599,"token_type":"Bearer"}`, token)
+	})
+	return api
+}
+
+// tokensMinted returns how many access tokens were handed out
+func (api *googleAPIServer) tokensMinted() int {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	return api.minted
+}
+
+func TestLoadGoogleServiceAccount(t *testing.T) {
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", "")
+	if _, err := LoadGoogleServiceAccount(""); err == nil {
+		t.Error("Expected an error without a service account")
+	}
+
+	path := writeGoogleServiceAccount(t, "")
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", path)
+	account, err := LoadGoogleServiceAccount("")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if account.ProjectID != "test-project" || account.TokenURI != googleTokenURI {
+		t.Errorf("Unexpected account %+v", account)
+	}
+
+	invalid := filepath.Join(t.TempDir(), "user.json")
+	_ = os.WriteFile(invalid, []byte(`{"type":"authorized_user","client_id":"id"}`), 0600)
+	if _, err := LoadGoogleServiceAccount(invalid); err == nil {
+		t.Error("Expected an error for a non service-account file")
+	}
+}
+
+func TestGoogleTokenSource(t *testing.T) {
+	api := newGoogleAPIServer(t, http.NotFound)
+	account, err := LoadGoogleServiceAccount(writeGoogleServiceAccount(t, api.URL+"/token"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	source, err := newGoogleTokenSource(account, "scope-a", "scope-b")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	token, err := source.get(ctx, http.DefaultClient)
+	if err != nil || token != "token-1" {
+		t.Fatalf("Expected token-1, got %q (%v)", token, err)
+	}
+	api.mu.Lock()
+	scope := api.scope
+	api.mu.Unlock()
+	if scope != "scope-a scope-b" {
+		t.Errorf("Expected both scopes to be requested, got %q", scope)
+	}
+
+	if token, _ := source.get(ctx, http.DefaultClient); token != "token-1" || api.tokensMinted() != 1 {
+		t.Error("Expected the access token to be cached")
+	}
+
+	source.invalidate("token-1")
+	if token, _ := source.get(ctx, http.DefaultClient); token != "token-2" {
+		t.Errorf("Expected a new token after invalidating, got %q", token)
+	}
+
+	// Rejected assertions are authentication failures
+	account.ClientEmail = "someone-else@test-project.iam.gserviceaccount.com"
+	source, _ = newGoogleTokenSource(account, "scope-a")
+	if _, err := source.get(ctx, http.DefaultClient); !errors.Is(err, ErrAuthFailure) {
+		t.Errorf("Expected an authentication failure, got %v", err)
+	}
+}
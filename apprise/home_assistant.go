@@ -28,7 +28,7 @@ type HomeAssistantRequest struct {
 // NewHomeAssistantService creates a new Home Assistant service instance
 func NewHomeAssistantService() Service {
 	return &HomeAssistantService{
-		client:  &http.Client{},
+		client:  GetWebhookHTTPClient("homeassistant"),
 		service: "persistent_notification.create", // Default service
 	}
 }
@@ -136,7 +136,7 @@ func (s *HomeAssistantService) callService(ctx context.Context, req Notification
 	
 	// Check response status
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("Home Assistant API returned status %d", resp.StatusCode)
+		return NewHTTPError(resp, fmt.Errorf("Home Assistant API returned status %d", resp.StatusCode))
 	}
 	
 	return nil
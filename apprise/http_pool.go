@@ -136,9 +136,10 @@ func (p *HTTPClientPool) GetClient(key string, config HTTPClientConfig) *http.Cl
 		ForceAttemptHTTP2: true,
 	}
 
-	// Create new client
+	// Create new client. Requests are routed through endpointTransport so
+	// API base URL overrides apply to every pooled client.
 	client = &http.Client{
-		Transport: transport,
+		Transport: &endpointTransport{next: transport},
 		Timeout:   config.Timeout,
 	}
 
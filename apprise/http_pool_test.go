@@ -210,7 +210,11 @@ func TestHTTPClientTransportConfiguration(t *testing.T) {
 	}
 
 	client := pool.GetClient("transport-test", config)
-	transport, ok := client.Transport.(*http.Transport)
+	wrapped, ok := client.Transport.(*endpointTransport)
+	if !ok {
+		t.Fatal("Expected endpoint transport")
+	}
+	transport, ok := wrapped.next.(*http.Transport)
 	if !ok {
 		t.Fatal("Expected HTTP transport")
 	}
@@ -26,7 +26,7 @@ type IFTTTRequest struct {
 // NewIFTTTService creates a new IFTTT service instance
 func NewIFTTTService() Service {
 	return &IFTTTService{
-		client: &http.Client{},
+		client: GetWebhookHTTPClient("ifttt"),
 	}
 }
 
@@ -113,7 +113,7 @@ func (s *IFTTTService) triggerWebhook(ctx context.Context, req NotificationReque
 	
 	// Check response status
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("IFTTT webhook returned status %d", resp.StatusCode)
+		return NewHTTPError(resp, fmt.Errorf("IFTTT webhook returned status %d", resp.StatusCode))
 	}
 	
 	return nil
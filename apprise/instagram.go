@@ -28,7 +28,7 @@ type InstagramPublishRequest struct {
 // NewInstagramService creates a new Instagram service instance
 func NewInstagramService() Service {
 	return &InstagramService{
-		client: &http.Client{},
+		client: GetCloudHTTPClient("instagram"),
 	}
 }
 
@@ -352,7 +352,7 @@ func (j *JiraService) sendViaWebhook(ctx context.Context, issue *JiraIssue, comm
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("jira webhook error (status %d): %s", resp.StatusCode, string(body))
+		return NewHTTPError(resp, fmt.Errorf("jira webhook error (status %d): %s", resp.StatusCode, string(body)))
 	}
 
 	return nil
@@ -382,7 +382,7 @@ func (j *JiraService) sendIssueDirectly(ctx context.Context, issue *JiraIssue) e
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("jira API error (status %d): %s", resp.StatusCode, string(body))
+		return NewHTTPError(resp, fmt.Errorf("jira API error (status %d): %s", resp.StatusCode, string(body)))
 	}
 
 	return nil
@@ -412,7 +412,7 @@ func (j *JiraService) sendCommentDirectly(ctx context.Context, issueKey string,
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("jira API error (status %d): %s", resp.StatusCode, string(body))
+		return NewHTTPError(resp, fmt.Errorf("jira API error (status %d): %s", resp.StatusCode, string(body)))
 	}
 
 	return nil
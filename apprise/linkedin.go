@@ -343,7 +343,7 @@ func (l *LinkedInService) sendViaWebhook(ctx context.Context, message LinkedInMe
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("linkedin webhook error (status %d): %s", resp.StatusCode, string(body))
+		return NewHTTPError(resp, fmt.Errorf("linkedin webhook error (status %d): %s", resp.StatusCode, string(body)))
 	}
 
 	return nil
@@ -377,7 +377,7 @@ func (l *LinkedInService) sendToLinkedInDirectly(ctx context.Context, message Li
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("linkedin api error (status %d): %s", resp.StatusCode, string(body))
+		return NewHTTPError(resp, fmt.Errorf("linkedin api error (status %d): %s", resp.StatusCode, string(body)))
 	}
 
 	return nil
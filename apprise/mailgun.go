@@ -22,7 +22,7 @@ type MailgunService struct {
 // NewMailgunService creates a new Mailgun service instance
 func NewMailgunService() Service {
 	return &MailgunService{
-		client: &http.Client{},
+		client: GetCloudHTTPClient("mailgun"),
 		region: "us", // Default to US region
 	}
 }
@@ -165,7 +165,7 @@ func (s *MailgunService) sendEmail(ctx context.Context, to, subject, body string
 	
 	// Check response status
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("Mailgun API returned status %d", resp.StatusCode)
+		return NewHTTPError(resp, fmt.Errorf("Mailgun API returned status %d", resp.StatusCode))
 	}
 	
 	return nil
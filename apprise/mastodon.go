@@ -35,7 +35,7 @@ type MastodonStatusResponse struct {
 // NewMastodonService creates a new Mastodon service instance
 func NewMastodonService() Service {
 	return &MastodonService{
-		client:     &http.Client{},
+		client:     GetWebhookHTTPClient("mastodon"),
 		visibility: "public", // Default visibility
 	}
 }
@@ -159,10 +159,10 @@ func (s *MastodonService) postStatus(ctx context.Context, content string) error
 		var errorBody map[string]interface{}
 		if json.NewDecoder(resp.Body).Decode(&errorBody) == nil {
 			if errorMsg, ok := errorBody["error"].(string); ok {
-				return fmt.Errorf("Mastodon API error: %s (status %d)", errorMsg, resp.StatusCode)
+				return NewHTTPError(resp, fmt.Errorf("Mastodon API error: %s (status %d)", errorMsg, resp.StatusCode))
 			}
 		}
-		return fmt.Errorf("Mastodon API returned status %d", resp.StatusCode)
+		return NewHTTPError(resp, fmt.Errorf("Mastodon API returned status %d", resp.StatusCode))
 	}
 	
 	return nil
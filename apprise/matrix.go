@@ -9,9 +9,14 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 )
 
+// matrixDefaultMaxUploadSize is used when the homeserver does not advertise
+// its m.upload.size limit (Synapse's default)
+const matrixDefaultMaxUploadSize = 50 * 1024 * 1024
+
 // MatrixService implements Matrix messaging notifications
 type MatrixService struct {
 	homeserver  string
@@ -21,13 +26,15 @@ type MatrixService struct {
 	rooms       []string
 	msgType     string // "m.text" or "m.notice"
 	htmlFormat  bool
+	maxUpload   int64      // cached m.upload.size, 0 until queried
+	maxUploadMu sync.Mutex // guards maxUpload
 	client      *http.Client
 }
 
 // NewMatrixService creates a new Matrix service instance
 func NewMatrixService() Service {
 	return &MatrixService{
-		client:     &http.Client{},
+		client:     GetWebhookHTTPClient("matrix"),
 		msgType:    "m.text", // Default to text message
 		htmlFormat: false,
 	}
@@ -199,6 +206,30 @@ type MatrixSendResponse struct {
 	EventID string `json:"event_id"`
 }
 
+// MatrixFileMessage represents an m.file or m.image message payload
+type MatrixFileMessage struct {
+	MsgType string         `json:"msgtype"`
+	Body    string         `json:"body"`
+	URL     string         `json:"url"`
+	Info    MatrixFileInfo `json:"info"`
+}
+
+// MatrixFileInfo describes uploaded media in a file message
+type MatrixFileInfo struct {
+	MimeType string `json:"mimetype,omitempty"`
+	Size     int64  `json:"size"`
+}
+
+// MatrixUploadResponse represents the media upload response
+type MatrixUploadResponse struct {
+	ContentURI string `json:"content_uri"`
+}
+
+// MatrixMediaConfig represents the media repository configuration
+type MatrixMediaConfig struct {
+	UploadSize int64 `json:"m.upload.size"`
+}
+
 // Send sends a notification to Matrix
 func (m *MatrixService) Send(ctx context.Context, req NotificationRequest) error {
 	// Ensure we have an access token
@@ -208,20 +239,47 @@ func (m *MatrixService) Send(ctx context.Context, req NotificationRequest) error
 		}
 	}
 
+	// Upload attachments once; the resulting mxc:// URIs are shared by all rooms
+	var files []MatrixFileMessage
+	if req.AttachmentMgr != nil && req.AttachmentMgr.Count() > 0 {
+		var err error
+		if files, err = m.uploadAttachments(ctx, req.AttachmentMgr.GetAll()); err != nil {
+			return err
+		}
+	}
+
 	// Send to each room
 	var lastError error
 	successCount := 0
+	delivered := 0
 
 	for _, room := range m.rooms {
 		if err := m.sendToRoom(ctx, room, req); err != nil {
 			lastError = err
+			continue
+		}
+		delivered++
+
+		var fileErr error
+		for _, file := range files {
+			if fileErr = m.sendEvent(ctx, room, file); fileErr != nil {
+				break
+			}
+		}
+
+		if fileErr != nil {
+			lastError = fileErr
 		} else {
 			successCount++
 		}
 	}
 
-	// Return error only if all sends failed
+	// Return error only if all sends failed. Rooms that got the message
+	// without its files must not get it again on a retry.
 	if successCount == 0 && lastError != nil {
+		if delivered > 0 {
+			return newPartialDeliveryError(lastError)
+		}
 		return lastError
 	}
 
@@ -271,7 +329,7 @@ func (m *MatrixService) login(ctx context.Context) error {
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("matrix login failed (status %d): %s", resp.StatusCode, string(body))
+		return NewHTTPError(resp, fmt.Errorf("matrix login failed (status %d): %s", resp.StatusCode, string(body)))
 	}
 
 	var loginResp MatrixLoginResponse
@@ -285,15 +343,18 @@ func (m *MatrixService) login(ctx context.Context) error {
 
 // sendToRoom sends a message to a specific Matrix room
 func (m *MatrixService) sendToRoom(ctx context.Context, room string, req NotificationRequest) error {
+	return m.sendEvent(ctx, room, m.formatMessage(req.Title, req.Body, req.BodyFormat))
+}
+
+// sendEvent sends an m.room.message event with the given content to a room
+func (m *MatrixService) sendEvent(ctx context.Context, room string, content interface{}) error {
 	// Generate transaction ID (simple timestamp-based)
 	txnID := fmt.Sprintf("apprise_%d", time.Now().UnixNano())
 
 	sendURL := fmt.Sprintf("%s/_matrix/client/v3/rooms/%s/send/m.room.message/%s",
 		m.homeserver, url.PathEscape(room), txnID)
 
-	message := m.formatMessage(req.Title, req.Body)
-
-	jsonData, err := json.Marshal(message)
+	jsonData, err := json.Marshal(content)
 	if err != nil {
 		return fmt.Errorf("failed to marshal Matrix message: %w", err)
 	}
@@ -320,7 +381,7 @@ func (m *MatrixService) sendToRoom(ctx context.Context, room string, req Notific
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("matrix API error (status %d): %s", resp.StatusCode, string(body))
+		return NewHTTPError(resp, fmt.Errorf("matrix API error (status %d): %s", resp.StatusCode, string(body)))
 	}
 
 	var result MatrixSendResponse
@@ -331,31 +392,149 @@ func (m *MatrixService) sendToRoom(ctx context.Context, room string, req Notific
 	return nil
 }
 
-// formatMessage formats the title and body into a Matrix message
-func (m *MatrixService) formatMessage(title, body string) MatrixMessage {
+// uploadAttachments uploads attachments to the media repository and returns
+// the m.file/m.image events that reference them
+func (m *MatrixService) uploadAttachments(ctx context.Context, attachments []AttachmentInterface) ([]MatrixFileMessage, error) {
+	maxSize := m.getMaxUploadSize(ctx)
+	files := make([]MatrixFileMessage, 0, len(attachments))
+
+	for _, attachment := range attachments {
+		data, err := readAttachment(attachment, maxSize)
+		if err != nil {
+			return nil, fmt.Errorf("matrix attachment error: %w", err)
+		}
+
+		contentURI, err := m.uploadMedia(ctx, attachment.GetName(), attachment.GetMimeType(), data)
+		if err != nil {
+			return nil, err
+		}
+
+		msgType := "m.file"
+		if isImageAttachment(attachment) {
+			msgType = "m.image"
+		}
+
+		files = append(files, MatrixFileMessage{
+			MsgType: msgType,
+			Body:    attachment.GetName(),
+			URL:     contentURI,
+			Info: MatrixFileInfo{
+				MimeType: attachment.GetMimeType(),
+				Size:     int64(len(data)),
+			},
+		})
+	}
+
+	return files, nil
+}
+
+// uploadMedia uploads content to /_matrix/media/v3/upload and returns its mxc:// URI
+func (m *MatrixService) uploadMedia(ctx context.Context, filename, mimeType string, data []byte) (string, error) {
+	uploadURL := fmt.Sprintf("%s/_matrix/media/v3/upload?filename=%s", m.homeserver, url.QueryEscape(filename))
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", uploadURL, bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("failed to create upload request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", mimeType)
+	httpReq.Header.Set("Authorization", "Bearer "+m.accessToken)
+	httpReq.Header.Set("User-Agent", GetUserAgent())
+
+	resp, err := m.client.Do(httpReq)
+	if err != nil {
+		return "", fmt.Errorf("failed to upload Matrix attachment %s: %w", filename, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read upload response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", NewHTTPError(resp, fmt.Errorf("matrix upload error (status %d): %s", resp.StatusCode, string(body)))
+	}
+
+	var result MatrixUploadResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("failed to parse Matrix upload response: %w", err)
+	}
+
+	if result.ContentURI == "" {
+		return "", fmt.Errorf("matrix upload response missing content_uri")
+	}
+
+	return result.ContentURI, nil
+}
+
+// getMaxUploadSize returns the homeserver's advertised upload limit, falling
+// back to matrixDefaultMaxUploadSize when it cannot be determined
+func (m *MatrixService) getMaxUploadSize(ctx context.Context) int64 {
+	m.maxUploadMu.Lock()
+	defer m.maxUploadMu.Unlock()
+
+	if m.maxUpload > 0 {
+		return m.maxUpload
+	}
+
+	m.maxUpload = matrixDefaultMaxUploadSize
+
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", m.homeserver+"/_matrix/media/v3/config", nil)
+	if err != nil {
+		return m.maxUpload
+	}
+
+	httpReq.Header.Set("Authorization", "Bearer "+m.accessToken)
+	httpReq.Header.Set("User-Agent", GetUserAgent())
+
+	resp, err := m.client.Do(httpReq)
+	if err != nil {
+		return m.maxUpload
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var config MatrixMediaConfig
+	if resp.StatusCode == http.StatusOK && json.NewDecoder(resp.Body).Decode(&config) == nil && config.UploadSize > 0 {
+		m.maxUpload = config.UploadSize
+	}
+
+	return m.maxUpload
+}
+
+// formatMessage formats the title and body into a Matrix message. bodyFormat
+// is the format NotifyAll converted the body to, if any.
+func (m *MatrixService) formatMessage(title, body, bodyFormat string) MatrixMessage {
 	message := MatrixMessage{
 		MsgType: m.msgType,
 	}
 
+	// HTML bodies get a plain-text fallback for clients that can't render it
+	plainBody := body
+	if bodyFormat == BodyFormatHTML {
+		plainBody = htmlToText(body)
+	}
+
 	// Format text content
-	if title != "" && body != "" {
-		message.Body = fmt.Sprintf("%s\n%s", title, body)
+	if title != "" && plainBody != "" {
+		message.Body = fmt.Sprintf("%s\n%s", title, plainBody)
 	} else if title != "" {
 		message.Body = title
 	} else {
-		message.Body = body
+		message.Body = plainBody
 	}
 
 	// Add HTML formatting if enabled
-	if m.htmlFormat && title != "" {
-		if body != "" {
-			message.Format = "org.matrix.custom.html"
-			message.FormattedBody = fmt.Sprintf("<h4>%s</h4><p>%s</p>",
-				m.escapeHTML(title), m.escapeHTML(body))
-		} else {
-			message.Format = "org.matrix.custom.html"
+	if m.htmlFormat && (title != "" || bodyFormat == BodyFormatHTML) {
+		message.Format = "org.matrix.custom.html"
+		if title != "" {
 			message.FormattedBody = fmt.Sprintf("<h4>%s</h4>", m.escapeHTML(title))
 		}
+		if bodyFormat == BodyFormatHTML {
+			message.FormattedBody += strings.ReplaceAll(body, "\n", "<br>")
+		} else if body != "" {
+			message.FormattedBody += fmt.Sprintf("<p>%s</p>", m.escapeHTML(body))
+		}
 	}
 
 	return message
@@ -382,6 +561,7 @@ func (m *MatrixService) TestURL(serviceURL string) error {
 }
 
 // SupportsAttachments returns true since Matrix supports file attachments
+// (uploaded to the media repository, limited by the homeserver's m.upload.size)
 func (m *MatrixService) SupportsAttachments() bool {
 	return true
 }
@@ -391,6 +571,14 @@ func (m *MatrixService) GetMaxBodyLength() int {
 	return 32768 // Matrix supports large messages (~32KB)
 }
 
+// GetNativeFormat returns HTML when formatted messages are enabled
+func (m *MatrixService) GetNativeFormat() string {
+	if m.htmlFormat {
+		return BodyFormatHTML
+	}
+	return BodyFormatText
+}
+
 // Example usage and URL formats:
 // matrix://access_token@matrix.org/!room_id:matrix.org
 // matrix://access_token@matrix.org/#room_alias:matrix.org
@@ -0,0 +1,97 @@
+package apprise
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMatrixService_SendWithAttachments(t *testing.T) {
+	var uploads []string
+	var events []map[string]interface{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer access_token" {
+			t.Errorf("Missing authorization header on %s", r.URL.Path)
+		}
+
+		switch {
+		case r.URL.Path == "/_matrix/media/v3/config":
+			_, _ = w.Write([]byte(`{"m.upload.size": 1048576}`))
+		case r.URL.Path == "/_matrix/media/v3/upload":
+			data, _ := io.ReadAll(r.Body)
+			uploads = append(uploads, r.URL.Query().Get("filename")+":"+r.Header.Get("Content-Type")+":"+string(data))
+			_, _ = w.Write([]byte(`{"content_uri":"mxc://example.org/` + r.URL.Query().Get("filename") + `"}`))
+		case strings.Contains(r.URL.Path, "/send/m.room.message/"):
+			var event map[string]interface{}
+			_ = json.NewDecoder(r.Body).Decode(&event)
+			events = append(events, event)
+			_, _ = w.Write([]byte(`{"event_id":"$event"}`))
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	service := NewMatrixService().(*MatrixService)
+	if err := service.TestURL("matrix://access_token@matrix.example.org/!room:example.org"); err != nil {
+		t.Fatalf("Failed to parse URL: %v", err)
+	}
+	service.homeserver = server.URL
+
+	mgr := NewAttachmentManager()
+	_ = mgr.AddData([]byte("png-bytes"), "chart.png", "image/png")
+	_ = mgr.AddData([]byte("csv,data"), "data.csv", "text/csv")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := service.Send(ctx, NotificationRequest{Body: "Daily stats", AttachmentMgr: mgr}); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	if len(uploads) != 2 || uploads[0] != "chart.png:image/png:png-bytes" {
+		t.Errorf("Unexpected uploads: %v", uploads)
+	}
+
+	if len(events) != 3 {
+		t.Fatalf("Expected 3 events (message + 2 files), got %d", len(events))
+	}
+	if events[1]["msgtype"] != "m.image" || events[1]["url"] != "mxc://example.org/chart.png" {
+		t.Errorf("Unexpected image event: %v", events[1])
+	}
+	if events[2]["msgtype"] != "m.file" || events[2]["body"] != "data.csv" {
+		t.Errorf("Unexpected file event: %v", events[2])
+	}
+	if service.maxUpload != 1048576 {
+		t.Errorf("Expected advertised upload limit to be cached, got %d", service.maxUpload)
+	}
+}
+
+func TestMatrixService_AttachmentExceedsServerLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/_matrix/media/v3/config" {
+			_, _ = w.Write([]byte(`{"m.upload.size": 4}`))
+			return
+		}
+		t.Errorf("Unexpected request to %s", r.URL.Path)
+	}))
+	defer server.Close()
+
+	service := NewMatrixService().(*MatrixService)
+	_ = service.TestURL("matrix://access_token@matrix.example.org/!room:example.org")
+	service.homeserver = server.URL
+
+	mgr := NewAttachmentManager()
+	_ = mgr.AddData([]byte("too large"), "big.txt", "text/plain")
+
+	err := service.Send(context.Background(), NotificationRequest{Body: "Report", AttachmentMgr: mgr})
+	if err == nil || !strings.Contains(err.Error(), "exceeds maximum size") {
+		t.Errorf("Expected size limit error, got %v", err)
+	}
+}
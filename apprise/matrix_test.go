@@ -351,7 +351,7 @@ func TestMatrixService_FormatMessage(t *testing.T) {
 				htmlFormat: tt.htmlFormat,
 			}
 
-			message := service.formatMessage(tt.title, tt.body)
+			message := service.formatMessage(tt.title, tt.body, "")
 
 			if message.Body != tt.expectedBody {
 				t.Errorf("Expected body %q, got %q", tt.expectedBody, message.Body)
@@ -30,7 +30,7 @@ type MessageBirdRequest struct {
 // NewMessageBirdService creates a new MessageBird service instance
 func NewMessageBirdService() Service {
 	return &MessageBirdService{
-		client: &http.Client{},
+		client: GetCloudHTTPClient("messagebird"),
 	}
 }
 
@@ -160,12 +160,12 @@ func (s *MessageBirdService) sendSMS(ctx context.Context, payload MessageBirdReq
 			if errors, ok := errorBody["errors"].([]interface{}); ok && len(errors) > 0 {
 				if errorMap, ok := errors[0].(map[string]interface{}); ok {
 					if description, ok := errorMap["description"].(string); ok {
-						return fmt.Errorf("MessageBird API error: %s (status %d)", description, resp.StatusCode)
+						return NewHTTPError(resp, fmt.Errorf("MessageBird API error: %s (status %d)", description, resp.StatusCode))
 					}
 				}
 			}
 		}
-		return fmt.Errorf("MessageBird API returned status %d", resp.StatusCode)
+		return NewHTTPError(resp, fmt.Errorf("MessageBird API returned status %d", resp.StatusCode))
 	}
 
 	return nil
@@ -179,4 +179,9 @@ func (s *MessageBirdService) SupportsAttachments() bool {
 // GetMaxBodyLength returns max body length (0 = unlimited)
 func (s *MessageBirdService) GetMaxBodyLength() int {
 	return 1600 // MessageBird supports up to 1600 characters
+}
+
+// GetNativeFormat returns plain text, as SMS does not render markup
+func (s *MessageBirdService) GetNativeFormat() string {
+	return BodyFormatText
 }
\ No newline at end of file
@@ -17,10 +17,15 @@ type MetricsManager struct {
 	notificationsFailed   *prometheus.CounterVec
 	httpRequestsTotal     *prometheus.CounterVec
 	httpRequestsFailed    *prometheus.CounterVec
+	notificationRetries   *prometheus.CounterVec
+	circuitTransitions    *prometheus.CounterVec
+	configReloads         *prometheus.CounterVec
 
 	// Histograms
 	notificationDuration *prometheus.HistogramVec
 	httpRequestDuration  *prometheus.HistogramVec
+	notificationAttempts *prometheus.HistogramVec
+	throttleWait         *prometheus.HistogramVec
 
 	// Gauges
 	activeConnections     prometheus.Gauge
@@ -28,6 +33,8 @@ type MetricsManager struct {
 	queueSize             prometheus.Gauge
 	memoryUsage           prometheus.Gauge
 	goroutineCount        prometheus.Gauge
+	circuitState          *prometheus.GaugeVec
+	configLastReload      prometheus.Gauge
 
 	// Summary
 	notificationBatchSize prometheus.Summary
@@ -43,6 +50,7 @@ type PrometheusServiceMetrics struct {
 	Service       string
 	TotalSent     uint64
 	TotalFailed   uint64
+	TotalRetries  uint64
 	LastSent      time.Time
 	LastError     error
 	AvgDuration   time.Duration
@@ -96,6 +104,33 @@ func NewMetricsManager(namespace string) *MetricsManager {
 		[]string{"method", "endpoint", "error_type"},
 	)
 
+	mm.notificationRetries = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "notification_retries_total",
+			Help:      "Total number of notification retries by service and error class",
+		},
+		[]string{"service", "error_class"},
+	)
+
+	mm.circuitTransitions = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "circuit_transitions_total",
+			Help:      "Total number of circuit breaker state changes by service and new state",
+		},
+		[]string{"service", "state"},
+	)
+
+	mm.configReloads = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "config_reloads_total",
+			Help:      "Total number of configuration reloads by status",
+		},
+		[]string{"status"},
+	)
+
 	// Initialize histograms
 	mm.notificationDuration = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
@@ -117,6 +152,26 @@ func NewMetricsManager(namespace string) *MetricsManager {
 		[]string{"method", "endpoint"},
 	)
 
+	mm.notificationAttempts = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "notification_attempts",
+			Help:      "Number of send attempts per notification by service",
+			Buckets:   []float64{1, 2, 3, 4, 5, 7, 10},
+		},
+		[]string{"service"},
+	)
+
+	mm.throttleWait = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "notification_throttle_wait_seconds",
+			Help:      "Time notifications waited for the outbound throttle by service",
+			Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+		},
+		[]string{"service"},
+	)
+
 	// Initialize gauges
 	mm.activeConnections = prometheus.NewGauge(
 		prometheus.GaugeOpts{
@@ -158,6 +213,23 @@ func NewMetricsManager(namespace string) *MetricsManager {
 		},
 	)
 
+	mm.circuitState = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "circuit_state",
+			Help:      "Circuit breaker state by service and circuit (0 closed, 1 half-open, 2 open)",
+		},
+		[]string{"service", "circuit"},
+	)
+
+	mm.configLastReload = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "config_last_reload_success_timestamp_seconds",
+			Help:      "Unix time of the last successful configuration reload",
+		},
+	)
+
 	// Initialize summary
 	mm.notificationBatchSize = prometheus.NewSummary(
 		prometheus.SummaryOpts{
@@ -185,13 +257,20 @@ func (mm *MetricsManager) Register() error {
 		mm.notificationsFailed,
 		mm.httpRequestsTotal,
 		mm.httpRequestsFailed,
+		mm.notificationRetries,
+		mm.circuitTransitions,
+		mm.configReloads,
 		mm.notificationDuration,
 		mm.httpRequestDuration,
+		mm.notificationAttempts,
+		mm.throttleWait,
 		mm.activeConnections,
 		mm.servicesConfigured,
 		mm.queueSize,
 		mm.memoryUsage,
 		mm.goroutineCount,
+		mm.circuitState,
+		mm.configLastReload,
 		mm.notificationBatchSize,
 	}
 
@@ -263,6 +342,57 @@ func (mm *MetricsManager) RecordNotificationError(service, reason, errorType str
 	serviceMetric.mu.Unlock()
 }
 
+// RecordRetry records a retry of a failed notification
+func (mm *MetricsManager) RecordRetry(service, errorClass string) {
+	mm.notificationRetries.WithLabelValues(service, errorClass).Inc()
+
+	mm.mu.Lock()
+	serviceMetric, exists := mm.serviceMetrics[service]
+	if !exists {
+		serviceMetric = &PrometheusServiceMetrics{
+			Service: service,
+		}
+		mm.serviceMetrics[service] = serviceMetric
+	}
+	mm.mu.Unlock()
+
+	serviceMetric.mu.Lock()
+	serviceMetric.TotalRetries++
+	serviceMetric.mu.Unlock()
+}
+
+// RecordNotificationAttempts records how many send attempts a notification took
+func (mm *MetricsManager) RecordNotificationAttempts(service string, attempts int) {
+	mm.notificationAttempts.WithLabelValues(service).Observe(float64(attempts))
+}
+
+// RecordThrottleWait records how long a send waited for the outbound throttle
+func (mm *MetricsManager) RecordThrottleWait(service string, wait time.Duration) {
+	mm.throttleWait.WithLabelValues(service).Observe(wait.Seconds())
+}
+
+// RecordCircuitState records a circuit breaker state change
+func (mm *MetricsManager) RecordCircuitState(service, circuit string, state CircuitState) {
+	value := 0.0
+	switch state {
+	case CircuitHalfOpen:
+		value = 1
+	case CircuitOpen:
+		value = 2
+	}
+	mm.circuitState.WithLabelValues(service, circuit).Set(value)
+	mm.circuitTransitions.WithLabelValues(service, string(state)).Inc()
+}
+
+// RecordConfigReload records a configuration reload with its status,
+// "success" or "failed"
+func (mm *MetricsManager) RecordConfigReload(status string) {
+	mm.configReloads.WithLabelValues(status).Inc()
+	if status == "success" {
+		mm.configLastReload.SetToCurrentTime()
+	}
+}
+
 // RecordHTTPRequest records an HTTP request with timing
 func (mm *MetricsManager) RecordHTTPRequest(method, endpoint string, statusCode int, duration time.Duration) {
 	statusStr := strconv.Itoa(statusCode)
@@ -325,12 +455,13 @@ func (mm *MetricsManager) GetServiceMetrics(service string) *PrometheusServiceMe
 	defer serviceMetric.mu.RUnlock()
 	
 	return &PrometheusServiceMetrics{
-		Service:     serviceMetric.Service,
-		TotalSent:   serviceMetric.TotalSent,
-		TotalFailed: serviceMetric.TotalFailed,
-		LastSent:    serviceMetric.LastSent,
-		LastError:   serviceMetric.LastError,
-		AvgDuration: serviceMetric.AvgDuration,
+		Service:      serviceMetric.Service,
+		TotalSent:    serviceMetric.TotalSent,
+		TotalFailed:  serviceMetric.TotalFailed,
+		TotalRetries: serviceMetric.TotalRetries,
+		LastSent:     serviceMetric.LastSent,
+		LastError:    serviceMetric.LastError,
+		AvgDuration:  serviceMetric.AvgDuration,
 	}
 }
 
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -101,7 +102,7 @@ type RichPushPayload struct {
 // NewRichMobilePushService creates a new rich mobile push service
 func NewRichMobilePushService() *RichMobilePushService {
 	return &RichMobilePushService{
-		client:      &http.Client{Timeout: 30 * time.Second},
+		client:      GetDefaultHTTPClient(),
 		customData:  make(map[string]interface{}),
 		userData:    make(map[string]string),
 		localizedTitle: make(map[string]string),
@@ -122,6 +123,7 @@ func (rmp *RichMobilePushService) GetDefaultPort() int {
 func (rmp *RichMobilePushService) ParseURL(serviceURL *url.URL) error {
 	// URL format: rich-mobile-push://platform@tokens?params
 	// Example: rich-mobile-push://ios@token1,token2?sound=default&badge=5&priority=high
+	// Example: rich-mobile-push://android@token1?service_account=sa.json (Android via FCM HTTP v1)
 	
 	// Extract platform
 	if serviceURL.User != nil {
@@ -247,6 +249,38 @@ func (rmp *RichMobilePushService) ParseURL(serviceURL *url.URL) error {
 		rmp.campaignID = campaignID
 	}
 	
+	// Android delivery with the FCM HTTP v1 API
+	if rmp.platform != "ios" && (query.Get("service_account") != "" || query.Get("project_id") != "") {
+		if err := rmp.parseFCMOptions(query); err != nil {
+			return fmt.Errorf("Android delivery: %w", err)
+		}
+	}
+	
+	return nil
+}
+
+// parseFCMOptions sets up sending Android pushes to the device tokens with
+// the FCM HTTP v1 API, using the service_account and project_id parameters
+func (rmp *RichMobilePushService) parseFCMOptions(query url.Values) error {
+	fcmQuery := url.Values{}
+	for _, key := range []string{"service_account", "project_id"} {
+		if value := query.Get(key); value != "" {
+			fcmQuery.Set(key, value)
+		}
+	}
+
+	fcmURL := &url.URL{
+		Scheme:   "fcm",
+		Host:     fcmAPIHost,
+		Path:     "/" + strings.Join(rmp.deviceTokens, "/"),
+		RawQuery: fcmQuery.Encode(),
+	}
+
+	fcm := NewFCMService().(*FCMService)
+	if err := fcm.ParseURL(fcmURL); err != nil {
+		return err
+	}
+	rmp.fcmService = fcm
 	return nil
 }
 
@@ -255,23 +289,23 @@ func (rmp *RichMobilePushService) Send(ctx context.Context, req NotificationRequ
 	payload := rmp.createRichPayload(req)
 	
 	// Send to appropriate platforms
-	var errors []error
+	var errs []error
 	
 	if rmp.platform == "ios" || rmp.platform == "both" {
 		if err := rmp.sendToIOS(ctx, payload); err != nil {
-			errors = append(errors, fmt.Errorf("iOS delivery failed: %w", err))
+			errs = append(errs, fmt.Errorf("iOS delivery failed: %w", err))
 		}
 	}
 	
 	if rmp.platform == "android" || rmp.platform == "both" {
 		if err := rmp.sendToAndroid(ctx, payload); err != nil {
-			errors = append(errors, fmt.Errorf("Android delivery failed: %w", err))
+			errs = append(errs, fmt.Errorf("Android delivery failed: %w", err))
 		}
 	}
 	
 	// Return combined errors if any
-	if len(errors) > 0 {
-		return fmt.Errorf("push notification errors: %v", errors)
+	if len(errs) > 0 {
+		return fmt.Errorf("push notification errors: %w", errors.Join(errs...))
 	}
 	
 	return nil
@@ -469,6 +503,10 @@ func (rmp *RichMobilePushService) sendToIOS(ctx context.Context, payload *RichPu
 }
 
 func (rmp *RichMobilePushService) sendToAndroid(ctx context.Context, payload *RichPushPayload) error {
+	if rmp.fcmService != nil {
+		return rmp.fcmService.sendToTargets(ctx, rmp.createFCMMessage(payload))
+	}
+	
 	// Similar webhook approach for Android/FCM
 	
 	for _, token := range rmp.deviceTokens {
@@ -489,6 +527,51 @@ func (rmp *RichMobilePushService) sendToAndroid(ctx context.Context, payload *Ri
 	return nil
 }
 
+// createFCMMessage converts the Android payload to an FCM HTTP v1 message.
+// FCM data values must be strings, so other values are sent as JSON.
+func (rmp *RichMobilePushService) createFCMMessage(payload *RichPushPayload) FCMMessage {
+	priority := "normal"
+	if rmp.priority == "high" {
+		priority = "high"
+	}
+
+	message := FCMMessage{
+		Notification: &FCMNotification{
+			Title: payload.Title,
+			Body:  payload.Body,
+			Image: payload.Image,
+		},
+		Android: &FCMAndroidConfig{
+			CollapseKey:           rmp.collapseKey,
+			Priority:              priority,
+			TTL:                   fmt.Sprintf("%ds", int(rmp.timeToLive.Seconds())),
+			RestrictedPackageName: rmp.restrictedPackage,
+			Notification: &FCMAndroidNotification{
+				Icon:      rmp.icon,
+				Color:     rmp.color,
+				Sound:     rmp.sound,
+				ChannelID: rmp.channelID,
+				Tag:       rmp.groupKey,
+			},
+		},
+	}
+
+	android, _ := payload.AndroidPayload.(map[string]interface{})
+	data, _ := android["data"].(map[string]interface{})
+	if len(data) > 0 {
+		message.Data = make(map[string]string, len(data))
+		for key, value := range data {
+			if text, ok := value.(string); ok {
+				message.Data[key] = text
+			} else if encoded, err := json.Marshal(value); err == nil {
+				message.Data[key] = string(encoded)
+			}
+		}
+	}
+
+	return message
+}
+
 func (rmp *RichMobilePushService) sendWebhookRequest(ctx context.Context, request map[string]interface{}) error {
 	// Serialize request
 	jsonData, err := json.Marshal(request)
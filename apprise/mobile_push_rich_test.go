@@ -2,7 +2,9 @@ package apprise
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"net/url"
 	"testing"
 	"time"
@@ -651,4 +653,40 @@ func TestRichMobilePushService_TestURL(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestRichMobilePushService_SendAndroidFCM(t *testing.T) {
+	api := newGoogleAPIServer(t, answerFCM(func(message FCMMessage) (int, string) {
+		if message.Token == "token2" {
+			return http.StatusNotFound, "UNREGISTERED"
+		}
+		return http.StatusOK, ""
+	}))
+	accountPath := writeGoogleServiceAccount(t, api.URL+"/token")
+
+	service := NewRichMobilePushService()
+	serviceURL, _ := url.Parse("rich-mobile-push://android@token1,token2?priority=high&channel=alerts&collapse=updates&tracking=t-1&service_account=" + url.QueryEscape(accountPath))
+	if err := service.ParseURL(serviceURL); err != nil {
+		t.Fatalf("Failed to parse URL: %v", err)
+	}
+	if service.fcmService == nil {
+		t.Fatal("Expected Android pushes to use FCM")
+	}
+
+	err := service.Send(useFCMAPIServer(t, api), NotificationRequest{Title: "Build", Body: "Passed"})
+	var sendErr *FCMSendError
+	if !errors.As(err, &sendErr) || len(sendErr.InvalidTokens()) != 1 || sendErr.InvalidTokens()[0] != "token2" {
+		t.Fatalf("Expected token2 to be reported invalid, got %v", err)
+	}
+
+	received := api.receivedAt("/v1/")
+	if len(received) != 2 {
+		t.Fatalf("Expected 2 messages, got %d", len(received))
+	}
+	message := fcmMessage(received[0])
+	if message.Token != "token1" || message.Notification.Title != "Build" || message.Android.Priority != "high" ||
+		message.Android.CollapseKey != "updates" || message.Android.Notification.ChannelID != "alerts" ||
+		message.Data["tracking_id"] != "t-1" {
+		t.Errorf("Unexpected message %+v", message)
+	}
+}
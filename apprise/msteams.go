@@ -265,7 +265,7 @@ func (m *MSTeamsService) sendPayload(ctx context.Context, payload MSTeamsPayload
 	// Check response status
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("teams API error (status %d): %s", resp.StatusCode, string(body))
+		return NewHTTPError(resp, fmt.Errorf("teams API error (status %d): %s", resp.StatusCode, string(body)))
 	}
 
 	// Teams typically returns "1" for success
@@ -366,6 +366,11 @@ func (m *MSTeamsService) GetMaxBodyLength() int {
 	return 28000 // Teams has a high character limit
 }
 
+// GetNativeFormat returns markdown, which Teams renders natively
+func (m *MSTeamsService) GetNativeFormat() string {
+	return BodyFormatMarkdown
+}
+
 // createAdaptiveCard creates an Adaptive Card for rich content with attachments
 func (m *MSTeamsService) createAdaptiveCard(req NotificationRequest) MSTeamsAdaptiveCard {
 	card := MSTeamsAdaptiveCard{
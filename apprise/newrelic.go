@@ -359,7 +359,7 @@ func (n *NewRelicService) sendViaWebhook(ctx context.Context, event *NewRelicEve
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("New Relic webhook error (status %d): %s", resp.StatusCode, string(body))
+		return NewHTTPError(resp, fmt.Errorf("New Relic webhook error (status %d): %s", resp.StatusCode, string(body)))
 	}
 
 	return nil
@@ -410,7 +410,7 @@ func (n *NewRelicService) sendEvents(ctx context.Context, events []NewRelicEvent
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("New Relic events API error (status %d): %s", resp.StatusCode, string(body))
+		return NewHTTPError(resp, fmt.Errorf("New Relic events API error (status %d): %s", resp.StatusCode, string(body)))
 	}
 
 	return nil
@@ -441,7 +441,7 @@ func (n *NewRelicService) sendMetrics(ctx context.Context, metrics []NewRelicMet
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("New Relic metrics API error (status %d): %s", resp.StatusCode, string(body))
+		return NewHTTPError(resp, fmt.Errorf("New Relic metrics API error (status %d): %s", resp.StatusCode, string(body)))
 	}
 
 	return nil
@@ -472,7 +472,7 @@ func (n *NewRelicService) sendLogs(ctx context.Context, logs []NewRelicLogEntry)
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("New Relic logs API error (status %d): %s", resp.StatusCode, string(body))
+		return NewHTTPError(resp, fmt.Errorf("New Relic logs API error (status %d): %s", resp.StatusCode, string(body)))
 	}
 
 	return nil
@@ -28,7 +28,7 @@ type NodeREDRequest struct {
 // NewNodeREDService creates a new Node-RED service instance
 func NewNodeREDService() Service {
 	return &NodeREDService{
-		client: &http.Client{},
+		client: GetWebhookHTTPClient("nodered"),
 	}
 }
 
@@ -125,7 +125,7 @@ func (s *NodeREDService) sendWebhook(ctx context.Context, req NotificationReques
 	
 	// Check response status
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("Node-RED webhook returned status %d", resp.StatusCode)
+		return NewHTTPError(resp, fmt.Errorf("Node-RED webhook returned status %d", resp.StatusCode))
 	}
 	
 	return nil
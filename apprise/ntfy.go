@@ -33,7 +33,7 @@ type NtfyService struct {
 // NewNtfyService creates a new Ntfy service instance
 func NewNtfyService() Service {
 	return &NtfyService{
-		client:   &http.Client{},
+		client:   GetWebhookHTTPClient("ntfy"),
 		priority: 3, // Default priority (normal)
 	}
 }
@@ -48,6 +48,12 @@ func (n *NtfyService) GetDefaultPort() int {
 	return 443
 }
 
+// throttleCredential shares a budget between the topics of a server,
+// which limits each visitor across all of them
+func (n *NtfyService) throttleCredential() string {
+	return n.baseURL
+}
+
 // ParseURL parses a Ntfy service URL
 // Format: ntfy://[username:password@]server[:port]/topic
 // Format: ntfys://[username:password@]server[:port]/topic  (HTTPS)
@@ -235,7 +241,7 @@ func (n *NtfyService) Send(ctx context.Context, req NotificationRequest) error {
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("ntfy API error (status %d): %s", resp.StatusCode, string(body))
+		return NewHTTPError(resp, fmt.Errorf("ntfy API error (status %d): %s", resp.StatusCode, string(body)))
 	}
 
 	return nil
@@ -289,6 +295,11 @@ func (n *NtfyService) GetMaxBodyLength() int {
 	return 4096 // Ntfy message limit is 4096 characters
 }
 
+// GetNativeFormat returns plain text, as ntfy does not render markup
+func (n *NtfyService) GetNativeFormat() string {
+	return BodyFormatText
+}
+
 // Example usage and URL formats:
 // ntfy://ntfy.sh/my-topic
 // ntfys://ntfy.example.com/alerts
@@ -30,7 +30,7 @@ type OpsgenieService struct {
 // NewOpsgenieService creates a new Opsgenie service instance
 func NewOpsgenieService() Service {
 	return &OpsgenieService{
-		client: &http.Client{},
+		client: GetCloudHTTPClient("opsgenie"),
 		region: "us", // Default to US region
 	}
 }
@@ -279,7 +279,7 @@ func (o *OpsgenieService) Send(ctx context.Context, req NotificationRequest) err
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("opsgenie API error (status %d): %s", resp.StatusCode, string(body))
+		return NewHTTPError(resp, fmt.Errorf("opsgenie API error (status %d): %s", resp.StatusCode, string(body)))
 	}
 
 	return nil
@@ -0,0 +1,314 @@
+package apprise
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode"
+)
+
+// OverflowMode controls what happens to a body longer than a service's
+// GetMaxBodyLength
+type OverflowMode string
+
+const (
+	// OverflowUpstream sends the body as-is and lets the remote end decide
+	OverflowUpstream OverflowMode = "upstream"
+	// OverflowTruncate cuts the body down to the service limit
+	OverflowTruncate OverflowMode = "truncate"
+	// OverflowSplit sends the body as numbered chunks, in order
+	OverflowSplit OverflowMode = "split"
+)
+
+// chunkCounterReserve is the room kept for a "[nn/nn] " prefix when a split
+// body has no title to carry the counter
+const chunkCounterReserve = 10
+
+// ParseOverflowMode parses an overflow mode name
+func ParseOverflowMode(value string) (OverflowMode, error) {
+	switch mode := OverflowMode(strings.ToLower(strings.TrimSpace(value))); mode {
+	case OverflowUpstream, OverflowTruncate, OverflowSplit:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid overflow mode %q: must be upstream, truncate or split", value)
+	}
+}
+
+// SetOverflowMode sets the default overflow mode for all services. Services
+// added with an overflow URL parameter keep their own mode.
+func (a *Apprise) SetOverflowMode(mode OverflowMode) {
+	a.overflowMode = mode
+}
+
+// GetOverflowMode returns the default overflow mode
+func (a *Apprise) GetOverflowMode() OverflowMode {
+	return a.overflowMode
+}
+
+// parseOverflowOverride extracts the overflow parameter from the URL query
+// and removes it so services never see it. An empty mode means the Apprise
+// default applies.
+func parseOverflowOverride(query url.Values) (OverflowMode, error) {
+	value := query.Get("overflow")
+	query.Del("overflow")
+
+	if value == "" {
+		return "", nil
+	}
+	return ParseOverflowMode(value)
+}
+
+// applyOverflow returns the requests to send for req given the service's
+// body limit. Upstream mode, unlimited services and bodies that fit yield
+// req unchanged.
+func applyOverflow(req NotificationRequest, maxLength int, mode OverflowMode) []NotificationRequest {
+	if maxLength <= 0 || len([]rune(req.Body)) <= maxLength {
+		return []NotificationRequest{req}
+	}
+
+	switch mode {
+	case OverflowTruncate:
+		req.Body = splitBody(req.Body, maxLength, req.BodyFormat)[0]
+		return []NotificationRequest{req}
+
+	case OverflowSplit:
+		limit := maxLength
+		if req.Title == "" && limit > 2*chunkCounterReserve {
+			limit -= chunkCounterReserve
+		}
+
+		// A counter in the body is escaped for the body's format, and kept
+		// on a line of its own in front of HTML markup
+		separator := " "
+		if strings.EqualFold(req.BodyFormat, BodyFormatHTML) {
+			separator = "\n"
+		}
+
+		chunks := splitBody(req.Body, limit, req.BodyFormat)
+		requests := make([]NotificationRequest, len(chunks))
+		for i, chunk := range chunks {
+			part := req
+			counter := fmt.Sprintf("[%d/%d]", i+1, len(chunks))
+			if req.Title != "" {
+				part.Title = req.Title + " " + counter
+				part.Body = chunk
+			} else {
+				part.Body = ConvertBody(counter, BodyFormatText, req.BodyFormat) + separator + chunk
+			}
+
+			// Attachments travel with the first chunk only
+			if i > 0 {
+				part.Attachments = nil
+				part.AttachmentMgr = nil
+			}
+			requests[i] = part
+		}
+		return requests
+
+	default:
+		return []NotificationRequest{req}
+	}
+}
+
+// splitBody splits body into chunks of at most limit characters. Cuts
+// prefer paragraph, then line, sentence and word boundaries, never land
+// inside an HTML tag or entity, and keep markdown links, inline code and
+// fenced code blocks intact where possible.
+func splitBody(body string, limit int, format string) []string {
+	if limit <= 0 {
+		return []string{body}
+	}
+
+	// Slack's mrkdwn has markdown code and HTML-like links and entities
+	mrkdwn := strings.EqualFold(format, BodyFormatMrkdwn)
+	markdown := strings.EqualFold(format, BodyFormatMarkdown) || strings.EqualFold(format, BodyFormatMarkdownV2) ||
+		strings.EqualFold(format, BodyFormatMarkdownLegacy) || mrkdwn
+	html := strings.EqualFold(format, BodyFormatHTML) || mrkdwn
+
+	// Leave room to close and reopen a code fence that spans a cut
+	fence := "```"
+	fenceReserve := 0
+	if markdown && limit > 4*(len(fence)+1) {
+		fenceReserve = len(fence) + 1
+	}
+
+	var chunks []string
+	carry := ""
+	runes := []rune(strings.TrimSpace(body))
+	for len(runes) > 0 {
+		room := limit - len([]rune(carry))
+		if len(runes) > room {
+			room -= fenceReserve
+		}
+		if room < 1 {
+			room = 1
+		}
+
+		cut := len(runes)
+		if cut > room {
+			cut = findSplit(runes, room, markdown, html)
+		}
+
+		chunk := carry + strings.TrimRightFunc(string(runes[:cut]), unicode.IsSpace)
+		runes = []rune(strings.TrimLeftFunc(string(runes[cut:]), unicode.IsSpace))
+
+		carry = ""
+		if markdown && len(runes) > 0 && strings.Count(chunk, fence)%2 == 1 {
+			chunk += "\n" + fence
+			carry = fence + "\n"
+		}
+
+		if chunk != "" {
+			chunks = append(chunks, chunk)
+		}
+	}
+
+	if len(chunks) == 0 {
+		return []string{""}
+	}
+	return chunks
+}
+
+// findSplit returns where to cut runes so the first part is at most limit
+// characters long
+func findSplit(runes []rune, limit int, markdown, html bool) int {
+	// One character of lookahead lets constructs ending at the limit be seen
+	window := runes[:limit]
+	if limit < len(runes) {
+		window = runes[:limit+1]
+	}
+	unsafe := unsafeCuts(window, markdown, html)
+	floor := limit / 2
+
+	// Try each boundary level from coarse to fine, keeping chunks at least
+	// half full so a stray early newline doesn't produce tiny messages
+	for level := 0; level < 4; level++ {
+		for cut := limit; cut > floor; cut-- {
+			if !unsafe[cut] && isBoundary(runes, cut, level, html) {
+				return cut
+			}
+		}
+	}
+
+	// No boundary: cut hard, backing off to the start of any open construct
+	for cut := limit; cut > 0; cut-- {
+		if !unsafe[cut] {
+			return cut
+		}
+	}
+	return limit
+}
+
+// isBoundary reports whether cutting before runes[cut] lands on a boundary
+// of the given level: 0 paragraph, 1 line, 2 sentence, 3 word
+func isBoundary(runes []rune, cut, level int, html bool) bool {
+	prev := runes[cut-1]
+	var next rune
+	if cut < len(runes) {
+		next = runes[cut]
+	}
+
+	switch level {
+	case 0:
+		if prev == '\n' && cut >= 2 && runes[cut-2] == '\n' {
+			return true
+		}
+		return html && endsWithBlockTag(runes[:cut])
+	case 1:
+		return prev == '\n' || (html && endsWithLineBreakTag(runes[:cut]))
+	case 2:
+		return unicode.IsSpace(next) && (prev == '.' || prev == '!' || prev == '?')
+	default:
+		return unicode.IsSpace(prev) || unicode.IsSpace(next)
+	}
+}
+
+// htmlBlockEnds are closing tags after which an HTML body splits cleanly
+var htmlBlockEnds = []string{"</p>", "</div>", "</li>", "</ul>", "</ol>", "</pre>", "</blockquote>", "</table>", "</h1>", "</h2>", "</h3>", "</h4>", "</h5>", "</h6>"}
+
+// htmlLineBreaks are tags that end a line in an HTML body
+var htmlLineBreaks = []string{"<br>", "<br/>", "<br />", "</tr>"}
+
+func endsWithBlockTag(runes []rune) bool {
+	return endsWithAny(runes, htmlBlockEnds)
+}
+
+func endsWithLineBreakTag(runes []rune) bool {
+	return endsWithAny(runes, htmlLineBreaks)
+}
+
+func endsWithAny(runes []rune, suffixes []string) bool {
+	start := len(runes) - 16
+	if start < 0 {
+		start = 0
+	}
+	tail := strings.ToLower(string(runes[start:]))
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(tail, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// unsafeCuts marks the cut positions (0..len(runes)) that would split an
+// HTML tag or entity, a markdown link or inline code span, or separate a
+// backslash from the character it escapes
+func unsafeCuts(runes []rune, markdown, html bool) []bool {
+	unsafe := make([]bool, len(runes)+1)
+	if !markdown && !html {
+		return unsafe
+	}
+
+	inTag, inEntity := false, false
+	inCode, inLinkText, inLinkURL := false, false, false
+	for i, r := range runes {
+		if html {
+			switch {
+			case r == '<':
+				inTag = true
+			case r == '>':
+				inTag = false
+			case r == '&' && !inTag:
+				inEntity = true
+			case inEntity && (r == ';' || !(unicode.IsLetter(r) || unicode.IsDigit(r) || r == '#')):
+				inEntity = false
+			}
+		}
+
+		if markdown {
+			switch {
+			case r == '\n':
+				inCode, inLinkText, inLinkURL = false, false, false
+			case r == '`':
+				// Runs of three backticks are fences, handled by splitBody
+				if !(i+2 < len(runes) && runes[i+1] == '`' && runes[i+2] == '`') &&
+					!(i >= 1 && runes[i-1] == '`') {
+					inCode = !inCode
+				}
+			case inCode:
+			case r == '[':
+				inLinkText = true
+			case r == ']' && inLinkText:
+				inLinkText = false
+				inLinkURL = i+1 < len(runes) && runes[i+1] == '('
+			case r == ')' && inLinkURL:
+				inLinkURL = false
+			}
+		}
+
+		unsafe[i+1] = inTag || inEntity || inCode || inLinkText || inLinkURL ||
+			(markdown && r == '\\') ||
+			(markdown && (r == ']' || r == '`') && i+1 < len(runes) && runes[i+1] == nextInPair(r))
+	}
+	return unsafe
+}
+
+// nextInPair returns the character that must not be separated from r in
+// markdown: the "(" of a link target after "]", or another backtick
+func nextInPair(r rune) rune {
+	if r == ']' {
+		return '('
+	}
+	return r
+}
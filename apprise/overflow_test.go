@@ -0,0 +1,248 @@
+package apprise
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// limitedService records every request it receives and reports a fixed
+// body limit
+type limitedService struct {
+	*MockService
+	maxLength int
+	mu        sync.Mutex
+	received  []NotificationRequest
+}
+
+func (l *limitedService) Send(ctx context.Context, req NotificationRequest) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.received = append(l.received, req)
+	return nil
+}
+
+func (l *limitedService) GetMaxBodyLength() int {
+	return l.maxLength
+}
+
+func newLimitedService(maxLength int) *limitedService {
+	return &limitedService{MockService: NewMockService("limited", 0), maxLength: maxLength}
+}
+
+func TestParseOverflowMode(t *testing.T) {
+	for _, value := range []string{"upstream", "truncate", "split", " SPLIT "} {
+		if _, err := ParseOverflowMode(value); err != nil {
+			t.Errorf("ParseOverflowMode(%q) returned error: %v", value, err)
+		}
+	}
+	if _, err := ParseOverflowMode("chop"); err == nil {
+		t.Error("Expected error for unknown overflow mode")
+	}
+
+	query := url.Values{"overflow": {"truncate"}, "other": {"kept"}}
+	mode, err := parseOverflowOverride(query)
+	if err != nil || mode != OverflowTruncate {
+		t.Errorf("Expected truncate, got %q (%v)", mode, err)
+	}
+	if query.Get("overflow") != "" || query.Get("other") != "kept" {
+		t.Errorf("Overflow parameter should be stripped and others kept, got %v", query)
+	}
+}
+
+func TestSplitBodyBoundaries(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		limit    int
+		format   string
+		expected []string
+	}{
+		{
+			name:     "paragraphs",
+			body:     "First paragraph here.\n\nSecond paragraph here.",
+			limit:    30,
+			expected: []string{"First paragraph here.", "Second paragraph here."},
+		},
+		{
+			name:     "sentences",
+			body:     "One sentence here. Another sentence follows.",
+			limit:    30,
+			expected: []string{"One sentence here.", "Another sentence follows."},
+		},
+		{
+			name:     "words",
+			body:     "alpha beta gamma delta epsilon",
+			limit:    12,
+			expected: []string{"alpha beta", "gamma delta", "epsilon"},
+		},
+		{
+			name:     "hard cut",
+			body:     "abcdefghij",
+			limit:    4,
+			expected: []string{"abcd", "efgh", "ij"},
+		},
+		{
+			name:     "html tag kept whole",
+			body:     "<p>Hello world</p><p>Second</p>",
+			limit:    20,
+			format:   "html",
+			expected: []string{"<p>Hello world</p>", "<p>Second</p>"},
+		},
+		{
+			name:     "markdown link kept whole",
+			body:     "Please see [docs](https://ex.com) today",
+			limit:    30,
+			format:   "markdown",
+			expected: []string{"Please see", "[docs](https://ex.com) today"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := splitBody(tt.body, tt.limit, tt.format)
+			if strings.Join(got, "|") != strings.Join(tt.expected, "|") {
+				t.Errorf("splitBody() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSplitBodyMarkdownFence(t *testing.T) {
+	body := "Intro\n```\n" + strings.Repeat("line of code\n", 10) + "```\nOutro"
+
+	chunks := splitBody(body, 60, "markdown")
+	if len(chunks) < 2 {
+		t.Fatalf("Expected multiple chunks, got %d", len(chunks))
+	}
+	for i, chunk := range chunks {
+		if len([]rune(chunk)) > 60 {
+			t.Errorf("Chunk %d exceeds limit: %d characters", i, len([]rune(chunk)))
+		}
+		if strings.Count(chunk, "```")%2 != 0 {
+			t.Errorf("Chunk %d leaves a code fence open: %q", i, chunk)
+		}
+	}
+}
+
+func TestSplitBodyMarkdownLegacy(t *testing.T) {
+	tests := []struct {
+		body  string
+		limit int
+		whole string
+	}{
+		// Plain cuts would land between a backslash and the character it
+		// escapes, inside a link and inside a code span
+		{"aaaaaaaaa\\_bbbbbbbb", 10, "\\_"},
+		{"see [docs](https://x.io) now please", 26, "[docs](https://x.io)"},
+		{"run `go vet ./...` now", 15, "`go vet ./...`"},
+	}
+
+	for _, tt := range tests {
+		chunks := splitBody(tt.body, tt.limit, BodyFormatMarkdownLegacy)
+		kept := false
+		for _, chunk := range chunks {
+			kept = kept || strings.Contains(chunk, tt.whole)
+		}
+		if !kept {
+			t.Errorf("Expected %q to stay in one chunk, got %q", tt.whole, chunks)
+		}
+	}
+}
+
+func TestApplyOverflow(t *testing.T) {
+	req := NotificationRequest{Title: "Report", Body: "alpha beta gamma delta", AttachmentMgr: NewAttachmentManager()}
+
+	if parts := applyOverflow(req, 10, OverflowUpstream); len(parts) != 1 || parts[0].Body != req.Body {
+		t.Errorf("Upstream mode should pass the body through, got %+v", parts)
+	}
+	if parts := applyOverflow(req, 0, OverflowSplit); len(parts) != 1 {
+		t.Errorf("Unlimited services should get a single request, got %d", len(parts))
+	}
+
+	parts := applyOverflow(req, 10, OverflowTruncate)
+	if len(parts) != 1 || parts[0].Body != "alpha beta" {
+		t.Errorf("Expected truncated body, got %+v", parts)
+	}
+
+	parts = applyOverflow(req, 12, OverflowSplit)
+	if len(parts) != 2 {
+		t.Fatalf("Expected 2 chunks, got %d", len(parts))
+	}
+	if parts[0].Title != "Report [1/2]" || parts[1].Title != "Report [2/2]" {
+		t.Errorf("Unexpected chunk titles: %q, %q", parts[0].Title, parts[1].Title)
+	}
+	if parts[0].AttachmentMgr == nil || parts[1].AttachmentMgr != nil {
+		t.Error("Attachments should only be sent with the first chunk")
+	}
+
+	untitled := NotificationRequest{Body: strings.Repeat("word ", 20)}
+	for i, part := range applyOverflow(untitled, 40, OverflowSplit) {
+		if !strings.HasPrefix(part.Body, "[") || len([]rune(part.Body)) > 40 {
+			t.Errorf("Chunk %d should carry a counter and fit the limit, got %q", i, part.Body)
+		}
+	}
+}
+
+func TestApplyOverflowEscapesCounter(t *testing.T) {
+	untitled := NotificationRequest{Body: strings.Repeat("word ", 20), BodyFormat: BodyFormatMarkdownV2}
+	parts := applyOverflow(untitled, 40, OverflowSplit)
+	for i, part := range parts {
+		prefix := fmt.Sprintf("\\[%d/%d\\] ", i+1, len(parts))
+		if !strings.HasPrefix(part.Body, prefix) || len([]rune(part.Body)) > 40 {
+			t.Errorf("Chunk %d should start with an escaped counter and fit the limit, got %q", i, part.Body)
+		}
+	}
+
+	untitled = NotificationRequest{Body: strings.Repeat("<p>word</p>", 8), BodyFormat: BodyFormatHTML}
+	if parts := applyOverflow(untitled, 40, OverflowSplit); !strings.HasPrefix(parts[0].Body, "[1/") || !strings.Contains(parts[0].Body, "]\n<p>") {
+		t.Errorf("Expected the counter on its own line before HTML markup, got %q", parts[0].Body)
+	}
+}
+
+func TestNotifyAllOverflow(t *testing.T) {
+	app := New()
+	app.SetOverflowMode(OverflowSplit)
+
+	split := newLimitedService(12)
+	truncated := newLimitedService(12)
+	app.services = append(app.services,
+		serviceEntry{service: split},
+		serviceEntry{service: truncated, overflow: OverflowTruncate},
+	)
+
+	responses := app.Notify("Title", "alpha beta gamma delta", NotifyTypeInfo)
+	for i, resp := range responses {
+		if !resp.Success {
+			t.Errorf("Response %d failed: %v", i, resp.Error)
+		}
+	}
+
+	if len(split.received) != 2 || split.received[0].Body != "alpha beta" || split.received[1].Body != "gamma delta" {
+		t.Errorf("Expected two ordered chunks, got %+v", split.received)
+	}
+	if responses[0].Attempts != 2 {
+		t.Errorf("Expected 2 attempts for 2 chunks, got %d", responses[0].Attempts)
+	}
+	if len(truncated.received) != 1 || truncated.received[0].Body != "alpha beta" {
+		t.Errorf("Expected a single truncated body, got %+v", truncated.received)
+	}
+}
+
+func TestAddWithOverflowParameter(t *testing.T) {
+	app := New()
+
+	if err := app.Add("json://localhost/notify?overflow=split"); err != nil {
+		t.Fatalf("Failed to add service: %v", err)
+	}
+	if app.services[0].overflow != OverflowSplit {
+		t.Errorf("Expected split overflow, got %q", app.services[0].overflow)
+	}
+
+	if err := app.Add("json://localhost/notify?overflow=bogus"); err == nil {
+		t.Error("Expected error for invalid overflow parameter")
+	}
+}
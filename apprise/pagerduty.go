@@ -25,7 +25,7 @@ type PagerDutyService struct {
 // NewPagerDutyService creates a new PagerDuty service instance
 func NewPagerDutyService() Service {
 	return &PagerDutyService{
-		client: &http.Client{},
+		client: GetCloudHTTPClient("pagerduty"),
 		region: "us", // Default to US region
 	}
 }
@@ -195,7 +195,7 @@ func (p *PagerDutyService) Send(ctx context.Context, req NotificationRequest) er
 
 	// Check response status
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("PagerDuty API error (status %d): %s", resp.StatusCode, result.Message)
+		return NewHTTPError(resp, fmt.Errorf("PagerDuty API error (status %d): %s", resp.StatusCode, result.Message))
 	}
 
 	if result.Status != "success" {
@@ -29,7 +29,7 @@ type PlivoSMSRequest struct {
 // NewPlivoService creates a new Plivo service instance
 func NewPlivoService() Service {
 	return &PlivoService{
-		client: &http.Client{},
+		client: GetCloudHTTPClient("plivo"),
 	}
 }
 
@@ -150,7 +150,7 @@ func (s *PlivoService) sendSMS(ctx context.Context, to, message string) error {
 	
 	// Check response status
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("Plivo API returned status %d", resp.StatusCode)
+		return NewHTTPError(resp, fmt.Errorf("Plivo API returned status %d", resp.StatusCode))
 	}
 	
 	return nil
@@ -164,4 +164,9 @@ func (s *PlivoService) SupportsAttachments() bool {
 // GetMaxBodyLength returns max body length (0 = unlimited)
 func (s *PlivoService) GetMaxBodyLength() int {
 	return 1600 // Plivo supports long SMS up to 1600 characters
+}
+
+// GetNativeFormat returns plain text, as SMS does not render markup
+func (s *PlivoService) GetNativeFormat() string {
+	return BodyFormatText
 }
\ No newline at end of file
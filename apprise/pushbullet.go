@@ -23,7 +23,7 @@ type PushbulletService struct {
 // NewPushbulletService creates a new Pushbullet service instance
 func NewPushbulletService() Service {
 	return &PushbulletService{
-		client: &http.Client{},
+		client: GetCloudHTTPClient("pushbullet"),
 	}
 }
 
@@ -314,7 +314,7 @@ func (p *PushbulletService) sendPush(ctx context.Context, apiURL string, req Not
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("pushbullet API error (status %d): %s", resp.StatusCode, string(body))
+		return NewHTTPError(resp, fmt.Errorf("pushbullet API error (status %d): %s", resp.StatusCode, string(body)))
 	}
 
 	return nil
@@ -356,6 +356,11 @@ func (p *PushbulletService) GetMaxBodyLength() int {
 	return 0 // Pushbullet doesn't have a strict limit
 }
 
+// GetNativeFormat returns plain text, as Pushbullet does not render markup
+func (p *PushbulletService) GetNativeFormat() string {
+	return BodyFormatText
+}
+
 // Example usage and URL formats:
 // pball://access_token
 // pushbullet://access_token/device_id
@@ -27,7 +27,7 @@ type PushoverService struct {
 // NewPushoverService creates a new Pushover service instance
 func NewPushoverService() Service {
 	return &PushoverService{
-		client:   &http.Client{},
+		client:   GetCloudHTTPClient("pushover"),
 		priority: 0,          // Normal priority
 		sound:    "pushover", // Default sound
 	}
@@ -274,9 +274,9 @@ func (p *PushoverService) sendToDevice(ctx context.Context, apiURL, device strin
 
 	if result.Status != 1 {
 		if len(result.Errors) > 0 {
-			return fmt.Errorf("pushover API error: %s", strings.Join(result.Errors, ", "))
+			return NewHTTPError(resp, fmt.Errorf("pushover API error: %s", strings.Join(result.Errors, ", ")))
 		}
-		return fmt.Errorf("pushover API error: status %d", result.Status)
+		return NewHTTPError(resp, fmt.Errorf("pushover API error: status %d", result.Status))
 	}
 
 	return nil
@@ -318,6 +318,11 @@ func (p *PushoverService) GetMaxBodyLength() int {
 	return 1024 // Pushover's character limit for messages
 }
 
+// GetNativeFormat returns plain text, as Pushover does not render markup
+func (p *PushoverService) GetNativeFormat() string {
+	return BodyFormatText
+}
+
 // Example usage and URL formats:
 // pushover://token@userkey
 // pover://token@userkey/device1/device2
@@ -50,7 +50,7 @@ type RedditMessageRequest struct {
 // NewRedditService creates a new Reddit service instance
 func NewRedditService() Service {
 	return &RedditService{
-		client:    &http.Client{},
+		client:    GetCloudHTTPClient("reddit"),
 		userAgent: "Apprise-Go/1.0",
 	}
 }
@@ -167,7 +167,7 @@ func (s *RedditService) authenticate(ctx context.Context) error {
 	defer resp.Body.Close()
 	
 	if resp.StatusCode != 200 {
-		return fmt.Errorf("Reddit auth returned status %d", resp.StatusCode)
+		return NewHTTPError(resp, fmt.Errorf("Reddit auth returned status %d", resp.StatusCode))
 	}
 	
 	// Parse response
@@ -217,7 +217,7 @@ func (s *RedditService) postToSubreddit(ctx context.Context, req NotificationReq
 	defer resp.Body.Close()
 	
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("Reddit submit returned status %d", resp.StatusCode)
+		return NewHTTPError(resp, fmt.Errorf("Reddit submit returned status %d", resp.StatusCode))
 	}
 	
 	return nil
@@ -258,7 +258,7 @@ func (s *RedditService) sendDirectMessage(ctx context.Context, req NotificationR
 	defer resp.Body.Close()
 	
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("Reddit message returned status %d", resp.StatusCode)
+		return NewHTTPError(resp, fmt.Errorf("Reddit message returned status %d", resp.StatusCode))
 	}
 	
 	return nil
@@ -0,0 +1,281 @@
+package apprise
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryPolicy controls how NotifyAll retries a failed Send
+type RetryPolicy struct {
+	MaxAttempts int           // total attempts including the first (1 = no retries)
+	BaseDelay   time.Duration // delay before the first retry, doubled on each retry
+	MaxDelay    time.Duration // upper bound for a single backoff delay
+	Jitter      float64       // random spread applied to each delay (0.2 = ±20%)
+	RetryOn     []ErrorClass  // error classes worth retrying
+}
+
+// DefaultRetryPolicy returns the policy used when none is configured. It
+// makes a single attempt; raise MaxAttempts to enable retries.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 1,
+		BaseDelay:   1 * time.Second,
+		MaxDelay:    30 * time.Second,
+		Jitter:      0.2,
+		RetryOn: []ErrorClass{
+			ErrorClassRateLimited,
+			ErrorClassTransientNetwork,
+			ErrorClassRemote5xx,
+			ErrorClassTimeout,
+		},
+	}
+}
+
+// ShouldRetry reports whether err belongs to one of the retryable classes.
+// Errors that say they are not worth retrying, such as a send that failed
+// for some of its targets for good, are never retried.
+func (p RetryPolicy) ShouldRetry(err error) bool {
+	if err == nil || !IsRetryable(err) {
+		return false
+	}
+
+	class := ClassifyError(err)
+	for _, retryable := range p.RetryOn {
+		if class == retryable {
+			return true
+		}
+	}
+	return false
+}
+
+// Backoff returns the delay before the next attempt after the given attempt
+// (1-based) failed with err. A server-provided Retry-After takes precedence
+// over the exponential schedule.
+func (p RetryPolicy) Backoff(attempt int, err error) time.Duration {
+	if retryAfter := GetRetryAfter(err); retryAfter > 0 {
+		return retryAfter
+	}
+
+	if attempt < 1 {
+		attempt = 1
+	}
+
+	delay := float64(p.BaseDelay) * math.Pow(2, float64(attempt-1))
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		delay = float64(p.MaxDelay)
+	}
+
+	if p.Jitter > 0 {
+		delay += delay * p.Jitter * (2*rand.Float64() - 1)
+	}
+
+	if delay < 0 {
+		return 0
+	}
+	return time.Duration(delay)
+}
+
+// SetRetryPolicy sets the default retry policy for all services. Services
+// added with a retry URL parameter keep their own attempt count.
+func (a *Apprise) SetRetryPolicy(policy RetryPolicy) {
+	a.retryPolicy = policy
+}
+
+// GetRetryPolicy returns the default retry policy
+func (a *Apprise) GetRetryPolicy() RetryPolicy {
+	return a.retryPolicy
+}
+
+// retryOverrides holds per-URL retry settings parsed from query parameters
+type retryOverrides struct {
+	maxAttempts int
+	baseDelay   time.Duration
+}
+
+// parseRetryOverrides extracts retry and retry_delay from the URL query and
+// removes them so services never see them:
+//
+//	retry=3          allow up to 3 retries (4 attempts)
+//	retry_delay=2s   base delay before the first retry
+func parseRetryOverrides(query url.Values) (retryOverrides, error) {
+	var overrides retryOverrides
+
+	if value := query.Get("retry"); value != "" {
+		retries, err := strconv.Atoi(value)
+		if err != nil || retries < 0 {
+			return overrides, fmt.Errorf("invalid retry value %q: must be a non-negative integer", value)
+		}
+		overrides.maxAttempts = retries + 1
+	}
+	query.Del("retry")
+
+	if value := query.Get("retry_delay"); value != "" {
+		delay, err := time.ParseDuration(value)
+		if err != nil || delay < 0 {
+			return overrides, fmt.Errorf("invalid retry_delay value %q: must be a duration such as 500ms or 2s", value)
+		}
+		overrides.baseDelay = delay
+	}
+	query.Del("retry_delay")
+
+	return overrides, nil
+}
+
+// apply returns policy with the per-URL overrides applied
+func (o retryOverrides) apply(policy RetryPolicy) RetryPolicy {
+	if o.maxAttempts > 0 {
+		policy.MaxAttempts = o.maxAttempts
+	}
+	if o.baseDelay > 0 {
+		policy.BaseDelay = o.baseDelay
+	}
+	return policy
+}
+
+// sendWithRetry sends req to svc, retrying according to policy. Each attempt
+// first checks the circuit breaker, if any, and waits for the throttle, if
+// any. Retries stop once the circuit opens, and a send that reached some of
+// its targets is never retried since that would notify them twice. It
+// returns the number of attempts made and the error of the last attempt.
+func (a *Apprise) sendWithRetry(ctx context.Context, svc Service, req NotificationRequest, policy RetryPolicy, throttle *tokenBucket, breaker *circuitBreaker, breakerPolicy CircuitBreakerPolicy) (int, error) {
+	attempt := 0
+	for {
+		if breaker != nil {
+			state, err := breaker.allow(time.Now())
+			if err != nil {
+				return attempt, err
+			}
+			if state == CircuitHalfOpen {
+				a.metrics.RecordCircuitState(svc.GetServiceID(), breaker.key, state)
+			}
+		}
+
+		if throttle != nil {
+			waited, err := throttle.wait(ctx, throttleCost(svc))
+			if err != nil {
+				if breaker != nil {
+					breaker.release()
+				}
+				return attempt, err
+			}
+			if waited > 0 {
+				a.metrics.RecordThrottleWait(svc.GetServiceID(), waited)
+			}
+		}
+
+		attempt++
+		err := svc.Send(ctx, req)
+
+		if breaker != nil {
+			state, changed := breaker.record(err, breakerPolicy, time.Now())
+			if changed {
+				a.metrics.RecordCircuitState(svc.GetServiceID(), breaker.key, state)
+			}
+			if state != CircuitClosed {
+				return attempt, err
+			}
+		}
+
+		if err == nil || attempt >= policy.MaxAttempts || IsPartialDelivery(err) || !policy.ShouldRetry(err) {
+			return attempt, err
+		}
+
+		delay := policy.Backoff(attempt, err)
+
+		// Don't start a wait that the notification deadline would cut short
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return attempt, err
+		}
+
+		a.metrics.RecordRetry(svc.GetServiceID(), string(ClassifyError(err)))
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return attempt, err
+		case <-timer.C:
+		}
+	}
+}
+
+// ParseRetryAfter parses a Retry-After header value given either as delay
+// seconds or as an HTTP date. Invalid or past values yield zero.
+func ParseRetryAfter(value string) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		if seconds <= 0 {
+			return 0
+		}
+		return time.Duration(seconds * float64(time.Second))
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		if delay := time.Until(date); delay > 0 {
+			return delay
+		}
+	}
+
+	return 0
+}
+
+// GetRetryAfter returns the server-requested retry delay carried by err, if any
+func GetRetryAfter(err error) time.Duration {
+	var notifyErr *NotificationError
+	if errors.As(err, &notifyErr) {
+		return notifyErr.RetryAfter
+	}
+	return 0
+}
+
+// IsPartialDelivery reports whether err is the failure of a send that
+// reached some of its targets. Sending again would deliver to them twice.
+func IsPartialDelivery(err error) bool {
+	var notifyErr *NotificationError
+	return errors.As(err, &notifyErr) && notifyErr.Partial
+}
+
+// newPartialDeliveryError marks err, the failure of a send that had already
+// reached some of its targets, as a partial delivery that is not retried
+func newPartialDeliveryError(err error) *NotificationError {
+	notifyErr := NewNotificationError(ClassifyError(err), GetStatusCode(err), err)
+	notifyErr.RetryAfter = GetRetryAfter(err)
+	notifyErr.Retryable = false
+	notifyErr.Partial = true
+	return notifyErr
+}
+
+// newPartialFailureError classifies the failure of a send to several
+// targets. err describes the whole send and failures holds the errors of the
+// targets that failed, out of the given number of targets. The class and
+// status come from the first failure; the send is only retried when every
+// target failed and every failure is worth retrying, after the longest delay
+// any of them asked for.
+func newPartialFailureError(err error, failures []error, targets int) *NotificationError {
+	notifyErr := NewNotificationError(ClassifyError(failures[0]), GetStatusCode(failures[0]), err)
+	if len(failures) < targets {
+		notifyErr.Retryable = false
+		notifyErr.Partial = true
+	}
+	for _, failure := range failures {
+		if !IsRetryable(failure) {
+			notifyErr.Retryable = false
+		}
+		if retryAfter := GetRetryAfter(failure); retryAfter > notifyErr.RetryAfter {
+			notifyErr.RetryAfter = retryAfter
+		}
+	}
+	return notifyErr
+}
@@ -0,0 +1,224 @@
+package apprise
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// flakyService fails with a classified error a fixed number of times
+type flakyService struct {
+	*MockService
+	err error
+}
+
+func (f *flakyService) Send(ctx context.Context, req NotificationRequest) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.callCount++
+	if f.failures > 0 {
+		f.failures--
+		return f.err
+	}
+	return nil
+}
+
+func newFlakyService(failures int, err error) *flakyService {
+	mock := NewMockService("flaky", 0)
+	mock.failures = failures
+	return &flakyService{MockService: mock, err: err}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected time.Duration
+	}{
+		{"", 0},
+		{"5", 5 * time.Second},
+		{"0.5", 500 * time.Millisecond},
+		{"-1", 0},
+		{"invalid", 0},
+		{"Mon, 02 Jan 2006 15:04:05 GMT", 0}, // in the past
+	}
+
+	for _, tt := range tests {
+		if got := ParseRetryAfter(tt.value); got != tt.expected {
+			t.Errorf("ParseRetryAfter(%q) = %v, expected %v", tt.value, got, tt.expected)
+		}
+	}
+
+	future := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	if got := ParseRetryAfter(future); got <= 0 || got > time.Minute {
+		t.Errorf("ParseRetryAfter(%q) = %v, expected roughly a minute", future, got)
+	}
+}
+
+func TestRetryPolicyShouldRetry(t *testing.T) {
+	policy := DefaultRetryPolicy()
+
+	if !policy.ShouldRetry(NewNotificationError(ErrorClassRateLimited, 429, nil)) {
+		t.Error("Rate limited errors should be retried by default")
+	}
+	if !policy.ShouldRetry(NewNotificationError(ErrorClassRemote5xx, 503, nil)) {
+		t.Error("5xx errors should be retried by default")
+	}
+	if !policy.ShouldRetry(NewNotificationError(ErrorClassTimeout, 0, nil)) {
+		t.Error("Timeouts should be retried by default")
+	}
+	if policy.ShouldRetry(&NotificationError{Class: ErrorClassRemote5xx, StatusCode: 503, Retryable: false}) {
+		t.Error("Errors marked as not retryable should not be retried")
+	}
+	if policy.ShouldRetry(newPartialFailureError(errors.New("mixed"), []error{NewNotificationError(ErrorClassRemote5xx, 503, nil), NewNotificationError(ErrorClassInvalidRecipient, 400, nil)}, 2)) {
+		t.Error("A failure that is permanent for some targets should not be retried")
+	}
+	if policy.ShouldRetry(fmt.Errorf("plain failure")) {
+		t.Error("Unclassified errors should not be retried")
+	}
+	if policy.ShouldRetry(nil) {
+		t.Error("nil error should not be retried")
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: 300 * time.Millisecond}
+
+	if d := policy.Backoff(1, nil); d != 100*time.Millisecond {
+		t.Errorf("Expected 100ms for first retry, got %v", d)
+	}
+	if d := policy.Backoff(2, nil); d != 200*time.Millisecond {
+		t.Errorf("Expected 200ms for second retry, got %v", d)
+	}
+	if d := policy.Backoff(5, nil); d != 300*time.Millisecond {
+		t.Errorf("Expected delay capped at 300ms, got %v", d)
+	}
+
+	retryAfter := &NotificationError{Class: ErrorClassRateLimited, RetryAfter: 2 * time.Second}
+	if d := policy.Backoff(1, retryAfter); d != 2*time.Second {
+		t.Errorf("Expected Retry-After to win, got %v", d)
+	}
+
+	policy.Jitter = 0.5
+	for i := 0; i < 20; i++ {
+		if d := policy.Backoff(1, nil); d < 50*time.Millisecond || d > 150*time.Millisecond {
+			t.Fatalf("Jittered delay %v outside ±50%% of 100ms", d)
+		}
+	}
+}
+
+func TestParseRetryOverrides(t *testing.T) {
+	query := url.Values{"retry": {"3"}, "retry_delay": {"250ms"}, "other": {"kept"}}
+
+	overrides, err := parseRetryOverrides(query)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if overrides.maxAttempts != 4 || overrides.baseDelay != 250*time.Millisecond {
+		t.Errorf("Unexpected overrides: %+v", overrides)
+	}
+	if query.Get("retry") != "" || query.Get("retry_delay") != "" || query.Get("other") != "kept" {
+		t.Errorf("Retry parameters should be stripped and others kept, got %v", query)
+	}
+
+	policy := overrides.apply(DefaultRetryPolicy())
+	if policy.MaxAttempts != 4 || policy.BaseDelay != 250*time.Millisecond {
+		t.Errorf("Overrides not applied: %+v", policy)
+	}
+
+	for _, bad := range []url.Values{{"retry": {"-1"}}, {"retry": {"x"}}, {"retry_delay": {"soon"}}} {
+		if _, err := parseRetryOverrides(bad); err == nil {
+			t.Errorf("Expected error for %v", bad)
+		}
+	}
+}
+
+func TestNotifyAllRetries(t *testing.T) {
+	app := New()
+	app.SetRetryPolicy(RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		RetryOn:     []ErrorClass{ErrorClassRateLimited},
+	})
+
+	flaky := newFlakyService(2, &NotificationError{Class: ErrorClassRateLimited, StatusCode: 429, Retryable: true, RetryAfter: time.Millisecond})
+	fatal := newFlakyService(5, fmt.Errorf("bad credentials"))
+	app.services = append(app.services, serviceEntry{service: flaky}, serviceEntry{service: fatal})
+
+	responses := app.Notify("Title", "Body", NotifyTypeInfo)
+
+	if !responses[0].Success || responses[0].Attempts != 3 {
+		t.Errorf("Expected success after 3 attempts, got success=%v attempts=%d", responses[0].Success, responses[0].Attempts)
+	}
+	if responses[1].Success || responses[1].Attempts != 1 {
+		t.Errorf("Expected non-retryable failure after 1 attempt, got success=%v attempts=%d", responses[1].Success, responses[1].Attempts)
+	}
+	if retries := app.GetServiceMetrics("flaky").TotalRetries; retries < 2 {
+		t.Errorf("Expected retries to be recorded, got %d", retries)
+	}
+}
+
+func TestNotifyAllPerURLRetryOverride(t *testing.T) {
+	app := New()
+	app.SetRetryPolicy(RetryPolicy{MaxAttempts: 1, RetryOn: []ErrorClass{ErrorClassRemote5xx}})
+
+	flaky := newFlakyService(1, NewNotificationError(ErrorClassRemote5xx, 503, nil))
+	app.services = append(app.services, serviceEntry{
+		service: flaky,
+		retry:   retryOverrides{maxAttempts: 2, baseDelay: time.Millisecond},
+	})
+
+	responses := app.Notify("Title", "Body", NotifyTypeInfo)
+	if !responses[0].Success || responses[0].Attempts != 2 {
+		t.Errorf("Expected per-URL retry to succeed on attempt 2, got success=%v attempts=%d", responses[0].Success, responses[0].Attempts)
+	}
+}
+
+func TestNotifyAllRetryRespectsDeadline(t *testing.T) {
+	app := New()
+	app.SetTimeout(50 * time.Millisecond)
+	app.SetRetryPolicy(RetryPolicy{MaxAttempts: 5, RetryOn: []ErrorClass{ErrorClassRateLimited}})
+
+	flaky := newFlakyService(5, &NotificationError{Class: ErrorClassRateLimited, Retryable: true, RetryAfter: time.Minute})
+	app.services = append(app.services, serviceEntry{service: flaky})
+
+	start := time.Now()
+	responses := app.Notify("Title", "Body", NotifyTypeInfo)
+	if time.Since(start) > time.Second {
+		t.Error("Retry should not wait beyond the notification deadline")
+	}
+	if responses[0].Success || responses[0].Attempts != 1 {
+		t.Errorf("Expected a single failed attempt, got success=%v attempts=%d", responses[0].Success, responses[0].Attempts)
+	}
+}
+
+func TestAddWithRetryParameter(t *testing.T) {
+	app := New()
+
+	if err := app.Add("json://localhost/notify?retry=2&retry_delay=1s"); err != nil {
+		t.Fatalf("Failed to add service: %v", err)
+	}
+	if app.services[0].retry.maxAttempts != 3 {
+		t.Errorf("Expected 3 max attempts, got %d", app.services[0].retry.maxAttempts)
+	}
+
+	if err := app.Add("json://localhost/notify?retry=abc"); err == nil {
+		t.Error("Expected error for invalid retry parameter")
+	}
+}
+
+func TestNotifyAllDoesNotRetryPartialDelivery(t *testing.T) {
+	app := New()
+	app.SetRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, RetryOn: []ErrorClass{ErrorClassRemote5xx}})
+
+	partial := newFlakyService(2, newPartialDeliveryError(&NotificationError{Class: ErrorClassRemote5xx, StatusCode: 503}))
+	app.services = append(app.services, serviceEntry{service: partial})
+
+	responses := app.Notify("Title", "Body", NotifyTypeInfo)
+	if responses[0].Success || responses[0].Attempts != 1 {
+		t.Errorf("Expected a partial delivery not to be retried, got success=%v attempts=%d", responses[0].Success, responses[0].Attempts)
+	}
+}
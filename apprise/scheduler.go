@@ -22,6 +22,7 @@ type NotificationScheduler struct {
 	mu       sync.RWMutex
 	running  bool
 	logger   *log.Logger
+	wake     chan struct{} // signals the queue processor that a job was added
 }
 
 // ScheduledJob represents a scheduled notification job
@@ -74,6 +75,7 @@ type QueuedJob struct {
 	StartedAt    *time.Time        `json:"started_at,omitempty" db:"started_at"`
 	CompletedAt  *time.Time        `json:"completed_at,omitempty" db:"completed_at"`
 	NextRetryAt  *time.Time        `json:"next_retry_at,omitempty" db:"next_retry_at"`
+	RunCount     int               `json:"run_count" db:"run_count"` // runs started, never reset by a requeue
 }
 
 // JobStatus represents the status of a job
@@ -190,9 +192,72 @@ func (s *NotificationScheduler) GetQueuedJobs(limit int) ([]QueuedJob, error) {
 	return s.queue.GetPendingJobs(limit)
 }
 
-// QueueNotification adds a notification to the queue
+// QueueNotification adds a notification to the queue. A running scheduler
+// processes it right away rather than at the next polling interval.
 func (s *NotificationScheduler) QueueNotification(job QueuedJob) (*QueuedJob, error) {
-	return s.queue.Add(job)
+	queued, err := s.queue.Add(job)
+	if err != nil {
+		return nil, err
+	}
+
+	s.wakeQueue()
+	return queued, nil
+}
+
+// wakeQueue tells the queue processor that there are jobs to send, without
+// waiting for its next pass
+func (s *NotificationScheduler) wakeQueue() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// GetQueuedJob returns a single queued job by ID
+func (s *NotificationScheduler) GetQueuedJob(jobID int64) (*QueuedJob, error) {
+	return s.queue.GetJob(jobID)
+}
+
+// UpdateQueuedJob updates the content and delivery settings of a queued job
+func (s *NotificationScheduler) UpdateQueuedJob(job QueuedJob) error {
+	return s.queue.UpdateJob(job)
+}
+
+// RetryQueuedJob resets a queued job to pending so it is sent again
+func (s *NotificationScheduler) RetryQueuedJob(jobID int64) error {
+	if err := s.queue.RequeueJob(jobID); err != nil {
+		return err
+	}
+
+	s.wakeQueue()
+	return nil
+}
+
+// GetDeadLetterJobs returns queued jobs that failed after exhausting their retries
+func (s *NotificationScheduler) GetDeadLetterJobs(limit, offset int) ([]QueuedJob, error) {
+	return s.queue.GetFailedJobs(limit, offset)
+}
+
+// CountDeadLetterJobs returns the number of queued jobs that failed after
+// exhausting their retries
+func (s *NotificationScheduler) CountDeadLetterJobs() (int64, error) {
+	return s.queue.CountFailedJobs()
+}
+
+// RequeueDeadLetterJobs requeues the given failed jobs, or all of them when
+// all is set
+func (s *NotificationScheduler) RequeueDeadLetterJobs(jobIDs []int64, all bool) (int64, error) {
+	requeued, err := s.queue.RequeueFailedJobs(jobIDs, all)
+	if err == nil && requeued > 0 {
+		s.wakeQueue()
+	}
+	return requeued, err
+}
+
+// PurgeDeadLetterJobs deletes the given failed jobs, or all of them when all
+// is set
+func (s *NotificationScheduler) PurgeDeadLetterJobs(jobIDs []int64, all bool) (int64, error) {
+	return s.queue.PurgeFailedJobs(jobIDs, all)
 }
 
 // GetQueueStats returns queue statistics
@@ -277,6 +342,7 @@ func NewNotificationScheduler(dbPath string, apprise *Apprise) (*NotificationSch
 		apprise: apprise,
 		queue:   queue,
 		logger:  log.Default(),
+		wake:    make(chan struct{}, 1),
 	}
 
 	return scheduler, nil
@@ -52,6 +52,7 @@ func initSchedulerSchema(db *sql.DB) error {
 		started_at DATETIME,
 		completed_at DATETIME,
 		next_retry_at DATETIME,
+		run_count INTEGER NOT NULL DEFAULT 0,
 		FOREIGN KEY (scheduled_id) REFERENCES scheduled_jobs(id) ON DELETE SET NULL
 	);`
 
@@ -96,6 +97,7 @@ func initSchedulerSchema(db *sql.DB) error {
 		`CREATE INDEX IF NOT EXISTS idx_metrics_timestamp ON notification_metrics(timestamp);`,
 		`CREATE INDEX IF NOT EXISTS idx_metrics_service ON notification_metrics(service_id);`,
 		`CREATE INDEX IF NOT EXISTS idx_metrics_status ON notification_metrics(status);`,
+		`CREATE INDEX IF NOT EXISTS idx_metrics_job ON notification_metrics(job_id);`,
 	}
 
 	// Execute table creation
@@ -112,6 +114,11 @@ func initSchedulerSchema(db *sql.DB) error {
 		}
 	}
 
+	// Add columns that databases created by earlier versions lack
+	if err := addMissingColumn(db, "notification_queue", "run_count", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+
 	// Execute index creation
 	for _, query := range createIndexes {
 		if _, err := db.Exec(query); err != nil {
@@ -122,6 +129,37 @@ func initSchedulerSchema(db *sql.DB) error {
 	return nil
 }
 
+// addMissingColumn adds a column to an existing table unless it is there
+// already
+func addMissingColumn(db *sql.DB, table, column, definition string) error {
+	rows, err := db.Query("PRAGMA table_info(" + table + ")")
+	if err != nil {
+		return fmt.Errorf("failed to read columns of %s: %w", table, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var cid, notNull, primaryKey int
+		var name, columnType string
+		var defaultValue sql.NullString
+		if err := rows.Scan(&cid, &name, &columnType, &notNull, &defaultValue, &primaryKey); err != nil {
+			return fmt.Errorf("failed to read columns of %s: %w", table, err)
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read columns of %s: %w", table, err)
+	}
+	_ = rows.Close()
+
+	if _, err := db.Exec("ALTER TABLE " + table + " ADD COLUMN " + column + " " + definition); err != nil {
+		return fmt.Errorf("failed to add column %s to %s: %w", column, table, err)
+	}
+	return nil
+}
+
 // getScheduledJob retrieves a single scheduled job by ID
 func (s *NotificationScheduler) getScheduledJob(jobID int64) (*ScheduledJob, error) {
 	query := `SELECT id, name, cron_expression, title, body, notify_type, services, tags, metadata,
@@ -292,6 +330,12 @@ func (s *NotificationScheduler) substituteVariables(template string, variables m
 	return result
 }
 
+// DB returns the scheduler's database so other components, such as the API
+// server's user store, can keep their tables alongside the scheduler's
+func (s *NotificationScheduler) DB() *sql.DB {
+	return s.db
+}
+
 // Close closes the database connection
 func (s *NotificationScheduler) Close() error {
 	if s.db != nil {
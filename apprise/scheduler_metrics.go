@@ -95,6 +95,50 @@ func (mc *MetricsCollector) RecordMetrics(metrics NotificationMetrics) error {
 	return nil
 }
 
+// GetJobMetrics returns the metrics recorded for a queued job, one entry per
+// service and attempt, oldest first
+func (mc *MetricsCollector) GetJobMetrics(jobID int64) ([]NotificationMetrics, error) {
+	query := `SELECT id, job_id, scheduled_job_id, service_id, service_url, notification_type,
+			  status, duration_ms, error_message, metadata, timestamp
+			  FROM notification_metrics
+			  WHERE job_id = ?
+			  ORDER BY timestamp ASC, id ASC`
+
+	rows, err := mc.db.Query(query, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query job metrics: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var results []NotificationMetrics
+	for rows.Next() {
+		var metrics NotificationMetrics
+		var jobIDValue, scheduledJobID sql.NullInt64
+		var errorMessage sql.NullString
+		var metadataJSON string
+
+		err := rows.Scan(&metrics.ID, &jobIDValue, &scheduledJobID, &metrics.ServiceID,
+			&metrics.ServiceURL, &metrics.NotificationType, &metrics.Status, &metrics.DurationMs,
+			&errorMessage, &metadataJSON, &metrics.Timestamp)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan job metrics: %w", err)
+		}
+
+		if jobIDValue.Valid {
+			metrics.JobID = &jobIDValue.Int64
+		}
+		if scheduledJobID.Valid {
+			metrics.ScheduledJobID = &scheduledJobID.Int64
+		}
+		metrics.ErrorMessage = errorMessage.String
+		_ = json.Unmarshal([]byte(metadataJSON), &metrics.Metadata)
+
+		results = append(results, metrics)
+	}
+
+	return results, rows.Err()
+}
+
 // GetMetricsReport generates a comprehensive metrics report
 func (mc *MetricsCollector) GetMetricsReport(startTime, endTime time.Time) (*MetricsReport, error) {
 	report := &MetricsReport{
@@ -362,3 +406,8 @@ func (s *NotificationScheduler) GetMetricsCollector() *MetricsCollector {
 	return NewMetricsCollector(s.db)
 }
 
+// GetJobMetrics returns the per-service results recorded for a queued job
+func (s *NotificationScheduler) GetJobMetrics(jobID int64) ([]NotificationMetrics, error) {
+	return s.GetMetricsCollector().GetJobMetrics(jobID)
+}
+
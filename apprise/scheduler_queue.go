@@ -4,10 +4,27 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 )
 
+// QueueMetadataBodyFormat is the queued job metadata key holding the format
+// of the notification body, such as markdown or html
+const QueueMetadataBodyFormat = "body_format"
+
+var (
+	// ErrQueuedJobNotFound is returned when no queued job has the requested ID
+	ErrQueuedJobNotFound = errors.New("queued job not found")
+	// ErrQueuedJobRunning is returned when modifying a job that is being sent
+	ErrQueuedJobRunning = errors.New("queued job is running")
+	// ErrNoJobsSelected is returned by bulk operations given neither job IDs
+	// nor a request for every job
+	ErrNoJobsSelected = errors.New("no jobs selected")
+)
+
 // Add adds a job to the notification queue
 func (q *NotificationQueue) Add(job QueuedJob) (*QueuedJob, error) {
 	q.mu.Lock()
@@ -62,7 +79,7 @@ func (q *NotificationQueue) GetPendingJobs(limit int) ([]QueuedJob, error) {
 
 	query := `SELECT id, scheduled_id, title, body, notify_type, services, tags, metadata,
 			  priority, max_retries, retry_count, retry_delay, status, error_message,
-			  created_at, scheduled_at, started_at, completed_at, next_retry_at
+			  created_at, scheduled_at, started_at, completed_at, next_retry_at, run_count
 			  FROM notification_queue
 			  WHERE status IN ('pending', 'retrying') AND (next_retry_at IS NULL OR next_retry_at <= ?)
 			  ORDER BY priority DESC, created_at ASC
@@ -97,7 +114,7 @@ func (q *NotificationQueue) UpdateJobStatus(jobID int64, status JobStatus, error
 
 	switch status {
 	case JobStatusRunning:
-		query = `UPDATE notification_queue SET status = ?, started_at = ? WHERE id = ?`
+		query = `UPDATE notification_queue SET status = ?, started_at = ?, run_count = run_count + 1 WHERE id = ?`
 		args = []interface{}{string(status), now, jobID}
 	case JobStatusCompleted:
 		query = `UPDATE notification_queue SET status = ?, completed_at = ? WHERE id = ?`
@@ -205,11 +222,194 @@ func (q *NotificationQueue) CleanupCompletedJobs(olderThan time.Duration) (int64
 	return deleted, nil
 }
 
+// GetJob returns a single queued job by ID
+func (q *NotificationQueue) GetJob(jobID int64) (*QueuedJob, error) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	return q.getQueuedJob(jobID)
+}
+
+// UpdateJob updates the content and delivery settings of a queued job.
+// Status and retry bookkeeping are left unchanged; running jobs cannot be
+// updated.
+func (q *NotificationQueue) UpdateJob(job QueuedJob) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	existing, err := q.getQueuedJob(job.ID)
+	if err != nil {
+		return err
+	}
+	if existing.Status == string(JobStatusRunning) {
+		return fmt.Errorf("job %d: %w", job.ID, ErrQueuedJobRunning)
+	}
+
+	servicesJSON, _ := json.Marshal(job.Services)
+	tagsJSON, _ := json.Marshal(job.Tags)
+	metadataJSON, _ := json.Marshal(job.Metadata)
+
+	query := `UPDATE notification_queue SET title = ?, body = ?, notify_type = ?, services = ?, tags = ?,
+			  metadata = ?, priority = ?, max_retries = ?, retry_delay = ? WHERE id = ?`
+
+	_, err = q.db.Exec(query, job.Title, job.Body, int(job.NotifyType), string(servicesJSON),
+		string(tagsJSON), string(metadataJSON), job.Priority, job.MaxRetries, int64(job.RetryDelay), job.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update queued job: %w", err)
+	}
+
+	return nil
+}
+
+// RequeueJob resets a job to pending so it is picked up on the next queue
+// pass with a fresh retry budget. Running jobs cannot be requeued.
+func (q *NotificationQueue) RequeueJob(jobID int64) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	existing, err := q.getQueuedJob(jobID)
+	if err != nil {
+		return err
+	}
+	if existing.Status == string(JobStatusRunning) {
+		return fmt.Errorf("job %d: %w", jobID, ErrQueuedJobRunning)
+	}
+
+	query := `UPDATE notification_queue SET status = ?, retry_count = 0, scheduled_at = ?,
+			  started_at = NULL, completed_at = NULL, next_retry_at = NULL WHERE id = ?`
+	if _, err := q.db.Exec(query, string(JobStatusPending), time.Now(), jobID); err != nil {
+		return fmt.Errorf("failed to requeue job: %w", err)
+	}
+
+	q.logger.Printf("Requeued job %d", jobID)
+	return nil
+}
+
+// GetFailedJobs returns jobs that exhausted their retries, most recent
+// failures first
+func (q *NotificationQueue) GetFailedJobs(limit, offset int) ([]QueuedJob, error) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	query := `SELECT id, scheduled_id, title, body, notify_type, services, tags, metadata,
+			  priority, max_retries, retry_count, retry_delay, status, error_message,
+			  created_at, scheduled_at, started_at, completed_at, next_retry_at, run_count
+			  FROM notification_queue
+			  WHERE status = ?
+			  ORDER BY completed_at DESC, id DESC
+			  LIMIT ? OFFSET ?`
+
+	rows, err := q.db.Query(query, string(JobStatusFailed), limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query failed jobs: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	jobs := []QueuedJob{}
+	for rows.Next() {
+		job, err := q.scanQueuedJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+
+	return jobs, nil
+}
+
+// CountFailedJobs returns the number of jobs that exhausted their retries
+func (q *NotificationQueue) CountFailedJobs() (int64, error) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	var count int64
+	query := `SELECT COUNT(*) FROM notification_queue WHERE status = ?`
+	if err := q.db.QueryRow(query, string(JobStatusFailed)).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count failed jobs: %w", err)
+	}
+	return count, nil
+}
+
+// RequeueFailedJobs moves the failed jobs with the given IDs, or every
+// failed job when all is set, back to pending with a fresh retry budget. It
+// returns the number of jobs requeued.
+func (q *NotificationQueue) RequeueFailedJobs(jobIDs []int64, all bool) (int64, error) {
+	filter, args, err := failedJobsFilter(jobIDs, all)
+	if err != nil {
+		return 0, err
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	query := `UPDATE notification_queue SET status = ?, retry_count = 0, scheduled_at = ?,
+			  started_at = NULL, completed_at = NULL, next_retry_at = NULL WHERE ` + filter
+	args = append([]interface{}{string(JobStatusPending), time.Now()}, args...)
+
+	result, err := q.db.Exec(query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to requeue failed jobs: %w", err)
+	}
+
+	requeued, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	q.logger.Printf("Requeued %d failed jobs", requeued)
+	return requeued, nil
+}
+
+// PurgeFailedJobs deletes the failed jobs with the given IDs, or every
+// failed job when all is set. It returns the number of jobs deleted.
+func (q *NotificationQueue) PurgeFailedJobs(jobIDs []int64, all bool) (int64, error) {
+	filter, args, err := failedJobsFilter(jobIDs, all)
+	if err != nil {
+		return 0, err
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	result, err := q.db.Exec(`DELETE FROM notification_queue WHERE `+filter, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge failed jobs: %w", err)
+	}
+
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	q.logger.Printf("Purged %d failed jobs", purged)
+	return purged, nil
+}
+
+// failedJobsFilter builds a WHERE clause matching every failed job when all
+// is set, or else the failed jobs with the given IDs. Selecting nothing is
+// an error so that a missing list never turns into a bulk operation.
+func failedJobsFilter(jobIDs []int64, all bool) (string, []interface{}, error) {
+	args := []interface{}{string(JobStatusFailed)}
+	if all {
+		return "status = ?", args, nil
+	}
+	if len(jobIDs) == 0 {
+		return "", nil, ErrNoJobsSelected
+	}
+
+	placeholders := make([]string, len(jobIDs))
+	for i, id := range jobIDs {
+		placeholders[i] = "?"
+		args = append(args, id)
+	}
+	return "status = ? AND id IN (" + strings.Join(placeholders, ", ") + ")", args, nil
+}
+
 // getQueuedJob retrieves a single queued job by ID
 func (q *NotificationQueue) getQueuedJob(jobID int64) (*QueuedJob, error) {
 	query := `SELECT id, scheduled_id, title, body, notify_type, services, tags, metadata,
 			  priority, max_retries, retry_count, retry_delay, status, error_message,
-			  created_at, scheduled_at, started_at, completed_at, next_retry_at
+			  created_at, scheduled_at, started_at, completed_at, next_retry_at, run_count
 			  FROM notification_queue WHERE id = ?`
 
 	row := q.db.QueryRow(query, jobID)
@@ -226,7 +426,7 @@ func (q *NotificationQueue) scanQueuedJob(rows *sql.Rows) (QueuedJob, error) {
 	err := rows.Scan(&job.ID, &scheduledID, &job.Title, &job.Body, &job.NotifyType,
 		&servicesJSON, &tagsJSON, &metadataJSON, &job.Priority, &job.MaxRetries,
 		&job.RetryCount, &job.RetryDelay, &job.Status, &job.ErrorMessage,
-		&job.CreatedAt, &job.ScheduledAt, &startedAt, &completedAt, &nextRetryAt)
+		&job.CreatedAt, &job.ScheduledAt, &startedAt, &completedAt, &nextRetryAt, &job.RunCount)
 	if err != nil {
 		return job, fmt.Errorf("failed to scan queued job: %w", err)
 	}
@@ -266,8 +466,11 @@ func (q *NotificationQueue) scanQueuedJobRow(row *sql.Row) (*QueuedJob, error) {
 	err := row.Scan(&job.ID, &scheduledID, &job.Title, &job.Body, &job.NotifyType,
 		&servicesJSON, &tagsJSON, &metadataJSON, &job.Priority, &job.MaxRetries,
 		&job.RetryCount, &job.RetryDelay, &job.Status, &job.ErrorMessage,
-		&job.CreatedAt, &job.ScheduledAt, &startedAt, &completedAt, &nextRetryAt)
+		&job.CreatedAt, &job.ScheduledAt, &startedAt, &completedAt, &nextRetryAt, &job.RunCount)
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrQueuedJobNotFound
+		}
 		return nil, fmt.Errorf("failed to scan queued job: %w", err)
 	}
 
@@ -308,6 +511,8 @@ func (s *NotificationScheduler) processQueue(ctx context.Context) {
 			return
 		case <-ticker.C:
 			s.processQueueBatch()
+		case <-s.wake:
+			s.processQueueBatch()
 		}
 	}
 }
@@ -333,11 +538,12 @@ func (s *NotificationScheduler) processQueueBatch() {
 
 // processQueuedJob processes a single queued job
 func (s *NotificationScheduler) processQueuedJob(job QueuedJob) {
-	// Mark job as running
+	// Mark job as running, which starts a new run of it
 	if err := s.queue.UpdateJobStatus(job.ID, JobStatusRunning, ""); err != nil {
 		s.logger.Printf("Failed to mark job %d as running: %v", job.ID, err)
 		return
 	}
+	job.RunCount++
 
 	s.logger.Printf("Processing job %d: %s", job.ID, job.Title)
 
@@ -347,19 +553,32 @@ func (s *NotificationScheduler) processQueuedJob(job QueuedJob) {
 		Body:       job.Body,
 		NotifyType: job.NotifyType,
 		Tags:       job.Tags,
-	}
-
-	// Create temporary Apprise instance for this job
-	tempApprise := New()
-	for _, serviceURL := range job.Services {
-		if err := tempApprise.Add(serviceURL); err != nil {
-			s.logger.Printf("Failed to add service %s for job %d: %v", serviceURL, job.ID, err)
-			continue
+		BodyFormat: job.Metadata[QueueMetadataBodyFormat],
+	}
+
+	// Jobs without services go to the scheduler's configured services;
+	// services given by URL are always notified, whatever the job's tags
+	targets := s.apprise
+	if len(job.Services) > 0 || targets == nil {
+		targets = New()
+		for _, serviceURL := range job.Services {
+			if err := targets.Add(serviceURL, TagAlways); err != nil {
+				s.logger.Printf("Failed to add service %s for job %d: %v", serviceURL, job.ID, err)
+				continue
+			}
 		}
 	}
 
 	// Send notifications
-	responses := tempApprise.NotifyAll(req)
+	responses := targets.NotifyAll(req)
+	s.recordJobMetrics(job, responses)
+
+	if len(responses) == 0 {
+		if err := s.queue.UpdateJobStatus(job.ID, JobStatusFailed, "No services to notify"); err != nil {
+			s.logger.Printf("Failed to mark job %d as failed: %v", job.ID, err)
+		}
+		return
+	}
 
 	// Check results
 	successful := 0
@@ -411,4 +630,40 @@ func (s *NotificationScheduler) processQueuedJob(job QueuedJob) {
 			}
 		}
 	}
-}
\ No newline at end of file
+}
+
+// recordJobMetrics stores the outcome of a queued job for each service it
+// was sent to, so it can be reported per service once the job has run. Each
+// row carries the run of the job it belongs to, since a retried or requeued
+// job records its services again.
+func (s *NotificationScheduler) recordJobMetrics(job QueuedJob, responses []NotificationResponse) {
+	mc := s.GetMetricsCollector()
+	for _, resp := range responses {
+		jobID := job.ID
+		metrics := NotificationMetrics{
+			JobID:            &jobID,
+			ScheduledJobID:   job.ScheduledID,
+			ServiceID:        resp.ServiceID,
+			ServiceURL:       resp.ServiceURL,
+			NotificationType: int(job.NotifyType),
+			Status:           "success",
+			DurationMs:       resp.Duration.Milliseconds(),
+			Metadata: map[string]string{
+				"attempts": strconv.Itoa(resp.Attempts),
+				"job_run":  strconv.Itoa(job.RunCount),
+			},
+			Timestamp:        time.Now(),
+		}
+		if !resp.Success {
+			metrics.Status = "failed"
+			if resp.Error != nil {
+				metrics.ErrorMessage = resp.Error.Error()
+				metrics.Metadata["error_class"] = string(ClassifyError(resp.Error))
+			}
+		}
+
+		if err := mc.RecordMetrics(metrics); err != nil {
+			s.logger.Printf("Failed to record metrics for job %d: %v", job.ID, err)
+		}
+	}
+}
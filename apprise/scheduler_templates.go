@@ -3,12 +3,39 @@ package apprise
 import (
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"text/template"
 	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+var (
+	// ErrTemplateNotFound is returned when no template has the requested name
+	ErrTemplateNotFound = errors.New("template not found")
+	// ErrTemplateExists is returned when adding a template whose name is taken
+	ErrTemplateExists = errors.New("template already exists")
+	// ErrInvalidTemplate is returned when a template fails to parse or render
+	ErrInvalidTemplate = errors.New("invalid template")
 )
 
+// templateFuncs are the helper functions available to notification templates
+var templateFuncs = template.FuncMap{
+	// default returns def when value is missing or empty:
+	//	{{.severity | default "Medium"}}
+	"default": func(def string, value interface{}) interface{} {
+		if value == nil {
+			return def
+		}
+		if s, ok := value.(string); ok && s == "" {
+			return def
+		}
+		return value
+	},
+}
+
 // NotificationTemplate represents a reusable notification template
 type NotificationTemplate struct {
 	ID          int64             `json:"id" db:"id"`
@@ -50,6 +77,10 @@ func (tm *TemplateManager) AddTemplate(template NotificationTemplate) (*Notifica
 	result, err := tm.db.Exec(query, template.Name, template.Title, template.Body,
 		string(variablesJSON), template.Description, template.CreatedAt, template.UpdatedAt)
 	if err != nil {
+		var sqliteErr sqlite3.Error
+		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+			return nil, fmt.Errorf("template '%s': %w", template.Name, ErrTemplateExists)
+		}
 		return nil, fmt.Errorf("failed to insert template: %w", err)
 	}
 
@@ -119,7 +150,7 @@ func (tm *TemplateManager) UpdateTemplate(template NotificationTemplate) error {
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("template '%s' not found", template.Name)
+		return fmt.Errorf("template '%s': %w", template.Name, ErrTemplateNotFound)
 	}
 
 	return nil
@@ -140,7 +171,7 @@ func (tm *TemplateManager) DeleteTemplate(name string) error {
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("template '%s' not found", name)
+		return fmt.Errorf("template '%s': %w", name, ErrTemplateNotFound)
 	}
 
 	return nil
@@ -172,25 +203,25 @@ func (tm *TemplateManager) RenderTemplate(templateName string, variables map[str
 	allVars["time"] = time.Now().Format("15:04:05")
 
 	// Render title
-	titleTmpl, err := template.New("title").Parse(tmpl.Title)
+	titleTmpl, err := template.New("title").Funcs(templateFuncs).Parse(tmpl.Title)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse title template: %w", err)
+		return nil, fmt.Errorf("%w: failed to parse title template: %w", ErrInvalidTemplate, err)
 	}
 
 	var titleBuf strings.Builder
 	if err := titleTmpl.Execute(&titleBuf, allVars); err != nil {
-		return nil, fmt.Errorf("failed to render title template: %w", err)
+		return nil, fmt.Errorf("%w: failed to render title template: %w", ErrInvalidTemplate, err)
 	}
 
 	// Render body
-	bodyTmpl, err := template.New("body").Parse(tmpl.Body)
+	bodyTmpl, err := template.New("body").Funcs(templateFuncs).Parse(tmpl.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse body template: %w", err)
+		return nil, fmt.Errorf("%w: failed to parse body template: %w", ErrInvalidTemplate, err)
 	}
 
 	var bodyBuf strings.Builder
 	if err := bodyTmpl.Execute(&bodyBuf, allVars); err != nil {
-		return nil, fmt.Errorf("failed to render body template: %w", err)
+		return nil, fmt.Errorf("%w: failed to render body template: %w", ErrInvalidTemplate, err)
 	}
 
 	return &NotificationRequest{
@@ -203,13 +234,13 @@ func (tm *TemplateManager) RenderTemplate(templateName string, variables map[str
 // ValidateTemplate validates a template's syntax
 func (tm *TemplateManager) ValidateTemplate(tmpl NotificationTemplate) error {
 	// Validate title template
-	if _, err := template.New("title").Parse(tmpl.Title); err != nil {
-		return fmt.Errorf("invalid title template: %w", err)
+	if _, err := template.New("title").Funcs(templateFuncs).Parse(tmpl.Title); err != nil {
+		return fmt.Errorf("%w: title: %w", ErrInvalidTemplate, err)
 	}
 
 	// Validate body template
-	if _, err := template.New("body").Parse(tmpl.Body); err != nil {
-		return fmt.Errorf("invalid body template: %w", err)
+	if _, err := template.New("body").Funcs(templateFuncs).Parse(tmpl.Body); err != nil {
+		return fmt.Errorf("%w: body: %w", ErrInvalidTemplate, err)
 	}
 
 	return nil
@@ -243,7 +274,7 @@ func (tm *TemplateManager) scanTemplateRow(row *sql.Row) (*NotificationTemplate,
 		&variablesJSON, &template.Description, &template.CreatedAt, &template.UpdatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("template not found")
+			return nil, ErrTemplateNotFound
 		}
 		return nil, fmt.Errorf("failed to scan template: %w", err)
 	}
@@ -2,6 +2,9 @@ package apprise
 
 import (
 	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
@@ -334,6 +337,194 @@ func TestTemplateManager_AddAndRenderTemplate(t *testing.T) {
 	}
 }
 
+func TestTemplateManager_Errors(t *testing.T) {
+	tempDir := t.TempDir()
+	dbPath := filepath.Join(tempDir, "test_template_errors.db")
+
+	scheduler, err := NewNotificationScheduler(dbPath, New())
+	if err != nil {
+		t.Fatalf("Failed to create scheduler: %v", err)
+	}
+	defer scheduler.Close()
+
+	tm := scheduler.GetTemplateManager()
+
+	template := NotificationTemplate{Name: "dup", Body: "{{.level | default \"low\"}}"}
+	if _, err := tm.AddTemplate(template); err != nil {
+		t.Fatalf("Failed to add template: %v", err)
+	}
+	if _, err := tm.AddTemplate(template); !errors.Is(err, ErrTemplateExists) {
+		t.Errorf("Expected ErrTemplateExists, got %v", err)
+	}
+
+	req, err := tm.RenderTemplate("dup", nil)
+	if err != nil || req.Body != "low" {
+		t.Errorf("Expected default value to render, got %q (%v)", req.Body, err)
+	}
+
+	if _, err := tm.GetTemplate("missing"); !errors.Is(err, ErrTemplateNotFound) {
+		t.Errorf("Expected ErrTemplateNotFound from GetTemplate, got %v", err)
+	}
+	if err := tm.UpdateTemplate(NotificationTemplate{Name: "missing"}); !errors.Is(err, ErrTemplateNotFound) {
+		t.Errorf("Expected ErrTemplateNotFound from UpdateTemplate, got %v", err)
+	}
+	if err := tm.DeleteTemplate("missing"); !errors.Is(err, ErrTemplateNotFound) {
+		t.Errorf("Expected ErrTemplateNotFound from DeleteTemplate, got %v", err)
+	}
+	if err := tm.ValidateTemplate(NotificationTemplate{Body: "{{.x"}); !errors.Is(err, ErrInvalidTemplate) {
+		t.Errorf("Expected ErrInvalidTemplate, got %v", err)
+	}
+}
+
+func TestNotificationQueue_DeadLetter(t *testing.T) {
+	tempDir := t.TempDir()
+	dbPath := filepath.Join(tempDir, "test_dead_letter.db")
+
+	scheduler, err := NewNotificationScheduler(dbPath, New())
+	if err != nil {
+		t.Fatalf("Failed to create scheduler: %v", err)
+	}
+	defer scheduler.Close()
+
+	var ids []int64
+	for _, title := range []string{"one", "two", "three"} {
+		job, err := scheduler.QueueNotification(QueuedJob{Title: title, Body: "body", Services: []string{"json://localhost"}})
+		if err != nil {
+			t.Fatalf("Failed to queue job: %v", err)
+		}
+		ids = append(ids, job.ID)
+		if err := scheduler.queue.UpdateJobStatus(job.ID, JobStatusFailed, "boom"); err != nil {
+			t.Fatalf("Failed to mark job failed: %v", err)
+		}
+	}
+
+	failed, err := scheduler.GetDeadLetterJobs(10, 0)
+	if err != nil || len(failed) != 3 {
+		t.Fatalf("Expected 3 dead-letter jobs, got %d (%v)", len(failed), err)
+	}
+	if page, _ := scheduler.GetDeadLetterJobs(2, 0); len(page) != 2 {
+		t.Errorf("Expected a page of 2 dead-letter jobs, got %d", len(page))
+	}
+	if total, err := scheduler.CountDeadLetterJobs(); err != nil || total != 3 {
+		t.Errorf("Expected 3 dead-letter jobs in total, got %d (%v)", total, err)
+	}
+
+	if _, err := scheduler.PurgeDeadLetterJobs(nil, false); !errors.Is(err, ErrNoJobsSelected) {
+		t.Errorf("Expected ErrNoJobsSelected for an empty selection, got %v", err)
+	}
+
+	requeued, err := scheduler.RequeueDeadLetterJobs([]int64{ids[0]}, false)
+	if err != nil || requeued != 1 {
+		t.Fatalf("Expected 1 requeued job, got %d (%v)", requeued, err)
+	}
+	job, err := scheduler.GetQueuedJob(ids[0])
+	if err != nil {
+		t.Fatalf("Failed to get job: %v", err)
+	}
+	if job.Status != string(JobStatusPending) || job.RetryCount != 0 || job.CompletedAt != nil {
+		t.Errorf("Requeued job should be pending with a fresh retry budget, got %+v", job)
+	}
+
+	purged, err := scheduler.PurgeDeadLetterJobs(nil, true)
+	if err != nil || purged != 2 {
+		t.Fatalf("Expected 2 purged jobs, got %d (%v)", purged, err)
+	}
+	if _, err := scheduler.GetQueuedJob(ids[1]); !errors.Is(err, ErrQueuedJobNotFound) {
+		t.Errorf("Expected ErrQueuedJobNotFound for purged job, got %v", err)
+	}
+
+	job.Title = "edited"
+	job.Priority = 5
+	if err := scheduler.UpdateQueuedJob(*job); err != nil {
+		t.Fatalf("Failed to update job: %v", err)
+	}
+	if updated, _ := scheduler.GetQueuedJob(ids[0]); updated.Title != "edited" || updated.Priority != 5 {
+		t.Errorf("Expected updated job, got %+v", updated)
+	}
+
+	if err := scheduler.queue.UpdateJobStatus(ids[0], JobStatusRunning, ""); err != nil {
+		t.Fatalf("Failed to mark job running: %v", err)
+	}
+	if err := scheduler.RetryQueuedJob(ids[0]); !errors.Is(err, ErrQueuedJobRunning) {
+		t.Errorf("Expected ErrQueuedJobRunning, got %v", err)
+	}
+}
+
+func TestNotificationQueue_JobMetrics(t *testing.T) {
+	ok := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ok.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer failing.Close()
+
+	app := New()
+	if err := app.Add("webhook://"+strings.TrimPrefix(ok.URL, "http://")+"/hook", "ops"); err != nil {
+		t.Fatalf("Failed to add service: %v", err)
+	}
+
+	scheduler, err := NewNotificationScheduler(filepath.Join(t.TempDir(), "test_job_metrics.db"), app)
+	if err != nil {
+		t.Fatalf("Failed to create scheduler: %v", err)
+	}
+	defer scheduler.Close()
+
+	// Without services the job goes to the scheduler's services
+	configured, err := scheduler.QueueNotification(QueuedJob{Body: "configured", Tags: []string{"ops"}})
+	if err != nil {
+		t.Fatalf("Failed to queue job: %v", err)
+	}
+	// Services given by URL are notified whatever the job's tags
+	explicit, err := scheduler.QueueNotification(QueuedJob{
+		Body:     "explicit",
+		Services: []string{"webhook://" + strings.TrimPrefix(failing.URL, "http://") + "/hook"},
+		Tags:     []string{"ops"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to queue job: %v", err)
+	}
+
+	scheduler.processQueueBatch()
+
+	tests := []struct {
+		id     int64
+		status JobStatus
+		result string
+	}{
+		{configured.ID, JobStatusCompleted, "success"},
+		{explicit.ID, JobStatusRetrying, "failed"},
+	}
+	for _, tt := range tests {
+		job, err := scheduler.GetQueuedJob(tt.id)
+		if err != nil {
+			t.Fatalf("Failed to get job %d: %v", tt.id, err)
+		}
+		if job.Status != string(tt.status) {
+			t.Errorf("Job %d: expected status %s, got %s (%s)", tt.id, tt.status, job.Status, job.ErrorMessage)
+		}
+
+		metrics, err := scheduler.GetJobMetrics(tt.id)
+		if err != nil {
+			t.Fatalf("Failed to get metrics for job %d: %v", tt.id, err)
+		}
+		if len(metrics) != 1 {
+			t.Fatalf("Job %d: expected 1 metrics entry, got %d", tt.id, len(metrics))
+		}
+		m := metrics[0]
+		if m.Status != tt.result || m.ServiceID != "webhook" || m.JobID == nil || *m.JobID != tt.id {
+			t.Errorf("Job %d: unexpected metrics %+v", tt.id, m)
+		}
+		if m.Metadata["attempts"] == "" {
+			t.Errorf("Job %d: expected the attempt count in the metadata, got %v", tt.id, m.Metadata)
+		}
+		if tt.result == "failed" && (m.ErrorMessage == "" || m.Metadata["error_class"] == "") {
+			t.Errorf("Job %d: expected the error to be recorded, got %+v", tt.id, m)
+		}
+	}
+}
+
 func TestCronExpressionBuilder(t *testing.T) {
 	tests := []struct {
 		name     string
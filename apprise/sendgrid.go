@@ -48,7 +48,7 @@ type SendGridRequest struct {
 // NewSendGridService creates a new SendGrid service instance
 func NewSendGridService() Service {
 	return &SendGridService{
-		client: &http.Client{},
+		client: GetCloudHTTPClient("sendgrid"),
 	}
 }
 
@@ -190,12 +190,12 @@ func (s *SendGridService) sendEmail(ctx context.Context, payload SendGridRequest
 			if errors, ok := errorBody["errors"].([]interface{}); ok && len(errors) > 0 {
 				if errorMap, ok := errors[0].(map[string]interface{}); ok {
 					if message, ok := errorMap["message"].(string); ok {
-						return fmt.Errorf("SendGrid API error: %s (status %d)", message, resp.StatusCode)
+						return NewHTTPError(resp, fmt.Errorf("SendGrid API error: %s (status %d)", message, resp.StatusCode))
 					}
 				}
 			}
 		}
-		return fmt.Errorf("SendGrid API returned status %d", resp.StatusCode)
+		return NewHTTPError(resp, fmt.Errorf("SendGrid API returned status %d", resp.StatusCode))
 	}
 	
 	return nil
@@ -28,7 +28,7 @@ type SignalRequest struct {
 // NewSignalService creates a new Signal service instance
 func NewSignalService() Service {
 	return &SignalService{
-		client: &http.Client{},
+		client: GetWebhookHTTPClient("signal"),
 	}
 }
 
@@ -156,7 +156,7 @@ func (s *SignalService) sendMessage(ctx context.Context, recipient, message stri
 	
 	// Check response status
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("Signal API returned status %d", resp.StatusCode)
+		return NewHTTPError(resp, fmt.Errorf("Signal API returned status %d", resp.StatusCode))
 	}
 	
 	return nil
@@ -170,4 +170,9 @@ func (s *SignalService) SupportsAttachments() bool {
 // GetMaxBodyLength returns max body length (0 = unlimited)
 func (s *SignalService) GetMaxBodyLength() int {
 	return 0 // Signal has no specific body length limit
+}
+
+// GetNativeFormat returns plain text, as Signal does not render markup
+func (s *SignalService) GetNativeFormat() string {
+	return BodyFormatText
 }
\ No newline at end of file
@@ -8,9 +8,13 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 )
 
+// slackMaxUploadSize is Slack's per-file upload limit
+const slackMaxUploadSize = 1024 * 1024 * 1024
+
 // SlackService implements Slack webhook and bot API notifications
 type SlackService struct {
 	// Webhook mode fields
@@ -27,6 +31,7 @@ type SlackService struct {
 	username  string
 	iconURL   string
 	iconEmoji string
+	apiURL    string
 	client    *http.Client
 	mode      string // "webhook" or "bot"
 }
@@ -34,6 +39,7 @@ type SlackService struct {
 // NewSlackService creates a new Slack service instance
 func NewSlackService() Service {
 	return &SlackService{
+		apiURL: "https://slack.com/api",
 		client: GetWebhookHTTPClient("slack"),
 	}
 }
@@ -48,6 +54,15 @@ func (s *SlackService) GetDefaultPort() int {
 	return 443
 }
 
+// throttleCredential shares a budget per incoming webhook, or per channel
+// of a bot token
+func (s *SlackService) throttleCredential() string {
+	if s.mode == "webhook" {
+		return s.webhookURL
+	}
+	return s.botToken + "/" + s.channel
+}
+
 // ParseURL parses a Slack service URL
 // Webhook format: slack://TokenA/TokenB/TokenC/Channel
 // Bot format: slack://bottoken/Channel
@@ -154,10 +169,26 @@ type SlackText struct {
 
 // Send sends a notification to Slack
 func (s *SlackService) Send(ctx context.Context, req NotificationRequest) error {
+	// Incoming webhooks cannot upload files, so attachments are only
+	// delivered in bot mode
 	if s.mode == "webhook" {
 		return s.sendWebhook(ctx, req)
 	}
-	return s.sendBot(ctx, req)
+
+	channelID, err := s.sendBot(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	// The message is already in the channel, so a failed upload must not
+	// cause it to be posted again on a retry
+	if req.AttachmentMgr != nil && req.AttachmentMgr.Count() > 0 {
+		if err := s.uploadAttachments(ctx, channelID, req.AttachmentMgr.GetAll()); err != nil {
+			return newPartialDeliveryError(err)
+		}
+	}
+
+	return nil
 }
 
 // sendWebhook sends notification via Slack webhook
@@ -187,8 +218,9 @@ func (s *SlackService) sendWebhook(ctx context.Context, req NotificationRequest)
 	return s.sendPayload(ctx, s.webhookURL, payload)
 }
 
-// sendBot sends notification via Slack bot API
-func (s *SlackService) sendBot(ctx context.Context, req NotificationRequest) error {
+// sendBot sends notification via Slack bot API and returns the ID of the
+// channel it was posted to
+func (s *SlackService) sendBot(ctx context.Context, req NotificationRequest) (string, error) {
 	color := s.getColorForNotifyType(req.NotifyType)
 
 	payload := SlackBotPayload{
@@ -211,7 +243,7 @@ func (s *SlackService) sendBot(ctx context.Context, req NotificationRequest) err
 		payload.Text = req.Body
 	}
 
-	apiURL := "https://slack.com/api/chat.postMessage"
+	apiURL := s.apiURL + "/chat.postMessage"
 	return s.sendBotPayload(ctx, apiURL, payload)
 }
 
@@ -239,7 +271,7 @@ func (s *SlackService) sendPayload(ctx context.Context, webhookURL string, paylo
 	// Check response status
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("slack API error (status %d): %s", resp.StatusCode, string(body))
+		return NewHTTPError(resp, fmt.Errorf("slack API error (status %d): %s", resp.StatusCode, string(body)))
 	}
 
 	// For webhooks, check if response is "ok"
@@ -251,16 +283,17 @@ func (s *SlackService) sendPayload(ctx context.Context, webhookURL string, paylo
 	return nil
 }
 
-// sendBotPayload sends a bot API payload to Slack
-func (s *SlackService) sendBotPayload(ctx context.Context, apiURL string, payload SlackBotPayload) error {
+// sendBotPayload sends a bot API payload to Slack and returns the ID of the
+// channel Slack resolved the payload's channel name to
+func (s *SlackService) sendBotPayload(ctx context.Context, apiURL string, payload SlackBotPayload) (string, error) {
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
-		return fmt.Errorf("failed to marshal Slack bot payload: %w", err)
+		return "", fmt.Errorf("failed to marshal Slack bot payload: %w", err)
 	}
 
 	httpReq, err := http.NewRequestWithContext(ctx, "POST", apiURL, bytes.NewBuffer(jsonData))
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return "", fmt.Errorf("failed to create request: %w", err)
 	}
 
 	httpReq.Header.Set("Content-Type", "application/json")
@@ -269,32 +302,194 @@ func (s *SlackService) sendBotPayload(ctx context.Context, apiURL string, payloa
 
 	resp, err := s.client.Do(httpReq)
 	if err != nil {
-		return fmt.Errorf("failed to send Slack bot notification: %w", err)
+		return "", fmt.Errorf("failed to send Slack bot notification: %w", err)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	// Parse response for bot API
-	var result struct {
-		OK    bool   `json:"ok"`
-		Error string `json:"error,omitempty"`
-	}
+	var result slackPostMessageResponse
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("failed to read response body: %w", err)
+		return "", fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		return "", NewHTTPError(resp, fmt.Errorf("slack API error (status %d): %s", resp.StatusCode, string(body)))
 	}
 
 	if err := json.Unmarshal(body, &result); err != nil {
-		return fmt.Errorf("failed to parse Slack response: %w", err)
+		return "", fmt.Errorf("failed to parse Slack response: %w", err)
 	}
 
-	if !result.OK {
-		return fmt.Errorf("slack API error: %s", result.Error)
+	return result.Channel, result.apiError()
+}
+
+// slackAPIResponse is the common envelope of Slack Web API responses
+type slackAPIResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// slackPostMessageResponse is the response of chat.postMessage, which
+// carries the ID of the channel a #name or @user was resolved to
+type slackPostMessageResponse struct {
+	slackAPIResponse
+	Channel string `json:"channel"`
+}
+
+// slackUploadURLResponse is the response of files.getUploadURLExternal
+type slackUploadURLResponse struct {
+	slackAPIResponse
+	UploadURL string `json:"upload_url"`
+	FileID    string `json:"file_id"`
+}
+
+// slackCompletedFile identifies an uploaded file in files.completeUploadExternal
+type slackCompletedFile struct {
+	ID    string `json:"id"`
+	Title string `json:"title,omitempty"`
+}
+
+// slackCompleteUploadRequest is the payload of files.completeUploadExternal
+type slackCompleteUploadRequest struct {
+	Files     []slackCompletedFile `json:"files"`
+	ChannelID string               `json:"channel_id,omitempty"`
+}
+
+// uploadAttachments uploads attachments with the files.uploadV2 flow:
+// request an upload URL per file, post the content, then complete the
+// upload once to share all files to the channel. files.completeUploadExternal
+// only accepts channel IDs, so channelID is the one chat.postMessage
+// resolved the configured channel name to
+func (s *SlackService) uploadAttachments(ctx context.Context, channelID string, attachments []AttachmentInterface) error {
+	completed := make([]slackCompletedFile, 0, len(attachments))
+
+	for _, attachment := range attachments {
+		data, err := readAttachment(attachment, slackMaxUploadSize)
+		if err != nil {
+			return fmt.Errorf("slack attachment error: %w", err)
+		}
+
+		form := url.Values{}
+		form.Set("filename", attachment.GetName())
+		form.Set("length", strconv.Itoa(len(data)))
+
+		var upload slackUploadURLResponse
+		if err := s.callAPI(ctx, "files.getUploadURLExternal", "application/x-www-form-urlencoded",
+			strings.NewReader(form.Encode()), &upload); err != nil {
+			return err
+		}
+
+		if err := s.uploadFileContent(ctx, upload.UploadURL, attachment, data); err != nil {
+			return err
+		}
+
+		completed = append(completed, slackCompletedFile{ID: upload.FileID, Title: attachment.GetName()})
+	}
+
+	complete := slackCompleteUploadRequest{
+		Files:     completed,
+		ChannelID: channelID,
+	}
+
+	jsonData, err := json.Marshal(complete)
+	if err != nil {
+		return fmt.Errorf("failed to marshal Slack upload completion: %w", err)
+	}
+
+	var result slackAPIResponse
+	return s.callAPI(ctx, "files.completeUploadExternal", "application/json", bytes.NewBuffer(jsonData), &result)
+}
+
+// uploadFileContent posts the raw file content to an upload URL
+func (s *SlackService) uploadFileContent(ctx context.Context, uploadURL string, attachment AttachmentInterface, data []byte) error {
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", uploadURL, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create upload request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", attachment.GetMimeType())
+	httpReq.Header.Set("User-Agent", GetUserAgent())
+
+	resp, err := s.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to upload Slack attachment %s: %w", attachment.GetName(), err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return NewHTTPError(resp, fmt.Errorf("slack upload error (status %d): %s", resp.StatusCode, string(body)))
 	}
 
 	return nil
 }
 
+// callAPI invokes a Slack Web API method with bot authentication and
+// decodes the response into result, which must embed slackAPIResponse
+func (s *SlackService) callAPI(ctx context.Context, method, contentType string, body io.Reader, result interface{ apiError() error }) error {
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", s.apiURL+"/"+method, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", contentType)
+	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.botToken))
+	httpReq.Header.Set("User-Agent", GetUserAgent())
+
+	resp, err := s.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to call Slack %s: %w", method, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		return NewHTTPError(resp, fmt.Errorf("slack API error (status %d): %s", resp.StatusCode, string(respBody)))
+	}
+
+	if err := json.Unmarshal(respBody, result); err != nil {
+		return fmt.Errorf("failed to parse Slack %s response: %w", method, err)
+	}
+
+	return result.apiError()
+}
+
+// apiError returns the Slack API error carried by the response, if any
+func (r *slackAPIResponse) apiError() error {
+	if r.OK {
+		return nil
+	}
+
+	err := fmt.Errorf("slack API error: %s", r.Error)
+	if class, ok := slackErrorClasses[r.Error]; ok {
+		return NewNotificationError(class, 0, err)
+	}
+	return err
+}
+
+// slackErrorClasses classifies the Web API error codes Slack reports with
+// a 200 status
+var slackErrorClasses = map[string]ErrorClass{
+	"invalid_auth":      ErrorClassAuth,
+	"not_authed":        ErrorClassAuth,
+	"account_inactive":  ErrorClassAuth,
+	"token_revoked":     ErrorClassAuth,
+	"missing_scope":     ErrorClassAuth,
+	"channel_not_found": ErrorClassInvalidRecipient,
+	"not_in_channel":    ErrorClassInvalidRecipient,
+	"is_archived":       ErrorClassInvalidRecipient,
+	"user_not_found":    ErrorClassInvalidRecipient,
+	"ratelimited":       ErrorClassRateLimited,
+	"rate_limited":      ErrorClassRateLimited,
+	"msg_too_long":      ErrorClassPayloadTooLarge,
+}
+
 // TestURL validates a Slack service URL
 func (s *SlackService) TestURL(serviceURL string) error {
 	parsedURL, err := url.Parse(serviceURL)
@@ -305,9 +500,10 @@ func (s *SlackService) TestURL(serviceURL string) error {
 	return s.ParseURL(parsedURL)
 }
 
-// SupportsAttachments returns true since Slack supports file attachments
+// SupportsAttachments reports whether attachments are uploaded, which
+// needs a bot token since incoming webhooks cannot upload files
 func (s *SlackService) SupportsAttachments() bool {
-	return true
+	return s.mode != "webhook"
 }
 
 // GetMaxBodyLength returns Slack's message length limit
@@ -315,6 +511,11 @@ func (s *SlackService) GetMaxBodyLength() int {
 	return 4000 // Slack's character limit for messages
 }
 
+// GetNativeFormat returns mrkdwn, Slack's own markup
+func (s *SlackService) GetNativeFormat() string {
+	return BodyFormatMrkdwn
+}
+
 // getColorForNotifyType returns appropriate color for notification type
 func (s *SlackService) getColorForNotifyType(notifyType NotifyType) string {
 	switch notifyType {
@@ -0,0 +1,108 @@
+package apprise
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSlackService_SendWithAttachments(t *testing.T) {
+	var calls []string
+	var uploaded string
+	var complete slackCompleteUploadRequest
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/chat.postMessage":
+			_, _ = w.Write([]byte(`{"ok":true,"channel":"C0123"}`))
+		case "/files.getUploadURLExternal":
+			if r.Header.Get("Authorization") != "Bearer xoxb-token" {
+				t.Errorf("Missing bot authorization header")
+			}
+			_ = r.ParseForm()
+			if r.FormValue("filename") != "report.txt" || r.FormValue("length") != "6" {
+				t.Errorf("Unexpected upload URL request: %v", r.Form)
+			}
+			_, _ = w.Write([]byte(`{"ok":true,"upload_url":"` + server.URL + `/upload/F123","file_id":"F123"}`))
+		case "/upload/F123":
+			data, _ := io.ReadAll(r.Body)
+			uploaded = string(data)
+			w.WriteHeader(http.StatusOK)
+		case "/files.completeUploadExternal":
+			if err := json.NewDecoder(r.Body).Decode(&complete); err != nil {
+				t.Errorf("Failed to decode completion: %v", err)
+			}
+			_, _ = w.Write([]byte(`{"ok":true}`))
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	service := NewSlackService().(*SlackService)
+	service.mode = "bot"
+	service.botToken = "xoxb-token"
+	service.channel = "#general"
+	service.apiURL = server.URL
+
+	mgr := NewAttachmentManager()
+	_ = mgr.AddData([]byte("report"), "report.txt", "text/plain")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := service.Send(ctx, NotificationRequest{Body: "Nightly report", AttachmentMgr: mgr}); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	expected := []string{"/chat.postMessage", "/files.getUploadURLExternal", "/upload/F123", "/files.completeUploadExternal"}
+	if len(calls) != len(expected) {
+		t.Fatalf("Expected calls %v, got %v", expected, calls)
+	}
+	for i := range expected {
+		if calls[i] != expected[i] {
+			t.Errorf("Call %d: expected %s, got %s", i, expected[i], calls[i])
+		}
+	}
+
+	if uploaded != "report" {
+		t.Errorf("Expected uploaded content 'report', got %q", uploaded)
+	}
+	if complete.ChannelID != "C0123" || len(complete.Files) != 1 || complete.Files[0].ID != "F123" {
+		t.Errorf("Unexpected completion request: %+v", complete)
+	}
+}
+
+func TestSlackService_UploadError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/chat.postMessage" {
+			_, _ = w.Write([]byte(`{"ok":true}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":false,"error":"missing_scope"}`))
+	}))
+	defer server.Close()
+
+	service := NewSlackService().(*SlackService)
+	service.mode = "bot"
+	service.botToken = "xoxb-token"
+	service.channel = "C0123"
+	service.apiURL = server.URL
+
+	mgr := NewAttachmentManager()
+	_ = mgr.AddData([]byte("data"), "data.txt", "text/plain")
+
+	err := service.Send(context.Background(), NotificationRequest{Body: "Report", AttachmentMgr: mgr})
+	if err == nil {
+		t.Fatal("Expected error from failed upload")
+	}
+}
@@ -2,6 +2,8 @@ package apprise
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"testing"
 	"time"
@@ -111,6 +113,13 @@ func TestSlackService_Properties(t *testing.T) {
 		t.Error("Slack service should support attachments")
 	}
 
+	if err := service.TestURL("slack://T1/B1/secret/general"); err != nil {
+		t.Fatalf("Failed to parse webhook URL: %v", err)
+	}
+	if service.SupportsAttachments() {
+		t.Error("Slack incoming webhooks should not report attachment support")
+	}
+
 	if service.GetMaxBodyLength() != 4000 {
 		t.Errorf("Expected max body length 4000, got %d", service.GetMaxBodyLength())
 	}
@@ -176,3 +185,82 @@ func TestSlackService_QueryParams(t *testing.T) {
 		t.Errorf("Expected icon emoji ':ghost:', got '%s'", service.iconEmoji)
 	}
 }
+
+func TestSlackService_SendClassifiesAPIErrors(t *testing.T) {
+	tests := []struct {
+		code  string
+		class ErrorClass
+	}{
+		{"invalid_auth", ErrorClassAuth},
+		{"channel_not_found", ErrorClassInvalidRecipient},
+		{"msg_too_long", ErrorClassPayloadTooLarge},
+		{"something_else", ErrorClassUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"ok":false,"error":"` + tt.code + `"}`))
+			}))
+			defer server.Close()
+
+			service := NewSlackService().(*SlackService)
+			service.mode = "bot"
+			service.botToken = "xoxb-token"
+			service.channel = "#general"
+			service.apiURL = server.URL
+
+			err := service.Send(context.Background(), NotificationRequest{Body: "Hello", NotifyType: NotifyTypeInfo})
+			if err == nil {
+				t.Fatal("Expected error from Slack API")
+			}
+			if class := ClassifyError(err); class != tt.class {
+				t.Errorf("Expected class %s, got %s", tt.class, class)
+			}
+		})
+	}
+}
+
+func TestSlackService_FailedUploadDoesNotRepostMessage(t *testing.T) {
+	posts := 0
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/chat.postMessage":
+			posts++
+			_, _ = w.Write([]byte(`{"ok":true,"channel":"C123"}`))
+		case "/files.getUploadURLExternal":
+			_, _ = w.Write([]byte(`{"ok":true,"upload_url":"` + server.URL + `/upload","file_id":"F1"}`))
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	service := NewSlackService().(*SlackService)
+	service.mode = "bot"
+	service.botToken = "xoxb-token"
+	service.channel = "#general"
+	service.apiURL = server.URL
+
+	attachments := NewAttachmentManager()
+	if err := attachments.AddData([]byte("log"), "build.log", "text/plain"); err != nil {
+		t.Fatalf("Failed to add attachment: %v", err)
+	}
+
+	policy := DefaultRetryPolicy()
+	policy.MaxAttempts = 3
+	policy.BaseDelay = time.Millisecond
+
+	attempts, err := New().sendWithRetry(context.Background(), service,
+		NotificationRequest{Body: "Hello", NotifyType: NotifyTypeInfo, AttachmentMgr: attachments},
+		policy, nil, nil, CircuitBreakerPolicy{})
+	if !IsPartialDelivery(err) {
+		t.Errorf("Expected a partial delivery error, got %v", err)
+	}
+	if attempts != 1 || posts != 1 {
+		t.Errorf("Expected the message to be posted once in 1 attempt, got %d posts in %d attempts", posts, attempts)
+	}
+}
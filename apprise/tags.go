@@ -0,0 +1,94 @@
+package apprise
+
+import (
+	"strings"
+)
+
+const (
+	// TagMatchAll is a special filter tag that matches every configured service
+	TagMatchAll = "all"
+
+	// TagAlways is a special service tag; services carrying it are notified
+	// regardless of the tag filter in use
+	TagAlways = "always"
+)
+
+// MatchTags reports whether a service carrying serviceTags should receive a
+// notification filtered by the given tag expressions.
+//
+// Expressions follow Python Apprise semantics:
+//   - each entry in filter is OR'd with the others
+//   - within an entry, a comma separates OR'd terms ("ops, dev")
+//   - within a term, whitespace separates AND'd tags ("ops critical")
+//   - the "all" tag matches any service, and services tagged "always" match
+//     any filter
+//
+// An empty filter matches every service. Matching is case-insensitive.
+func MatchTags(serviceTags []string, filter []string) bool {
+	terms := parseTagExpressions(filter)
+	if len(terms) == 0 {
+		return true
+	}
+
+	tagSet := make(map[string]bool, len(serviceTags))
+	for _, tag := range serviceTags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" {
+			tagSet[tag] = true
+		}
+	}
+
+	if tagSet[TagAlways] {
+		return true
+	}
+
+	for _, term := range terms {
+		if matchTagTerm(tagSet, term) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseTagExpressions flattens filter expressions into a list of OR'd terms,
+// each of which is a list of AND'd tags
+func parseTagExpressions(filter []string) [][]string {
+	var terms [][]string
+
+	for _, expr := range filter {
+		for _, part := range strings.Split(expr, ",") {
+			fields := strings.Fields(strings.ToLower(part))
+			if len(fields) > 0 {
+				terms = append(terms, fields)
+			}
+		}
+	}
+
+	return terms
+}
+
+// matchTagTerm reports whether every tag in term is present in tagSet
+func matchTagTerm(tagSet map[string]bool, term []string) bool {
+	for _, tag := range term {
+		if tag == TagMatchAll {
+			continue
+		}
+		if !tagSet[tag] {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeTags trims tags and drops empty entries
+func normalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag != "" {
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized
+}
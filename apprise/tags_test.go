@@ -0,0 +1,94 @@
+package apprise
+
+import (
+	"testing"
+)
+
+func TestMatchTags(t *testing.T) {
+	tests := []struct {
+		name        string
+		serviceTags []string
+		filter      []string
+		expected    bool
+	}{
+		{"empty filter matches untagged", nil, nil, true},
+		{"empty filter matches tagged", []string{"ops"}, nil, true},
+		{"single tag match", []string{"ops"}, []string{"ops"}, true},
+		{"single tag mismatch", []string{"marketing"}, []string{"ops"}, false},
+		{"untagged service with filter", nil, []string{"ops"}, false},
+		{"comma is OR", []string{"dev"}, []string{"ops, dev"}, true},
+		{"space is AND satisfied", []string{"ops", "critical"}, []string{"ops critical"}, true},
+		{"space is AND unsatisfied", []string{"ops"}, []string{"ops critical"}, false},
+		{"mixed AND/OR", []string{"dev"}, []string{"ops critical, dev"}, true},
+		{"separate entries are OR", []string{"dev"}, []string{"ops", "dev"}, true},
+		{"all matches tagged", []string{"marketing"}, []string{"all"}, true},
+		{"all matches untagged", nil, []string{"all"}, true},
+		{"always tag bypasses filter", []string{"always"}, []string{"ops"}, true},
+		{"case insensitive", []string{"Ops"}, []string{"OPS"}, true},
+		{"blank filter entries ignored", []string{"ops"}, []string{"", " , "}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MatchTags(tt.serviceTags, tt.filter); got != tt.expected {
+				t.Errorf("MatchTags(%v, %v) = %v, expected %v", tt.serviceTags, tt.filter, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAppriseTagFiltering(t *testing.T) {
+	app := New()
+
+	ops := NewMockService("ops", 0)
+	marketing := NewMockService("marketing", 0)
+	pager := NewMockService("pager", 0)
+	audit := NewMockService("audit", 0)
+
+	app.services = append(app.services,
+		serviceEntry{service: ops, tags: []string{"ops"}},
+		serviceEntry{service: marketing, tags: []string{"marketing"}},
+		serviceEntry{service: pager, tags: []string{"ops", "critical"}},
+		serviceEntry{service: audit, tags: []string{"always"}},
+	)
+
+	responses := app.Notify("Title", "Body", NotifyTypeInfo, WithTags("ops"))
+	if len(responses) != 3 {
+		t.Fatalf("Expected 3 responses for 'ops', got %d", len(responses))
+	}
+	if marketing.GetCallCount() != 0 {
+		t.Error("Marketing service should not be notified for 'ops'")
+	}
+
+	responses = app.Notify("Title", "Body", NotifyTypeInfo, WithTags("ops critical"))
+	if len(responses) != 2 {
+		t.Errorf("Expected 2 responses for 'ops critical', got %d", len(responses))
+	}
+
+	responses = app.Notify("Title", "Body", NotifyTypeInfo)
+	if len(responses) != 4 {
+		t.Errorf("Expected 4 responses without tags, got %d", len(responses))
+	}
+
+	if count := app.CountByTag("marketing, critical"); count != 3 {
+		t.Errorf("Expected 3 services for 'marketing, critical', got %d", count)
+	}
+}
+
+func TestAppriseAddRemembersTags(t *testing.T) {
+	app := New()
+
+	if err := app.Add("json://localhost/ops", "ops", " "); err != nil {
+		t.Fatalf("Failed to add service: %v", err)
+	}
+	if err := app.Add("json://localhost/all"); err != nil {
+		t.Fatalf("Failed to add service: %v", err)
+	}
+
+	if len(app.services[0].tags) != 1 || app.services[0].tags[0] != "ops" {
+		t.Errorf("Expected tags [ops], got %v", app.services[0].tags)
+	}
+	if app.CountByTag("ops") != 1 {
+		t.Errorf("Expected 1 service tagged ops, got %d", app.CountByTag("ops"))
+	}
+}
@@ -5,11 +5,26 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"html"
 	"io"
+	"mime/multipart"
 	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
+)
+
+const (
+	// telegramMaxPhotoSize is the Bot API limit for sendPhoto uploads; larger
+	// images are sent as documents
+	telegramMaxPhotoSize = 10 * 1024 * 1024
+
+	// telegramMaxDocumentSize is the Bot API limit for file uploads
+	telegramMaxDocumentSize = 50 * 1024 * 1024
+
+	// telegramMaxMediaGroupSize is the number of items sendMediaGroup accepts
+	telegramMaxMediaGroupSize = 10
 )
 
 // TelegramService implements Telegram Bot API notifications
@@ -20,13 +35,15 @@ type TelegramService struct {
 	preview   bool
 	parseMode string
 	threadID  string
+	apiURL    string
 	client    *http.Client
 }
 
 // NewTelegramService creates a new Telegram service instance
 func NewTelegramService() Service {
 	return &TelegramService{
-		client:    &http.Client{},
+		apiURL:    "https://api.telegram.org",
+		client:    GetCloudHTTPClient("telegram"),
 		preview:   true,       // Enable web page preview by default
 		parseMode: "Markdown", // Default to Markdown parsing
 	}
@@ -42,6 +59,17 @@ func (t *TelegramService) GetDefaultPort() int {
 	return 443
 }
 
+// throttleCredential shares a budget between every chat of a bot, since
+// Telegram limits messages per bot token
+func (t *TelegramService) throttleCredential() string {
+	return t.botToken
+}
+
+// throttleCost counts one request per chat
+func (t *TelegramService) throttleCost() int {
+	return len(t.chatIDs)
+}
+
 // ParseURL parses a Telegram service URL
 // Format: tgram://bot_token/chat_id[/chat_id2/...]
 // Format: telegram://bot_token/chat_id[/chat_id2/...]
@@ -118,30 +146,55 @@ type TelegramMessage struct {
 
 // TelegramResponse represents the Telegram API response
 type TelegramResponse struct {
-	OK          bool   `json:"ok"`
-	Description string `json:"description,omitempty"`
-	ErrorCode   int    `json:"error_code,omitempty"`
+	OK          bool                        `json:"ok"`
+	Description string                      `json:"description,omitempty"`
+	ErrorCode   int                         `json:"error_code,omitempty"`
+	Parameters  *TelegramResponseParameters `json:"parameters,omitempty"`
+}
+
+// TelegramResponseParameters carries extra details of a failed request
+type TelegramResponseParameters struct {
+	RetryAfter int `json:"retry_after,omitempty"`
 }
 
 // Send sends a notification to Telegram
 func (t *TelegramService) Send(ctx context.Context, req NotificationRequest) error {
 	// Combine title and body
-	message := t.formatMessage(req.Title, req.Body, req.NotifyType)
+	message := t.formatMessage(req.Title, req.Body, req.NotifyType, req.BodyFormat)
+
+	// Read attachments once up front so each chat receives the same files
+	var files []telegramFile
+	if req.AttachmentMgr != nil && req.AttachmentMgr.Count() > 0 {
+		var err error
+		if files, err = t.prepareAttachments(req.AttachmentMgr.GetAll()); err != nil {
+			return err
+		}
+	}
 
 	// Send to each chat ID
 	var lastError error
 	successCount := 0
+	delivered := 0
 
 	for _, chatID := range t.chatIDs {
 		if err := t.sendToChat(ctx, chatID, message); err != nil {
 			lastError = err
+			continue
+		}
+		delivered++
+		if err := t.sendAttachments(ctx, chatID, files); err != nil {
+			lastError = err
 		} else {
 			successCount++
 		}
 	}
 
-	// Return error only if all sends failed
+	// Return error only if all sends failed. Chats that got the message
+	// without its files must not get it again on a retry.
 	if successCount == 0 && lastError != nil {
+		if delivered > 0 {
+			return newPartialDeliveryError(lastError)
+		}
 		return lastError
 	}
 
@@ -150,7 +203,7 @@ func (t *TelegramService) Send(ctx context.Context, req NotificationRequest) err
 
 // sendToChat sends a message to a specific Telegram chat
 func (t *TelegramService) sendToChat(ctx context.Context, chatID, message string) error {
-	apiURL := fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", t.botToken)
+	apiURL := t.methodURL("sendMessage")
 
 	payload := TelegramMessage{
 		ChatID:                chatID,
@@ -174,6 +227,16 @@ func (t *TelegramService) sendToChat(ctx context.Context, chatID, message string
 	httpReq.Header.Set("Content-Type", "application/json")
 	httpReq.Header.Set("User-Agent", GetUserAgent())
 
+	return t.doRequest(httpReq)
+}
+
+// methodURL returns the Bot API URL for a method
+func (t *TelegramService) methodURL(method string) string {
+	return fmt.Sprintf("%s/bot%s/%s", t.apiURL, t.botToken, method)
+}
+
+// doRequest performs a Bot API request and checks the response envelope
+func (t *TelegramService) doRequest(httpReq *http.Request) error {
 	resp, err := t.client.Do(httpReq)
 	if err != nil {
 		return fmt.Errorf("failed to send Telegram notification: %w", err)
@@ -188,18 +251,180 @@ func (t *TelegramService) sendToChat(ctx context.Context, chatID, message string
 
 	var result TelegramResponse
 	if err := json.Unmarshal(body, &result); err != nil {
+		if resp.StatusCode >= 500 {
+			return NewHTTPError(resp, fmt.Errorf("telegram API error (status %d): %s", resp.StatusCode, string(body)))
+		}
 		return fmt.Errorf("failed to parse Telegram response: %w", err)
 	}
 
 	if !result.OK {
-		return fmt.Errorf("telegram API error (%d): %s", result.ErrorCode, result.Description)
+		notifyErr := NewHTTPError(resp, fmt.Errorf("telegram API error (%d): %s", result.ErrorCode, result.Description))
+		// Unknown chats and bots blocked by the user are recipient problems
+		if resp.StatusCode == http.StatusForbidden ||
+			(resp.StatusCode == http.StatusBadRequest && strings.Contains(strings.ToLower(result.Description), "chat not found")) {
+			notifyErr.Class = ErrorClassInvalidRecipient
+		}
+		// Flood control reports its wait time in the body rather than a header
+		if result.Parameters != nil && result.Parameters.RetryAfter > 0 {
+			notifyErr.RetryAfter = time.Duration(result.Parameters.RetryAfter) * time.Second
+		}
+		return notifyErr
 	}
 
 	return nil
 }
 
-// formatMessage formats the title and body into a single message
-func (t *TelegramService) formatMessage(title, body string, notifyType NotifyType) string {
+// telegramFile is an attachment read into memory for upload
+type telegramFile struct {
+	name     string
+	mimeType string
+	data     []byte
+	photo    bool
+}
+
+// prepareAttachments reads attachments and decides how each is uploaded.
+// Images within the photo limit go out as photos, everything else as
+// documents.
+func (t *TelegramService) prepareAttachments(attachments []AttachmentInterface) ([]telegramFile, error) {
+	files := make([]telegramFile, 0, len(attachments))
+
+	for _, attachment := range attachments {
+		data, err := readAttachment(attachment, telegramMaxDocumentSize)
+		if err != nil {
+			return nil, fmt.Errorf("telegram attachment error: %w", err)
+		}
+
+		files = append(files, telegramFile{
+			name:     attachment.GetName(),
+			mimeType: attachment.GetMimeType(),
+			data:     data,
+			photo:    isImageAttachment(attachment) && len(data) <= telegramMaxPhotoSize,
+		})
+	}
+
+	return files, nil
+}
+
+// sendAttachments uploads files to a chat. Photos and documents cannot share
+// a media group, so each kind is grouped separately: single files use
+// sendPhoto/sendDocument and larger sets use sendMediaGroup.
+func (t *TelegramService) sendAttachments(ctx context.Context, chatID string, files []telegramFile) error {
+	var photos, documents []telegramFile
+	for _, file := range files {
+		if file.photo {
+			photos = append(photos, file)
+		} else {
+			documents = append(documents, file)
+		}
+	}
+
+	for _, group := range [][]telegramFile{photos, documents} {
+		for start := 0; start < len(group); start += telegramMaxMediaGroupSize {
+			end := start + telegramMaxMediaGroupSize
+			if end > len(group) {
+				end = len(group)
+			}
+
+			var err error
+			if end-start == 1 {
+				err = t.sendFile(ctx, chatID, group[start])
+			} else {
+				err = t.sendMediaGroup(ctx, chatID, group[start:end])
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// sendFile uploads a single file with sendPhoto or sendDocument
+func (t *TelegramService) sendFile(ctx context.Context, chatID string, file telegramFile) error {
+	method, field := "sendDocument", "document"
+	if file.photo {
+		method, field = "sendPhoto", "photo"
+	}
+
+	return t.sendMultipart(ctx, method, chatID, nil, map[string]telegramFile{field: file})
+}
+
+// telegramInputMedia describes one item of a sendMediaGroup request
+type telegramInputMedia struct {
+	Type  string `json:"type"`
+	Media string `json:"media"`
+}
+
+// sendMediaGroup uploads 2-10 files of the same kind as one album
+func (t *TelegramService) sendMediaGroup(ctx context.Context, chatID string, files []telegramFile) error {
+	media := make([]telegramInputMedia, 0, len(files))
+	parts := make(map[string]telegramFile, len(files))
+
+	for i, file := range files {
+		name := fmt.Sprintf("file%d", i)
+		mediaType := "document"
+		if file.photo {
+			mediaType = "photo"
+		}
+		media = append(media, telegramInputMedia{Type: mediaType, Media: "attach://" + name})
+		parts[name] = file
+	}
+
+	mediaJSON, err := json.Marshal(media)
+	if err != nil {
+		return fmt.Errorf("failed to marshal Telegram media group: %w", err)
+	}
+
+	return t.sendMultipart(ctx, "sendMediaGroup", chatID, map[string]string{"media": string(mediaJSON)}, parts)
+}
+
+// sendMultipart posts a multipart/form-data Bot API request
+func (t *TelegramService) sendMultipart(ctx context.Context, method, chatID string, fields map[string]string, files map[string]telegramFile) error {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	params := map[string]string{"chat_id": chatID}
+	if t.silent {
+		params["disable_notification"] = "true"
+	}
+	if t.threadID != "" {
+		params["message_thread_id"] = t.threadID
+	}
+	for key, value := range fields {
+		params[key] = value
+	}
+
+	for key, value := range params {
+		if err := writer.WriteField(key, value); err != nil {
+			return fmt.Errorf("failed to write Telegram field %s: %w", key, err)
+		}
+	}
+
+	for field, file := range files {
+		if err := writeMultipartFile(writer, field, file.name, file.mimeType, file.data); err != nil {
+			return fmt.Errorf("failed to write Telegram attachment %s: %w", file.name, err)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to finalize Telegram upload: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", t.methodURL(method), body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", writer.FormDataContentType())
+	httpReq.Header.Set("User-Agent", GetUserAgent())
+
+	return t.doRequest(httpReq)
+}
+
+// formatMessage formats the title and body into a single message. bodyFormat
+// is the format NotifyAll converted the body to, if any.
+func (t *TelegramService) formatMessage(title, body string, notifyType NotifyType, bodyFormat string) string {
 	var message strings.Builder
 
 	// Add notification type emoji based on type
@@ -212,9 +437,9 @@ func (t *TelegramService) formatMessage(title, body string, notifyType NotifyTyp
 	if title != "" {
 		switch t.parseMode {
 		case "HTML":
-			message.WriteString(fmt.Sprintf("<b>%s</b>\n", title))
+			message.WriteString(fmt.Sprintf("<b>%s</b>\n", html.EscapeString(title)))
 		case "Markdown":
-			message.WriteString(fmt.Sprintf("*%s*\n", title))
+			message.WriteString(markdownLegacyRenderer{}.bold(escapeChars(title, markdownLegacyReserved)) + "\n")
 		case "MarkdownV2":
 			// Escape special characters for MarkdownV2
 			title = t.escapeMarkdownV2(title)
@@ -224,9 +449,9 @@ func (t *TelegramService) formatMessage(title, body string, notifyType NotifyTyp
 		}
 	}
 
-	// Add body
+	// Add body; bodies already converted to MarkdownV2 keep their markup
 	if body != "" {
-		if t.parseMode == "MarkdownV2" {
+		if t.parseMode == "MarkdownV2" && bodyFormat != BodyFormatMarkdownV2 {
 			body = t.escapeMarkdownV2(body)
 		}
 		message.WriteString(body)
@@ -253,17 +478,7 @@ func (t *TelegramService) getEmojiForNotifyType(notifyType NotifyType) string {
 
 // escapeMarkdownV2 escapes special characters for Telegram's MarkdownV2
 func (t *TelegramService) escapeMarkdownV2(text string) string {
-	// Characters that need to be escaped in MarkdownV2
-	specialChars := []string{
-		"_", "*", "[", "]", "(", ")", "~", "`", ">", "#", "+", "-", "=", "|", "{", "}", ".", "!",
-	}
-
-	result := text
-	for _, char := range specialChars {
-		result = strings.ReplaceAll(result, char, "\\"+char)
-	}
-
-	return result
+	return escapeMarkdownV2(text)
 }
 
 // TestURL validates a Telegram service URL
@@ -277,6 +492,7 @@ func (t *TelegramService) TestURL(serviceURL string) error {
 }
 
 // SupportsAttachments returns true since Telegram supports file attachments
+// (photos up to 10MB, other files up to 50MB)
 func (t *TelegramService) SupportsAttachments() bool {
 	return true
 }
@@ -286,6 +502,20 @@ func (t *TelegramService) GetMaxBodyLength() int {
 	return 4096 // Telegram's character limit for messages
 }
 
+// GetNativeFormat returns the body format matching the configured parse mode
+func (t *TelegramService) GetNativeFormat() string {
+	switch t.parseMode {
+	case "HTML":
+		return BodyFormatHTML
+	case "Markdown":
+		return BodyFormatMarkdownLegacy
+	case "MarkdownV2":
+		return BodyFormatMarkdownV2
+	default:
+		return BodyFormatText
+	}
+}
+
 // validateChatID validates that a chat ID is in the correct format
 func (t *TelegramService) validateChatID(chatID string) bool {
 	// Chat ID can be:
@@ -0,0 +1,102 @@
+package apprise
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTelegramService_SendWithAttachments(t *testing.T) {
+	methods := make(map[string]int)
+	var media []telegramInputMedia
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		methods[method]++
+
+		if !strings.HasPrefix(r.URL.Path, "/botbot_token/") {
+			t.Errorf("Unexpected path: %s", r.URL.Path)
+		}
+
+		if method != "sendMessage" {
+			if err := r.ParseMultipartForm(1 << 20); err != nil {
+				t.Fatalf("Failed to parse multipart form: %v", err)
+			}
+			if r.FormValue("chat_id") != "12345" {
+				t.Errorf("Expected chat_id 12345, got %q", r.FormValue("chat_id"))
+			}
+		}
+
+		switch method {
+		case "sendPhoto":
+			if _, ok := r.MultipartForm.File["photo"]; !ok {
+				t.Error("sendPhoto missing photo part")
+			}
+		case "sendMediaGroup":
+			if err := json.Unmarshal([]byte(r.FormValue("media")), &media); err != nil {
+				t.Errorf("Failed to decode media: %v", err)
+			}
+			if len(r.MultipartForm.File) != len(media) {
+				t.Errorf("Expected %d files, got %d", len(media), len(r.MultipartForm.File))
+			}
+		}
+
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	service := NewTelegramService().(*TelegramService)
+	if err := service.TestURL("tgram://bot_token/12345"); err != nil {
+		t.Fatalf("Failed to parse URL: %v", err)
+	}
+	service.apiURL = server.URL
+
+	mgr := NewAttachmentManager()
+	_ = mgr.AddData([]byte("png-bytes"), "screenshot.png", "image/png")
+	_ = mgr.AddData([]byte("log one"), "one.log", "text/plain")
+	_ = mgr.AddData([]byte("log two"), "two.log", "text/plain")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := service.Send(ctx, NotificationRequest{Body: "Build failed", AttachmentMgr: mgr}); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	if methods["sendMessage"] != 1 || methods["sendPhoto"] != 1 || methods["sendMediaGroup"] != 1 {
+		t.Errorf("Unexpected method calls: %v", methods)
+	}
+	if len(media) != 2 || media[0].Type != "document" || media[0].Media != "attach://file0" {
+		t.Errorf("Unexpected media group: %+v", media)
+	}
+}
+
+func TestTelegramService_PrepareAttachments(t *testing.T) {
+	service := NewTelegramService().(*TelegramService)
+
+	mgr := NewAttachmentManager()
+	_ = mgr.AddData([]byte("small"), "small.jpg", "image/jpeg")
+	_ = mgr.AddData(make([]byte, telegramMaxPhotoSize+1), "large.jpg", "image/jpeg")
+
+	files, err := service.prepareAttachments(mgr.GetAll())
+	if err != nil {
+		t.Fatalf("prepareAttachments failed: %v", err)
+	}
+
+	if !files[0].photo {
+		t.Error("Small image should be sent as a photo")
+	}
+	if files[1].photo {
+		t.Error("Image above the photo limit should be sent as a document")
+	}
+
+	mgr.Clear()
+	_ = mgr.AddData(make([]byte, telegramMaxDocumentSize+1), "huge.bin", "application/octet-stream")
+	if _, err := service.prepareAttachments(mgr.GetAll()); err == nil {
+		t.Error("Expected error for attachment above the document limit")
+	}
+}
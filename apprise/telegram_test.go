@@ -2,9 +2,12 @@ package apprise
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestTelegramServiceURLParsing(t *testing.T) {
@@ -202,7 +205,7 @@ func TestTelegramMessageFormatting(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			service.parseMode = tc.parseMode
-			message := service.formatMessage(tc.title, tc.body, tc.notifyType)
+			message := service.formatMessage(tc.title, tc.body, tc.notifyType, "")
 
 			for _, expected := range tc.contains {
 				if !strings.Contains(message, expected) {
@@ -326,3 +329,41 @@ func TestTelegramSendMethod(t *testing.T) {
 		t.Errorf("Error should be network-related or API error, got: %v", err)
 	}
 }
+
+func TestTelegramSendClassifiesErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		body       string
+		class      ErrorClass
+		retryAfter time.Duration
+	}{
+		{"flood control", http.StatusTooManyRequests, `{"ok":false,"error_code":429,"description":"Too Many Requests","parameters":{"retry_after":7}}`, ErrorClassRateLimited, 7 * time.Second},
+		{"chat not found", http.StatusBadRequest, `{"ok":false,"error_code":400,"description":"Bad Request: chat not found"}`, ErrorClassInvalidRecipient, 0},
+		{"bad token", http.StatusUnauthorized, `{"ok":false,"error_code":401,"description":"Unauthorized"}`, ErrorClassAuth, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			service := NewTelegramService().(*TelegramService)
+			if err := service.TestURL("tgram://bot_token/12345"); err != nil {
+				t.Fatalf("Failed to parse URL: %v", err)
+			}
+			service.apiURL = server.URL
+
+			err := service.Send(context.Background(), NotificationRequest{Body: "Hello", NotifyType: NotifyTypeInfo})
+			if class := ClassifyError(err); class != tt.class {
+				t.Errorf("Expected class %s, got %s (%v)", tt.class, class, err)
+			}
+			if retryAfter := GetRetryAfter(err); retryAfter != tt.retryAfter {
+				t.Errorf("Expected Retry-After %v, got %v", tt.retryAfter, retryAfter)
+			}
+		})
+	}
+}
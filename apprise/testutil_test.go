@@ -0,0 +1,134 @@
+package apprise
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// stubRequest is a request received by a stubAPI
+type stubRequest struct {
+	path          string
+	escapedPath   string
+	query         string
+	proto         int
+	header        http.Header
+	authorization string
+	clientCN      string // common name of the client certificate, if any
+	body          []byte
+}
+
+// decode unmarshals the request's JSON body into v
+func (req stubRequest) decode(v interface{}) {
+	_ = json.Unmarshal(req.body, v)
+}
+
+// stubAPI is a fake HTTP API standing in for a service's. It answers with
+// the handler it was started with and records every request it answered,
+// in the order they were answered.
+type stubAPI struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []stubRequest
+}
+
+// newStubAPI starts a stubAPI serving handler
+func newStubAPI(t *testing.T, handler http.HandlerFunc) *stubAPI {
+	t.Helper()
+	api := &stubAPI{}
+	api.Server = httptest.NewServer(api.record(handler))
+	t.Cleanup(api.Close)
+	return api
+}
+
+// newTLSStubAPI starts a stubAPI serving handler over HTTP/2 and TLS,
+// asking clients for certificates as clientAuth says
+func newTLSStubAPI(t *testing.T, clientAuth tls.ClientAuthType, handler http.HandlerFunc) *stubAPI {
+	t.Helper()
+	api := &stubAPI{}
+	api.Server = httptest.NewUnstartedServer(api.record(handler))
+	api.EnableHTTP2 = true
+	api.TLS = &tls.Config{ClientAuth: clientAuth}
+	api.StartTLS()
+	t.Cleanup(api.Close)
+	return api
+}
+
+// record wraps handler to record the requests it answers
+func (api *stubAPI) record(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		req := stubRequest{
+			path:          r.URL.Path,
+			escapedPath:   r.URL.EscapedPath(),
+			query:         r.URL.RawQuery,
+			proto:         r.ProtoMajor,
+			header:        r.Header.Clone(),
+			authorization: r.Header.Get("Authorization"),
+			body:          body,
+		}
+		if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+			req.clientCN = r.TLS.PeerCertificates[0].Subject.CommonName
+		}
+
+		handler(w, r)
+
+		api.mu.Lock()
+		api.requests = append(api.requests, req)
+		api.mu.Unlock()
+	}
+}
+
+// received returns the requests answered so far
+func (api *stubAPI) received() []stubRequest {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	return append([]stubRequest(nil), api.requests...)
+}
+
+// receivedAt returns the requests answered so far whose path starts with
+// prefix, leaving out token requests and the like
+func (api *stubAPI) receivedAt(prefix string) []stubRequest {
+	var requests []stubRequest
+	for _, req := range api.received() {
+		if strings.HasPrefix(req.path, prefix) {
+			requests = append(requests, req)
+		}
+	}
+	return requests
+}
+
+// paths returns the paths of the requests answered so far
+func (api *stubAPI) paths() []string {
+	var paths []string
+	for _, req := range api.received() {
+		paths = append(paths, req.path)
+	}
+	return paths
+}
+
+// answerStatus returns a handler answering every request with status
+func answerStatus(status int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}
+}
+
+// answerByPath returns a handler answering each path with the status given
+// for it, 200 by default
+func answerByPath(statuses map[string]int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if status, ok := statuses[r.URL.Path]; ok {
+			w.WriteHeader(status)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+}
@@ -29,7 +29,7 @@ type TextMagicSMSRequest struct {
 // NewTextMagicService creates a new TextMagic service instance
 func NewTextMagicService() Service {
 	return &TextMagicService{
-		client: &http.Client{},
+		client: GetCloudHTTPClient("textmagic"),
 	}
 }
 
@@ -145,7 +145,7 @@ func (s *TextMagicService) sendBulkSMS(ctx context.Context, message string) erro
 	
 	// Check response status
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("TextMagic API returned status %d", resp.StatusCode)
+		return NewHTTPError(resp, fmt.Errorf("TextMagic API returned status %d", resp.StatusCode))
 	}
 	
 	return nil
@@ -159,4 +159,9 @@ func (s *TextMagicService) SupportsAttachments() bool {
 // GetMaxBodyLength returns max body length (0 = unlimited)
 func (s *TextMagicService) GetMaxBodyLength() int {
 	return 1600 // TextMagic supports long SMS up to 1600 characters
+}
+
+// GetNativeFormat returns plain text, as SMS does not render markup
+func (s *TextMagicService) GetNativeFormat() string {
+	return BodyFormatText
 }
\ No newline at end of file
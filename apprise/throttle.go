@@ -0,0 +1,318 @@
+package apprise
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ThrottleLimit caps how fast notifications are sent to a provider: at most
+// Requests sends per Interval, with bursts of up to Requests. The zero value
+// disables throttling.
+type ThrottleLimit struct {
+	Requests int
+	Interval time.Duration
+}
+
+// Enabled reports whether the limit throttles anything
+func (l ThrottleLimit) Enabled() bool {
+	return l.Requests > 0 && l.Interval > 0
+}
+
+// String formats the limit as accepted by ParseThrottleLimit
+func (l ThrottleLimit) String() string {
+	if !l.Enabled() {
+		return "off"
+	}
+	return strconv.Itoa(l.Requests) + "/" + l.Interval.String()
+}
+
+// defaultThrottleLimits follow the rate limits documented by each provider.
+// Services not listed here are not throttled unless configured.
+var defaultThrottleLimits = map[string]ThrottleLimit{
+	"discord":     {Requests: 5, Interval: 2 * time.Second},  // webhook limit
+	"telegram":    {Requests: 30, Interval: time.Second},     // per bot
+	"slack":       {Requests: 1, Interval: time.Second},      // incoming webhooks
+	"msteams":     {Requests: 4, Interval: time.Second},      // connector webhooks
+	"twilio":      {Requests: 1, Interval: time.Second},      // long code SMS
+	"nexmo":       {Requests: 30, Interval: time.Second},     // SMS API
+	"aws-sns-sms": {Requests: 20, Interval: time.Second},     // SNS SMS default quota
+	"pagerduty":   {Requests: 120, Interval: time.Minute},    // Events API v2 per key
+	"ntfy":        {Requests: 60, Interval: 5 * time.Minute}, // ntfy.sh visitor limit
+	"mastodon":    {Requests: 300, Interval: 5 * time.Minute},
+}
+
+// DefaultThrottleLimit returns the built-in limit for a service ID, or the
+// zero limit when the service is not throttled by default
+func DefaultThrottleLimit(serviceID string) ThrottleLimit {
+	return defaultThrottleLimits[serviceID]
+}
+
+// ParseThrottleLimit parses a limit such as "5/2s", "30/s" or "120/m".
+// "off" and "0" disable throttling.
+func ParseThrottleLimit(value string) (ThrottleLimit, error) {
+	value = strings.TrimSpace(value)
+	if value == "off" || value == "0" {
+		return ThrottleLimit{}, nil
+	}
+
+	count, per, found := strings.Cut(value, "/")
+	if !found {
+		return ThrottleLimit{}, fmt.Errorf("invalid throttle value %q: must be requests/interval such as 5/2s", value)
+	}
+
+	requests, err := strconv.Atoi(count)
+	if err != nil || requests <= 0 {
+		return ThrottleLimit{}, fmt.Errorf("invalid throttle value %q: requests must be a positive integer", value)
+	}
+
+	// A bare unit means one of it: 30/s is 30 per second
+	switch per {
+	case "s", "m", "h":
+		per = "1" + per
+	}
+	interval, err := time.ParseDuration(per)
+	if err != nil || interval <= 0 {
+		return ThrottleLimit{}, fmt.Errorf("invalid throttle value %q: interval must be a duration such as 2s or 1m", value)
+	}
+
+	return ThrottleLimit{Requests: requests, Interval: interval}, nil
+}
+
+// SetThrottleLimit sets the outbound limit for services with the given
+// service ID, replacing the built-in default. A zero limit turns throttling
+// off for the service. Services added with a throttle URL parameter keep
+// their own limit.
+func (a *Apprise) SetThrottleLimit(serviceID string, limit ThrottleLimit) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.throttles[serviceID] = limit
+}
+
+// GetThrottleLimit returns the limit applied to services with the given
+// service ID that have no throttle URL parameter
+func (a *Apprise) GetThrottleLimit(serviceID string) ThrottleLimit {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if limit, ok := a.throttles[serviceID]; ok {
+		return limit
+	}
+	return DefaultThrottleLimit(serviceID)
+}
+
+// parseThrottleOverride extracts the throttle parameter from the URL query
+// and removes it so services never see it:
+//
+//	throttle=5/2s    at most 5 notifications every 2 seconds
+//	throttle=off     never throttle this URL
+func parseThrottleOverride(query url.Values) (*ThrottleLimit, error) {
+	value := query.Get("throttle")
+	query.Del("throttle")
+
+	if value == "" {
+		return nil, nil
+	}
+	limit, err := ParseThrottleLimit(value)
+	if err != nil {
+		return nil, err
+	}
+	return &limit, nil
+}
+
+// throttleCredentialer is implemented by services whose provider limits
+// apply to a credential that URLs with different targets share, such as a
+// Telegram bot token or an SMS account
+type throttleCredentialer interface {
+	throttleCredential() string
+}
+
+// throttleCoster is implemented by services that make one provider request
+// per target, so a send takes one token per target
+type throttleCoster interface {
+	throttleCost() int
+}
+
+// throttleKey identifies the credential a configured service sends with, so
+// URLs sharing a webhook or token share a budget. Services name their
+// credential through throttleCredentialer; for the others it is the URL
+// without its query. The key is a digest, which keeps secrets out of the
+// bucket table.
+func throttleKey(service Service, serviceURL *url.URL) string {
+	credential := *serviceURL
+	credential.RawQuery = ""
+	credential.Fragment = ""
+	identity := credential.String()
+	if c, ok := service.(throttleCredentialer); ok {
+		identity = c.throttleCredential()
+	}
+	sum := sha256.Sum256([]byte(identity))
+	return hex.EncodeToString(sum[:16])
+}
+
+// throttleCost returns the number of tokens a send to svc takes
+func throttleCost(svc Service) int {
+	if c, ok := svc.(throttleCoster); ok {
+		if cost := c.throttleCost(); cost > 1 {
+			return cost
+		}
+	}
+	return 1
+}
+
+// throttleFor returns the bucket an entry's sends draw from, or nil when the
+// entry is not throttled
+func (a *Apprise) throttleFor(entry serviceEntry) *tokenBucket {
+	serviceID := entry.service.GetServiceID()
+
+	limit := a.GetThrottleLimit(serviceID)
+	if entry.throttle != nil {
+		limit = *entry.throttle
+	}
+	if !limit.Enabled() {
+		return nil
+	}
+	return throttleBuckets.get(serviceID+"|"+entry.throttleKey+"|"+limit.String(), limit)
+}
+
+// throttleBuckets holds the token buckets of every Apprise instance in the
+// process. Provider limits apply per credential, however many instances
+// send with it.
+var throttleBuckets = &bucketRegistry{buckets: make(map[string]*tokenBucket)}
+
+// maxIdleBuckets is the table size above which idle buckets are pruned
+const maxIdleBuckets = 1024
+
+// bucketRegistry maps throttle keys to their token buckets
+type bucketRegistry struct {
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+// get returns the bucket for key, creating it with limit if needed
+func (r *bucketRegistry) get(key string, limit ThrottleLimit) *tokenBucket {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if bucket, ok := r.buckets[key]; ok {
+		return bucket
+	}
+
+	// A bucket that has refilled completely is the same as a new one, so
+	// dropping it loses nothing
+	if len(r.buckets) >= maxIdleBuckets {
+		now := time.Now()
+		for k, bucket := range r.buckets {
+			if bucket.full(now) {
+				delete(r.buckets, k)
+			}
+		}
+	}
+
+	bucket := newTokenBucket(limit)
+	r.buckets[key] = bucket
+	return bucket
+}
+
+// tokenBucket is a token bucket that lets callers reserve a token ahead of
+// time and wait for it
+type tokenBucket struct {
+	mu       sync.Mutex
+	capacity float64
+	rate     float64 // tokens per second
+	tokens   float64 // negative while callers wait for reserved tokens
+	last     time.Time
+}
+
+// newTokenBucket creates a full bucket for limit
+func newTokenBucket(limit ThrottleLimit) *tokenBucket {
+	return &tokenBucket{
+		capacity: float64(limit.Requests),
+		rate:     float64(limit.Requests) / limit.Interval.Seconds(),
+		tokens:   float64(limit.Requests),
+		last:     time.Now(),
+	}
+}
+
+// refill adds the tokens accumulated since the last update. The caller must
+// hold b.mu.
+func (b *tokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens += elapsed * b.rate
+		if b.tokens > b.capacity {
+			b.tokens = b.capacity
+		}
+		b.last = now
+	}
+}
+
+// full reports whether the bucket has refilled to capacity
+func (b *tokenBucket) full(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill(now)
+	return b.tokens >= b.capacity
+}
+
+// reserve takes n tokens and returns how long the caller must wait before
+// using them. When ctx would expire first, nothing is taken and the wait is
+// returned with ok false.
+func (b *tokenBucket) reserve(ctx context.Context, n int) (wait time.Duration, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.refill(now)
+
+	if need := float64(n); b.tokens < need {
+		wait = time.Duration((need - b.tokens) / b.rate * float64(time.Second))
+	}
+	if deadline, hasDeadline := ctx.Deadline(); hasDeadline && deadline.Sub(now) < wait {
+		return wait, false
+	}
+
+	b.tokens -= float64(n)
+	return wait, true
+}
+
+// cancel returns n reserved tokens that will not be used
+func (b *tokenBucket) cancel(n int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens += float64(n)
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+}
+
+// wait blocks until the bucket allows a send of n requests. It fails with a
+// rate limited error, without waiting, when the context deadline comes
+// before the tokens are free, and with the context's error if it is
+// cancelled while waiting.
+func (b *tokenBucket) wait(ctx context.Context, n int) (time.Duration, error) {
+	delay, ok := b.reserve(ctx, n)
+	if !ok {
+		err := NewNotificationError(ErrorClassRateLimited, 0,
+			fmt.Errorf("throttled: next send allowed in %s, after the notification deadline", delay.Round(time.Millisecond)))
+		err.RetryAfter = delay
+		return 0, err
+	}
+	if delay <= 0 {
+		return 0, nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		b.cancel(n)
+		return 0, ctx.Err()
+	case <-timer.C:
+		return delay, nil
+	}
+}
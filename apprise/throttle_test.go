@@ -0,0 +1,154 @@
+package apprise
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseThrottleLimit(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    ThrottleLimit
+		wantErr bool
+	}{
+		{"5/2s", ThrottleLimit{Requests: 5, Interval: 2 * time.Second}, false},
+		{"30/s", ThrottleLimit{Requests: 30, Interval: time.Second}, false},
+		{"120/m", ThrottleLimit{Requests: 120, Interval: time.Minute}, false},
+		{"off", ThrottleLimit{}, false},
+		{"0", ThrottleLimit{}, false},
+		{"5", ThrottleLimit{}, true},
+		{"-1/s", ThrottleLimit{}, true},
+		{"5/soon", ThrottleLimit{}, true},
+		{"5/0s", ThrottleLimit{}, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseThrottleLimit(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseThrottleLimit(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseThrottleLimit(%q) = %+v, want %+v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestSetThrottleLimit(t *testing.T) {
+	app := New()
+
+	if got := app.GetThrottleLimit("discord"); got != DefaultThrottleLimit("discord") || !got.Enabled() {
+		t.Errorf("Expected the built-in Discord limit, got %v", got)
+	}
+	if got := app.GetThrottleLimit("webhook"); got.Enabled() {
+		t.Errorf("Expected webhooks to be unthrottled by default, got %v", got)
+	}
+
+	app.SetThrottleLimit("discord", ThrottleLimit{Requests: 1, Interval: time.Second})
+	if got := app.GetThrottleLimit("discord"); got.String() != "1/1s" {
+		t.Errorf("Expected the configured limit, got %v", got)
+	}
+	app.SetThrottleLimit("discord", ThrottleLimit{})
+	if got := app.GetThrottleLimit("discord"); got.Enabled() {
+		t.Errorf("Expected throttling to be off, got %v", got)
+	}
+
+	if err := app.Add("webhook://localhost/hook?throttle=fast"); err == nil {
+		t.Error("Expected an invalid throttle parameter to be rejected")
+	}
+}
+
+func TestThrottleWaitsForToken(t *testing.T) {
+	rs := newRecordingServer(t, `{}`)
+	app := New()
+	if err := app.Add("webhook://" + strings.TrimPrefix(rs.URL, "http://") + "/wait?throttle=2/200ms"); err != nil {
+		t.Fatalf("Failed to add service: %v", err)
+	}
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		for _, resp := range app.NotifyAll(NotificationRequest{Body: "hello"}) {
+			if !resp.Success {
+				t.Fatalf("Send %d failed: %v", i+1, resp.Error)
+			}
+		}
+	}
+
+	// Two sends use the burst, the third waits for a token (100ms)
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Errorf("Expected the third send to be throttled, all three took %s", elapsed)
+	}
+	if got := len(rs.received()); got != 3 {
+		t.Errorf("Expected 3 requests, got %d", got)
+	}
+}
+
+func TestThrottleFailsBeforeDeadline(t *testing.T) {
+	rs := newRecordingServer(t, `{}`)
+	serviceURL := "webhook://" + strings.TrimPrefix(rs.URL, "http://") + "/deadline?throttle=1/1h"
+
+	// Both instances send with the same credential, so they share a budget
+	first, second := New(), New()
+	for _, app := range []*Apprise{first, second} {
+		if err := app.Add(serviceURL); err != nil {
+			t.Fatalf("Failed to add service: %v", err)
+		}
+	}
+
+	if resp := first.NotifyAll(NotificationRequest{Body: "one"}); !resp[0].Success {
+		t.Fatalf("First send failed: %v", resp[0].Error)
+	}
+
+	start := time.Now()
+	resp := second.NotifyAll(NotificationRequest{Body: "two"})
+	if resp[0].Success || !errors.Is(resp[0].Error, ErrRateLimited) {
+		t.Fatalf("Expected a rate limited error, got %v", resp[0].Error)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected to fail without waiting past the deadline, took %s", elapsed)
+	}
+	if got := len(rs.received()); got != 1 {
+		t.Errorf("Expected only the first request to be sent, got %d", got)
+	}
+}
+
+func TestThrottleSharesBotCredential(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, _ = w.Write([]byte(`{"ok":true,"result":{}}`))
+	}))
+	defer server.Close()
+
+	// A send to two chats takes both tokens of the bot's budget
+	first := New()
+	if err := first.Add("tgram://111throttle/1/2?throttle=2/1h&api_url=" + server.URL); err != nil {
+		t.Fatalf("Failed to add service: %v", err)
+	}
+	if resp := first.NotifyAll(NotificationRequest{Body: "one"}); !resp[0].Success {
+		t.Fatalf("First send failed: %v", resp[0].Error)
+	}
+
+	// Another chat of the same bot draws from that budget, another bot
+	// has its own
+	second := New()
+	for _, serviceURL := range []string{"tgram://111throttle/3", "tgram://222throttle/3"} {
+		if err := second.Add(serviceURL + "?throttle=2/1h&api_url=" + server.URL); err != nil {
+			t.Fatalf("Failed to add service: %v", err)
+		}
+	}
+	resp := second.NotifyAll(NotificationRequest{Body: "two"})
+	if resp[0].Success || !errors.Is(resp[0].Error, ErrRateLimited) {
+		t.Errorf("Expected the same bot to be rate limited, got %v", resp[0].Error)
+	}
+	if !resp[1].Success {
+		t.Errorf("Expected another bot to send, got %v", resp[1].Error)
+	}
+	if requests != 3 {
+		t.Errorf("Expected 3 requests, got %d", requests)
+	}
+}
@@ -18,7 +18,7 @@ type TikTokService struct {
 // NewTikTokService creates a new TikTok service instance
 func NewTikTokService() Service {
 	return &TikTokService{
-		client: &http.Client{},
+		client: GetCloudHTTPClient("tiktok"),
 	}
 }
 
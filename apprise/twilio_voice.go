@@ -312,7 +312,7 @@ func (t *TwilioVoiceService) sendViaWebhook(ctx context.Context, calls []TwilioV
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("twilio voice webhook error (status %d): %s", resp.StatusCode, string(body))
+		return NewHTTPError(resp, fmt.Errorf("twilio voice webhook error (status %d): %s", resp.StatusCode, string(body)))
 	}
 
 	return nil
@@ -354,7 +354,7 @@ func (t *TwilioVoiceService) sendSingleCall(ctx context.Context, callsURL string
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("twilio API error for %s (status %d): %s", call.To, resp.StatusCode, string(body))
+		return NewHTTPError(resp, fmt.Errorf("twilio API error for %s (status %d): %s", call.To, resp.StatusCode, string(body)))
 	}
 
 	return nil
@@ -317,7 +317,7 @@ func (t *TwitterService) sendViaWebhook(ctx context.Context, message TwitterMess
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("twitter webhook error (status %d): %s", resp.StatusCode, string(body))
+		return NewHTTPError(resp, fmt.Errorf("twitter webhook error (status %d): %s", resp.StatusCode, string(body)))
 	}
 
 	return nil
@@ -360,7 +360,7 @@ func (t *TwitterService) sendTweetV2(ctx context.Context, message TwitterMessage
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("twitter api error (status %d): %s", resp.StatusCode, string(body))
+		return NewHTTPError(resp, fmt.Errorf("twitter api error (status %d): %s", resp.StatusCode, string(body)))
 	}
 
 	return nil
@@ -395,7 +395,7 @@ func (t *TwitterService) sendTweetV1(ctx context.Context, message TwitterMessage
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("twitter api error (status %d): %s", resp.StatusCode, string(body))
+		return NewHTTPError(resp, fmt.Errorf("twitter api error (status %d): %s", resp.StatusCode, string(body)))
 	}
 
 	return nil
@@ -180,7 +180,7 @@ func (w *WebhookService) Send(ctx context.Context, req NotificationRequest) erro
 	// Check response status
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("webhook error (status %d): %s", resp.StatusCode, string(body))
+		return NewHTTPError(resp, fmt.Errorf("webhook error (status %d): %s", resp.StatusCode, string(body)))
 	}
 
 	return nil
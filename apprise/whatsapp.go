@@ -39,7 +39,7 @@ type WhatsAppRequest struct {
 // NewWhatsAppService creates a new WhatsApp Business API service instance
 func NewWhatsAppService() Service {
 	return &WhatsAppService{
-		client: &http.Client{},
+		client: GetCloudHTTPClient("whatsapp"),
 	}
 }
 
@@ -165,11 +165,11 @@ func (s *WhatsAppService) sendMessage(ctx context.Context, recipient, message st
 		if json.NewDecoder(resp.Body).Decode(&errorBody) == nil {
 			if errorData, ok := errorBody["error"].(map[string]interface{}); ok {
 				if message, ok := errorData["message"].(string); ok {
-					return fmt.Errorf("WhatsApp API error: %s (status %d)", message, resp.StatusCode)
+					return NewHTTPError(resp, fmt.Errorf("WhatsApp API error: %s (status %d)", message, resp.StatusCode))
 				}
 			}
 		}
-		return fmt.Errorf("WhatsApp API returned status %d", resp.StatusCode)
+		return NewHTTPError(resp, fmt.Errorf("WhatsApp API returned status %d", resp.StatusCode))
 	}
 	
 	return nil
@@ -29,7 +29,7 @@ type YouTubeCommentRequest struct {
 // NewYouTubeService creates a new YouTube service instance
 func NewYouTubeService() Service {
 	return &YouTubeService{
-		client: &http.Client{},
+		client: GetCloudHTTPClient("youtube"),
 	}
 }
 
@@ -26,7 +26,7 @@ type ZapierRequest struct {
 // NewZapierService creates a new Zapier service instance
 func NewZapierService() Service {
 	return &ZapierService{
-		client: &http.Client{},
+		client: GetWebhookHTTPClient("zapier"),
 	}
 }
 
@@ -109,7 +109,7 @@ func (s *ZapierService) triggerWebhook(ctx context.Context, req NotificationRequ
 	
 	// Check response status
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("Zapier webhook returned status %d", resp.StatusCode)
+		return NewHTTPError(resp, fmt.Errorf("Zapier webhook returned status %d", resp.StatusCode))
 	}
 	
 	return nil
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"syscall"
 	"time"
 
@@ -20,13 +21,23 @@ var (
 	host            = flag.String("host", "0.0.0.0", "Host to bind to")
 	dbPath          = flag.String("db", "./apprise-api.db", "Database path for scheduler and config storage")
 	configPath      = flag.String("config", "", "Path to configuration file")
+	configDir       = flag.String("config-dir", "", "Directory administrators may load configuration files from through the API (default: the directory of -config)")
+	configWatch     = flag.Bool("config-watch", true, "Reload the configuration file when it changes (also reloaded on SIGHUP)")
+	configPoll      = flag.Duration("config-poll", 0, "Poll the configuration file at this interval instead of using file system notifications")
 	logLevel        = flag.String("log-level", "info", "Log level (debug, info, warn, error)")
 	corsOrigin      = flag.String("cors-origin", "*", "CORS allowed origins")
 	jwtSecret       = flag.String("jwt-secret", "", "JWT secret for authentication (generate if empty)")
 	requireAuth     = flag.Bool("require-auth", false, "Require authentication for API access")
 	tokenDuration   = flag.Int("token-duration", 24, "JWT token duration in hours")
+	adminUser       = flag.String("admin-user", "admin", "Username of the initial admin user, created when no users exist")
+	adminPassword   = flag.String("admin-password", "", "Password of the initial admin user (generated and written to -admin-password-file if empty)")
+	adminPassFile   = flag.String("admin-password-file", "", "File a generated initial admin password is written to (default: the database path plus .admin-password)")
+	allowRegister   = flag.Bool("allow-registration", false, "Let anyone register a user account; otherwise only admins can")
+	allowPrivURLs   = flag.Bool("allow-private-attachment-urls", false, "Let attachment URLs reach loopback, private and link-local addresses")
 	enableRateLimit = flag.Bool("enable-ratelimit", true, "Enable rate limiting")
 	rateLimit       = flag.Int("rate-limit", 60, "Requests per minute per client")
+	rateLimitAlgo   = flag.String("ratelimit-algorithm", api.RateLimitSlidingWindow, "Rate limiting algorithm (sliding_window, token_bucket)")
+	rateLimitBack   = flag.String("ratelimit-backend", api.RateLimitBackendMemory, "Where rate limit state is kept (memory, database); database shares limits between replicas using the same database")
 	version         = flag.Bool("version", false, "Show version information")
 )
 
@@ -67,9 +78,40 @@ func main() {
 	}
 
 	// Load configuration if provided
+	var configLoader *apprise.ConfigLoader
 	if *configPath != "" {
-		// TODO: Implement configuration loading
-		logger.Printf("Configuration file: %s", *configPath)
+		configLoader = apprise.NewConfigLoader(appriseInstance)
+		if err := configLoader.AddFromFile(*configPath); err != nil {
+			logger.Fatalf("Failed to load configuration: %v", err)
+		}
+		if err := configLoader.ApplyToApprise(); err != nil {
+			logger.Fatalf("Failed to load configuration: %v", err)
+		}
+		logger.Printf("Loaded %d services from %s", appriseInstance.Count(), *configPath)
+
+		if *configWatch {
+			watcher := apprise.NewConfigWatcher(configLoader)
+			if *configPoll > 0 {
+				watcher.SetPollInterval(*configPoll)
+				watcher.SetForcePolling(true)
+			}
+			watcher.OnReload(func(event apprise.ReloadEvent) {
+				if event.Err != nil {
+					logger.Printf("Configuration reload failed, keeping the previous services: %v", event.Err)
+					return
+				}
+				logger.Printf("Configuration reloaded (%s): %d services", event.Trigger, event.Services)
+			})
+			if err := watcher.Start(); err != nil {
+				logger.Fatalf("Failed to watch configuration: %v", err)
+			}
+			defer watcher.Stop()
+			logger.Printf("Watching configuration for changes (%s)", watcher.Trigger())
+		}
+	}
+
+	if *configDir == "" && *configPath != "" {
+		*configDir = filepath.Dir(*configPath)
 	}
 
 	// Generate JWT secret if not provided
@@ -80,19 +122,27 @@ func main() {
 
 	// Create API server configuration
 	serverConfig := &api.ServerConfig{
-		Host:          *host,
-		Port:          *port,
-		DatabasePath:  *dbPath,
-		CORSOrigins:   []string{*corsOrigin},
-		JWTSecret:     *jwtSecret,
-		LogLevel:      *logLevel,
-		RequireAuth:   *requireAuth,
-		TokenDuration: *tokenDuration,
+		Host:                       *host,
+		Port:                       *port,
+		DatabasePath:               *dbPath,
+		CORSOrigins:                []string{*corsOrigin},
+		JWTSecret:                  *jwtSecret,
+		LogLevel:                   *logLevel,
+		RequireAuth:                *requireAuth,
+		TokenDuration:              *tokenDuration,
+		AdminUsername:              *adminUser,
+		AdminPassword:              *adminPassword,
+		AdminPasswordFile:          *adminPassFile,
+		AllowRegistration:          *allowRegister,
+		ConfigDir:                  *configDir,
+		AllowPrivateAttachmentURLs: *allowPrivURLs,
 		RateLimit: api.RateLimitConfig{
 			Enabled:        *enableRateLimit,
 			RequestsPerMin: *rateLimit,
 			BurstSize:      *rateLimit / 4, // 25% of limit as burst
 			WindowSize:     time.Minute,
+			Algorithm:      *rateLimitAlgo,
+			Backend:        *rateLimitBack,
 		},
 	}
 
@@ -118,6 +168,22 @@ func main() {
 		}
 	}()
 
+	// Reload the configuration on SIGHUP
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for range hup {
+			if configLoader == nil {
+				continue
+			}
+			if count, err := configLoader.Reload(); err != nil {
+				logger.Printf("Configuration reload failed, keeping the previous services: %v", err)
+			} else {
+				logger.Printf("Configuration reloaded (SIGHUP): %d services", count)
+			}
+		}
+	}()
+
 	// Wait for interrupt signal to gracefully shutdown
 	<-c
 	logger.Println("Shutting down server...")